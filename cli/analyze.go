@@ -67,6 +67,10 @@ var analyzeFlags = []cli.Flag{
 		Hidden: false,
 		Value:  0,
 	},
+	cli.DurationFlag{
+		Name:  "analyze.expected-interval",
+		Usage: "Correct latency percentiles for coordinated omission, with operations intended to start this often per worker. Derived from --rps-limit if unset.",
+	},
 	cli.IntFlag{
 		Name:   "analyze.limit",
 		Usage:  "Max operations to load for analysis.",
@@ -231,7 +235,7 @@ func printMixedOpAnalysis(ctx *cli.Context, aggr aggregate.Aggregated, details b
 			for _, ep := range ops.HostNames {
 				totals := eps[ep]
 				console.SetColor("Print", color.New(color.FgWhite))
-				console.Print(" * ", ep, ": Avg: ", totals.StringDetails(details), ".")
+				console.Print(" * ", ep, ": Avg: ", totals.StringDetails(details), ", ", totals.Operations, " ops.")
 				if totals.Errors > 0 {
 					console.SetColor("Print", color.New(color.FgHiRed))
 					console.Print(" Errors: ", totals.Errors)
@@ -343,6 +347,8 @@ func printAnalysis(ctx *cli.Context, w io.Writer, o bench.Operations) {
 		Prefiltered: prefiltered,
 		DurFunc:     durFn,
 		SkipDur:     ctx.Duration("analyze.skip"),
+
+		ExpectedInterval: expectedInterval(ctx),
 	})
 	if wrSegs != nil {
 		for _, ops := range aggr.Operations {
@@ -438,7 +444,7 @@ func printAnalysis(ctx *cli.Context, w io.Writer, o bench.Operations) {
 				console.SetColor("Print", color.New(color.FgWhite))
 				console.Print(" * ", ep, ":")
 				if !details {
-					console.Print(" Avg: ", ops.StringDetails(details), "\n")
+					console.Print(" Avg: ", ops.StringDetails(details), ", ", ops.Operations, " ops\n")
 				} else {
 					console.Print("\n")
 				}
@@ -558,6 +564,9 @@ func printRequestAnalysis(_ *cli.Context, ops aggregate.Operation, details bool)
 		if reqs.FirstByte != nil {
 			console.Println(" * TTFB:", reqs.FirstByte)
 		}
+		if ops.CorrectedLatency != nil {
+			console.Println(" * Corrected for coordinated omission:", ops.CorrectedLatency)
+		}
 
 		if details && reqs.FirstAccess != nil {
 			reqs := reqs.FirstAccess
@@ -626,6 +635,9 @@ func printRequestAnalysis(_ *cli.Context, ops aggregate.Operation, details bool)
 	if reqs.Skipped {
 		console.Println("Not enough requests")
 	}
+	if ops.CorrectedLatency != nil {
+		console.Println(" * Corrected for coordinated omission:", ops.CorrectedLatency)
+	}
 
 	sizes := reqs.BySize
 	for _, s := range sizes {
@@ -686,6 +698,18 @@ func printRequestAnalysis(_ *cli.Context, ops aggregate.Operation, details bool)
 	}
 }
 
+// expectedInterval returns the intended time between operations of a worker.
+// If not set it is derived from the rate limit, which applies to all workers of a client.
+func expectedInterval(ctx *cli.Context) time.Duration {
+	if d := ctx.Duration("analyze.expected-interval"); d > 0 {
+		return d
+	}
+	if rps := ctx.Float64("rps-limit"); rps > 0 && ctx.Int("concurrent") > 0 {
+		return time.Duration(float64(ctx.Int("concurrent")) / rps * float64(time.Second))
+	}
+	return 0
+}
+
 // analysisDur returns the analysis duration or 0 if un-parsable.
 func analysisDur(ctx *cli.Context, total time.Duration) time.Duration {
 	dur := ctx.String("analyze.dur")
@@ -24,6 +24,7 @@ import (
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/pkg/v3/console"
 	"github.com/minio/warp/pkg/bench"
+	"github.com/minio/warp/pkg/generator"
 )
 
 var appendFlags = []cli.Flag{
@@ -65,6 +66,10 @@ func mainAppend(ctx *cli.Context) error {
 	if b.Versioned {
 		return fmt.Errorf("append versioned objects is not supported")
 	}
+	if ctx.Bool("obj.static") {
+		// Continue the pattern in every append, so objects can be verified.
+		b.Pattern = generator.NewAppendPattern(0)
+	}
 	switch {
 	case !b.PutOpts.Checksum.IsSet():
 		// Set checksum to CRC64NVME if not set
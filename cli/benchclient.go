@@ -226,11 +226,12 @@ func serveWs(w http.ResponseWriter, r *http.Request) {
 				break
 			}
 			info.startRequested = true
+			wait := max(time.Until(req.StartTime), 0)
+			info.startTime = time.Now().Add(wait)
 			ab.Lock()
 			ab.info[req.Stage] = info
 			ab.Unlock()
 
-			wait := max(time.Until(req.StartTime), 0)
 			console.Infoln("Starting stage", req.Stage, "in", wait)
 			go func() {
 				time.Sleep(wait)
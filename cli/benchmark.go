@@ -32,10 +32,12 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dustin/go-humanize"
 	"github.com/klauspost/compress/zstd"
 	"github.com/minio/cli"
 	"github.com/minio/madmin-go/v4"
 	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
 	"github.com/minio/pkg/v3/console"
 	"github.com/minio/warp/api"
 	"github.com/minio/warp/pkg/aggregate"
@@ -58,6 +60,44 @@ var benchFlags = []cli.Flag{
 		Usage: "Duration to run the benchmark. Use 's' and 'm' to specify seconds and minutes.",
 		Value: 5 * time.Minute,
 	},
+	cli.DurationFlag{
+		Name:  "warmup",
+		Usage: "Run operations for this duration before recording them. Included in --duration.",
+	},
+	cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "Print the operations that would be sent without contacting the server.",
+	},
+	cli.IntFlag{
+		Name:  "dry-run.ops",
+		Usage: "Number of operations to plan with --dry-run.",
+		Value: 10000,
+	},
+	cli.BoolFlag{
+		Name:  "bucket.create",
+		Usage: "Create missing buckets in --region before the benchmark. Existing buckets are not modified.",
+	},
+	cli.BoolFlag{
+		Name:  "resources",
+		Usage: "Sample CPU, heap and goroutine use of the client during the benchmark and print min/max/avg. Sampled at --progress interval or every second.",
+	},
+	cli.BoolFlag{
+		Name:  "selftest",
+		Usage: "Upload, verify and delete a few objects before preparing, and stop if any fail.",
+	},
+	cli.DurationFlag{
+		Name:  "drain-timeout",
+		Usage: "When interrupted, wait this long for running operations to finish before printing results.",
+		Value: 10 * time.Second,
+	},
+	cli.StringFlag{
+		Name:  "max-bytes",
+		Usage: "Stop the benchmark once operations have transferred this many bytes, for example 500GiB. Applies to each client.",
+	},
+	cli.StringFlag{
+		Name:  "sla",
+		Usage: "Exit with an error if thresholds are exceeded. Requires --full. Example: --sla p99=200ms,GET:p50=20ms,errors=1%. errors=0% allows no errors",
+	},
 	cli.BoolFlag{
 		Name:  "autoterm",
 		Usage: "Auto terminate when benchmark is considered stable.",
@@ -76,6 +116,10 @@ var benchFlags = []cli.Flag{
 		Name:  "noclear",
 		Usage: "Do not clear bucket before or after running benchmarks. Use when running multiple clients.",
 	},
+	cli.BoolFlag{
+		Name:  "cleanup.tracked",
+		Usage: "After the benchmark delete the objects it created, including prepared objects and delete markers, also with --noclear.",
+	},
 	cli.BoolFlag{
 		Name:   "keep-data",
 		Usage:  "Leave benchmark data. Do not run cleanup after benchmark. Bucket will still be cleaned prior to benchmark",
@@ -112,11 +156,20 @@ func runBench(ctx *cli.Context, b bench.Benchmark) error {
 	activeBenchmarkMu.Unlock()
 	c := b.GetCommon()
 	c.Error = printError
+	fatalIf(probe.NewError(bench.Validate(b)), "Invalid benchmark options")
 	if ab != nil {
 		c.ClientIdx = ab.clientIdx
 		return runClientBenchmark(ctx, b, ab)
 	}
-	if done, err := runServerBenchmark(ctx, b); done || err != nil {
+	if ctx.Bool("dry-run") {
+		for _, out := range c.ExtraOut {
+			close(out)
+		}
+		fatalIf(probe.NewError(runDryRun(ctx, b)), "Unable to plan dry run")
+		return nil
+	}
+	sla := parseSLA(ctx)
+	if done, err := runServerBenchmark(ctx, b, sla); done || err != nil {
 		// Close all extra output channels so the benchmark will terminate
 		for _, out := range c.ExtraOut {
 			close(out)
@@ -124,6 +177,7 @@ func runBench(ctx *cli.Context, b bench.Benchmark) error {
 		fatalIf(probe.NewError(err), "Error running remote benchmark")
 		return nil
 	}
+	var slaRes *aggregate.SLAResult
 	var ui ui
 	if !globalQuiet && !globalJSON {
 		registerUI(&ui)
@@ -139,6 +193,39 @@ func runBench(ctx *cli.Context, b bench.Benchmark) error {
 	}, printError)
 	defer monitor.Done()
 
+	if ctx.Bool("bucket.create") {
+		cl, done := c.Client()
+		created, err := bench.EnsureBuckets(context.Background(), cl, c.BucketNames(), minio.MakeBucketOptions{Region: c.Location})
+		done()
+		if err != nil {
+			ui.Update(tea.Quit())
+			ui.Wait()
+			fatalIf(probe.NewError(err), "Unable to create buckets")
+			return nil
+		}
+		if len(created) > 0 {
+			monitor.InfoLn("Created buckets:", strings.Join(created, ", "))
+		}
+	}
+
+	if ctx.Bool("selftest") {
+		monitor.InfoLn("Running self-test")
+		res, err := runSelfTest(context.Background(), b)
+		if err == nil {
+			err = res.Err()
+		}
+		if err != nil {
+			ui.Update(tea.Quit())
+			ui.Wait()
+			if !globalQuiet && !globalJSON {
+				console.Print(res.String())
+			}
+			fatalIf(probe.NewError(err), "Self-test failed")
+			return nil
+		}
+		monitor.InfoLn("Self-test passed")
+	}
+
 	monitor.InfoLn("Preparing server")
 	c.Clear = !ctx.Bool("noclear")
 	if ctx.Bool("autoterm") {
@@ -189,11 +276,25 @@ func runBench(ctx *cli.Context, b bench.Benchmark) error {
 	if u := ui.updates.Load(); u != nil {
 		*u <- aggregate.UpdateReq{Reset: true}
 	}
+	if w := ctx.Duration("warmup"); w > 0 {
+		c.Collector = bench.NewWarmupCollector(c.Collector, tStart.Add(w))
+	}
 	benchDur := ctx.Duration("duration")
 	ui.StartBenchmark("Benchmarking", tStart, tStart.Add(benchDur), updates)
 	ctx2, cancel := context.WithDeadline(context.Background(), tStart.Add(benchDur))
 	defer cancel()
-	ui.cancelFn.Store(&cancel)
+	irq := newInterruptHandler(cancel, monitor.InfoLn)
+	stopIrq := irq.notify()
+	defer stopIrq()
+	uiCancel := context.CancelFunc(irq.interrupt)
+	ui.cancelFn.Store(&uiCancel)
+	budget := newByteBudget(ctx, cancel)
+	if budget != nil {
+		c.Collector = budget.Collector(c.Collector)
+	}
+	// Workers may still be running when results are printed after an interrupt.
+	drain := bench.NewDrainCollector(c.Collector)
+	c.Collector = drain
 	start := make(chan struct{})
 	go func() {
 		monitor.InfoLn("Pausing before benchmark")
@@ -211,9 +312,52 @@ func runBench(ctx *cli.Context, b bench.Benchmark) error {
 	prof, err := startProfiling(ctx2, ctx)
 	fatalIf(probe.NewError(err), "Unable to start profile.")
 	monitor.InfoLn("Starting benchmark in", time.Until(tStart).Round(time.Second))
-	b.Start(ctx2, start)
+	var resources *aggregate.ResourceSampler
+	if ctx.Bool("resources") {
+		interval := ctx.Duration("progress")
+		if interval <= 0 {
+			interval = time.Second
+		}
+		resources = aggregate.NewResourceSampler()
+		go func() {
+			<-start
+			resources.Run(ctx2, interval)
+		}()
+	}
+	startDone := make(chan struct{})
+	go func() {
+		defer close(startDone)
+		b.Start(ctx2, start)
+	}()
+	if !irq.wait(startDone, ctx.Duration("drain-timeout")) {
+		monitor.Errorln("Operations still running after --drain-timeout, printing results without them")
+	}
 	c.Collector.Close()
 	cancel()
+	if n := c.Retry.Retries(); n > 0 {
+		monitor.InfoLn("Retries:", n)
+	}
+	if s := clockSkew.Stats(); s.Count > 0 {
+		monitor.InfoLn("Server clock skew:", s)
+	}
+	if n := slowRequests.Count(); n > 0 {
+		monitor.InfoLn("Slow requests logged:", n)
+	}
+	if resources != nil {
+		monitor.InfoLn("Client resources:", resources.Stats())
+	}
+	if err := slowRequests.Close(); err != nil {
+		printError("Unable to write slow request log:", err)
+	}
+	if p := protocols.String(); p != "" {
+		monitor.InfoLn("Protocols:", p)
+	}
+	if s := dnsDialer.Stats(); s.Lookups > 0 || s.Cached > 0 {
+		monitor.InfoLn("DNS:", s)
+	}
+	if budget.Exhausted() {
+		monitor.InfoLn("Stopped after transferring", humanize.IBytes(uint64(budget.Used())))
+	}
 
 	ctx2 = context.Background()
 	prof.stop(ctx2, ctx, fileName+".profiles.zip")
@@ -243,6 +387,9 @@ func runBench(ctx *cli.Context, b bench.Benchmark) error {
 			}
 		}
 		monitor.OperationsReady(ops, fileName, commandLine(ctx))
+		if sla != nil {
+			slaRes = evaluateSLA(ctx, sla, ops)
+		}
 		var buf bytes.Buffer
 		printAnalysis(ctx, &buf, ops)
 		ui.Update(tea.Quit())
@@ -297,6 +444,23 @@ func runBench(ctx *cli.Context, b bench.Benchmark) error {
 		ui.Wait()
 		fmt.Println("")
 		fmt.Println(rep)
+		if hosts, counts := hostCounts(); len(hosts) > 0 && !globalJSON && !globalQuiet {
+			for _, host := range hosts {
+				console.Infof("Host %q: %d requests\n", host, counts[host])
+			}
+		}
+	}
+	// Cleanup must not run while operations are still running.
+	select {
+	case <-startDone:
+	default:
+		monitor.InfoLn("Waiting for running operations to finish...")
+		<-startDone
+	}
+	drain.Finish()
+	if tracked := trackedUploads(ctx); tracked != nil {
+		ui.SetPhase("Cleanup")
+		deleteTracked(ctx, c, tracked, monitor.InfoLn, monitor.Errorln)
 	}
 	if !ctx.Bool("keep-data") && !ctx.Bool("noclear") {
 		ui.SetPhase("Cleanup")
@@ -306,6 +470,49 @@ func runBench(ctx *cli.Context, b bench.Benchmark) error {
 	monitor.InfoLn("Cleanup Done.")
 	ui.Wait()
 	registerUI(nil)
+	if sla != nil {
+		if err := checkSLA(slaRes); err != nil {
+			fatal(errDummy(), err.Error())
+		}
+	}
+	return nil
+}
+
+// parseSLA returns the thresholds set with --sla, or nil if not set.
+func parseSLA(ctx *cli.Context) aggregate.SLA {
+	s := ctx.String("sla")
+	if s == "" {
+		return nil
+	}
+	if !ctx.Bool("full") {
+		fatal(errInvalidArgument(), "--sla requires --full")
+	}
+	sla, err := aggregate.ParseSLA(s)
+	fatalIf(probe.NewError(err), "Invalid --sla value")
+	return sla
+}
+
+// evaluateSLA returns the result of checking ops against sla.
+// nil is returned if there are no operations.
+func evaluateSLA(ctx *cli.Context, sla aggregate.SLA, ops bench.Operations) *aggregate.SLAResult {
+	if len(ops) == 0 {
+		return nil
+	}
+	res := sla.Evaluate(aggregate.ResultsFromOperationsOpts(ops, nil, aggregate.Options{ExpectedInterval: expectedInterval(ctx)}))
+	return &res
+}
+
+// checkSLA prints the violations in res and returns an error if the SLA was not met.
+func checkSLA(res *aggregate.SLAResult) error {
+	switch {
+	case res == nil:
+		return errors.New("unable to check SLA, no operations were recorded")
+	case !res.Passed():
+		for _, v := range res.Violations {
+			printError("SLA violation:", v)
+		}
+		return fmt.Errorf("SLA not met, %d violation(s)", len(res.Violations))
+	}
 	return nil
 }
 
@@ -328,6 +535,7 @@ type clientBenchmark struct {
 
 type stageInfo struct {
 	start          chan struct{}
+	startTime      time.Time
 	done           chan struct{}
 	custom         map[string]string
 	stageCtx       context.Context
@@ -439,6 +647,17 @@ func runClientBenchmark(ctx *cli.Context, b bench.Benchmark, cb *clientBenchmark
 
 	ctx2, cancel := benchStage.stageCtx, benchStage.cancelFn
 	defer cancel()
+	if budget := newByteBudget(ctx, cancel); budget != nil {
+		common.Collector = budget.Collector(common.Collector)
+	}
+	if w := ctx.Duration("warmup"); w > 0 {
+		// The stage start time is set by the server when all clients are prepared.
+		common.Collector = bench.NewWarmupCollectorFunc(common.Collector, func() time.Time {
+			cb.Lock()
+			defer cb.Unlock()
+			return cb.info[stageBenchmark].startTime.Add(w)
+		})
+	}
 
 	// Start after waiting a second or until we reached the start time.
 	benchDur := ctx.Duration("duration")
@@ -471,17 +690,20 @@ func runClientBenchmark(ctx *cli.Context, b bench.Benchmark, cb *clientBenchmark
 	}
 
 	err = b.Start(ctx2, start)
+	// Forward the last operations before retrieving them.
+	common.Collector.Close()
 	ops := retrieveOps()
 	cb.Lock()
 	cb.results = ops
 	cb.Unlock()
 	cb.stageDone(stageBenchmark, err, common.Custom)
 	if err != nil {
+		// Objects uploaded before the error are still removed.
+		deleteTracked(ctx, common, trackedUploads(ctx), console.Infoln, console.Errorln)
 		return err
 	}
 	ops.SetClientID(cID)
 	ops.SortByStartTime()
-	common.Collector.Close()
 
 	if len(ops) > 0 {
 		f, err := os.Create(fileName + ".csv.zst")
@@ -535,6 +757,7 @@ func runClientBenchmark(ctx *cli.Context, b bench.Benchmark, cb *clientBenchmark
 	if err != nil {
 		return err
 	}
+	deleteTracked(ctx, common, trackedUploads(ctx), console.Infoln, console.Errorln)
 	if !ctx.Bool("keep-data") && !ctx.Bool("noclear") {
 		console.Infoln("Starting cleanup...")
 		b.Cleanup(cb.info[stageCleanup].stageCtx)
@@ -613,6 +836,37 @@ func (rp *runningProfiles) stop(ctx2 context.Context, _ *cli.Context, fileName s
 	console.Infof("Profile data successfully downloaded as %s\n", fileName)
 }
 
+// newByteBudget returns the budget set by --max-bytes, or nil if not set.
+// cancel is called when the budget is used up.
+func newByteBudget(ctx *cli.Context, cancel func()) *bench.ByteBudget {
+	s := ctx.String("max-bytes")
+	if s == "" {
+		return nil
+	}
+	n, err := toSize(s)
+	fatalIf(probe.NewError(err), "Invalid --max-bytes value")
+	return bench.NewByteBudget(int64(n), cancel)
+}
+
+// deleteTracked deletes the objects in tracked.
+// Objects that cannot be deleted are reported, but do not stop the cleanup.
+func deleteTracked(ctx *cli.Context, c *bench.Common, tracked *bench.UploadTracker, infoln, errorln func(data ...any)) {
+	if tracked == nil {
+		return
+	}
+	infoln("Deleting", tracked.Len(), "objects created by the benchmark...")
+	cl, done := c.Client()
+	defer done()
+	res := tracked.DeleteAll(context.Background(), cl, ctx.Int("concurrent"), 1000)
+	infoln("Deleted", res.Deleted, "objects.")
+	if len(res.Failed) > 0 {
+		errorln("Unable to delete", len(res.Failed), "objects. First error:", res.Failed[0].Err)
+	}
+	if len(res.Compliance) > 0 {
+		errorln("Not deleting", len(res.Compliance), "objects with COMPLIANCE retention, for example", res.Compliance[0].Name)
+	}
+}
+
 func checkBenchmark(ctx *cli.Context) {
 	profilerTypes := []madmin.ProfilerType{
 		madmin.ProfilerCPU,
@@ -627,6 +881,15 @@ func checkBenchmark(ctx *cli.Context) {
 	_, err := parseInfluxURL(ctx)
 	fatalIf(probe.NewError(err), "invalid influx config")
 
+	if ctx.Bool("ktls") && (ctx.String("dns") != dnsSystem || ctx.String("dns.pin") != "") {
+		fatalIf(errDummy(), "--dns and --dns.pin cannot be used with --ktls")
+	}
+	switch v := ctx.String("http-version"); v {
+	case "", "1.1", "2", "h2c":
+	default:
+		fatalIf(errDummy(), "Unknown --http-version %q. Possible values are: 1.1, 2, h2c.", v)
+	}
+
 	profs := strings.SplitSeq(ctx.String("serverprof"), ",")
 	for profilerType := range profs {
 		if len(profilerType) == 0 {
@@ -650,6 +913,14 @@ func checkBenchmark(ctx *cli.Context) {
 			fatalIf(errDummy(), "syncstart is in the past: %v", t)
 		}
 	}
+	if s := ctx.String("max-bytes"); s != "" {
+		if n, err := toSize(s); err != nil || n == 0 {
+			fatalIf(errDummy(), "max-bytes must be a size > 0, for example 500GiB")
+		}
+	}
+	if w := ctx.Duration("warmup"); w < 0 || (w > 0 && w >= ctx.Duration("duration")) {
+		fatalIf(errDummy(), "warmup must be less than duration")
+	}
 	if ctx.Bool("autoterm") {
 		// TODO: autoterm cannot be used when in client/server mode
 		if ctx.Duration("autoterm.dur") <= 0 {
@@ -95,7 +95,8 @@ type serverRequest struct {
 
 // runServerBenchmark will run a benchmark server if requested.
 // Returns a bool whether clients were specified.
-func runServerBenchmark(ctx *cli.Context, b bench.Benchmark) (bool, error) {
+// If sla is set it is checked against the operations of all clients.
+func runServerBenchmark(ctx *cli.Context, b bench.Benchmark, sla aggregate.SLA) (bool, error) {
 	if ctx.String("warp-client") == "" {
 		return false, nil
 	}
@@ -126,6 +127,7 @@ func runServerBenchmark(ctx *cli.Context, b bench.Benchmark) (bool, error) {
 	errorLn := printError
 
 	var allOps bench.Operations
+	var slaRes *aggregate.SLAResult
 
 	// Serialize parameters
 	excludeFlags := map[string]struct{}{
@@ -286,6 +288,9 @@ func runServerBenchmark(ctx *cli.Context, b bench.Benchmark) (bool, error) {
 			}
 		}
 		monitor.OperationsReady(allOps, fileName, commandLine(ctx))
+		if sla != nil {
+			slaRes = evaluateSLA(ctx, sla, allOps)
+		}
 		ui.Update(tea.Quit())
 		ui.Wait()
 		printAnalysis(ctx, os.Stdout, allOps)
@@ -342,6 +347,11 @@ func runServerBenchmark(ctx *cli.Context, b bench.Benchmark) (bool, error) {
 		}
 		infoLn("Cleanup done.\n")
 	}
+	if sla != nil {
+		if err := checkSLA(slaRes); err != nil {
+			fatal(errDummy(), err.Error())
+		}
+	}
 
 	return true, nil
 }
@@ -93,6 +93,10 @@ func init() {
 		deleteCmd,
 		listCmd,
 		statCmd,
+		selectCmd,
+		copyCmd,
+		presignedCmd,
+		lifecycleCmd,
 		versionedCmd,
 		retentionCmd,
 		multipartCmd,
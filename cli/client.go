@@ -41,15 +41,44 @@ import (
 	"github.com/minio/pkg/v3/console"
 	"github.com/minio/pkg/v3/ellipses"
 	"github.com/minio/warp/pkg"
+	"github.com/minio/warp/pkg/bench"
 )
 
 type hostSelectType string
 
 const (
 	hostSelectTypeRoundrobin hostSelectType = "roundrobin"
+	hostSelectTypeRandom     hostSelectType = "random"
 	hostSelectTypeWeighed    hostSelectType = "weighed"
 )
 
+// hostSelectors are the selectors created by newClient,
+// so the number of requests sent to each host can be printed.
+var hostSelectors struct {
+	mu    sync.Mutex
+	hosts [][]string
+	sels  []*bench.EndpointSelector
+}
+
+// hostCounts returns the number of requests sent to each host
+// by the roundrobin and random host selections.
+// The returned hosts are in the order they were specified.
+func hostCounts() (hosts []string, counts map[string]int64) {
+	hostSelectors.mu.Lock()
+	defer hostSelectors.mu.Unlock()
+	counts = make(map[string]int64)
+	for i, sel := range hostSelectors.sels {
+		for j, n := range sel.Counts() {
+			host := hostSelectors.hosts[i][j]
+			if _, ok := counts[host]; !ok {
+				hosts = append(hosts, host)
+			}
+			counts[host] += n
+		}
+	}
+	return hosts, counts
+}
+
 func newClient(ctx *cli.Context) func() (cl *minio.Client, done func()) {
 	hosts := parseHosts(ctx.String("host"), ctx.Bool("resolve-host"))
 	switch len(hosts) {
@@ -65,22 +94,27 @@ func newClient(ctx *cli.Context) func() (cl *minio.Client, done func()) {
 	}
 	hostSelect := hostSelectType(ctx.String("host-select"))
 	switch hostSelect {
-	case hostSelectTypeRoundrobin:
-		// Do round-robin.
-		var current int
-		var mu sync.Mutex
+	case hostSelectTypeRoundrobin, hostSelectTypeRandom:
 		clients := make([]*minio.Client, len(hosts))
 		for i := range hosts {
 			cl, err := getClient(ctx, hosts[i])
 			fatalIf(probe.NewError(err), "Unable to create MinIO client")
 			clients[i] = cl
 		}
+		sel := bench.NewRoundRobinSelector(len(clients))
+		if hostSelect == hostSelectTypeRandom {
+			seed := rand.Int63()
+			if seeds := seedSource(ctx); seeds != nil {
+				seed = seeds.Seed("host-select")
+			}
+			sel = bench.NewRandomSelector(len(clients), seed)
+		}
+		hostSelectors.mu.Lock()
+		hostSelectors.hosts = append(hostSelectors.hosts, hosts)
+		hostSelectors.sels = append(hostSelectors.sels, sel)
+		hostSelectors.mu.Unlock()
 		return func() (*minio.Client, func()) {
-			mu.Lock()
-			now := current % len(clients)
-			current++
-			mu.Unlock()
-			return clients[now], func() {}
+			return clients[sel.Next()], func() {}
 		}
 	case hostSelectTypeWeighed:
 		// Keep track of handed out clients.
@@ -194,14 +228,25 @@ func getClient(ctx *cli.Context, host string) (*minio.Client, error) {
 }
 
 func clientTransport(ctx *cli.Context) http.RoundTripper {
+	var tr http.RoundTripper
 	switch {
 	case ctx.Bool("ktls"):
-		return clientTransportKTLS(ctx)
+		tr = clientTransportKTLS(ctx)
 	case ctx.Bool("tls"):
-		return clientTransportTLS(ctx)
+		tr = clientTransportTLS(ctx)
 	default:
-		return clientTransportDefault(ctx)
+		tr = clientTransportDefault(ctx)
+	}
+	if ctx.Bool("expect-continue") {
+		tr = expectContinueTransport{RoundTripper: tr}
+	}
+	if ctx.Bool("clock-skew") {
+		tr = clockSkewTransport{RoundTripper: tr, skew: clockSkew}
 	}
+	tr = trackedUploads(ctx).Transport(tr)
+	tr = slowRequestLog(ctx).Transport(tr)
+	tr = protocolTransport{RoundTripper: tr, protocols: &protocols}
+	return tr
 }
 
 // parseHosts will parse the host parameter given.
@@ -23,19 +23,24 @@ import (
 	"time"
 
 	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
 	"gitlab.com/go-extension/http"
 	"gitlab.com/go-extension/tls"
 )
 
 func clientTransportKTLS(ctx *cli.Context) stdHttp.RoundTripper {
+	opts := tlsOptionsFromContext(ctx)
+	rootCAs, err := opts.rootCAs()
+	fatalIf(probe.NewError(err), "Unable to configure TLS")
+
 	// Keep TLS config.
 	tlsConfig := &tls.Config{
-		RootCAs: mustGetSystemCertPool(),
+		RootCAs: rootCAs,
 		// Can't use SSLv3 because of POODLE and BEAST
 		// Can't use TLSv1.0 because of POODLE and BEAST using CBC cipher
 		// Can't use TLSv1.1 because of RC4 cipher usage
 		MinVersion:         tls.VersionTLS12,
-		InsecureSkipVerify: ctx.Bool("insecure"),
+		InsecureSkipVerify: opts.insecure,
 		ClientSessionCache: tls.NewLRUClientSessionCache(1024), // up to 1024 nodes
 
 		// Extra configs
@@ -48,13 +53,21 @@ func clientTransportKTLS(ctx *cli.Context) stdHttp.RoundTripper {
 		CertificateCompressionDisabled: true,
 	}
 
+	hasCert, err := opts.hasClientCert()
+	fatalIf(probe.NewError(err), "Unable to configure TLS")
+	if hasCert {
+		cert, err := tls.LoadX509KeyPair(opts.certFile, opts.keyFile)
+		fatalIf(probe.NewError(err), "Unable to load TLS client certificate")
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
 	if ctx.Bool("debug") {
 		tlsConfig.KeyLogWriter = os.Stdout
 	}
 
 	// If we don't enable http/2, then using a custom DialTLSConext is the best choice.
 	// It can improve performance by not using a compatibility layer.
-	if !ctx.Bool("http2") {
+	if httpVersion(ctx) != "2" {
 		dialer := &tls.Dialer{NetDialer: netDialer, Config: tlsConfig}
 		return newClientTransport(ctx, withDialTLSContext(dialer.DialContext))
 	}
@@ -23,19 +23,14 @@ import (
 	"os"
 
 	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
 )
 
 func clientTransportTLS(ctx *cli.Context) http.RoundTripper {
 	// Keep TLS config.
-	tlsConfig := &tls.Config{
-		RootCAs: mustGetSystemCertPool(),
-		// Can't use SSLv3 because of POODLE and BEAST
-		// Can't use TLSv1.0 because of POODLE and BEAST using CBC cipher
-		// Can't use TLSv1.1 because of RC4 cipher usage
-		MinVersion:         tls.VersionTLS12,
-		InsecureSkipVerify: ctx.Bool("insecure"),
-		ClientSessionCache: tls.NewLRUClientSessionCache(1024), // up to 1024 nodes
-	}
+	tlsConfig, err := tlsOptionsFromContext(ctx).config()
+	fatalIf(probe.NewError(err), "Unable to configure TLS")
+	tlsConfig.ClientSessionCache = tls.NewLRUClientSessionCache(1024) // up to 1024 nodes
 
 	if ctx.Bool("debug") {
 		tlsConfig.KeyLogWriter = os.Stdout
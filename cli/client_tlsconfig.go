@@ -0,0 +1,95 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cli
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/minio/cli"
+)
+
+// tlsOptions contains the TLS settings shared by the TLS and kTLS transports.
+type tlsOptions struct {
+	caFile   string
+	certFile string
+	keyFile  string
+	insecure bool
+}
+
+func tlsOptionsFromContext(ctx *cli.Context) tlsOptions {
+	return tlsOptions{
+		caFile:   ctx.String("tls.ca"),
+		certFile: ctx.String("tls.cert"),
+		keyFile:  ctx.String("tls.key"),
+		insecure: ctx.Bool("insecure"),
+	}
+}
+
+// rootCAs returns the system CAs with the certificates in caFile added.
+func (o tlsOptions) rootCAs() (*x509.CertPool, error) {
+	pool := mustGetSystemCertPool()
+	if o.caFile == "" {
+		return pool, nil
+	}
+	pem, err := os.ReadFile(o.caFile)
+	if err != nil {
+		return nil, err
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %s", o.caFile)
+	}
+	return pool, nil
+}
+
+// hasClientCert returns whether a client certificate is configured.
+func (o tlsOptions) hasClientCert() (bool, error) {
+	if (o.certFile == "") != (o.keyFile == "") {
+		return false, errors.New("--tls.cert and --tls.key must be specified together")
+	}
+	return o.certFile != "", nil
+}
+
+// config returns the crypto/tls configuration.
+func (o tlsOptions) config() (*tls.Config, error) {
+	rootCAs, err := o.rootCAs()
+	if err != nil {
+		return nil, err
+	}
+	cfg := &tls.Config{
+		RootCAs: rootCAs,
+		// Can't use SSLv3 because of POODLE and BEAST
+		// Can't use TLSv1.0 because of POODLE and BEAST using CBC cipher
+		// Can't use TLSv1.1 because of RC4 cipher usage
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: o.insecure,
+	}
+	if ok, err := o.hasClientCert(); err != nil {
+		return nil, err
+	} else if ok {
+		cert, err := tls.LoadX509KeyPair(o.certFile, o.keyFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
@@ -0,0 +1,137 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cli
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeTestCert creates a certificate signed by parent, or self-signed if parent is nil,
+// and writes it and its key as PEM files to dir.
+func writeTestCert(t *testing.T, dir, name string, tmpl *x509.Certificate, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parent == nil {
+		parent, parentKey = tmpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	write := func(file, typ string, b []byte) {
+		if err := os.WriteFile(filepath.Join(dir, file), pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: b}), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(name+".crt", "CERTIFICATE", der)
+	write(name+".key", "EC PRIVATE KEY", keyDER)
+	return cert, key
+}
+
+func TestTLSOptionsConfig(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	ca, caKey := writeTestCert(t, dir, "ca", &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "warp test CA"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}, nil, nil)
+	server, _ := writeTestCert(t, dir, "server", &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "s3.internal"},
+		DNSNames:     []string{"s3.internal"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, ca, caKey)
+	client, _ := writeTestCert(t, dir, "client", &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "warp"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca, caKey)
+	verify := func(o tlsOptions) error {
+		cfg, err := o.config()
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = server.Verify(x509.VerifyOptions{DNSName: "s3.internal", Roots: cfg.RootCAs})
+		return err
+	}
+
+	// Without the CA the server is not trusted.
+	if err := verify(tlsOptions{}); err == nil {
+		t.Error("server certificate trusted without custom CA")
+	}
+	if err := verify(tlsOptions{caFile: filepath.Join(dir, "ca.crt")}); err != nil {
+		t.Errorf("server certificate not trusted with custom CA: %v", err)
+	}
+
+	cfg, err := tlsOptions{
+		certFile: filepath.Join(dir, "client.crt"),
+		keyFile:  filepath.Join(dir, "client.key"),
+		insecure: true,
+	}.config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Certificates) != 1 || !cfg.Certificates[0].Leaf.Equal(client) {
+		t.Errorf("client certificate not loaded: %+v", cfg.Certificates)
+	}
+	if !cfg.InsecureSkipVerify {
+		t.Error("InsecureSkipVerify not set")
+	}
+
+	for name, o := range map[string]tlsOptions{
+		"missing CA":     {caFile: filepath.Join(dir, "missing.crt")},
+		"CA without PEM": {caFile: filepath.Join(dir, "ca.key")},
+		"cert only":      {certFile: filepath.Join(dir, "client.crt")},
+		"key only":       {keyFile: filepath.Join(dir, "client.key")},
+		"mismatched key": {certFile: filepath.Join(dir, "client.crt"), keyFile: filepath.Join(dir, "server.key")},
+	} {
+		if _, err := o.config(); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
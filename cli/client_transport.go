@@ -20,11 +20,18 @@ package cli
 import (
 	"context"
 	"crypto/tls"
+	"fmt"
+	"maps"
 	"net"
 	"net/http"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/warp/pkg/bench"
 )
 
 var netDialer = &net.Dialer{
@@ -32,6 +39,69 @@ var netDialer = &net.Dialer{
 	KeepAlive: 10 * time.Second,
 }
 
+// Values of --dns.
+const (
+	dnsSystem   = "system"
+	dnsUncached = "uncached"
+	dnsCached   = "cached"
+)
+
+var (
+	dnsDialerOnce sync.Once
+	// dnsDialer resolves host names when --dns or --dns.pin is set.
+	// It is shared by all clients, so the cache is too.
+	dnsDialer *bench.DNSDialer
+)
+
+// dialContext returns the function used to dial connections.
+func dialContext(ctx *cli.Context) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	dnsDialerOnce.Do(func() {
+		dnsDialer = newDNSDialer(ctx)
+	})
+	if dnsDialer == nil {
+		return netDialer.DialContext
+	}
+	return dnsDialer.DialContext
+}
+
+// newDNSDialer returns the dialer configured by --dns and --dns.pin, or nil to use the system resolver.
+func newDNSDialer(ctx *cli.Context) *bench.DNSDialer {
+	pin, err := parseDNSPin(ctx.String("dns.pin"))
+	fatalIf(probe.NewError(err), "Invalid --dns.pin value")
+	o := bench.DNSOptions{Pin: pin}
+	switch ctx.String("dns") {
+	case dnsSystem, "":
+		if len(pin) == 0 {
+			return nil
+		}
+	case dnsUncached:
+	case dnsCached:
+		o.TTL = ctx.Duration("dns.ttl")
+	default:
+		fatalIf(errDummy(), "Unknown --dns value %q", ctx.String("dns"))
+	}
+	return bench.NewDNSDialer(netDialer.DialContext, o)
+}
+
+// parseDNSPin parses comma separated host=ip pairs.
+func parseDNSPin(s string) (map[string]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+	pin := make(map[string]string)
+	for kv := range strings.SplitSeq(s, ",") {
+		host, ip, ok := strings.Cut(strings.TrimSpace(kv), "=")
+		if !ok || host == "" {
+			return nil, fmt.Errorf("expected host=ip, got %q", kv)
+		}
+		if net.ParseIP(ip) == nil {
+			return nil, fmt.Errorf("invalid IP %q for host %s", ip, host)
+		}
+		pin[host] = ip
+	}
+	return pin, nil
+}
+
 type transportOption func(transport *http.Transport)
 
 func withTLSConfig(tlsConfig *tls.Config) transportOption {
@@ -46,11 +116,187 @@ func withDialTLSContext(dialer func(ctx context.Context, network, addr string) (
 	}
 }
 
+// httpVersion returns the HTTP version selected with --http-version,
+// "2" if only the older --http2 is set, or "1.1".
+func httpVersion(ctx *cli.Context) string {
+	if v := ctx.String("http-version"); v != "" {
+		return v
+	}
+	if ctx.Bool("http2") {
+		return "2"
+	}
+	return "1.1"
+}
+
+// withHTTPVersion sets the protocols the transport may use.
+// "1.1" only allows HTTP/1.1.
+// "2" negotiates HTTP/2 on TLS connections, falling back to HTTP/1.1
+// if the server does not support it. Plain connections use HTTP/1.1.
+// "h2c" uses unencrypted HTTP/2 with prior knowledge on plain connections.
+func withHTTPVersion(version string) transportOption {
+	return func(transport *http.Transport) {
+		var p http.Protocols
+		switch version {
+		case "2":
+			p.SetHTTP1(true)
+			p.SetHTTP2(true)
+		case "h2c":
+			p.SetHTTP2(true)
+			p.SetUnencryptedHTTP2(true)
+		default:
+			p.SetHTTP1(true)
+		}
+		transport.Protocols = &p
+	}
+}
+
+// protocols counts the protocols of responses seen by protocolTransport.
+var protocols protocolCounter
+
+// protocolCounter counts responses by protocol.
+// It is safe for concurrent use.
+type protocolCounter struct {
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+// add counts a response with protocol proto.
+func (p *protocolCounter) add(proto string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.counts == nil {
+		p.counts = make(map[string]int64)
+	}
+	p.counts[proto]++
+}
+
+// String returns the protocols and their counts, for example "HTTP/2.0 (1000 responses)".
+func (p *protocolCounter) String() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	res := make([]string, 0, len(p.counts))
+	for _, proto := range slices.Sorted(maps.Keys(p.counts)) {
+		res = append(res, fmt.Sprintf("%s (%d responses)", proto, p.counts[proto]))
+	}
+	return strings.Join(res, ", ")
+}
+
+// protocolTransport counts the protocol of every response.
+type protocolTransport struct {
+	http.RoundTripper
+	protocols *protocolCounter
+}
+
+// RoundTrip sends req and counts the protocol of the response.
+func (t protocolTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.RoundTripper.RoundTrip(req)
+	if err == nil {
+		t.protocols.add(resp.Proto)
+	}
+	return resp, err
+}
+
+// expectContinueTransport adds "Expect: 100-continue" to PUT requests with a body.
+// The transport waits up to its ExpectContinueTimeout for the server to accept
+// the request headers before sending the body.
+type expectContinueTransport struct {
+	http.RoundTripper
+}
+
+// RoundTrip sends req with the Expect header.
+func (t expectContinueTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodPut && req.ContentLength != 0 && req.Header.Get("Expect") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("Expect", "100-continue")
+	}
+	return t.RoundTripper.RoundTrip(req)
+}
+
+// clockSkew collects the clock skew observed by clockSkewTransport.
+var clockSkew = bench.NewClockSkew(1)
+
+// clockSkewTransport records the skew between the local clock and
+// the Date header of every response.
+type clockSkewTransport struct {
+	http.RoundTripper
+	skew *bench.ClockSkew
+}
+
+// RoundTrip sends req and records the skew reported by the response.
+func (t clockSkewTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	sent := time.Now()
+	resp, err := t.RoundTripper.RoundTrip(req)
+	if err == nil {
+		if skew, ok := bench.ServerClockSkew(resp.Header.Get("Date"), sent, time.Now()); ok {
+			t.skew.Add(skew)
+		}
+	}
+	return resp, err
+}
+
+var (
+	slowRequestsOnce sync.Once
+	// slowRequests logs the slow requests of all clients, if set by --slow-log.
+	slowRequests *bench.SlowRequestLog
+)
+
+// slowRequestLog returns the log of slow requests, creating it on first use.
+// It returns nil if --slow-log is not set.
+func slowRequestLog(ctx *cli.Context) *bench.SlowRequestLog {
+	slowRequestsOnce.Do(func() {
+		path := ctx.String("slow-log")
+		if path == "" {
+			return
+		}
+		l, err := bench.CreateSlowRequestLog(path, ctx.Duration("slow-log.threshold"))
+		fatalIf(probe.NewError(err), "Unable to create slow request log")
+		slowRequests = l
+	})
+	return slowRequests
+}
+
+var (
+	trackedUploadsOnce sync.Once
+	// uploads tracks the objects created by all clients, if set by --cleanup.tracked.
+	uploads *bench.UploadTracker
+)
+
+// trackedUploads returns the tracker of created objects, creating it on first use.
+// It returns nil if --cleanup.tracked is not set.
+func trackedUploads(ctx *cli.Context) *bench.UploadTracker {
+	trackedUploadsOnce.Do(func() {
+		if !ctx.Bool("cleanup.tracked") {
+			return
+		}
+		buckets := []string{ctx.String("bucket")}
+		if s := newBucketSelector(ctx); s != nil {
+			buckets = append(buckets, s.Buckets()...)
+		}
+		if dst := ctx.String("dest.bucket"); dst != "" {
+			buckets = append(buckets, dst)
+		}
+		uploads = bench.NewUploadTracker(buckets)
+	})
+	return uploads
+}
+
+// maxIdleConnsPerHost returns the number of idle connections each transport keeps per host.
+func maxIdleConnsPerHost(ctx *cli.Context) int {
+	switch {
+	case ctx.Int("max-idle-conns") > 0:
+		return ctx.Int("max-idle-conns")
+	case ctx.Bool("isolate-conns"):
+		// Every worker has a single request in flight.
+		return 1
+	}
+	return ctx.Int("concurrent")
+}
+
 func newClientTransport(ctx *cli.Context, options ...transportOption) http.RoundTripper {
 	tr := &http.Transport{
 		Proxy:                 http.ProxyFromEnvironment,
-		DialContext:           netDialer.DialContext,
-		MaxIdleConnsPerHost:   ctx.Int("concurrent"),
+		DialContext:           dialContext(ctx),
+		MaxIdleConnsPerHost:   maxIdleConnsPerHost(ctx),
 		WriteBufferSize:       ctx.Int("sndbuf"), // Configure beyond 4KiB default buffer size.
 		ReadBufferSize:        ctx.Int("rcvbuf"), // Configure beyond 4KiB default buffer size.
 		IdleConnTimeout:       90 * time.Second,
@@ -65,10 +311,8 @@ func newClientTransport(ctx *cli.Context, options ...transportOption) http.Round
 		//    https://golang.org/src/net/http/transport.go?h=roundTrip#L1843
 		DisableCompression: true,
 		DisableKeepAlives:  ctx.Bool("disable-http-keepalive"),
-		// Because we create a custom TLSClientConfig, we have to opt-in to HTTP/2.
-		// See https://github.com/golang/go/issues/14275
-		ForceAttemptHTTP2: ctx.Bool("http2"),
 	}
+	withHTTPVersion(httpVersion(ctx))(tr)
 
 	for _, option := range options {
 		option(tr)
@@ -0,0 +1,223 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/tls"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/warp/pkg/bench"
+)
+
+func TestExpectContinueTransport(t *testing.T) {
+	var mu sync.Mutex
+	var expect []string
+	var bodies [][]byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		expect = append(expect, r.Header.Get("Expect"))
+		bodies = append(bodies, b)
+		mu.Unlock()
+		sum := md5.Sum(b)
+		w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+
+	content := bytes.Repeat([]byte("expect"), 10000)
+	for _, enabled := range []bool{false, true} {
+		expect, bodies = nil, nil
+		base := http.DefaultTransport.(*http.Transport).Clone()
+		base.ExpectContinueTimeout = 10 * time.Second
+		var tr http.RoundTripper = base
+		if enabled {
+			tr = expectContinueTransport{RoundTripper: base}
+		}
+		cl, err := minio.New(u.Host, &minio.Options{
+			Creds:        credentials.NewStaticV4("access", "secret", ""),
+			Region:       "us-east-1",
+			BucketLookup: minio.BucketLookupPath,
+			Transport:    tr,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx := context.Background()
+		_, err = cl.PutObject(ctx, "bucket", "obj", bytes.NewReader(content), int64(len(content)), minio.PutObjectOptions{DisableMultipart: true, DisableContentSha256: true})
+		if err != nil {
+			t.Fatalf("enabled=%v: %v", enabled, err)
+		}
+		// Requests without a body are not changed.
+		if _, err := cl.StatObject(ctx, "bucket", "obj", minio.StatObjectOptions{}); err != nil {
+			t.Fatalf("enabled=%v: %v", enabled, err)
+		}
+
+		mu.Lock()
+		if len(expect) != 2 {
+			t.Fatalf("enabled=%v: got %d requests, want 2", enabled, len(expect))
+		}
+		want := ""
+		if enabled {
+			want = "100-continue"
+		}
+		if expect[0] != want {
+			t.Errorf("enabled=%v: PUT Expect header %q, want %q", enabled, expect[0], want)
+		}
+		if expect[1] != "" {
+			t.Errorf("enabled=%v: HEAD Expect header %q, want none", enabled, expect[1])
+		}
+		if !bytes.Equal(bodies[0], content) {
+			t.Errorf("enabled=%v: uploaded %d bytes, want %d", enabled, len(bodies[0]), len(content))
+		}
+		mu.Unlock()
+	}
+}
+
+// fixedDateTransport responds to every request with the next Date header.
+type fixedDateTransport struct {
+	dates []string
+}
+
+func (f *fixedDateTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp := &http.Response{StatusCode: http.StatusOK, Header: make(http.Header), Body: http.NoBody, Request: req}
+	if len(f.dates) > 0 {
+		if f.dates[0] != "" {
+			resp.Header.Set("Date", f.dates[0])
+		}
+		f.dates = f.dates[1:]
+	}
+	return resp, nil
+}
+
+func TestClockSkewTransport(t *testing.T) {
+	now := time.Now().UTC()
+	skew := bench.NewClockSkew(1)
+	tr := clockSkewTransport{RoundTripper: &fixedDateTransport{dates: []string{
+		now.Add(-10 * time.Second).Format(http.TimeFormat),
+		now.Format(http.TimeFormat),
+		"",
+		"not a date",
+		now.Add(time.Minute).Format(http.TimeFormat),
+	}}, skew: skew}
+	for range 5 {
+		req, _ := http.NewRequest(http.MethodGet, "http://example.com/bucket/obj", nil)
+		resp, err := tr.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	s := skew.Stats()
+	if s.Count != 3 {
+		t.Fatalf("got %d observations, want 3", s.Count)
+	}
+	// Dates are truncated to seconds, so observed skew is within a second of the offset.
+	near := func(got, want time.Duration) bool {
+		return got > want-time.Second && got < want+time.Second
+	}
+	if !near(s.Min, -10*time.Second) || !near(s.Median, 0) || !near(s.Max, time.Minute) {
+		t.Errorf("unexpected skew %v", s)
+	}
+}
+
+func TestWithHTTPVersion(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Proto", r.Proto)
+	})
+	tlsSrv := httptest.NewUnstartedServer(handler)
+	tlsSrv.EnableHTTP2 = true
+	tlsSrv.StartTLS()
+	defer tlsSrv.Close()
+
+	h2cSrv := httptest.NewUnstartedServer(handler)
+	h2cSrv.Config.Protocols = new(http.Protocols)
+	h2cSrv.Config.Protocols.SetHTTP1(true)
+	h2cSrv.Config.Protocols.SetUnencryptedHTTP2(true)
+	h2cSrv.Start()
+	defer h2cSrv.Close()
+
+	tests := []struct {
+		version string
+		url     string
+		want    string
+	}{
+		{version: "1.1", url: tlsSrv.URL, want: "HTTP/1.1"},
+		{version: "2", url: tlsSrv.URL, want: "HTTP/2.0"},
+		{version: "2", url: h2cSrv.URL, want: "HTTP/1.1"},
+		{version: "h2c", url: h2cSrv.URL, want: "HTTP/2.0"},
+	}
+	for _, test := range tests {
+		t.Run(test.version+"-"+test.url, func(t *testing.T) {
+			tr := &http.Transport{TLSClientConfig: &tls.Config{
+				RootCAs: tlsSrv.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs,
+			}}
+			withHTTPVersion(test.version)(tr)
+			defer tr.CloseIdleConnections()
+			var counts protocolCounter
+			client := http.Client{Transport: protocolTransport{RoundTripper: tr, protocols: &counts}}
+			resp, err := client.Get(test.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.Proto != test.want {
+				t.Errorf("client got %s, want %s", resp.Proto, test.want)
+			}
+			if got := resp.Header.Get("X-Proto"); got != test.want {
+				t.Errorf("server got %s, want %s", got, test.want)
+			}
+			if got, want := counts.String(), test.want+" (1 responses)"; got != want {
+				t.Errorf("counts: got %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestParseDNSPin(t *testing.T) {
+	pin, err := parseDNSPin("s3.example.com=10.0.0.1, s3b.example.com=::1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pin) != 2 || pin["s3.example.com"] != "10.0.0.1" || pin["s3b.example.com"] != "::1" {
+		t.Errorf("got %v", pin)
+	}
+	for _, bad := range []string{"s3.example.com", "=10.0.0.1", "s3.example.com=notanip"} {
+		if _, err := parseDNSPin(bad); err == nil {
+			t.Errorf("%q: want error", bad)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"os"
+
+	"github.com/minio/cli"
+	"github.com/minio/warp/pkg/bench"
+)
+
+// runDryRun prints the operations b would send, without contacting the server.
+func runDryRun(ctx *cli.Context, b bench.Benchmark) error {
+	c := b.GetCommon()
+	o := bench.DryRunOptions{
+		Bucket: c.Bucket,
+		Ops:    ctx.Int("dry-run.ops"),
+		Source: c.Source,
+	}
+	if seeds := seedSource(ctx); seeds != nil {
+		o.Seed = seeds.Seed("dryrun")
+	} else {
+		o.Seed = rand.Int63()
+	}
+	switch b := b.(type) {
+	case *bench.Put:
+		o.OpType = http.MethodPut
+	case *bench.Get:
+		if b.ListExisting {
+			return errors.New("--dry-run cannot be used with --list-existing")
+		}
+		o.OpType, o.Prepare = http.MethodGet, b.CreateObjects
+	case *bench.Stat:
+		if b.ListExisting {
+			return errors.New("--dry-run cannot be used with --list-existing")
+		}
+		o.OpType, o.Prepare = "STAT", b.CreateObjects
+	case *bench.Delete:
+		if b.ListExisting {
+			return errors.New("--dry-run cannot be used with --list-existing")
+		}
+		o.OpType, o.Prepare = http.MethodDelete, b.CreateObjects
+	case *bench.Mixed:
+		o.Dist, o.Prepare, o.Seed = b.Dist, b.CreateObjects, b.Dist.Seed
+	default:
+		return errors.New("--dry-run is not supported by this benchmark")
+	}
+	plan, err := bench.DryRun(context.Background(), o)
+	if err != nil {
+		return err
+	}
+	plan.WriteSummary(os.Stdout, c.RpsLimiter.Limit())
+	return nil
+}
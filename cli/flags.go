@@ -18,18 +18,21 @@
 package cli
 
 import (
+	"context"
 	"fmt"
+	"math/rand"
 	"os"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/minio/cli"
 	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
 	"github.com/minio/pkg/v3/console"
+	"github.com/minio/warp/pkg/aggregate"
 	"github.com/minio/warp/pkg/bench"
 	"github.com/minio/warp/pkg/generator"
-
-	"golang.org/x/time/rate"
 )
 
 // Collection of warp flags currently supported
@@ -54,7 +57,7 @@ var globalFlags = []cli.Flag{
 	},
 	cli.BoolFlag{
 		Name:  "insecure",
-		Usage: "disable TLS certificate verification",
+		Usage: "disable TLS certificate verification (INSECURE)",
 	},
 	cli.BoolFlag{
 		Name:  "autocompletion",
@@ -181,6 +184,21 @@ var ioFlags = []cli.Flag{
 		Usage:  "Use Kernel TLS (HTTPS) for transport if available",
 		EnvVar: appNameUC + "_KTLS",
 	},
+	cli.StringFlag{
+		Name:   "tls.ca",
+		Usage:  "PEM file with CA certificates to trust in addition to the system CAs",
+		EnvVar: appNameUC + "_TLS_CA",
+	},
+	cli.StringFlag{
+		Name:   "tls.cert",
+		Usage:  "PEM file with a client certificate for mutual TLS. Requires --tls.key",
+		EnvVar: appNameUC + "_TLS_CERT",
+	},
+	cli.StringFlag{
+		Name:   "tls.key",
+		Usage:  "PEM file with the private key of --tls.cert",
+		EnvVar: appNameUC + "_TLS_KEY",
+	},
 	cli.StringFlag{
 		Name:   "region",
 		Usage:  "Specify a custom region",
@@ -200,6 +218,10 @@ var ioFlags = []cli.Flag{
 		Name:  "sse-s3-encrypt",
 		Usage: "server-side sse-s3 encrypt/decrypt objects",
 	},
+	cli.StringFlag{
+		Name:  "sse-kms-key-id",
+		Usage: "server-side sse-kms encrypt objects with the given KMS key ID",
+	},
 	cli.StringFlag{
 		Name:  "bucket",
 		Value: appName + "-benchmark-bucket",
@@ -208,7 +230,7 @@ var ioFlags = []cli.Flag{
 	cli.StringFlag{
 		Name:  "host-select",
 		Value: string(hostSelectTypeWeighed),
-		Usage: fmt.Sprintf("Host selection algorithm. Can be %q or %q", hostSelectTypeWeighed, hostSelectTypeRoundrobin),
+		Usage: fmt.Sprintf("Host selection algorithm. Can be %q, %q or %q", hostSelectTypeWeighed, hostSelectTypeRoundrobin, hostSelectTypeRandom),
 	},
 	cli.BoolFlag{
 		Name:   "resolve-host",
@@ -262,11 +284,23 @@ var ioFlags = []cli.Flag{
 		Usage:  "Disable HTTP Keep-Alive",
 		Hidden: true,
 	},
+	cli.BoolFlag{
+		Name:  "isolate-conns",
+		Usage: "Give each concurrent worker its own HTTP transport and connection pool",
+	},
+	cli.IntFlag{
+		Name:  "max-idle-conns",
+		Usage: "Maximum idle connections kept per host and transport. Defaults to --concurrent, or 1 with --isolate-conns",
+	},
 	cli.BoolFlag{
 		Name:   "http2",
 		Usage:  "enable HTTP2 support if server supports it",
 		Hidden: true,
 	},
+	cli.StringFlag{
+		Name:  "http-version",
+		Usage: "HTTP version to use. '1.1', '2' to negotiate HTTP/2 over TLS, or 'h2c' for HTTP/2 without TLS. Default 1.1",
+	},
 	cli.BoolFlag{
 		Name:  "stress",
 		Usage: "stress test only and discard output",
@@ -276,11 +310,72 @@ var ioFlags = []cli.Flag{
 		EnvVar: appNameUC + "_INFLUXDB_CONNECT",
 		Usage:  "Send operations to InfluxDB. Specify as 'http://<token>@<hostname>:<port>/<bucket>/<org>'",
 	},
+	cli.StringFlag{
+		Name:  "prometheus",
+		Usage: "Serve live metrics for Prometheus at '/metrics' on this address, for example ':9100'",
+	},
 	cli.Float64Flag{
 		Name:  "rps-limit",
 		Value: 0,
 		Usage: "Rate limit each instance to this number of requests per second (0 to disable)",
 	},
+	cli.IntFlag{
+		Name:  "rps-limit.burst",
+		Value: 1,
+		Usage: "Allow bursts of up to this many requests when --rps-limit is set",
+	},
+	cli.BoolFlag{
+		Name:  "rps-limit.adaptive",
+		Usage: "Lower the --rps-limit rate when the server throttles requests and raise it back when throttling stops",
+	},
+	cli.StringFlag{
+		Name:  "think",
+		Usage: "Pause each worker before every operation. A duration like 100ms, a uniform range like 50ms-150ms or exp:100ms for exponentially distributed pauses",
+	},
+	cli.DurationFlag{
+		Name:  "rampup",
+		Usage: "Start workers at random times spread over this duration instead of all at once",
+	},
+	cli.DurationFlag{
+		Name:  "op-timeout",
+		Usage: "Fail GET, PUT and STAT attempts not done within this duration. Each retry has the full duration (0 to disable)",
+	},
+	cli.IntFlag{
+		Name:  "retry",
+		Value: 0,
+		Usage: "Retry operations of the get, put and stat benchmarks failing with throttling, server errors or timeouts up to this many times (0 to disable)",
+	},
+	cli.DurationFlag{
+		Name:  "retry.base",
+		Value: 100 * time.Millisecond,
+		Usage: "Delay before the first retry. Doubled for each following retry",
+	},
+	cli.DurationFlag{
+		Name:  "retry.max",
+		Value: 5 * time.Second,
+		Usage: "Maximum delay between retries",
+	},
+	cli.Float64Flag{
+		Name:  "retry.jitter",
+		Value: 0.5,
+		Usage: "Fraction of the retry delay to randomize, 0 to 1",
+	},
+	cli.DurationFlag{
+		Name:  "progress",
+		Usage: "Print a progress line with operations, throughput and p99 latency at this interval. Best combined with --quiet",
+	},
+	cli.Int64Flag{
+		Name:  "progress.total",
+		Usage: "Expected number of operations of a fixed-count run. Adds percent complete and ETA to --progress lines",
+	},
+	cli.StringFlag{
+		Name:  "stream-ops",
+		Usage: "Write each operation to this file as it completes. Format and compression are selected by extension, for example ops.csv.zst or ops.json.gz",
+	},
+	cli.StringFlag{
+		Name:  "oplog",
+		Usage: "Write each completed operation to this file as a JSON line with type, key, size, start, duration, status and bytes",
+	},
 	cli.BoolFlag{
 		Name:   "stdout",
 		Usage:  "Send operations to stdout",
@@ -295,6 +390,37 @@ var ioFlags = []cli.Flag{
 		Usage: "Add checksum to uploaded object. Values: CRC64NVME, CRC32[-FO], CRC32C[-FO], SHA1 or SHA256. Requires server trailing headers (AWS, MinIO)",
 		Value: "",
 	},
+	cli.BoolFlag{
+		Name:  "expect-continue",
+		Usage: "Send 'Expect: 100-continue' on PUT requests, so the body is only sent once the server has accepted the request",
+	},
+	cli.BoolFlag{
+		Name:  "clock-skew",
+		Usage: "Measure the clock skew between client and server from response Date headers and print it after the benchmark",
+	},
+	cli.StringFlag{
+		Name:  "slow-log",
+		Usage: "Write requests slower than --slow-log.threshold to this file as JSON lines with status, request ID and all headers",
+	},
+	cli.DurationFlag{
+		Name:  "slow-log.threshold",
+		Value: time.Second,
+		Usage: "Minimum time until response headers for a request to be written to --slow-log",
+	},
+	cli.StringFlag{
+		Name:  "dns",
+		Value: dnsSystem,
+		Usage: fmt.Sprintf("Host name resolution. %q leaves it to the system, %q looks up hosts for every connection and reports lookup times, %q caches lookups for --dns.ttl", dnsSystem, dnsUncached, dnsCached),
+	},
+	cli.DurationFlag{
+		Name:  "dns.ttl",
+		Value: time.Minute,
+		Usage: "Time lookups are cached with --dns=cached",
+	},
+	cli.StringFlag{
+		Name:  "dns.pin",
+		Usage: "Connect to a fixed IP for host names, without lookups, for example 's3.example.com=10.0.0.1,s3b.example.com=10.0.0.2'",
+	},
 }
 
 func getCommon(ctx *cli.Context, src func() generator.Source) bench.Common {
@@ -308,6 +434,20 @@ func getCommon(ctx *cli.Context, src func() generator.Source) bench.Common {
 			extra = append(extra, in)
 		}
 	}
+	if addr := ctx.String("prometheus"); addr != "" {
+		metrics := aggregate.NewMetrics()
+		mctx, cancel := context.WithCancel(context.Background())
+		_, err := aggregate.ServeMetrics(mctx, addr, metrics)
+		fatalIf(probe.NewError(err), "Unable to serve Prometheus metrics")
+		mc := make(chan bench.Operation, 1000)
+		go func() {
+			for op := range mc {
+				metrics.Add(op)
+			}
+			cancel()
+		}()
+		extra = append(extra, mc)
+	}
 	statusln := func(s string) {
 		console.Eraseline()
 		console.Print(s)
@@ -333,18 +473,98 @@ func getCommon(ctx *cli.Context, src func() generator.Source) bench.Common {
 		}()
 		extra = append(extra, so)
 	}
+	if path := ctx.String("stream-ops"); path != "" {
+		rw, err := bench.CreateReport(path)
+		fatalIf(probe.NewError(err), "Unable to create operation output")
+		ro := make(chan bench.Operation, 1000)
+		globalWG.Add(1)
+		go func() {
+			defer globalWG.Done()
+			// Errors are retained by the writer and returned by Close.
+			_ = rw.WriteFrom(ro)
+			_ = rw.WriteComment(commandLine(ctx))
+			if err := rw.Close(); err != nil {
+				printError("Unable to write operations:", err)
+			}
+		}()
+		extra = append(extra, ro)
+	}
+	if path := ctx.String("oplog"); path != "" {
+		ol, err := bench.CreateOperationLog(path)
+		fatalIf(probe.NewError(err), "Unable to create operation log")
+		oc := make(chan bench.Operation, 1000)
+		globalWG.Add(1)
+		go func() {
+			defer globalWG.Done()
+			ol.LogFrom(oc)
+			if err := ol.Close(); err != nil {
+				printError("Unable to write operation log:", err)
+			}
+		}()
+		extra = append(extra, oc)
+	}
+	if interval := ctx.Duration("progress"); interval > 0 {
+		pc := make(chan bench.Operation, 1000)
+		go func() {
+			var counters aggregate.ProgressCounters
+			counters.SetTotal(ctx.Int64("progress.total"))
+			pctx, cancel := context.WithCancel(context.Background())
+			go aggregate.PrintProgress(pctx, &counters, interval, os.Stdout)
+			for op := range pc {
+				counters.Add(op)
+			}
+			cancel()
+		}()
+		extra = append(extra, pc)
+	}
 	noOps := ctx.Bool("stress")
 
-	rpsLimit := ctx.Float64("rps-limit")
-	var rpsLimiter *rate.Limiter
-	if rpsLimit > 0 {
-		// set burst to 1 as limiter will always be called to wait for 1 token
-		rpsLimiter = rate.NewLimiter(rate.Limit(rpsLimit), 1)
+	rpsLimiter := bench.NewRateLimiter(ctx.Float64("rps-limit"), ctx.Int("rps-limit.burst"))
+	if ctx.Bool("rps-limit.adaptive") {
+		if rpsLimiter == nil {
+			fatalIf(errDummy(), "--rps-limit.adaptive requires --rps-limit")
+		}
+		adaptive := bench.NewAdaptiveRate(rpsLimiter, bench.AdaptiveOptions{})
+		ac := make(chan bench.Operation, 1000)
+		go func() {
+			actx, cancel := context.WithCancel(context.Background())
+			go adaptive.Run(actx)
+			for op := range ac {
+				adaptive.Observe(op)
+			}
+			cancel()
+		}()
+		extra = append(extra, ac)
+	}
+	var thinkTime *bench.ThinkTime
+	if tt := ctx.String("think"); tt != "" {
+		seed := rand.Int63()
+		if seeds := seedSource(ctx); seeds != nil {
+			seed = seeds.Seed("think")
+		}
+		thinkTime, err = bench.ParseThinkTime(tt, seed)
+		fatalIf(probe.NewError(err), "Invalid --think value")
+	}
+	var rampUp *bench.RampUp
+	if w := ctx.Duration("rampup"); w > 0 {
+		seed := rand.Int63()
+		if seeds := seedSource(ctx); seeds != nil {
+			seed = seeds.Seed("rampup")
+		}
+		rampUp, err = bench.NewRampUp(w, max(ctx.Int("concurrent"), 1), seed)
+		fatalIf(probe.NewError(err), "Invalid --rampup value")
 	}
 	// Create put options now, so ensure that trailing headers are set.
 	putOpts := putOpts(ctx)
+	var workerClient func(int) func() (*minio.Client, func())
+	if ctx.Bool("isolate-conns") {
+		workerClient = bench.NewWorkerClients(func(int) func() (*minio.Client, func()) {
+			return newClient(ctx)
+		}).Client
+	}
 	return bench.Common{
 		Client:        newClient(ctx),
+		WorkerClient:  workerClient,
 		Concurrency:   ctx.Int("concurrent"),
 		Source:        src,
 		Bucket:        ctx.String("bucket"),
@@ -353,6 +573,10 @@ func getCommon(ctx *cli.Context, src func() generator.Source) bench.Common {
 		DiscardOutput: noOps,
 		ExtraOut:      extra,
 		RpsLimiter:    rpsLimiter,
+		ThinkTime:     thinkTime,
+		RampUp:        rampUp,
+		OpTimeout:     ctx.Duration("op-timeout"),
+		Retry:         bench.NewRetrier(ctx.Int("retry"), ctx.Duration("retry.base"), ctx.Duration("retry.max"), ctx.Float64("retry.jitter")),
 		Transport:     clientTransport(ctx),
 		UpdateStatus:  statusln,
 	}
@@ -20,6 +20,7 @@ package cli
 import (
 	"errors"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/dustin/go-humanize"
@@ -41,10 +42,39 @@ var genFlags = []cli.Flag{
 		Name:  "obj.randsize",
 		Usage: "Randomize size of objects so they will be up to the specified size",
 	},
+	cli.StringFlag{
+		Name:  "obj.size-schedule",
+		Usage: "Read object sizes from this file, one size per line, and use them in order instead of the object size",
+	},
 	cli.BoolFlag{
 		Name:  "obj.static",
 		Usage: "Use static (repeating) data instead of random data for PUT operations",
 	},
+	cli.BoolFlag{
+		Name:  "obj.trace-header",
+		Usage: "Start the data of each object with a 32 byte header containing a hash of the object name and a sequence number",
+	},
+	cli.StringFlag{
+		Name:  "obj.filetype",
+		Usage: "Start the data of each object with a valid file header and set the content type. Possible values: " + strings.Join(generator.FileTypes, ", "),
+	},
+	cli.StringFlag{
+		Name:  "obj.template",
+		Usage: "Start the data of each object with this template. Placeholders {{id}}, {{ts}} and {{rand:n}} are replaced for each object. Use @file to read the template from a file",
+	},
+	cli.Int64Flag{
+		Name:  "seed",
+		Usage: "Seed for all random generators, making object names, data and operation order reproducible (0 for random)",
+	},
+}
+
+// seedSource returns the seed source from --seed, or nil if not set.
+func seedSource(ctx *cli.Context) *generator.SeedSource {
+	seed := ctx.Int64("seed")
+	if seed == 0 {
+		return nil
+	}
+	return generator.NewSeedSource(seed)
 }
 
 // newGenSource returns a new generator
@@ -99,6 +129,30 @@ func newGenSource(ctx *cli.Context, sizeField string) func() generator.Source {
 		opts = append([]generator.Option{g.Apply()}, append(opts, generator.WithRandomSize(ctx.Bool("obj.randsize")), generator.WithStaticData(ctx.Bool("obj.static")))...)
 	}
 
+	if path := ctx.String("obj.size-schedule"); path != "" {
+		sched, err := generator.LoadSizeSchedule(path)
+		fatalIf(probe.NewError(err), "Invalid --obj.size-schedule")
+		opts = append(opts, generator.WithSizeSchedule(sched))
+	}
+	if ctx.Bool("obj.trace-header") {
+		opts = append(opts, generator.WithTraceHeader(true))
+	}
+	if ft := ctx.String("obj.filetype"); ft != "" {
+		opts = append(opts, generator.WithFileType(ft))
+	}
+	if tmpl := ctx.String("obj.template"); tmpl != "" {
+		if path, ok := strings.CutPrefix(tmpl, "@"); ok {
+			b, err := os.ReadFile(path)
+			fatalIf(probe.NewError(err), "Unable to read template")
+			tmpl = string(b)
+		}
+		t, err := generator.ParseTemplate(tmpl)
+		fatalIf(probe.NewError(err), "Invalid --obj.template")
+		opts = append(opts, generator.WithTemplate(t))
+	}
+	if seeds := seedSource(ctx); seeds != nil {
+		opts = append(opts, generator.WithSeedSource(seeds.Child("data")))
+	}
 	src, err := generator.NewFn(opts...)
 	fatalIf(probe.NewError(err), "Unable to create data generator")
 	return src
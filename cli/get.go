@@ -18,7 +18,10 @@
 package cli
 
 import (
+	"math/rand"
+
 	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/pkg/v3/console"
 	"github.com/minio/warp/pkg/bench"
@@ -62,6 +65,28 @@ var getFlags = []cli.Flag{
 		Name:  "list-flat",
 		Usage: "When using --list-existing, do not use recursive listing",
 	},
+	cli.BoolFlag{
+		Name:  "conditional",
+		Usage: "Send If-None-Match with the ETag of each object and report the ratio of 304 to 200 responses",
+	},
+	cli.StringFlag{
+		Name:  "access",
+		Value: "random",
+		Usage: "Order objects are read in. 'random', 'sequential' in upload order or 'shuffled' to read each object once per pass in an order given by --seed.",
+	},
+	cli.Float64Flag{
+		Name:  "zipf",
+		Usage: "Read objects with Zipf distributed popularity with this skew, for example 1.1. Higher values read fewer hot objects more often.",
+	},
+	cli.BoolFlag{
+		Name:  "read-buffer",
+		Usage: "Read response bodies in chunks of --read-buffer.size instead of 8KiB",
+	},
+	cli.StringFlag{
+		Name:  "read-buffer.size",
+		Value: "1MiB",
+		Usage: "Read buffer size used with --read-buffer",
+	},
 }
 
 var GetCombinedFlags = combineFlags(globalFlags, ioFlags, getFlags, genFlags, benchFlags, analyzeFlags)
@@ -97,6 +122,7 @@ func mainGet(ctx *cli.Context) error {
 		rangeSize = int64(s)
 	}
 
+	access, _ := bench.ParseAccessPattern(ctx.String("access"))
 	sse := newSSE(ctx)
 	b := bench.Get{
 		Common:        getCommon(ctx, newGenSource(ctx, "obj.size")),
@@ -108,8 +134,37 @@ func mainGet(ctx *cli.Context) error {
 		ListExisting:  ctx.Bool("list-existing"),
 		ListFlat:      ctx.Bool("list-flat"),
 		ListPrefix:    ctx.String("prefix"),
+		Access:        access,
+	}
+	if ctx.Bool("conditional") {
+		b.Conditional = &bench.ConditionalGet{}
 	}
-	return runBench(ctx, &b)
+	if access == bench.AccessShuffled {
+		b.AccessSeed = rand.Int63()
+		if seeds := seedSource(ctx); seeds != nil {
+			b.AccessSeed = seeds.Seed("access")
+		}
+	}
+	if skew := ctx.Float64("zipf"); skew > 0 {
+		seed := rand.Int63()
+		if seeds := seedSource(ctx); seeds != nil {
+			seed = seeds.Seed("zipf")
+		}
+		var err error
+		b.Zipf, err = bench.NewZipfSelector(skew, seed)
+		fatalIf(probe.NewError(err), "Invalid zipf skew specified")
+	}
+	if ctx.Bool("read-buffer") {
+		size, err := toSize(ctx.String("read-buffer.size"))
+		fatalIf(probe.NewError(err), "Invalid read buffer size specified")
+		b.ReadBuffer, err = bench.NewReadBuffer(int(size))
+		fatalIf(probe.NewError(err), "Invalid read buffer size specified")
+	}
+	err := runBench(ctx, &b)
+	if b.Conditional != nil && !globalQuiet {
+		console.Infoln("Conditional GET:", b.Conditional.Stats())
+	}
+	return err
 }
 
 func checkGetSyntax(ctx *cli.Context) {
@@ -119,6 +174,20 @@ func checkGetSyntax(ctx *cli.Context) {
 	if ctx.Int("versions") < 1 {
 		console.Fatal("At least one version must be tested")
 	}
+	if _, err := bench.ParseAccessPattern(ctx.String("access")); err != nil {
+		console.Fatal(err)
+	}
+	if ctx.IsSet("zipf") {
+		if ctx.Float64("zipf") <= 0 {
+			console.Fatal("--zipf must be > 0")
+		}
+		if ctx.IsSet("access") {
+			console.Fatal("--zipf cannot be combined with --access")
+		}
+	}
+	if ctx.Bool("conditional") && (ctx.Bool("range") || ctx.IsSet("range-size")) {
+		console.Fatal("--conditional cannot be combined with --range or --range-size")
+	}
 	if ctx.Bool("list-existing") {
 		if ctx.Int("objects") < 0 {
 			console.Fatal("Object count must be 0 or greater")
@@ -0,0 +1,103 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cli
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/minio/pkg/v3/console"
+)
+
+// interruptHandler stops a running benchmark on the first interrupt
+// and exits on the second.
+type interruptHandler struct {
+	cancel func()
+	info   func(data ...any)
+	exit   func()
+
+	once        sync.Once
+	interrupted chan struct{}
+}
+
+func newInterruptHandler(cancel func(), info func(data ...any)) *interruptHandler {
+	return &interruptHandler{
+		cancel:      cancel,
+		info:        info,
+		exit:        func() { console.Fatalln("Interrupted twice, exiting without results") },
+		interrupted: make(chan struct{}),
+	}
+}
+
+// notify installs h as the handler of SIGINT and SIGTERM.
+// The returned function restores the default behavior.
+func (h *interruptHandler) notify() (stop func()) {
+	sig := make(chan os.Signal, 2)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+	go h.run(sig, done)
+	return func() {
+		signal.Stop(sig)
+		close(done)
+	}
+}
+
+// run handles signals received on sig until done is closed.
+func (h *interruptHandler) run(sig <-chan os.Signal, done <-chan struct{}) {
+	for n := 0; ; n++ {
+		select {
+		case <-done:
+			return
+		case <-sig:
+		}
+		if n > 0 {
+			h.exit()
+			return
+		}
+		h.info("Interrupted, waiting for running operations to finish. Interrupt again to exit immediately.")
+		h.interrupt()
+	}
+}
+
+// interrupt stops the benchmark.
+func (h *interruptHandler) interrupt() {
+	h.once.Do(func() { close(h.interrupted) })
+	h.cancel()
+}
+
+// wait waits for done to be closed.
+// After an interrupt it waits at most drain more.
+// Returns false if the wait was cut short.
+func (h *interruptHandler) wait(done <-chan struct{}, drain time.Duration) bool {
+	select {
+	case <-done:
+		return true
+	case <-h.interrupted:
+	}
+	t := time.NewTimer(drain)
+	defer t.Stop()
+	select {
+	case <-done:
+		return true
+	case <-t.C:
+		return false
+	}
+}
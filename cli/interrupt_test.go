@@ -0,0 +1,76 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cli
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestInterruptHandler(t *testing.T) {
+	cancelled := make(chan struct{}, 2)
+	exited := make(chan struct{})
+	h := newInterruptHandler(func() { cancelled <- struct{}{} }, func(...any) {})
+	h.exit = func() { close(exited) }
+
+	sig := make(chan os.Signal, 2)
+	done := make(chan struct{})
+	defer close(done)
+	go h.run(sig, done)
+
+	running := make(chan struct{})
+	finished := make(chan bool)
+	go func() { finished <- h.wait(running, 20*time.Millisecond) }()
+
+	sig <- os.Interrupt
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("first interrupt did not cancel")
+	}
+	select {
+	case ok := <-finished:
+		if ok {
+			t.Fatal("wait returned true while operations were running")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("wait did not honor drain timeout")
+	}
+	select {
+	case <-exited:
+		t.Fatal("exited on first interrupt")
+	default:
+	}
+
+	sig <- os.Interrupt
+	select {
+	case <-exited:
+	case <-time.After(time.Second):
+		t.Fatal("second interrupt did not exit")
+	}
+
+	// Finished operations before the timeout report success.
+	h2 := newInterruptHandler(func() {}, func(...any) {})
+	h2.interrupt()
+	closed := make(chan struct{})
+	close(closed)
+	if !h2.wait(closed, time.Hour) {
+		t.Fatal("wait returned false for finished operations")
+	}
+}
@@ -0,0 +1,69 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cli
+
+import (
+	"github.com/minio/cli"
+	"github.com/minio/pkg/v3/console"
+	"github.com/minio/warp/pkg/bench"
+)
+
+var lifecycleFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "obj.size",
+		Value: "1MiB",
+		Usage: "Size of each generated object. Can be a number or 10KiB/MiB/GiB. All sizes are base 2 binary.",
+	},
+}
+
+var LifecycleCombinedFlags = combineFlags(globalFlags, ioFlags, lifecycleFlags, genFlags, benchFlags, analyzeFlags)
+
+var lifecycleCmd = cli.Command{
+	Name:   "lifecycle",
+	Usage:  "benchmark upload, download and delete of short-lived objects",
+	Action: mainLifecycle,
+	Before: setGlobalsFromContext,
+	Flags:  LifecycleCombinedFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS]
+  -> see https://github.com/minio/warp#lifecycle
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}`,
+}
+
+// mainLifecycle is the entry point for lifecycle command.
+func mainLifecycle(ctx *cli.Context) error {
+	checkLifecycleSyntax(ctx)
+	b := bench.Lifecycle{
+		Common: getCommon(ctx, newGenSource(ctx, "obj.size")),
+	}
+	return runBench(ctx, &b)
+}
+
+func checkLifecycleSyntax(ctx *cli.Context) {
+	if ctx.NArg() > 0 {
+		console.Fatal("Command takes no arguments")
+	}
+	checkAnalyze(ctx)
+	checkBenchmark(ctx)
+}
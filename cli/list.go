@@ -48,6 +48,15 @@ var listFlags = []cli.Flag{
 		Value: 100,
 		Usage: "Set the number of keys requested per list request.",
 	},
+	cli.BoolFlag{
+		Name:  "pages",
+		Usage: "Record each ListObjectsV2 page as a separate operation.",
+	},
+	cli.IntFlag{
+		Name:  "max-pages",
+		Value: 0,
+		Usage: "Stop each listing after this many pages when --pages is set. 0 lists all.",
+	},
 }
 
 var ListCombinedFlags = combineFlags(globalFlags, ioFlags, listFlags, genFlags, benchFlags, analyzeFlags)
@@ -81,6 +90,8 @@ func mainList(ctx *cli.Context) error {
 		CreateObjects: ctx.Int("objects"),
 		NoPrefix:      ctx.Bool("noprefix"),
 		MaxKeys:       ctx.Int("max-keys"),
+		Paged:         ctx.Bool("pages"),
+		MaxPages:      ctx.Int("max-pages"),
 	}
 	return runBench(ctx, &b)
 }
@@ -101,6 +112,12 @@ func checkListSyntax(ctx *cli.Context) {
 	if ctx.Int("max-keys") > 5000 {
 		console.Fatal("Max keys cannot be greater than 5000")
 	}
+	if ctx.Bool("pages") && (ctx.Int("versions") > 1 || ctx.Bool("metadata")) {
+		console.Fatal("--pages cannot be combined with --versions or --metadata")
+	}
+	if ctx.Int("max-pages") < 0 {
+		console.Fatal("Max pages cannot be negative")
+	}
 	checkAnalyze(ctx)
 	checkBenchmark(ctx)
 }
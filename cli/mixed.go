@@ -25,6 +25,7 @@ import (
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/pkg/v3/console"
 	"github.com/minio/warp/pkg/bench"
+	"github.com/minio/warp/pkg/generator"
 )
 
 var mixedFlags = []cli.Flag{
@@ -58,6 +59,44 @@ var mixedFlags = []cli.Flag{
 		Usage: "The amount of DELETE operations. Must be same or lower than -put-distrib",
 		Value: 10,
 	},
+	cli.Float64Flag{
+		Name:  "tag-put-distrib",
+		Usage: "The amount of TAG-PUT operations, replacing the tags of an object.",
+	},
+	cli.Float64Flag{
+		Name:  "tag-get-distrib",
+		Usage: "The amount of TAG-GET operations, reading the tags of an object.",
+	},
+	cli.IntFlag{
+		Name:  "tags",
+		Value: 5,
+		Usage: "Number of tags on each object when tagging operations are used. Max 10",
+	},
+	cli.StringFlag{
+		Name:  "tags.size",
+		Value: "32",
+		Usage: "Size of each tag value. Max 256 bytes.",
+	},
+	cli.BoolFlag{
+		Name:  "tags.randsize",
+		Usage: "Randomize the size of each tag value up to --tags.size.",
+	},
+	cli.IntFlag{
+		Name:  "get-concurrent",
+		Usage: "Maximum concurrent GET operations. 0 to only limit by --concurrent",
+	},
+	cli.IntFlag{
+		Name:  "stat-concurrent",
+		Usage: "Maximum concurrent STAT operations. 0 to only limit by --concurrent",
+	},
+	cli.IntFlag{
+		Name:  "put-concurrent",
+		Usage: "Maximum concurrent PUT operations. 0 to only limit by --concurrent",
+	},
+	cli.IntFlag{
+		Name:  "delete-concurrent",
+		Usage: "Maximum concurrent DELETE operations. 0 to only limit by --concurrent",
+	},
 }
 
 var MixedCombinedFlags = combineFlags(globalFlags, ioFlags, mixedFlags, genFlags, benchFlags, analyzeFlags)
@@ -90,8 +129,14 @@ func mainMixed(ctx *cli.Context) error {
 			"STAT":            ctx.Float64("stat-distrib"),
 			http.MethodPut:    ctx.Float64("put-distrib"),
 			http.MethodDelete: ctx.Float64("delete-distrib"),
+			bench.OpTagPut:    ctx.Float64("tag-put-distrib"),
+			bench.OpTagGet:    ctx.Float64("tag-get-distrib"),
 		},
 	}
+	seeds := seedSource(ctx)
+	if seeds != nil {
+		dist.Seed = seeds.Seed("mixed")
+	}
 	err := dist.Generate(ctx.Int("objects") * 2)
 	fatalIf(probe.NewError(err), "Invalid distribution")
 	b := bench.Mixed{
@@ -102,10 +147,37 @@ func mainMixed(ctx *cli.Context) error {
 			ServerSideEncryption: sse,
 		},
 		Dist: &dist,
+		OpConcurrency: map[string]int{
+			http.MethodGet:    ctx.Int("get-concurrent"),
+			"STAT":            ctx.Int("stat-concurrent"),
+			http.MethodPut:    ctx.Int("put-concurrent"),
+			http.MethodDelete: ctx.Int("delete-concurrent"),
+		},
+	}
+	if dist.Distribution[bench.OpTagPut] > 0 || dist.Distribution[bench.OpTagGet] > 0 {
+		b.Tags = newTagGenerator(ctx, seeds)
 	}
 	return runBench(ctx, &b)
 }
 
+// newTagGenerator returns a generator of the tags set by the mixed benchmark.
+func newTagGenerator(ctx *cli.Context, seeds *generator.SeedSource) *generator.TagGenerator {
+	size, err := toSize(ctx.String("tags.size"))
+	fatalIf(probe.NewError(err), "Invalid tags.size specified")
+	var seed int64
+	if seeds != nil {
+		seed = seeds.Seed("tags")
+	}
+	sizes := generator.NewConstantSizeSampler(int64(size))
+	if ctx.Bool("tags.randsize") {
+		sizes, err = generator.NewUniformSizeSampler(1, int64(size), seed)
+		fatalIf(probe.NewError(err), "Invalid tags.size specified")
+	}
+	tags, err := generator.NewTagGenerator(ctx.Int("tags"), sizes, seed)
+	fatalIf(probe.NewError(err), "Invalid tags specified")
+	return tags
+}
+
 func checkMixedSyntax(ctx *cli.Context) {
 	if ctx.NArg() > 0 {
 		console.Fatal("Command takes no arguments")
@@ -113,6 +185,14 @@ func checkMixedSyntax(ctx *cli.Context) {
 	if ctx.Int("objects") < 1 {
 		console.Fatal("At least one object must be tested")
 	}
+	for _, flag := range []string{"get-concurrent", "stat-concurrent", "put-concurrent", "delete-concurrent"} {
+		if ctx.Int(flag) < 0 {
+			console.Fatal("--" + flag + " cannot be negative")
+		}
+	}
+	if size, err := toSize(ctx.String("tags.size")); err != nil || size < 1 || size > generator.MaxTagValueBytes {
+		console.Fatal("--tags.size must be between 1 and 256 bytes")
+	}
 	checkAnalyze(ctx)
 	checkBenchmark(ctx)
 }
@@ -0,0 +1,101 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cli
+
+import (
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/pkg/v3/console"
+	"github.com/minio/warp/pkg/bench"
+)
+
+var presignedFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "method",
+		Value: "put",
+		Usage: "Operation to send through presigned URLs, 'put' or 'get'",
+	},
+	cli.IntFlag{
+		Name:  "objects",
+		Value: 2500,
+		Usage: "Number of objects to upload for --method=get.",
+	},
+	cli.StringFlag{
+		Name:  "obj.size",
+		Value: "10MiB",
+		Usage: "Size of each generated object. Can be a number or 10KiB/MiB/GiB. All sizes are base 2 binary.",
+	},
+	cli.DurationFlag{
+		Name:  "expires",
+		Value: 15 * time.Minute,
+		Usage: "Validity of the presigned URLs",
+	},
+}
+
+var PresignedCombinedFlags = combineFlags(globalFlags, ioFlags, presignedFlags, genFlags, benchFlags, analyzeFlags)
+
+var presignedCmd = cli.Command{
+	Name:   "presigned",
+	Usage:  "benchmark uploads or downloads through presigned URLs",
+	Action: mainPresigned,
+	Before: setGlobalsFromContext,
+	Flags:  PresignedCombinedFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS]
+  -> see https://github.com/minio/warp#presigned
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}`,
+}
+
+// mainPresigned is the entry point for presigned command.
+func mainPresigned(ctx *cli.Context) error {
+	checkPresignedSyntax(ctx)
+	b := bench.Presigned{
+		Common:        getCommon(ctx, newGenSource(ctx, "obj.size")),
+		Get:           ctx.String("method") == "get",
+		CreateObjects: ctx.Int("objects"),
+		Expires:       ctx.Duration("expires"),
+	}
+	return runBench(ctx, &b)
+}
+
+func checkPresignedSyntax(ctx *cli.Context) {
+	if ctx.NArg() > 0 {
+		console.Fatal("Command takes no arguments")
+	}
+	switch ctx.String("method") {
+	case "put":
+	case "get":
+		if ctx.Int("objects") < 1 {
+			console.Fatal("At least one object must be tested")
+		}
+	default:
+		console.Fatal("--method must be 'put' or 'get'")
+	}
+	if d := ctx.Duration("expires"); d <= 0 || d > 7*24*time.Hour {
+		console.Fatal("--expires must be between 1s and 7 days")
+	}
+	checkAnalyze(ctx)
+	checkBenchmark(ctx)
+}
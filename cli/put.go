@@ -23,12 +23,14 @@ import (
 	"math/rand"
 	"strings"
 	"sync/atomic"
+	"time"
 
 	"github.com/minio/cli"
 	"github.com/minio/mc/pkg/probe"
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/pkg/v3/console"
 	"github.com/minio/warp/pkg/bench"
+	"github.com/minio/warp/pkg/generator"
 )
 
 var putFlags = []cli.Flag{
@@ -47,6 +49,69 @@ var putFlags = []cli.Flag{
 		Name:  "post",
 		Usage: "Use PostObject for upload. Will force single part upload",
 	},
+	cli.StringFlag{
+		Name:  "content-type",
+		Value: "",
+		Usage: "Content-Type to set on uploaded objects.",
+	},
+	cli.IntFlag{
+		Name:  "metadata.keys",
+		Value: 0,
+		Usage: "Add this many user metadata keys with random values to each object.",
+	},
+	cli.StringFlag{
+		Name:  "metadata.size",
+		Value: "256B",
+		Usage: "Combined size of generated metadata keys and values per object when --metadata.keys is set.",
+	},
+	cli.StringFlag{
+		Name:  "lock.mode",
+		Usage: "Upload objects with Object Lock retention in this mode. Can be 'governance' or 'compliance'. The bucket is created with Object Lock enabled.",
+	},
+	cli.DurationFlag{
+		Name:  "lock.retain",
+		Value: time.Hour,
+		Usage: "Retain objects for this long after upload when --lock.mode is set.",
+	},
+	cli.BoolFlag{
+		Name:  "lock.legal-hold",
+		Usage: "Upload objects with a legal hold. The bucket is created with Object Lock enabled.",
+	},
+	cli.StringFlag{
+		Name:  "bandwidth",
+		Value: "",
+		Usage: "Limit each upload to this many bytes per second. Can be a number or 10KiB/MiB/GiB.",
+	},
+	cli.BoolFlag{
+		Name:  "verify-etag",
+		Usage: "Verify that the ETag of each single part upload matches the MD5 of the content.",
+	},
+	cli.BoolFlag{
+		Name:  "content-md5",
+		Usage: "Hash each object before upload and send it as Content-MD5 in a single PUT. Hashing time is reported separately.",
+	},
+	cli.BoolFlag{
+		Name:  "stream",
+		Usage: "Upload the object data through a reader that cannot be seeked, with the object size sent as Content-Length.",
+	},
+	cli.StringFlag{
+		Name:  "buckets",
+		Usage: "Spread uploads over several buckets with optional weights instead of --bucket. Example: --buckets logs:3,media:1,backup",
+	},
+	cli.StringFlag{
+		Name:  "read-after-write",
+		Usage: "Read each object back after upload and report how often it is not yet visible. Use 'head' or 'get'.",
+	},
+	cli.IntFlag{
+		Name:  "read-after-write.retries",
+		Value: 5,
+		Usage: "Number of times to read again if an object is not visible.",
+	},
+	cli.DurationFlag{
+		Name:  "read-after-write.backoff",
+		Value: 50 * time.Millisecond,
+		Usage: "Delay before the first read retry. Doubled for each following retry.",
+	},
 }
 
 var PutCombinedFlags = combineFlags(globalFlags, ioFlags, putFlags, genFlags, benchFlags, analyzeFlags)
@@ -73,14 +138,112 @@ FLAGS:
 // mainPut is the entry point for cp command.
 func mainPut(ctx *cli.Context) error {
 	checkPutSyntax(ctx)
+	var bandwidth uint64
+	if bw := ctx.String("bandwidth"); bw != "" {
+		var err error
+		bandwidth, err = toSize(bw)
+		fatalIf(probe.NewError(err), "Invalid bandwidth specified")
+	}
 	b := bench.Put{
-		Common:     getCommon(ctx, newGenSource(ctx, "obj.size")),
-		PostObject: ctx.Bool("post"),
+		Common:         getCommon(ctx, newGenSource(ctx, "obj.size")),
+		PostObject:     ctx.Bool("post"),
+		BandwidthLimit: int64(bandwidth),
+		Attrs:          newAttrsSource(ctx),
+		VerifyETag:     ctx.Bool("verify-etag"),
+		ReadAfterWrite: newReadAfterWrite(ctx),
+		ContentMD5:     ctx.Bool("content-md5"),
+		Stream:         ctx.Bool("stream"),
+		ReaderPool:     newReaderPool(ctx),
+	}
+	// Uploads are encrypted by the attributes.
+	b.PutOpts.ServerSideEncryption = nil
+	b.Buckets = newBucketSelector(ctx)
+	b.Locking = ctx.String("lock.mode") != "" || ctx.Bool("lock.legal-hold")
+	err := runBench(ctx, &b)
+	if b.ContentMD5 && !globalQuiet {
+		console.Infoln("Content-MD5:", b.PrehashStats())
+	}
+	if b.ReadAfterWrite != nil && !globalQuiet {
+		console.Infoln("Read after write:", b.ReadAfterWrite.Stats())
+	}
+	if b.Buckets != nil && !globalQuiet {
+		counts := b.Buckets.Counts()
+		for _, bucket := range b.Buckets.Buckets() {
+			console.Infof("Bucket %q: %d operations\n", bucket, counts[bucket])
+		}
+	}
+	return err
+}
+
+// newReaderPool returns a pool with a static reader for every upload worker
+// if the objects only contain static data, otherwise nil.
+func newReaderPool(ctx *cli.Context) *generator.ReaderPool {
+	if !ctx.Bool("obj.static") || ctx.Bool("obj.trace-header") || ctx.String("obj.filetype") != "" || ctx.String("obj.template") != "" {
+		return nil
 	}
-	return runBench(ctx, &b)
+	p, err := generator.NewReaderPool(max(ctx.Int("concurrent"), 1), 0)
+	fatalIf(probe.NewError(err), "Unable to create reader pool")
+	return p
 }
 
-const metadataChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890-_."
+// newBucketSelector returns the bucket selector from the context, or nil if --buckets is not set.
+func newBucketSelector(ctx *cli.Context) *generator.BucketSelector {
+	s := ctx.String("buckets")
+	if s == "" {
+		return nil
+	}
+	weights, err := generator.ParseBucketWeights(s)
+	fatalIf(probe.NewError(err), "Invalid --buckets value")
+	var seed int64
+	if seeds := seedSource(ctx); seeds != nil {
+		seed = seeds.Seed("buckets")
+	} else {
+		seed = rand.Int63()
+	}
+	sel, err := generator.NewBucketSelector(weights, seed)
+	fatalIf(probe.NewError(err), "Invalid --buckets value")
+	return sel
+}
+
+// newReadAfterWrite returns the read after write check from the context, or nil if disabled.
+func newReadAfterWrite(ctx *cli.Context) *bench.ReadAfterWrite {
+	mode := strings.ToLower(ctx.String("read-after-write"))
+	if mode == "" {
+		return nil
+	}
+	return &bench.ReadAfterWrite{
+		UseGet:  mode == "get",
+		Retries: ctx.Int("read-after-write.retries"),
+		Backoff: ctx.Duration("read-after-write.backoff"),
+	}
+}
+
+// newAttrsSource returns object attributes from the context, or nil if none are set.
+func newAttrsSource(ctx *cli.Context) *bench.AttrsSource {
+	attrs := bench.AttrsSource{ContentType: ctx.String("content-type")}
+	if n := ctx.Int("metadata.keys"); n > 0 {
+		size, err := toSize(ctx.String("metadata.size"))
+		fatalIf(probe.NewError(err), "Invalid metadata.size specified")
+		var seed int64
+		if seeds := seedSource(ctx); seeds != nil {
+			seed = seeds.Seed("metadata")
+		} else {
+			seed = rand.Int63()
+		}
+		attrs.Metadata, err = generator.NewMetadataGenerator(n, int(size), seed)
+		fatalIf(probe.NewError(err), "Invalid metadata options")
+	}
+	if mode := ctx.String("lock.mode"); mode != "" {
+		attrs.LockMode = minio.RetentionMode(strings.ToUpper(mode))
+		attrs.RetainFor = ctx.Duration("lock.retain")
+	}
+	attrs.LegalHold = ctx.Bool("lock.legal-hold")
+	attrs.SSE = newSSE(ctx)
+	if attrs.ContentType == "" && attrs.Metadata == nil && attrs.LockMode == "" && !attrs.LegalHold && attrs.SSE == nil {
+		return nil
+	}
+	return &attrs
+}
 
 // putOpts retrieves put options from the context.
 func putOpts(ctx *cli.Context) minio.PutObjectOptions {
@@ -114,7 +277,7 @@ func putOpts(ctx *cli.Context) minio.PutObjectOptions {
 				rng := rand.New(rand.NewSource(int64(rand.Uint64())))
 				value = ""
 				for i := 0; i < randN; i++ {
-					value += string(metadataChars[rng.Int()%len(metadataChars)])
+					value += string(generator.MetadataValueChars[rng.Int()%len(generator.MetadataValueChars)])
 				}
 			}
 			values[key] = value
@@ -136,6 +299,53 @@ func checkPutSyntax(ctx *cli.Context) {
 		console.Fatal("Command takes no arguments")
 	}
 
+	if ctx.Bool("verify-etag") {
+		if ctx.Bool("post") {
+			console.Fatal("--verify-etag cannot be used with --post")
+		}
+		if ctx.Bool("encrypt") || ctx.Bool("sse-s3-encrypt") || ctx.String("sse-kms-key-id") != "" {
+			console.Fatal("--verify-etag cannot be used with server side encryption")
+		}
+	}
+	if ctx.Bool("content-md5") {
+		if ctx.Bool("post") {
+			console.Fatal("--content-md5 cannot be used with --post")
+		}
+		if ctx.Bool("md5") || ctx.String("checksum") != "" {
+			console.Fatal("--content-md5 cannot be combined with --md5 or --checksum")
+		}
+	}
+	if ctx.Bool("stream") {
+		for _, flag := range []string{"post", "verify-etag", "content-md5", "bandwidth"} {
+			if ctx.IsSet(flag) {
+				console.Fatalf("--stream cannot be combined with --%s", flag)
+			}
+		}
+	}
+	if mode := strings.ToLower(ctx.String("read-after-write")); mode != "" {
+		if mode != "head" && mode != "get" {
+			console.Fatal("--read-after-write must be 'head' or 'get'")
+		}
+		if ctx.Int("read-after-write.retries") < 0 {
+			console.Fatal("--read-after-write.retries must be >= 0")
+		}
+	}
+
+	if mode := ctx.String("lock.mode"); mode != "" {
+		if !minio.RetentionMode(strings.ToUpper(mode)).IsValid() {
+			console.Fatal("--lock.mode must be 'governance' or 'compliance'")
+		}
+		if ctx.Duration("lock.retain") <= 0 {
+			console.Fatal("--lock.retain must be > 0")
+		}
+		if ctx.Bool("post") {
+			console.Fatal("--lock.mode cannot be used with --post")
+		}
+	}
+	if ctx.Bool("lock.legal-hold") && ctx.Bool("post") {
+		console.Fatal("--lock.legal-hold cannot be used with --post")
+	}
+
 	checkAnalyze(ctx)
 	checkBenchmark(ctx)
 }
@@ -0,0 +1,101 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cli
+
+import (
+	"github.com/minio/cli"
+	"github.com/minio/pkg/v3/console"
+	"github.com/minio/warp/pkg/bench"
+	"github.com/minio/warp/pkg/generator"
+)
+
+var selectFlags = []cli.Flag{
+	cli.IntFlag{
+		Name:  "objects",
+		Value: 100,
+		Usage: "Number of objects to upload.",
+	},
+	cli.StringFlag{
+		Name:  "obj.size",
+		Value: "1MiB",
+		Usage: "Size of each generated object, rounded down to whole records. Can be a number or 10KB/MB/GB. All sizes are base 2 binary.",
+	},
+	cli.StringFlag{
+		Name:  "select.format",
+		Value: generator.RecordFormatCSV,
+		Usage: "Format of the generated records. Can be csv or json (JSON lines).",
+	},
+	cli.StringFlag{
+		Name:  "select.query",
+		Value: bench.DefaultSelectQuery,
+		Usage: "SQL expression of each query. Records have the columns id, name, category and value.",
+	},
+}
+
+var SelectCombinedFlags = combineFlags(globalFlags, ioFlags, selectFlags, genFlags, benchFlags, analyzeFlags)
+
+var selectCmd = cli.Command{
+	Name:   "select",
+	Usage:  "benchmark S3 Select queries on CSV or JSON objects",
+	Action: mainSelect,
+	Before: setGlobalsFromContext,
+	Flags:  SelectCombinedFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS]
+  -> see https://github.com/minio/warp#select
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}`,
+}
+
+// mainSelect is the entry point for select command.
+func mainSelect(ctx *cli.Context) error {
+	checkSelectSyntax(ctx)
+	b := bench.Select{
+		Common:        getCommon(ctx, newGenSource(ctx, "obj.size")),
+		Format:        ctx.String("select.format"),
+		Query:         ctx.String("select.query"),
+		CreateObjects: ctx.Int("objects"),
+	}
+	err := runBench(ctx, &b)
+	if !globalQuiet {
+		console.Infoln("Select:", b.Stats())
+	}
+	return err
+}
+
+func checkSelectSyntax(ctx *cli.Context) {
+	if ctx.NArg() > 0 {
+		console.Fatal("Command takes no arguments")
+	}
+	if ctx.Int("objects") < 1 {
+		console.Fatal("At least one object must be tested")
+	}
+	if _, err := generator.NewRecordGenerator(ctx.String("select.format")); err != nil {
+		console.Fatal("Invalid --select.format: ", err)
+	}
+	if ctx.String("select.query") == "" {
+		console.Fatal("--select.query cannot be empty")
+	}
+	checkAnalyze(ctx)
+	checkBenchmark(ctx)
+}
@@ -0,0 +1,43 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cli
+
+import (
+	"context"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/warp/pkg/bench"
+)
+
+// runSelfTest uploads, verifies and deletes a few objects in the benchmark bucket.
+// The bucket is created if it doesn't exist.
+func runSelfTest(ctx context.Context, b bench.Benchmark) (bench.SelfTestResult, error) {
+	c := b.GetCommon()
+	cl, done := c.Client()
+	defer done()
+	exists, err := cl.BucketExists(ctx, c.Bucket)
+	if err != nil {
+		return bench.SelfTestResult{}, err
+	}
+	if !exists {
+		if err := cl.MakeBucket(ctx, c.Bucket, minio.MakeBucketOptions{Region: c.Location}); err != nil {
+			return bench.SelfTestResult{}, err
+		}
+	}
+	return bench.SelfTest(ctx, bench.NewObjectClient(cl), bench.SelfTestOptions{Bucket: c.Bucket}), nil
+}
@@ -0,0 +1,52 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cli
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/warp/pkg/aggregate"
+	"github.com/minio/warp/pkg/bench"
+)
+
+func TestCheckSLA(t *testing.T) {
+	ctx := cli.NewContext(nil, flag.NewFlagSet("sla", flag.ContinueOnError), nil)
+	sla, err := aggregate.ParseSLA("p99=20ms")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkSLA(evaluateSLA(ctx, sla, nil)); err == nil {
+		t.Error("SLA passed without operations")
+	}
+	start := time.Now()
+	var ops bench.Operations
+	for i := range 10 {
+		at := start.Add(time.Duration(i) * time.Second)
+		ops = append(ops, bench.Operation{OpType: "GET", ObjPerOp: 1, Start: at, End: at.Add(10 * time.Millisecond)})
+	}
+	if err := checkSLA(evaluateSLA(ctx, sla, ops)); err != nil {
+		t.Error(err)
+	}
+	ops[3].End = ops[3].Start.Add(time.Second)
+	if err := checkSLA(evaluateSLA(ctx, sla, ops)); err == nil {
+		t.Error("SLA passed with a slow operation")
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"crypto/rand"
 
 	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
 	"github.com/minio/minio-go/v7/pkg/encrypt"
 )
 
@@ -29,7 +30,7 @@ var sseKey encrypt.ServerSide
 // newSSE returns a randomly generated key if SSE is requested.
 // Only one key will be generated.
 func newSSE(ctx *cli.Context) encrypt.ServerSide {
-	if !ctx.Bool("encrypt") && !ctx.Bool("sse-s3-encrypt") {
+	if !ctx.Bool("encrypt") && !ctx.Bool("sse-s3-encrypt") && ctx.String("sse-kms-key-id") == "" {
 		return nil
 	}
 	if sseKey != nil {
@@ -41,6 +42,13 @@ func newSSE(ctx *cli.Context) encrypt.ServerSide {
 		return sseKey
 	}
 
+	if id := ctx.String("sse-kms-key-id"); id != "" {
+		var err error
+		sseKey, err = encrypt.NewSSEKMS(id, nil)
+		fatalIf(probe.NewError(err), "Invalid SSE-KMS key ID")
+		return sseKey
+	}
+
 	var key [32]byte
 	_, err := rand.Read(key[:])
 	if err != nil {
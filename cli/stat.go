@@ -48,6 +48,10 @@ var statFlags = []cli.Flag{
 		Name:  "list-flat",
 		Usage: "When using --list-existing, do not use recursive listing",
 	},
+	cli.Float64Flag{
+		Name:  "miss-ratio",
+		Usage: "Fraction of requests, 0 to 1, for objects that do not exist. These are reported as NotFound, not as errors",
+	},
 }
 
 var StatCombinedFlags = combineFlags(globalFlags, ioFlags, statFlags, genFlags, benchFlags, analyzeFlags)
@@ -85,6 +89,7 @@ func mainStat(ctx *cli.Context) error {
 		ListExisting: ctx.Bool("list-existing"),
 		ListFlat:     ctx.Bool("list-flat"),
 		ListPrefix:   ctx.String("prefix"),
+		MissRatio:    ctx.Float64("miss-ratio"),
 	}
 	return runBench(ctx, &b)
 }
@@ -99,6 +104,9 @@ func checkStatSyntax(ctx *cli.Context) {
 	if ctx.Int("objects") < 1 {
 		console.Fatal("At least one object must be tested")
 	}
+	if r := ctx.Float64("miss-ratio"); r < 0 || r > 1 {
+		console.Fatal("--miss-ratio must be between 0 and 1")
+	}
 	checkAnalyze(ctx)
 	checkBenchmark(ctx)
 }
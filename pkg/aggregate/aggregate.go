@@ -52,6 +52,9 @@ type Operation struct {
 	MultiSizedRequests *MultiSizedRequests `json:"multi_sized_requests,omitempty"`
 	// Populated if requests are all of same object size.
 	SingleSizedRequests *SingleSizedRequests `json:"single_sized_requests,omitempty"`
+	// Latency percentiles corrected for coordinated omission.
+	// Populated if Options.ExpectedInterval is set.
+	CorrectedLatency *LatencyPercentiles `json:"corrected_latency,omitempty"`
 	// Operation type
 	Type string `json:"type"`
 	// HostNames are sorted names of hosts
@@ -81,6 +84,11 @@ type Options struct {
 	DurFunc     SegmentDurFn
 	SkipDur     time.Duration
 	Prefiltered bool
+
+	// ExpectedInterval is the intended time between operations of a worker
+	// under a fixed rate. If set, latency percentiles are corrected for
+	// coordinated omission. See LatencyRecorder.ExpectedInterval.
+	ExpectedInterval time.Duration
 }
 
 // Aggregate returns statistics when only a single operation was running concurrently.
@@ -236,6 +244,14 @@ func Aggregate(o bench.Operations, opts Options) Aggregated {
 			} else {
 				a.MultiSizedRequests = RequestAnalysisMultiSized(ops, !opts.Prefiltered)
 			}
+			if opts.ExpectedInterval > 0 {
+				lat := LatencyRecorder{ExpectedInterval: opts.ExpectedInterval}
+				for _, op := range ops {
+					lat.Add(op.Duration())
+				}
+				p := lat.Percentiles()
+				a.CorrectedLatency = &p
+			}
 
 			eps := allOps.SortSplitByEndpoint()
 			if len(eps) == 1 {
@@ -0,0 +1,101 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package aggregate
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/minio/warp/pkg/bench"
+)
+
+func TestLiveCollectorInterrupted(t *testing.T) {
+	updates := make(chan UpdateReq, 10)
+	drain := bench.NewDrainCollector(LiveCollector(context.Background(), updates, "", nil))
+
+	// Workers run until canceled, then finish the operation in flight.
+	ctx, cancel := context.WithCancel(context.Background())
+	var sent atomic.Int64
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rcv := drain.Receiver()
+			for ctx.Err() == nil {
+				start := time.Now()
+				time.Sleep(time.Millisecond)
+				rcv <- bench.Operation{OpType: http.MethodGet, Thread: uint32(i), Start: start, End: time.Now(), ObjPerOp: 1, Size: 100}
+				sent.Add(1)
+			}
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	// Synthesized interrupt.
+	cancel()
+	wg.Wait()
+
+	// A worker that did not finish in time.
+	straggler := make(chan struct{})
+	go func() {
+		defer close(straggler)
+		drain.Receiver() <- bench.Operation{OpType: http.MethodGet, Start: time.Now(), End: time.Now(), ObjPerOp: 1}
+	}()
+	drain.Close()
+	<-straggler
+
+	finalCh := make(chan *Realtime, 1)
+	updates <- UpdateReq{Final: true, C: finalCh}
+	var final *Realtime
+	select {
+	case final = <-finalCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no final report")
+	}
+	if final == nil || !final.Final {
+		t.Fatalf("got %+v, want final report", final)
+	}
+	// The straggler is either sent before Close or dropped.
+	got := int64(final.Total.TotalRequests)
+	if got != sent.Load() && got != sent.Load()+1 {
+		t.Errorf("final report has %d requests, %d were sent before the interrupt", got, sent.Load())
+	}
+	if got+drain.Dropped() != sent.Load()+1 {
+		t.Errorf("%d reported and %d dropped, want %d", got, drain.Dropped(), sent.Load()+1)
+	}
+	if final.ByOpType[http.MethodGet] == nil {
+		t.Error("no GET results")
+	}
+
+	// Finish ends forwarding once the workers have returned.
+	finished := make(chan struct{})
+	go func() {
+		drain.Finish()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Finish did not return")
+	}
+	drain.Finish()
+}
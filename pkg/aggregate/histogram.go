@@ -0,0 +1,181 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package aggregate
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Histogram records durations in log-linear buckets, using bounded memory
+// regardless of the number of samples.
+// Quantiles are returned within a relative error of 10^-digits,
+// where digits is the number of significant digits it was created with.
+// It is safe for concurrent use.
+type Histogram struct {
+	mu      sync.Mutex
+	subBits uint
+	max     int64
+	counts  []uint64
+	n       uint64
+	sum     float64
+	minV    int64
+	maxV    int64
+}
+
+// NewHistogram returns a histogram with the specified number of significant digits (1-5).
+// Durations above maxValue are recorded as maxValue.
+func NewHistogram(digits int, maxValue time.Duration) (*Histogram, error) {
+	if digits < 1 || digits > 5 {
+		return nil, fmt.Errorf("histogram: significant digits must be 1 to 5, got %d", digits)
+	}
+	if maxValue <= 0 {
+		return nil, fmt.Errorf("histogram: invalid max value: %v", maxValue)
+	}
+	h := &Histogram{
+		subBits: uint(math.Ceil(math.Log2(math.Pow10(digits)))) + 1,
+		max:     int64(maxValue),
+	}
+	h.counts = make([]uint64, h.index(h.max)+1)
+	return h, nil
+}
+
+// index returns the bucket index of v.
+// Values below 2^subBits have their own bucket,
+// above that each power of two is split into 2^(subBits-1) buckets.
+func (h *Histogram) index(v int64) int {
+	half := 1 << (h.subBits - 1)
+	shift := max(bits.Len64(uint64(v))-int(h.subBits), 0)
+	return shift*half + int(v>>shift)
+}
+
+// value returns the midpoint of bucket idx.
+func (h *Histogram) value(idx int) int64 {
+	half := 1 << (h.subBits - 1)
+	if idx < 2*half {
+		return int64(idx)
+	}
+	shift := idx/half - 1
+	low := int64(idx-shift*half) << shift
+	return low + int64(1)<<(shift-1)
+}
+
+// Record adds a duration to the histogram.
+// Negative durations are recorded as 0.
+func (h *Histogram) Record(d time.Duration) {
+	v := min(max(int64(d), 0), h.max)
+	idx := h.index(v)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.n == 0 || v < h.minV {
+		h.minV = v
+	}
+	if v > h.maxV {
+		h.maxV = v
+	}
+	h.counts[idx]++
+	h.n++
+	h.sum += float64(v)
+}
+
+// Add a duration. Same as Record.
+func (h *Histogram) Add(d time.Duration) {
+	h.Record(d)
+}
+
+// Merge adds all durations recorded in o to h.
+// Both histograms must have been created with the same digits and max value.
+func (h *Histogram) Merge(o *Histogram) error {
+	if h.subBits != o.subBits || h.max != o.max {
+		return fmt.Errorf("histogram: cannot merge histograms with different precision or max value")
+	}
+	o.mu.Lock()
+	counts := slices.Clone(o.counts)
+	n, sum, minV, maxV := o.n, o.sum, o.minV, o.maxV
+	o.mu.Unlock()
+	if n == 0 {
+		return nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i, c := range counts {
+		h.counts[i] += c
+	}
+	if h.n == 0 || minV < h.minV {
+		h.minV = minV
+	}
+	h.maxV = max(h.maxV, maxV)
+	h.n += n
+	h.sum += sum
+	return nil
+}
+
+// Count returns the number of recorded durations.
+func (h *Histogram) Count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return int(h.n)
+}
+
+// Quantile returns the q quantile (0->1) of recorded durations.
+// Returns 0 if nothing has been recorded.
+func (h *Histogram) Quantile(q float64) time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.quantile(q)
+}
+
+func (h *Histogram) quantile(q float64) time.Duration {
+	if h.n == 0 {
+		return 0
+	}
+	q = min(max(q, 0), 1)
+	rank := max(uint64(math.Ceil(q*float64(h.n))), 1)
+	var seen uint64
+	for idx, c := range h.counts {
+		seen += c
+		if seen >= rank {
+			return time.Duration(min(max(h.value(idx), h.minV), h.maxV))
+		}
+	}
+	return time.Duration(h.maxV)
+}
+
+// Percentiles returns the percentiles of all recorded durations.
+func (h *Histogram) Percentiles() LatencyPercentiles {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.n == 0 {
+		return LatencyPercentiles{}
+	}
+	return LatencyPercentiles{
+		N:    int(h.n),
+		Min:  time.Duration(h.minV),
+		Mean: time.Duration(h.sum / float64(h.n)),
+		P50:  h.quantile(0.5),
+		P90:  h.quantile(0.9),
+		P99:  h.quantile(0.99),
+		P999: h.quantile(0.999),
+		Max:  time.Duration(h.maxV),
+	}
+}
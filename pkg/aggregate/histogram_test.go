@@ -0,0 +1,109 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package aggregate
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestHistogram(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	samples := make([]time.Duration, 200000)
+	for i := range samples {
+		// Log-normal latencies around 10ms.
+		samples[i] = time.Duration(math.Exp(rng.NormFloat64()*0.8) * float64(10*time.Millisecond))
+	}
+	sorted := slices.Clone(samples)
+	slices.Sort(sorted)
+	// exact returns the nearest-rank quantile.
+	exact := func(q float64) time.Duration {
+		rank := max(int(math.Ceil(q*float64(len(sorted)))), 1)
+		return sorted[rank-1]
+	}
+
+	for digits := 1; digits <= 4; digits++ {
+		h, err := NewHistogram(digits, time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, d := range samples {
+			h.Record(d)
+		}
+		maxErr := math.Pow10(-digits)
+		for _, q := range []float64{0, 0.01, 0.5, 0.9, 0.99, 0.999, 1} {
+			got, want := h.Quantile(q), exact(q)
+			if rel := math.Abs(float64(got-want)) / float64(want); rel > maxErr {
+				t.Errorf("digits %d, q %v: got %v, want %v (error %.5f > %v)", digits, q, got, want, rel, maxErr)
+			}
+		}
+		p := h.Percentiles()
+		if p.N != len(samples) || p.Min != sorted[0] || p.Max != sorted[len(sorted)-1] {
+			t.Errorf("digits %d: unexpected percentiles %v", digits, p)
+		}
+	}
+}
+
+func TestHistogramLimits(t *testing.T) {
+	h, err := NewHistogram(3, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Quantile(0.5) != 0 {
+		t.Error("empty histogram should return 0")
+	}
+	h.Record(-time.Second)
+	h.Record(time.Hour)
+	h.Record(5)
+	if got := h.Quantile(1); got != time.Second {
+		t.Errorf("max: got %v, want values above max clamped to 1s", got)
+	}
+	if got := h.Quantile(0); got != 0 {
+		t.Errorf("min: got %v, want 0", got)
+	}
+	// Small values are exact.
+	if got := h.Quantile(0.5); got != 5 {
+		t.Errorf("median: got %v, want 5ns", got)
+	}
+	if _, err := NewHistogram(0, time.Second); err == nil {
+		t.Error("want error for 0 digits")
+	}
+}
+
+func TestHistogramLatencyRecorder(t *testing.T) {
+	l, err := NewHistogramLatencyRecorder(3, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 1000; i++ {
+		l.Add(time.Duration(i) * time.Millisecond)
+	}
+	p := l.Percentiles()
+	if p.N != 1000 || p.Min != time.Millisecond || p.Max != time.Second {
+		t.Fatalf("unexpected percentiles: %v", p)
+	}
+	if math.Abs(float64(p.P99-990*time.Millisecond)) > float64(time.Millisecond) {
+		t.Errorf("p99: got %v, want about 990ms", p.P99)
+	}
+	if len(l.samples) != 0 {
+		t.Error("samples should not be stored")
+	}
+}
@@ -0,0 +1,224 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package aggregate
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+// LatencyRecorder records operation durations and computes percentiles.
+// The zero value keeps all samples and returns exact percentiles.
+// It is safe for concurrent use.
+type LatencyRecorder struct {
+	// ExpectedInterval corrects for coordinated omission when operations
+	// are started at a fixed rate, one every ExpectedInterval.
+	// A duration longer than the interval delayed the operations that should
+	// have started meanwhile, so a sample is added for each of them,
+	// with the duration reduced by one interval per sample.
+	// At most maxCorrectionSamples are added per duration.
+	// Zero disables the correction. It must be set before adding durations.
+	ExpectedInterval time.Duration
+
+	mu      sync.Mutex
+	samples []time.Duration
+	hist    *Histogram
+}
+
+// NewHistogramLatencyRecorder returns a recorder that uses a Histogram,
+// so memory use is bounded regardless of the number of samples.
+func NewHistogramLatencyRecorder(digits int, maxValue time.Duration) (*LatencyRecorder, error) {
+	h, err := NewHistogram(digits, maxValue)
+	if err != nil {
+		return nil, err
+	}
+	return &LatencyRecorder{hist: h}, nil
+}
+
+// LatencyPercentiles is a snapshot of recorded latencies.
+// All values are 0 if no samples have been recorded.
+type LatencyPercentiles struct {
+	N    int           `json:"n"`
+	Min  time.Duration `json:"min"`
+	Mean time.Duration `json:"mean"`
+	P50  time.Duration `json:"p50"`
+	P90  time.Duration `json:"p90"`
+	P99  time.Duration `json:"p99"`
+	P999 time.Duration `json:"p99_9"`
+	Max  time.Duration `json:"max"`
+}
+
+// String returns a human readable representation of the percentiles.
+func (p LatencyPercentiles) String() string {
+	if p.N == 0 {
+		return "no samples"
+	}
+	return fmt.Sprintf("n: %d, min: %v, mean: %v, p50: %v, p90: %v, p99: %v, p99.9: %v, max: %v",
+		p.N, p.Min, p.Mean, p.P50, p.P90, p.P99, p.P999, p.Max)
+}
+
+// maxCorrectionSamples is the maximum number of samples added for a single
+// duration when correcting for coordinated omission.
+// Only the longest of the missed durations are added beyond that,
+// so a long stall with a short interval cannot exhaust memory.
+const maxCorrectionSamples = 10000
+
+// Add a duration.
+func (l *LatencyRecorder) Add(d time.Duration) {
+	if l.hist != nil {
+		l.hist.Record(d)
+		l.correct(d, l.hist.Record)
+		return
+	}
+	l.mu.Lock()
+	l.samples = append(l.samples, d)
+	l.correct(d, func(d time.Duration) { l.samples = append(l.samples, d) })
+	l.mu.Unlock()
+}
+
+// correct calls add with the durations of the operations delayed by d.
+func (l *LatencyRecorder) correct(d time.Duration, add func(time.Duration)) {
+	if l.ExpectedInterval <= 0 {
+		return
+	}
+	missed := d - l.ExpectedInterval
+	for i := 0; i < maxCorrectionSamples && missed >= l.ExpectedInterval; i++ {
+		add(missed)
+		missed -= l.ExpectedInterval
+	}
+}
+
+// Percentiles returns the percentiles of all durations added so far.
+func (l *LatencyRecorder) Percentiles() LatencyPercentiles {
+	if l.hist != nil {
+		return l.hist.Percentiles()
+	}
+	l.mu.Lock()
+	sorted := slices.Clone(l.samples)
+	l.mu.Unlock()
+	slices.Sort(sorted)
+	return percentilesSorted(sorted)
+}
+
+// Merge adds all durations recorded by others to l.
+// All recorders must use the same backend,
+// and histograms must have been created with the same parameters.
+func (l *LatencyRecorder) Merge(others ...*LatencyRecorder) error {
+	for _, o := range others {
+		if (l.hist == nil) != (o.hist == nil) {
+			return errors.New("latency: cannot merge exact and histogram recorders")
+		}
+		if l.hist != nil {
+			if err := l.hist.Merge(o.hist); err != nil {
+				return err
+			}
+			continue
+		}
+		o.mu.Lock()
+		samples := slices.Clone(o.samples)
+		o.mu.Unlock()
+		l.mu.Lock()
+		l.samples = append(l.samples, samples...)
+		l.mu.Unlock()
+	}
+	return nil
+}
+
+// ShardedLatencyRecorder has a LatencyRecorder per shard,
+// so workers can record to their own shard without sharing a lock.
+// Use Merge to get percentiles of all shards.
+type ShardedLatencyRecorder struct {
+	shards   []*LatencyRecorder
+	newShard func() (*LatencyRecorder, error)
+}
+
+// NewShardedLatencyRecorder returns a recorder with n shards.
+// Shards are created by newShard, or keep all samples if newShard is nil.
+func NewShardedLatencyRecorder(n int, newShard func() (*LatencyRecorder, error)) (*ShardedLatencyRecorder, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("latency: invalid shard count: %d", n)
+	}
+	if newShard == nil {
+		newShard = func() (*LatencyRecorder, error) { return &LatencyRecorder{}, nil }
+	}
+	s := &ShardedLatencyRecorder{shards: make([]*LatencyRecorder, n), newShard: newShard}
+	for i := range s.shards {
+		var err error
+		s.shards[i], err = newShard()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Shard returns shard i, modulo the number of shards.
+func (s *ShardedLatencyRecorder) Shard(i int) *LatencyRecorder {
+	return s.shards[i%len(s.shards)]
+}
+
+// Merge returns a new recorder with the durations of all shards.
+// Shards can keep recording while merging, but those durations may not be included.
+func (s *ShardedLatencyRecorder) Merge() (*LatencyRecorder, error) {
+	l, err := s.newShard()
+	if err != nil {
+		return nil, err
+	}
+	return l, l.Merge(s.shards...)
+}
+
+// percentilesSorted returns percentiles of sorted samples.
+func percentilesSorted(sorted []time.Duration) LatencyPercentiles {
+	if len(sorted) == 0 {
+		return LatencyPercentiles{}
+	}
+	var total float64
+	for _, d := range sorted {
+		total += float64(d)
+	}
+	return LatencyPercentiles{
+		N:    len(sorted),
+		Min:  sorted[0],
+		Mean: time.Duration(total / float64(len(sorted))),
+		P50:  quantileSorted(sorted, 0.5),
+		P90:  quantileSorted(sorted, 0.9),
+		P99:  quantileSorted(sorted, 0.99),
+		P999: quantileSorted(sorted, 0.999),
+		Max:  sorted[len(sorted)-1],
+	}
+}
+
+// quantileSorted returns the q quantile (0->1) of sorted samples
+// using linear interpolation between closest ranks.
+func quantileSorted(sorted []time.Duration, q float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := q * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	if lo == hi {
+		return sorted[lo]
+	}
+	frac := rank - float64(lo)
+	return sorted[lo] + time.Duration(frac*float64(sorted[hi]-sorted[lo]))
+}
@@ -0,0 +1,245 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package aggregate
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/warp/pkg/bench"
+)
+
+func TestLatencyRecorder(t *testing.T) {
+	var l LatencyRecorder
+	if got := l.Percentiles(); got != (LatencyPercentiles{}) {
+		t.Fatalf("empty Percentiles() = %v", got)
+	}
+
+	// Add 1ms -> 10000ms in random order from several goroutines.
+	const n = 10000
+	perm := rand.New(rand.NewSource(1)).Perm(n)
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < n; i += 4 {
+				l.Add(time.Duration(perm[i]+1) * time.Millisecond)
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	got := l.Percentiles()
+	ms := func(f float64) time.Duration { return time.Duration(f * float64(time.Millisecond)) }
+	want := LatencyPercentiles{
+		N:    n,
+		Min:  time.Millisecond,
+		Mean: ms(5000.5),
+		P50:  ms(5000.5),
+		P90:  ms(9000.1),
+		P99:  ms(9900.01),
+		P999: ms(9990.001),
+		Max:  n * time.Millisecond,
+	}
+	tolerance := time.Microsecond
+	check := func(name string, got, want time.Duration) {
+		if d := got - want; d > tolerance || d < -tolerance {
+			t.Errorf("%s = %v, want %v", name, got, want)
+		}
+	}
+	if got.N != want.N {
+		t.Errorf("N = %d, want %d", got.N, want.N)
+	}
+	check("Min", got.Min, want.Min)
+	check("Mean", got.Mean, want.Mean)
+	check("P50", got.P50, want.P50)
+	check("P90", got.P90, want.P90)
+	check("P99", got.P99, want.P99)
+	check("P99.9", got.P999, want.P999)
+	check("Max", got.Max, want.Max)
+
+	var single LatencyRecorder
+	single.Add(time.Second)
+	if p := single.Percentiles(); p.P50 != time.Second || p.P999 != time.Second || p.Mean != time.Second {
+		t.Errorf("single sample: %v", p)
+	}
+}
+
+func TestShardedLatencyRecorder(t *testing.T) {
+	backends := map[string]func() (*LatencyRecorder, error){
+		"exact": nil,
+		"histogram": func() (*LatencyRecorder, error) {
+			return NewHistogramLatencyRecorder(3, time.Minute)
+		},
+	}
+	for name, newShard := range backends {
+		t.Run(name, func(t *testing.T) {
+			combined := &LatencyRecorder{}
+			if newShard != nil {
+				var err error
+				if combined, err = newShard(); err != nil {
+					t.Fatal(err)
+				}
+			}
+			const shards = 5
+			s, err := NewShardedLatencyRecorder(shards, newShard)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rng := rand.New(rand.NewSource(1))
+			samples := make([]time.Duration, 20000)
+			for i := range samples {
+				// Give each shard a different distribution.
+				samples[i] = time.Duration(rng.ExpFloat64()*float64((i%shards+1)*int(time.Millisecond))) + time.Microsecond
+				combined.Add(samples[i])
+			}
+			var wg sync.WaitGroup
+			for w := range shards {
+				wg.Add(1)
+				go func(w int) {
+					defer wg.Done()
+					for i := w; i < len(samples); i += shards {
+						s.Shard(w).Add(samples[i])
+					}
+				}(w)
+			}
+			wg.Wait()
+
+			merged, err := s.Merge()
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := combined.Percentiles()
+			got := merged.Percentiles()
+			// Sums may be added in a different order.
+			if d := got.Mean - want.Mean; d < -time.Nanosecond || d > time.Nanosecond {
+				t.Errorf("mean %v, want %v", got.Mean, want.Mean)
+			}
+			got.Mean = want.Mean
+			if got != want {
+				t.Errorf("merged percentiles differ:\n got %v\nwant %v", got, want)
+			}
+			if one := s.Shard(0).Percentiles(); one.N != len(samples)/shards {
+				t.Errorf("shard 0 has %d samples, want %d", one.N, len(samples)/shards)
+			}
+		})
+	}
+
+	// Backends must match.
+	h, err := NewHistogramLatencyRecorder(3, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Merge(&LatencyRecorder{}); err == nil {
+		t.Error("merged exact recorder into histogram")
+	}
+	h2, err := NewHistogramLatencyRecorder(2, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Merge(h2); err == nil {
+		t.Error("merged histograms with different precision")
+	}
+	if _, err := NewShardedLatencyRecorder(0, nil); err == nil {
+		t.Error("accepted 0 shards")
+	}
+}
+
+func TestLatencyRecorderCorrected(t *testing.T) {
+	// One operation every 10ms for 10s, with a 1s stall.
+	const interval = 10 * time.Millisecond
+	hist, err := NewHistogramLatencyRecorder(3, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hist.ExpectedInterval = interval
+	var naive LatencyRecorder
+	corrected := LatencyRecorder{ExpectedInterval: interval}
+	for i := range 1000 {
+		d := time.Millisecond
+		if i == 500 {
+			d = time.Second
+		}
+		naive.Add(d)
+		corrected.Add(d)
+		hist.Add(d)
+	}
+	n, c, h := naive.Percentiles(), corrected.Percentiles(), hist.Percentiles()
+	// The stall delayed the 99 operations that should have completed meanwhile.
+	if c.N != 1099 || h.N != 1099 {
+		t.Errorf("corrected sample count %d and %d, want 1099", c.N, h.N)
+	}
+	if n.P99 != time.Millisecond {
+		t.Errorf("naive p99 = %v, want 1ms", n.P99)
+	}
+	// About 9% of the corrected samples are stalled, p99 is near the full stall.
+	if c.P99 < 800*time.Millisecond {
+		t.Errorf("corrected p99 %v, want > 800ms", c.P99)
+	}
+	if d := h.P99 - c.P99; d > c.P99/50 || d < -c.P99/50 {
+		t.Errorf("histogram p99 %v, exact %v", h.P99, c.P99)
+	}
+	if c.Max != time.Second || c.Min != time.Millisecond {
+		t.Errorf("corrected min %v, max %v", c.Min, c.Max)
+	}
+
+	// Durations within the interval are unchanged.
+	var fast LatencyRecorder
+	fast.ExpectedInterval = interval
+	fast.Add(interval)
+	fast.Add(interval - 1)
+	if p := fast.Percentiles(); p.N != 2 {
+		t.Errorf("%d samples, want 2", p.N)
+	}
+
+	// A long stall adds a bounded number of samples.
+	stalled := LatencyRecorder{ExpectedInterval: time.Microsecond}
+	stalled.Add(time.Hour)
+	if p := stalled.Percentiles(); p.N != maxCorrectionSamples+1 || p.Min != time.Hour-maxCorrectionSamples*time.Microsecond {
+		t.Errorf("long stall: %d samples, min %v", p.N, p.Min)
+	}
+}
+
+func TestAggregateExpectedInterval(t *testing.T) {
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	var ops bench.Operations
+	for i := range 1000 {
+		d := time.Millisecond
+		if i == 500 {
+			d = time.Second
+		}
+		at := start.Add(time.Duration(i) * 10 * time.Millisecond)
+		ops = append(ops, bench.Operation{OpType: "GET", ObjPerOp: 1, Size: 100, Start: at, End: at.Add(d), Endpoint: "host"})
+	}
+	opts := Options{DurFunc: func(time.Duration) time.Duration { return time.Second }, Prefiltered: true}
+	if a := Aggregate(ops, opts); len(a.Operations) != 1 || a.Operations[0].CorrectedLatency != nil {
+		t.Fatalf("unexpected corrected latency without interval: %+v", a.Operations)
+	}
+	opts.ExpectedInterval = 10 * time.Millisecond
+	a := Aggregate(ops, opts)
+	if len(a.Operations) != 1 {
+		t.Fatalf("got %d operations", len(a.Operations))
+	}
+	got := a.Operations[0].CorrectedLatency
+	if got == nil || got.N != 1099 || got.P99 < 800*time.Millisecond {
+		t.Errorf("corrected latency %v", got)
+	}
+}
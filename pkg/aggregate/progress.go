@@ -0,0 +1,216 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package aggregate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/minio/warp/pkg/bench"
+)
+
+// ProgressCounters keeps running totals of operations for progress reporting.
+// It is safe for concurrent use.
+type ProgressCounters struct {
+	mu     sync.Mutex
+	ops    int64
+	bytes  int64
+	errors int64
+	total  int64
+	// latencies since the last snapshot.
+	window []time.Duration
+}
+
+// Add an operation to the counters.
+func (p *ProgressCounters) Add(op bench.Operation) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.ops++
+	if op.Err != "" {
+		p.errors++
+		return
+	}
+	p.bytes += op.Size
+	p.window = append(p.window, op.Duration())
+}
+
+// SetTotal sets the number of operations the run is expected to do.
+// When set, updates include the percent complete and an estimated time remaining.
+func (p *ProgressCounters) SetTotal(n int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.total = n
+}
+
+// Snapshot returns the current totals.
+// Latency percentiles are calculated from operations added since the previous snapshot.
+func (p *ProgressCounters) Snapshot() ProgressSnapshot {
+	p.mu.Lock()
+	s := ProgressSnapshot{
+		Time:   time.Now(),
+		Ops:    p.ops,
+		Bytes:  p.bytes,
+		Errors: p.errors,
+		Total:  p.total,
+	}
+	window := p.window
+	p.window = nil
+	p.mu.Unlock()
+
+	slices.Sort(window)
+	s.Latency = percentilesSorted(window)
+	return s
+}
+
+// ProgressSnapshot is a consistent view of ProgressCounters.
+type ProgressSnapshot struct {
+	Time    time.Time
+	Ops     int64
+	Bytes   int64
+	Errors  int64
+	Latency LatencyPercentiles
+
+	// Total is the expected number of operations, 0 if unknown.
+	Total int64
+}
+
+// ProgressUpdate is sent for every tick of a progress reporter.
+type ProgressUpdate struct {
+	ProgressSnapshot
+
+	// BytesPerSec and OpsPerSec are measured since the previous update.
+	BytesPerSec float64
+	OpsPerSec   float64
+
+	// Percent is the percent of Total done, if Total is set.
+	Percent float64
+
+	// ETA is the estimated time until Total operations are done,
+	// based on the rate over the last etaWindow updates.
+	// It is 0 if Total is not set or no progress was made.
+	ETA time.Duration
+}
+
+// etaWindow is the number of updates the ETA rate is averaged over.
+const etaWindow = 10
+
+// String returns the update as a single line,
+// for example "42.1k ops, 12.50GiB/s, 1400.0 ops/s, p99 18ms".
+// If a total is set, the percent done and ETA are added, for example "42.1%, ETA 1m20s".
+func (u ProgressUpdate) String() string {
+	s := fmt.Sprintf("%s ops, %v, %.1f ops/s", formatCount(u.Ops), bench.Throughput(u.BytesPerSec), u.OpsPerSec)
+	if u.Latency.N > 0 {
+		s += fmt.Sprintf(", p99 %v", u.Latency.P99.Round(100*time.Microsecond))
+	}
+	if u.Errors > 0 {
+		s += fmt.Sprintf(", %s errors", formatCount(u.Errors))
+	}
+	if u.Total > 0 {
+		s += fmt.Sprintf(", %.1f%%", u.Percent)
+		if u.ETA > 0 {
+			s += fmt.Sprintf(", ETA %v", u.ETA.Round(time.Second))
+		}
+	}
+	return s
+}
+
+// estimate returns the percent of the total done at the last snapshot in window,
+// and the time remaining at the average rate over window.
+// window must be ordered by time.
+func estimate(window []ProgressSnapshot) (percent float64, eta time.Duration) {
+	if len(window) == 0 {
+		return 0, 0
+	}
+	last := window[len(window)-1]
+	if last.Total <= 0 {
+		return 0, 0
+	}
+	percent = min(100*float64(last.Ops)/float64(last.Total), 100)
+	remaining := last.Total - last.Ops
+	first := window[0]
+	secs := last.Time.Sub(first.Time).Seconds()
+	if remaining <= 0 || secs <= 0 || last.Ops <= first.Ops {
+		return percent, 0
+	}
+	rate := float64(last.Ops-first.Ops) / secs
+	return percent, time.Duration(float64(remaining) / rate * float64(time.Second))
+}
+
+// formatCount returns n with a k/M/G suffix.
+func formatCount(n int64) string {
+	switch {
+	case n < 1e3:
+		return fmt.Sprint(n)
+	case n < 1e6:
+		return fmt.Sprintf("%.1fk", float64(n)/1e3)
+	case n < 1e9:
+		return fmt.Sprintf("%.1fM", float64(n)/1e6)
+	}
+	return fmt.Sprintf("%.1fG", float64(n)/1e9)
+}
+
+// Progress sends an update from p to sink every interval until ctx is canceled.
+// The sink will be closed when Progress returns.
+func Progress(ctx context.Context, p *ProgressCounters, interval time.Duration, sink chan<- ProgressUpdate) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	progress(ctx, p, t.C, sink)
+}
+
+// PrintProgress writes a line to w every interval until ctx is canceled.
+func PrintProgress(ctx context.Context, p *ProgressCounters, interval time.Duration, w io.Writer) {
+	updates := make(chan ProgressUpdate)
+	go Progress(ctx, p, interval, updates)
+	for u := range updates {
+		fmt.Fprintln(w, u)
+	}
+}
+
+func progress(ctx context.Context, p *ProgressCounters, tick <-chan time.Time, sink chan<- ProgressUpdate) {
+	defer close(sink)
+	prev := p.Snapshot()
+	window := []ProgressSnapshot{prev}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick:
+		}
+		s := p.Snapshot()
+		u := ProgressUpdate{ProgressSnapshot: s}
+		if secs := s.Time.Sub(prev.Time).Seconds(); secs > 0 {
+			u.BytesPerSec = float64(s.Bytes-prev.Bytes) / secs
+			u.OpsPerSec = float64(s.Ops-prev.Ops) / secs
+		}
+		prev = s
+		if len(window) > etaWindow {
+			window = window[1:]
+		}
+		window = append(window, s)
+		u.Percent, u.ETA = estimate(window)
+		select {
+		case sink <- u:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
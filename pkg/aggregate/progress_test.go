@@ -0,0 +1,173 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package aggregate
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/warp/pkg/bench"
+)
+
+func TestProgress(t *testing.T) {
+	var p ProgressCounters
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Update counters concurrently while snapshots are taken.
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				p.Add(bench.Operation{Start: start, End: start.Add(time.Millisecond), Size: 1000})
+			}
+		}()
+	}
+
+	tick := make(chan time.Time)
+	sink := make(chan ProgressUpdate)
+	go progress(ctx, &p, tick, sink)
+
+	const ticks = 5
+	var prev int64
+	for range ticks {
+		tick <- time.Now()
+		u := <-sink
+		// No torn reads: bytes always match successful ops.
+		if u.Bytes != u.Ops*1000 {
+			t.Errorf("inconsistent snapshot: %d ops, %d bytes", u.Ops, u.Bytes)
+		}
+		if u.Ops < prev {
+			t.Errorf("ops went backwards: %d -> %d", prev, u.Ops)
+		}
+		prev = u.Ops
+	}
+	close(stop)
+	wg.Wait()
+
+	cancel()
+	if _, ok := <-sink; ok {
+		t.Error("sink not closed after cancel")
+	}
+}
+
+func TestPrintProgress(t *testing.T) {
+	var p ProgressCounters
+	now := time.Now()
+	for range 1500 {
+		p.Add(bench.Operation{Start: now, End: now.Add(18 * time.Millisecond), Size: 1 << 20})
+	}
+	p.Add(bench.Operation{Err: "failed"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 55*time.Millisecond)
+	defer cancel()
+	var buf bytes.Buffer
+	PrintProgress(ctx, &p, 10*time.Millisecond, &buf)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) < 3 || len(lines) > 6 {
+		t.Errorf("got %d lines, want about 5:\n%s", len(lines), buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "1.5k ops, ") || !strings.HasSuffix(lines[0], ", 1 errors") {
+		t.Errorf("unexpected line %q", lines[0])
+	}
+	u := ProgressUpdate{
+		ProgressSnapshot: ProgressSnapshot{Ops: 42100, Latency: LatencyPercentiles{N: 1, P99: 18 * time.Millisecond}},
+		BytesPerSec:      12.5 * (1 << 30),
+		OpsPerSec:        1400,
+	}
+	if got, want := u.String(), "42.1k ops, 12.50GiB/s, 1400.0 ops/s, p99 18ms"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestProgressETA(t *testing.T) {
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	snap := func(sec, ops int64) ProgressSnapshot {
+		return ProgressSnapshot{Time: start.Add(time.Duration(sec) * time.Second), Ops: ops, Total: 1000}
+	}
+	tests := []struct {
+		name    string
+		window  []ProgressSnapshot
+		percent float64
+		eta     time.Duration
+	}{
+		{name: "empty"},
+		{name: "first", window: []ProgressSnapshot{snap(0, 0)}},
+		{name: "steady", window: []ProgressSnapshot{snap(0, 0), snap(1, 100), snap(2, 200)}, percent: 20, eta: 8 * time.Second},
+		// Only the rate within the window is used.
+		{name: "sped-up", window: []ProgressSnapshot{snap(10, 500), snap(11, 700)}, percent: 70, eta: 1500 * time.Millisecond},
+		{name: "stalled", window: []ProgressSnapshot{snap(0, 300), snap(5, 300)}, percent: 30},
+		{name: "done", window: []ProgressSnapshot{snap(0, 900), snap(1, 1000)}, percent: 100},
+		{name: "overrun", window: []ProgressSnapshot{snap(0, 900), snap(1, 1200)}, percent: 100},
+		{name: "no-total", window: []ProgressSnapshot{{Time: start, Ops: 1}, {Time: start.Add(time.Second), Ops: 2}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			percent, eta := estimate(test.window)
+			if percent != test.percent || eta != test.eta {
+				t.Errorf("got %v%%, ETA %v, want %v%%, ETA %v", percent, eta, test.percent, test.eta)
+			}
+		})
+	}
+
+	u := ProgressUpdate{
+		ProgressSnapshot: ProgressSnapshot{Ops: 421, Total: 1000},
+		OpsPerSec:        10,
+		Percent:          42.1,
+		ETA:              80 * time.Second,
+	}
+	if got, want := u.String(), "421 ops, 0B/s, 10.0 ops/s, 42.1%, ETA 1m20s"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// A total set on the counters is reported by updates.
+	var p ProgressCounters
+	p.SetTotal(10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tick := make(chan time.Time)
+	sink := make(chan ProgressUpdate)
+	go progress(ctx, &p, tick, sink)
+	// Wait for the first snapshot.
+	tick <- time.Now()
+	<-sink
+	for i := range 5 {
+		p.Add(bench.Operation{})
+		tick <- time.Now()
+		u := <-sink
+		if u.Total != 10 || u.Percent != float64(10*(i+1)) {
+			t.Errorf("update %d: total %d, %v%%", i, u.Total, u.Percent)
+		}
+		if u.ETA <= 0 {
+			t.Errorf("update %d: no ETA", i)
+		}
+	}
+}
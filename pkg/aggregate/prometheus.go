@@ -0,0 +1,202 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package aggregate
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/warp/pkg/bench"
+)
+
+// metricBuckets are the upper bounds in seconds of the latency histogram buckets.
+var metricBuckets = []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Metrics keeps counters of operations for export in the Prometheus text format.
+// It is safe for concurrent use.
+type Metrics struct {
+	mu     sync.Mutex
+	byType map[string]*opMetrics
+}
+
+type opMetrics struct {
+	ok, errors int64
+	bytes      int64
+	duration   histogram
+	// firstByte holds the time to first byte of operations that record it.
+	firstByte histogram
+}
+
+// histogram counts durations in metricBuckets.
+type histogram struct {
+	// buckets holds the number of operations in each bucket, not cumulative.
+	buckets []int64
+	sum     float64
+	count   int64
+}
+
+// add records a duration.
+func (h *histogram) add(d time.Duration) {
+	if h.buckets == nil {
+		h.buckets = make([]int64, len(metricBuckets)+1)
+	}
+	secs := d.Seconds()
+	idx, _ := slices.BinarySearch(metricBuckets, secs)
+	h.buckets[idx]++
+	h.sum += secs
+	h.count++
+}
+
+// write writes the histogram samples of name with the type label typ.
+func (h *histogram) write(w io.Writer, name, typ string) {
+	var cum int64
+	for i, le := range metricBuckets {
+		if h.buckets != nil {
+			cum += h.buckets[i]
+		}
+		fmt.Fprintf(w, "%s_bucket{type=%s,le=%q} %d\n", name, labelValue(typ), strconv.FormatFloat(le, 'g', -1, 64), cum)
+	}
+	fmt.Fprintf(w, "%s_bucket{type=%s,le=\"+Inf\"} %d\n", name, labelValue(typ), h.count)
+	fmt.Fprintf(w, "%s_sum{type=%s} %s\n", name, labelValue(typ), strconv.FormatFloat(h.sum, 'g', -1, 64))
+	fmt.Fprintf(w, "%s_count{type=%s} %d\n", name, labelValue(typ), h.count)
+}
+
+// NewMetrics returns empty metrics.
+func NewMetrics() *Metrics {
+	return &Metrics{byType: make(map[string]*opMetrics)}
+}
+
+// Add records op.
+// Bytes are only counted for successful operations.
+func (m *Metrics) Add(op bench.Operation) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	om := m.byType[op.OpType]
+	if om == nil {
+		om = &opMetrics{}
+		m.byType[op.OpType] = om
+	}
+	if op.Err != "" {
+		om.errors++
+	} else {
+		om.ok++
+		om.bytes += op.Size
+	}
+	om.duration.add(op.Duration())
+	if op.FirstByte != nil {
+		om.firstByte.add(op.FirstByte.Sub(op.Start))
+	}
+}
+
+// WriteTo writes the metrics to w in the Prometheus text exposition format.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	cw := &countWriter{w: bufio.NewWriter(w)}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	types := slices.Sorted(maps.Keys(m.byType))
+
+	fmt.Fprintln(cw, "# HELP warp_operations_total Number of completed operations.")
+	fmt.Fprintln(cw, "# TYPE warp_operations_total counter")
+	for _, typ := range types {
+		om := m.byType[typ]
+		fmt.Fprintf(cw, "warp_operations_total{type=%s,status=\"ok\"} %d\n", labelValue(typ), om.ok)
+		fmt.Fprintf(cw, "warp_operations_total{type=%s,status=\"error\"} %d\n", labelValue(typ), om.errors)
+	}
+
+	fmt.Fprintln(cw, "# HELP warp_operation_duration_seconds Duration of operations.")
+	fmt.Fprintln(cw, "# TYPE warp_operation_duration_seconds histogram")
+	for _, typ := range types {
+		m.byType[typ].duration.write(cw, "warp_operation_duration_seconds", typ)
+	}
+
+	fmt.Fprintln(cw, "# HELP warp_first_byte_seconds Time from sending the request to receiving the first byte of the response body.")
+	fmt.Fprintln(cw, "# TYPE warp_first_byte_seconds histogram")
+	for _, typ := range types {
+		if om := m.byType[typ]; om.firstByte.count > 0 {
+			om.firstByte.write(cw, "warp_first_byte_seconds", typ)
+		}
+	}
+
+	fmt.Fprintln(cw, "# HELP warp_bytes_transferred Bytes transferred by successful operations.")
+	fmt.Fprintln(cw, "# TYPE warp_bytes_transferred gauge")
+	for _, typ := range types {
+		fmt.Fprintf(cw, "warp_bytes_transferred{type=%s} %d\n", labelValue(typ), m.byType[typ].bytes)
+	}
+	if cw.err != nil {
+		return cw.n, cw.err
+	}
+	return cw.n, cw.w.Flush()
+}
+
+// ServeHTTP writes the metrics as a response.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WriteTo(w)
+}
+
+// ServeMetrics serves m on addr at /metrics until ctx is canceled.
+// The listening address is returned, which is useful if the port of addr is 0.
+func ServeMetrics(ctx context.Context, addr string, m *Metrics) (net.Addr, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m)
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go srv.Serve(l)
+	go func() {
+		<-ctx.Done()
+		sctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		srv.Shutdown(sctx)
+	}()
+	return l.Addr(), nil
+}
+
+// labelValue returns v quoted as a label value.
+func labelValue(v string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v) + `"`
+}
+
+// countWriter counts the bytes written and keeps the first error.
+type countWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
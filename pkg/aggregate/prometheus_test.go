@@ -0,0 +1,118 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package aggregate
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/warp/pkg/bench"
+)
+
+func TestMetrics(t *testing.T) {
+	m := NewMetrics()
+	ctx, cancel := context.WithCancel(context.Background())
+	addr, err := ServeMetrics(ctx, "127.0.0.1:0", m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	op := func(typ string, d time.Duration, size int64, err string) bench.Operation {
+		return bench.Operation{OpType: typ, Start: start, End: start.Add(d), Size: size, Err: err}
+	}
+	firstByte := func(o bench.Operation, d time.Duration) bench.Operation {
+		t := o.Start.Add(d)
+		o.FirstByte = &t
+		return o
+	}
+	for _, o := range []bench.Operation{
+		op("GET", 2*time.Millisecond, 100, ""),
+		firstByte(op("GET", 20*time.Millisecond, 200, ""), 4*time.Millisecond),
+		op("GET", 3*time.Second, 300, "timeout"),
+		op("PUT", 100*time.Millisecond, 1000, ""),
+		op("PUT", 20*time.Second, 1000, ""),
+	} {
+		m.Add(o)
+	}
+
+	resp, err := http.Get("http://" + addr.String() + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("content type %q", ct)
+	}
+	body := string(b)
+	for _, want := range []string{
+		"# TYPE warp_operations_total counter",
+		`warp_operations_total{type="GET",status="ok"} 2`,
+		`warp_operations_total{type="GET",status="error"} 1`,
+		`warp_operations_total{type="PUT",status="ok"} 2`,
+		`warp_operations_total{type="PUT",status="error"} 0`,
+		"# TYPE warp_operation_duration_seconds histogram",
+		`warp_operation_duration_seconds_bucket{type="GET",le="0.001"} 0`,
+		`warp_operation_duration_seconds_bucket{type="GET",le="0.0025"} 1`,
+		`warp_operation_duration_seconds_bucket{type="GET",le="0.025"} 2`,
+		`warp_operation_duration_seconds_bucket{type="GET",le="2.5"} 2`,
+		`warp_operation_duration_seconds_bucket{type="GET",le="5"} 3`,
+		`warp_operation_duration_seconds_bucket{type="GET",le="+Inf"} 3`,
+		`warp_operation_duration_seconds_count{type="GET"} 3`,
+		`warp_operation_duration_seconds_sum{type="GET"} 3.022`,
+		`warp_operation_duration_seconds_bucket{type="PUT",le="0.1"} 1`,
+		`warp_operation_duration_seconds_bucket{type="PUT",le="10"} 1`,
+		`warp_operation_duration_seconds_bucket{type="PUT",le="+Inf"} 2`,
+		"# TYPE warp_first_byte_seconds histogram",
+		`warp_first_byte_seconds_bucket{type="GET",le="0.0025"} 0`,
+		`warp_first_byte_seconds_bucket{type="GET",le="0.005"} 1`,
+		`warp_first_byte_seconds_count{type="GET"} 1`,
+		"# TYPE warp_bytes_transferred gauge",
+		`warp_bytes_transferred{type="GET"} 300`,
+		`warp_bytes_transferred{type="PUT"} 2000`,
+	} {
+		if !strings.Contains(body, want+"\n") {
+			t.Errorf("missing %q in:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, `warp_first_byte_seconds_count{type="PUT"}`) {
+		t.Errorf("unexpected PUT first byte metrics in:\n%s", body)
+	}
+
+	// The server stops with the context.
+	cancel()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		resp, err := http.Get("http://" + addr.String() + "/metrics")
+		if err != nil {
+			break
+		}
+		resp.Body.Close()
+		if time.Now().After(deadline) {
+			t.Fatal("server still running after cancel")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
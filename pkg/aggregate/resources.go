@@ -0,0 +1,190 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package aggregate
+
+import (
+	"context"
+	"fmt"
+	"runtime/metrics"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+)
+
+// resourceMetrics are the runtime metrics read by ResourceSampler.
+var resourceMetrics = []string{
+	"/cpu/classes/total:cpu-seconds",
+	"/cpu/classes/idle:cpu-seconds",
+	"/memory/classes/heap/objects:bytes",
+	"/sched/goroutines:goroutines",
+	"/sched/gomaxprocs:threads",
+}
+
+// ResourceSample is the resource usage of the process at one point in time.
+type ResourceSample struct {
+	Time time.Time
+	// CPU is the number of cores used since the previous sample with CPU set.
+	// The runtime updates CPU time at every garbage collection,
+	// so CPU is only set if one has run since the previous sample.
+	CPU        float64
+	CPUValid   bool
+	HeapBytes  uint64
+	Goroutines uint64
+}
+
+// ResourceSampler samples the CPU, heap and goroutine use of the process,
+// so it can be seen whether the client limited the benchmark.
+// It is safe for concurrent use.
+type ResourceSampler struct {
+	mu      sync.Mutex
+	read    []metrics.Sample
+	samples []ResourceSample
+	// CPU seconds used and available at the last CPU update.
+	used, total float64
+}
+
+// NewResourceSampler returns a sampler.
+// CPU use of the first sample is measured from this call.
+func NewResourceSampler() *ResourceSampler {
+	r := &ResourceSampler{read: make([]metrics.Sample, len(resourceMetrics))}
+	for i, name := range resourceMetrics {
+		r.read[i].Name = name
+	}
+	metrics.Read(r.read)
+	r.used, r.total = r.cpu()
+	return r
+}
+
+// cpu returns the CPU seconds used and available from the last read.
+// r.mu must be held or r not shared.
+func (r *ResourceSampler) cpu() (used, total float64) {
+	total, idle := r.float(0), r.float(1)
+	return total - idle, total
+}
+
+func (r *ResourceSampler) float(i int) float64 {
+	if r.read[i].Value.Kind() != metrics.KindFloat64 {
+		return 0
+	}
+	return r.read[i].Value.Float64()
+}
+
+func (r *ResourceSampler) uint(i int) uint64 {
+	if r.read[i].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return r.read[i].Value.Uint64()
+}
+
+// Sample reads the current resource usage and adds it to the samples.
+func (r *ResourceSampler) Sample() ResourceSample {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	metrics.Read(r.read)
+	s := ResourceSample{
+		Time:       time.Now(),
+		HeapBytes:  r.uint(2),
+		Goroutines: r.uint(3),
+	}
+	used, total := r.cpu()
+	if total > r.total {
+		s.CPU = (used - r.used) / (total - r.total) * float64(r.uint(4))
+		s.CPUValid = true
+		r.used, r.total = used, total
+	}
+	r.samples = append(r.samples, s)
+	return s
+}
+
+// Run samples every interval until ctx is canceled.
+// Use the progress interval to sample with progress updates.
+func (r *ResourceSampler) Run(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			r.Sample()
+		}
+	}
+}
+
+// Samples returns a copy of all samples.
+func (r *ResourceSampler) Samples() []ResourceSample {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]ResourceSample(nil), r.samples...)
+}
+
+// MinMaxAvg is the minimum, maximum and average of a number of values.
+type MinMaxAvg struct {
+	N   int     `json:"n"`
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+	Avg float64 `json:"avg"`
+}
+
+func (m *MinMaxAvg) add(v float64) {
+	if m.N == 0 || v < m.Min {
+		m.Min = v
+	}
+	if m.N == 0 || v > m.Max {
+		m.Max = v
+	}
+	m.Avg += (v - m.Avg) / float64(m.N+1)
+	m.N++
+}
+
+// ResourceStats summarizes resource samples.
+type ResourceStats struct {
+	// CPU is the number of cores used.
+	CPU        MinMaxAvg `json:"cpu_cores"`
+	Heap       MinMaxAvg `json:"heap_bytes"`
+	Goroutines MinMaxAvg `json:"goroutines"`
+}
+
+// Stats returns the minimum, maximum and average of all samples.
+func (r *ResourceSampler) Stats() ResourceStats {
+	var s ResourceStats
+	for _, sample := range r.Samples() {
+		if sample.CPUValid {
+			s.CPU.add(sample.CPU)
+		}
+		s.Heap.add(float64(sample.HeapBytes))
+		s.Goroutines.add(float64(sample.Goroutines))
+	}
+	return s
+}
+
+// String returns the stats as a single line.
+func (s ResourceStats) String() string {
+	if s.Goroutines.N == 0 {
+		return "no samples"
+	}
+	cpu := "CPU: unknown"
+	if s.CPU.N > 0 {
+		cpu = fmt.Sprintf("CPU: avg %.2f cores, min %.2f, max %.2f", s.CPU.Avg, s.CPU.Min, s.CPU.Max)
+	}
+	return fmt.Sprintf("%s. Heap: avg %s, min %s, max %s. Goroutines: avg %.0f, min %.0f, max %.0f",
+		cpu,
+		humanize.IBytes(uint64(s.Heap.Avg)), humanize.IBytes(uint64(s.Heap.Min)), humanize.IBytes(uint64(s.Heap.Max)),
+		s.Goroutines.Avg, s.Goroutines.Min, s.Goroutines.Max)
+}
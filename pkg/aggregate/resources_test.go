@@ -0,0 +1,78 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package aggregate
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestResourceSampler(t *testing.T) {
+	r := NewResourceSampler()
+	if got := r.Stats().String(); got != "no samples" {
+		t.Errorf("empty stats: %q", got)
+	}
+
+	// Keep a few goroutines busy allocating, so the runtime updates CPU time.
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var keep [][]byte
+			for ctx.Err() == nil {
+				keep = append(keep, make([]byte, 64<<10))
+				if len(keep) > 100 {
+					keep = keep[:0]
+				}
+			}
+		}()
+	}
+	r.Run(ctx, 20*time.Millisecond)
+	wg.Wait()
+
+	samples := r.Samples()
+	if len(samples) < 5 {
+		t.Fatalf("got %d samples", len(samples))
+	}
+	for i, s := range samples {
+		if s.Time.IsZero() || s.HeapBytes == 0 || s.Goroutines < 5 {
+			t.Errorf("sample %d: implausible %+v", i, s)
+		}
+		if s.CPUValid && (s.CPU < 0 || s.CPU > float64(runtime.GOMAXPROCS(0))+0.01) {
+			t.Errorf("sample %d: %.2f cores used", i, s.CPU)
+		}
+	}
+
+	st := r.Stats()
+	if st.Goroutines.N != len(samples) || st.Heap.Min > st.Heap.Avg || st.Heap.Avg > st.Heap.Max {
+		t.Errorf("unexpected stats %+v", st)
+	}
+	if st.CPU.N == 0 || st.CPU.Max <= 0 {
+		t.Errorf("no CPU use measured: %+v", st.CPU)
+	}
+	if s := st.String(); !strings.Contains(s, "cores") || !strings.Contains(s, "Goroutines: avg") {
+		t.Errorf("unexpected string %q", s)
+	}
+}
@@ -0,0 +1,336 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package aggregate
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/minio/warp/pkg/bench"
+)
+
+// Results is a machine readable summary of a benchmark run.
+type Results struct {
+	// Config contains the run configuration, for example flags.
+	Config map[string]string `json:"config,omitempty"`
+
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+
+	// Operations contains results by operation type, sorted by type.
+	Operations []OpResults `json:"operations"`
+}
+
+// BytesUploaded returns the bytes uploaded by all operation types.
+func (r Results) BytesUploaded() int64 {
+	var n int64
+	for _, o := range r.Operations {
+		n += o.BytesUploaded
+	}
+	return n
+}
+
+// BytesDownloaded returns the bytes downloaded by all operation types.
+func (r Results) BytesDownloaded() int64 {
+	var n int64
+	for _, o := range r.Operations {
+		n += o.BytesDownloaded
+	}
+	return n
+}
+
+// GoodputGiBPerSec returns the bytes transferred in either direction
+// divided by the wall time of the run, in GiB per second.
+func (r Results) GoodputGiBPerSec() float64 {
+	return goodput(r.BytesUploaded()+r.BytesDownloaded(), r.End.Sub(r.Start))
+}
+
+// OpResults contains the results of a single operation type.
+type OpResults struct {
+	OpType   string `json:"type"`
+	Requests int    `json:"requests"`
+	Objects  int    `json:"objects"`
+	Errors   int    `json:"errors"`
+	Bytes    int64  `json:"bytes"`
+
+	// BytesUploaded and BytesDownloaded count payload bytes of successful
+	// operations that send or receive object data.
+	// Other operation types, like STAT or DELETE, count neither.
+	BytesUploaded   int64 `json:"bytes_uploaded"`
+	BytesDownloaded int64 `json:"bytes_downloaded"`
+
+	// ErrorsByCategory counts errors by bench.ErrorCategory name.
+	ErrorsByCategory map[string]int `json:"errors_by_category,omitempty"`
+
+	// Retries is the number of retries of all operations, failed or not.
+	Retries int `json:"retries"`
+
+	// Duration is the time between the first operation starting and the last ending.
+	Duration time.Duration `json:"duration"`
+
+	Latency LatencyPercentiles `json:"latency"`
+}
+
+// BytesPerSec returns the average throughput in bytes per second.
+func (o OpResults) BytesPerSec() float64 {
+	if o.Duration <= 0 {
+		return 0
+	}
+	return float64(o.Bytes) / o.Duration.Seconds()
+}
+
+// GoodputGiBPerSec returns the bytes transferred in either direction
+// divided by Duration, in GiB per second.
+func (o OpResults) GoodputGiBPerSec() float64 {
+	return goodput(o.BytesUploaded+o.BytesDownloaded, o.Duration)
+}
+
+// ObjectsPerSec returns the average number of objects per second.
+func (o OpResults) ObjectsPerSec() float64 {
+	if o.Duration <= 0 {
+		return 0
+	}
+	return float64(o.Objects) / o.Duration.Seconds()
+}
+
+// goodput returns n bytes over d in GiB per second.
+func goodput(n int64, d time.Duration) float64 {
+	if d <= 0 {
+		return 0
+	}
+	return float64(n) / (1 << 30) / d.Seconds()
+}
+
+// transferDirection returns whether operations of type typ upload or download object data.
+func transferDirection(typ string) (upload, download bool) {
+	switch typ {
+	case http.MethodPut, http.MethodPost, "PUTPART", "APPEND":
+		return true, false
+	case "COPY":
+		// Data is written by the server.
+		return true, false
+	case http.MethodGet:
+		return false, true
+	}
+	return false, false
+}
+
+// ResultsFromOperations summarizes ops by operation type.
+func ResultsFromOperations(ops bench.Operations, config map[string]string) Results {
+	return ResultsFromOperationsOpts(ops, config, Options{})
+}
+
+// ResultsFromOperationsOpts summarizes ops by operation type.
+// Only the latency options of opts are used.
+func ResultsFromOperationsOpts(ops bench.Operations, config map[string]string, opts Options) Results {
+	r := Results{Config: config}
+	r.Start, r.End = ops.TimeRange()
+	byType := ops.SortSplitByOpType()
+	for _, typ := range slices.Sorted(maps.Keys(byType)) {
+		ops := byType[typ]
+		res := OpResults{OpType: typ}
+		upload, download := transferDirection(typ)
+		lat := LatencyRecorder{ExpectedInterval: opts.ExpectedInterval}
+		for _, op := range ops {
+			res.Requests++
+			res.Retries += op.Retries
+			if op.Err != "" {
+				res.Errors++
+				if res.ErrorsByCategory == nil {
+					res.ErrorsByCategory = make(map[string]int)
+				}
+				res.ErrorsByCategory[op.ErrCategory().String()]++
+				continue
+			}
+			res.Objects += op.ObjPerOp
+			res.Bytes += op.Size
+			if upload {
+				res.BytesUploaded += op.Size
+			}
+			if download {
+				res.BytesDownloaded += op.Size
+			}
+			lat.Add(op.Duration())
+		}
+		start, end := ops.TimeRange()
+		res.Duration = end.Sub(start)
+		res.Latency = lat.Percentiles()
+		r.Operations = append(r.Operations, res)
+	}
+	return r
+}
+
+// resultsUnits describes the units used by WriteJSON, keyed by field name suffix.
+var resultsUnits = map[string]string{
+	"bytes":           "bytes",
+	"millis":          "milliseconds",
+	"bytes_per_sec":   "bytes/second",
+	"objects_per_sec": "objects/second",
+	"gib_per_sec":     "GiB/second",
+}
+
+type jsonLatency struct {
+	N    int     `json:"n"`
+	Min  float64 `json:"min_millis"`
+	Mean float64 `json:"mean_millis"`
+	P50  float64 `json:"p50_millis"`
+	P90  float64 `json:"p90_millis"`
+	P99  float64 `json:"p99_millis"`
+	P999 float64 `json:"p99_9_millis"`
+	Max  float64 `json:"max_millis"`
+}
+
+type jsonOpResults struct {
+	OpType           string         `json:"type"`
+	Requests         int            `json:"requests"`
+	Objects          int            `json:"objects"`
+	Errors           int            `json:"errors"`
+	ErrorsByCategory map[string]int `json:"errors_by_category,omitempty"`
+	Retries          int            `json:"retries"`
+	Bytes            int64          `json:"bytes"`
+	BytesUploaded    int64          `json:"bytes_uploaded"`
+	BytesDownloaded  int64          `json:"bytes_downloaded"`
+	DurationMS       float64        `json:"duration_millis"`
+	BytesPerSec      float64        `json:"throughput_bytes_per_sec"`
+	ObjectsPerSec    float64        `json:"throughput_objects_per_sec"`
+	Goodput          float64        `json:"goodput_gib_per_sec"`
+	Latency          jsonLatency    `json:"latency"`
+}
+
+type jsonResults struct {
+	Units           map[string]string `json:"units"`
+	Config          map[string]string `json:"config,omitempty"`
+	Start           time.Time         `json:"start"`
+	End             time.Time         `json:"end"`
+	BytesUploaded   int64             `json:"bytes_uploaded"`
+	BytesDownloaded int64             `json:"bytes_downloaded"`
+	Goodput         float64           `json:"goodput_gib_per_sec"`
+	Operations      []jsonOpResults   `json:"operations"`
+}
+
+// WriteJSON writes r as indented JSON.
+// Units are included in field names and listed in the "units" object.
+func WriteJSON(w io.Writer, r Results) error {
+	out := jsonResults{
+		Units:           resultsUnits,
+		Config:          r.Config,
+		Start:           r.Start,
+		End:             r.End,
+		BytesUploaded:   r.BytesUploaded(),
+		BytesDownloaded: r.BytesDownloaded(),
+		Goodput:         r.GoodputGiBPerSec(),
+		Operations:      make([]jsonOpResults, 0, len(r.Operations)),
+	}
+	for _, o := range r.Operations {
+		out.Operations = append(out.Operations, jsonOpResults{
+			OpType:           o.OpType,
+			Requests:         o.Requests,
+			Objects:          o.Objects,
+			Errors:           o.Errors,
+			ErrorsByCategory: o.ErrorsByCategory,
+			Retries:          o.Retries,
+			Bytes:            o.Bytes,
+			BytesUploaded:    o.BytesUploaded,
+			BytesDownloaded:  o.BytesDownloaded,
+			DurationMS:       durToMillisF(o.Duration),
+			BytesPerSec:      o.BytesPerSec(),
+			ObjectsPerSec:    o.ObjectsPerSec(),
+			Goodput:          o.GoodputGiBPerSec(),
+			Latency: jsonLatency{
+				N:    o.Latency.N,
+				Min:  durToMillisF(o.Latency.Min),
+				Mean: durToMillisF(o.Latency.Mean),
+				P50:  durToMillisF(o.Latency.P50),
+				P90:  durToMillisF(o.Latency.P90),
+				P99:  durToMillisF(o.Latency.P99),
+				P999: durToMillisF(o.Latency.P999),
+				Max:  durToMillisF(o.Latency.Max),
+			},
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// resultsCSVHeader returns the header written by WriteCSV.
+func resultsCSVHeader() []string {
+	h := []string{"op", "requests", "objects", "errors"}
+	for _, c := range bench.ErrorCategories() {
+		h = append(h, "errors_"+c.String())
+	}
+	return append(h, "retries", "bytes", "bytes_uploaded", "bytes_downloaded", "duration_millis",
+		"throughput_bytes_per_sec", "throughput_objects_per_sec", "goodput_gib_per_sec",
+		"latency_min_millis", "latency_mean_millis", "latency_p50_millis", "latency_p90_millis",
+		"latency_p99_millis", "latency_p99_9_millis", "latency_max_millis",
+	)
+}
+
+// WriteCSV writes r as CSV with a header and one row per operation type.
+// Error counts by category are written as one column per category.
+func WriteCSV(w io.Writer, r Results) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(resultsCSVHeader()); err != nil {
+		return err
+	}
+	f := func(v float64) string {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	ms := func(d time.Duration) string {
+		return f(durToMillisF(d))
+	}
+	for _, o := range r.Operations {
+		row := []string{
+			o.OpType,
+			strconv.Itoa(o.Requests),
+			strconv.Itoa(o.Objects),
+			strconv.Itoa(o.Errors),
+		}
+		for _, c := range bench.ErrorCategories() {
+			row = append(row, strconv.Itoa(o.ErrorsByCategory[c.String()]))
+		}
+		row = append(row,
+			strconv.Itoa(o.Retries),
+			strconv.FormatInt(o.Bytes, 10),
+			strconv.FormatInt(o.BytesUploaded, 10),
+			strconv.FormatInt(o.BytesDownloaded, 10),
+			ms(o.Duration),
+			f(o.BytesPerSec()),
+			f(o.ObjectsPerSec()),
+			f(o.GoodputGiBPerSec()),
+			ms(o.Latency.Min),
+			ms(o.Latency.Mean),
+			ms(o.Latency.P50),
+			ms(o.Latency.P90),
+			ms(o.Latency.P99),
+			ms(o.Latency.P999),
+			ms(o.Latency.Max),
+		)
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
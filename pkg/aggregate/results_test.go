@@ -0,0 +1,292 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package aggregate
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/minio/warp/pkg/bench"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files in testdata")
+
+func testResults() Results {
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	return Results{
+		Config: map[string]string{
+			"benchmark":   "mixed",
+			"concurrent":  "20",
+			"obj.size":    "1MiB",
+			"duration":    "5m0s",
+			"host":        "localhost:9000",
+			"compression": "false",
+		},
+		Start: start,
+		End:   start.Add(5 * time.Minute),
+		Operations: []OpResults{
+			{
+				OpType:   "GET",
+				Requests: 1500,
+				Objects:  1498,
+				Errors:   2,
+				ErrorsByCategory: map[string]int{
+					"throttled": 1,
+					"timeout":   1,
+				},
+				Retries:         3,
+				Bytes:           1498 << 20,
+				BytesDownloaded: 1498 << 20,
+				Duration:        5 * time.Minute,
+				Latency: LatencyPercentiles{
+					N:    1498,
+					Min:  2 * time.Millisecond,
+					Mean: 12500 * time.Microsecond,
+					P50:  10 * time.Millisecond,
+					P90:  25 * time.Millisecond,
+					P99:  60 * time.Millisecond,
+					P999: 110 * time.Millisecond,
+					Max:  250 * time.Millisecond,
+				},
+			},
+			{
+				OpType:        "PUT",
+				Requests:      500,
+				Objects:       500,
+				Bytes:         500 << 20,
+				BytesUploaded: 500 << 20,
+				Duration:      4*time.Minute + 59*time.Second,
+				Latency: LatencyPercentiles{
+					N:    500,
+					Min:  5 * time.Millisecond,
+					Mean: 30 * time.Millisecond,
+					P50:  28 * time.Millisecond,
+					P90:  45 * time.Millisecond,
+					P99:  90 * time.Millisecond,
+					P999: 150 * time.Millisecond,
+					Max:  200 * time.Millisecond,
+				},
+			},
+		},
+	}
+}
+
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *updateGolden {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s mismatch, run with -update to regenerate.\ngot:\n%s\nwant:\n%s", name, got, want)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, testResults()); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "results.csv", buf.Bytes())
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, testResults()); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "results.json", buf.Bytes())
+}
+
+func TestResultsFromOperations(t *testing.T) {
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	op := func(typ string, at, dur time.Duration, size int64, err string) bench.Operation {
+		return bench.Operation{
+			OpType:   typ,
+			ObjPerOp: 1,
+			Size:     size,
+			Start:    start.Add(at),
+			End:      start.Add(at + dur),
+			Err:      err,
+		}
+	}
+	ops := bench.Operations{
+		op("PUT", 0, 10*time.Millisecond, 100, ""),
+		op("GET", time.Second, 20*time.Millisecond, 100, ""),
+		op("PUT", time.Second, 30*time.Millisecond, 100, ""),
+		op("GET", 2*time.Second, 10*time.Millisecond, 0, "Please reduce your request rate."),
+	}
+	// Categories stored on the operation take precedence over the message.
+	failed := op("GET", 2*time.Second, 10*time.Millisecond, 0, "We encountered an internal error")
+	failed.ErrCat = bench.ErrCatServer
+	failed.Retries = 2
+	ops = append(ops, failed)
+	r := ResultsFromOperations(ops, map[string]string{"concurrent": "1"})
+	if len(r.Operations) != 2 {
+		t.Fatalf("got %d op types, want 2", len(r.Operations))
+	}
+	get, put := r.Operations[0], r.Operations[1]
+	if get.OpType != "GET" || put.OpType != "PUT" {
+		t.Fatalf("unexpected order: %s, %s", get.OpType, put.OpType)
+	}
+	if get.Requests != 3 || get.Errors != 2 || get.Objects != 1 || get.Bytes != 100 {
+		t.Errorf("GET: unexpected counts %+v", get)
+	}
+	if get.ErrorsByCategory["throttled"] != 1 || get.ErrorsByCategory["server"] != 1 {
+		t.Errorf("GET: unexpected error categories %v", get.ErrorsByCategory)
+	}
+	if get.Retries != 2 || put.Retries != 0 {
+		t.Errorf("unexpected retries: GET %d, PUT %d", get.Retries, put.Retries)
+	}
+	if put.Requests != 2 || put.Errors != 0 || put.Objects != 2 || put.Bytes != 200 {
+		t.Errorf("PUT: unexpected counts %+v", put)
+	}
+	if put.Latency.Max != 30*time.Millisecond {
+		t.Errorf("PUT: max latency %v, want 30ms", put.Latency.Max)
+	}
+	if !r.Start.Equal(start) {
+		t.Errorf("start %v, want %v", r.Start, start)
+	}
+
+	// With a 10ms interval the 30ms PUT delayed two more.
+	r = ResultsFromOperationsOpts(ops, nil, Options{ExpectedInterval: 10 * time.Millisecond})
+	if put := r.Operations[1]; put.Latency.N != 4 || put.Latency.Min != 10*time.Millisecond || put.Requests != 2 {
+		t.Errorf("PUT: corrected latency %v, %d requests", put.Latency, put.Requests)
+	}
+}
+
+func TestResultsTransferred(t *testing.T) {
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	op := func(typ string, at time.Duration, size int64, err string) bench.Operation {
+		return bench.Operation{
+			OpType:   typ,
+			ObjPerOp: 1,
+			Size:     size,
+			Start:    start.Add(at),
+			End:      start.Add(at + time.Second),
+			Err:      err,
+		}
+	}
+	// Mixed sizes, 4 seconds wall time for both GET and PUT.
+	ops := bench.Operations{
+		op("PUT", 0, 1<<30, ""),
+		op("PUT", time.Second, 4<<10, ""),
+		op("PUT", 3*time.Second, 1<<30, ""),
+		op("PUT", 2*time.Second, 1<<20, "Please reduce your request rate."),
+		op("GET", 0, 3<<30, ""),
+		op("GET", 3*time.Second, 1<<30, ""),
+		op("STAT", 0, 1<<30, ""),
+		op("STAT", 3*time.Second, 1<<30, ""),
+	}
+	r := ResultsFromOperations(ops, nil)
+	if len(r.Operations) != 3 {
+		t.Fatalf("got %d op types, want 3", len(r.Operations))
+	}
+	get, put, stat := r.Operations[0], r.Operations[1], r.Operations[2]
+	if get.BytesDownloaded != 4<<30 || get.BytesUploaded != 0 {
+		t.Errorf("GET: uploaded %d, downloaded %d", get.BytesUploaded, get.BytesDownloaded)
+	}
+	if want := int64(2<<30 + 4<<10); put.BytesUploaded != want || put.BytesDownloaded != 0 {
+		t.Errorf("PUT: uploaded %d, want %d, downloaded %d", put.BytesUploaded, want, put.BytesDownloaded)
+	}
+	if stat.BytesUploaded != 0 || stat.BytesDownloaded != 0 {
+		t.Errorf("STAT: uploaded %d, downloaded %d, want none", stat.BytesUploaded, stat.BytesDownloaded)
+	}
+	if got := get.GoodputGiBPerSec(); got != 1 {
+		t.Errorf("GET: goodput %v GiB/s, want 1", got)
+	}
+	if got, want := put.GoodputGiBPerSec(), (2+4.0/(1<<20))/4; got != want {
+		t.Errorf("PUT: goodput %v GiB/s, want %v", got, want)
+	}
+	if stat.GoodputGiBPerSec() != 0 {
+		t.Errorf("STAT: goodput %v, want 0", stat.GoodputGiBPerSec())
+	}
+	if r.BytesUploaded() != put.BytesUploaded || r.BytesDownloaded() != 4<<30 {
+		t.Errorf("totals: uploaded %d, downloaded %d", r.BytesUploaded(), r.BytesDownloaded())
+	}
+	if got, want := r.GoodputGiBPerSec(), (6+4.0/(1<<20))/4; got != want {
+		t.Errorf("total goodput %v GiB/s, want %v", got, want)
+	}
+}
+
+func TestResultsZeroSize(t *testing.T) {
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	var ops bench.Operations
+	for i := range 100 {
+		at := start.Add(time.Duration(i) * 100 * time.Millisecond)
+		for _, typ := range []string{"PUT", "GET"} {
+			op := bench.Operation{
+				OpType:   typ,
+				ObjPerOp: 1,
+				Thread:   uint32(i % 4),
+				Start:    at,
+				End:      at.Add(5 * time.Millisecond),
+				Endpoint: "http://localhost:9000",
+			}
+			if typ == "GET" {
+				fb := at.Add(time.Millisecond)
+				op.FirstByte = &fb
+			}
+			ops = append(ops, op)
+		}
+	}
+	r := ResultsFromOperations(ops, nil)
+	if len(r.Operations) != 2 {
+		t.Fatalf("got %d op types, want 2", len(r.Operations))
+	}
+	for _, o := range r.Operations {
+		if o.Requests != 100 || o.Objects != 100 || o.Errors != 0 || o.Bytes != 0 {
+			t.Errorf("%s: %d requests, %d objects, %d errors, %d bytes", o.OpType, o.Requests, o.Objects, o.Errors, o.Bytes)
+		}
+		if o.GoodputGiBPerSec() != 0 || o.BytesPerSec() != 0 || o.ObjectsPerSec() <= 0 {
+			t.Errorf("%s: goodput %v, %v bytes/s, %v objects/s", o.OpType, o.GoodputGiBPerSec(), o.BytesPerSec(), o.ObjectsPerSec())
+		}
+	}
+	if _, err := json.Marshal(r); err != nil {
+		t.Errorf("results: %v", err)
+	}
+
+	for _, typ := range []string{"PUT", "GET"} {
+		a := Aggregate(ops.FilterByOp(typ), Options{DurFunc: func(time.Duration) time.Duration { return time.Second }})
+		if len(a.Operations) != 1 {
+			t.Fatalf("%s: got %d aggregated operations", typ, len(a.Operations))
+		}
+		tp := a.Operations[0].Throughput
+		if tp.Operations == 0 || tp.Bytes != 0 || tp.BytesPS() != 0 || tp.ObjectsPS() <= 0 {
+			t.Errorf("%s: %d operations, %v bytes, %v bytes/s, %v objects/s", typ, tp.Operations, tp.Bytes, tp.BytesPS(), tp.ObjectsPS())
+		}
+		if _, err := json.Marshal(a); err != nil {
+			t.Errorf("%s: aggregate: %v", typ, err)
+		}
+	}
+	var empty Throughput
+	if empty.BytesPS() != 0 || empty.ObjectsPS() != 0 {
+		t.Errorf("empty throughput: %v bytes/s, %v objects/s", empty.BytesPS(), empty.ObjectsPS())
+	}
+}
@@ -0,0 +1,216 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package aggregate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"time"
+)
+
+// ReadJSON reads results written by WriteJSON.
+// Derived values, like throughput, are recomputed from the read values.
+func ReadJSON(r io.Reader) (Results, error) {
+	var in jsonResults
+	if err := json.NewDecoder(r).Decode(&in); err != nil {
+		return Results{}, fmt.Errorf("reading results: %w", err)
+	}
+	ms := func(v float64) time.Duration {
+		return time.Duration(math.Round(v * float64(time.Millisecond)))
+	}
+	res := Results{
+		Config:     in.Config,
+		Start:      in.Start,
+		End:        in.End,
+		Operations: make([]OpResults, 0, len(in.Operations)),
+	}
+	for _, o := range in.Operations {
+		res.Operations = append(res.Operations, OpResults{
+			OpType:           o.OpType,
+			Requests:         o.Requests,
+			Objects:          o.Objects,
+			Errors:           o.Errors,
+			ErrorsByCategory: o.ErrorsByCategory,
+			Retries:          o.Retries,
+			Bytes:            o.Bytes,
+			BytesUploaded:    o.BytesUploaded,
+			BytesDownloaded:  o.BytesDownloaded,
+			Duration:         ms(o.DurationMS),
+			Latency: LatencyPercentiles{
+				N:    o.Latency.N,
+				Min:  ms(o.Latency.Min),
+				Mean: ms(o.Latency.Mean),
+				P50:  ms(o.Latency.P50),
+				P90:  ms(o.Latency.P90),
+				P99:  ms(o.Latency.P99),
+				P999: ms(o.Latency.P999),
+				Max:  ms(o.Latency.Max),
+			},
+		})
+	}
+	return res, nil
+}
+
+// ErrorRate returns the fraction of requests that failed, 0 to 1.
+func (o OpResults) ErrorRate() float64 {
+	if o.Requests == 0 {
+		return 0
+	}
+	return float64(o.Errors) / float64(o.Requests)
+}
+
+// Delta is a value in two runs.
+type Delta struct {
+	Before, After float64
+}
+
+// Change returns the difference from before to after.
+func (d Delta) Change() float64 {
+	return d.After - d.Before
+}
+
+// Percent returns the change as a percentage of the before value.
+// ok is false if the before value is 0.
+func (d Delta) Percent() (pct float64, ok bool) {
+	if d.Before == 0 {
+		return 0, false
+	}
+	return 100 * d.Change() / d.Before, true
+}
+
+// OpDiff compares the results of one operation type in two runs.
+// If the operation type is only in one run, the other results are nil
+// and the deltas are not set.
+type OpDiff struct {
+	OpType        string
+	Before, After *OpResults
+
+	// Throughput in bytes and objects per second.
+	BytesPerSec   Delta
+	ObjectsPerSec Delta
+
+	// ErrorRate is the fraction of failed requests, 0 to 1.
+	ErrorRate Delta
+
+	// Latency percentiles in milliseconds.
+	Mean, P50, P90, P99, P999 Delta
+}
+
+// InBoth returns whether the operation type is present in both runs.
+func (o OpDiff) InBoth() bool {
+	return o.Before != nil && o.After != nil
+}
+
+// Diff compares two runs by operation type.
+type Diff struct {
+	// Operations contains all operation types of either run, sorted by type.
+	Operations []OpDiff
+}
+
+// CompareResults compares run a, before, to run b, after, for each operation type.
+func CompareResults(a, b Results) Diff {
+	byType := make(map[string]*OpDiff)
+	get := func(typ string) *OpDiff {
+		d := byType[typ]
+		if d == nil {
+			d = &OpDiff{OpType: typ}
+			byType[typ] = d
+		}
+		return d
+	}
+	for i := range a.Operations {
+		get(a.Operations[i].OpType).Before = &a.Operations[i]
+	}
+	for i := range b.Operations {
+		get(b.Operations[i].OpType).After = &b.Operations[i]
+	}
+	var diff Diff
+	for _, typ := range slices.Sorted(maps.Keys(byType)) {
+		d := byType[typ]
+		if d.InBoth() {
+			before, after := d.Before, d.After
+			ms := func(b, a time.Duration) Delta {
+				return Delta{Before: durToMillisF(b), After: durToMillisF(a)}
+			}
+			d.BytesPerSec = Delta{Before: before.BytesPerSec(), After: after.BytesPerSec()}
+			d.ObjectsPerSec = Delta{Before: before.ObjectsPerSec(), After: after.ObjectsPerSec()}
+			d.ErrorRate = Delta{Before: before.ErrorRate(), After: after.ErrorRate()}
+			d.Mean = ms(before.Latency.Mean, after.Latency.Mean)
+			d.P50 = ms(before.Latency.P50, after.Latency.P50)
+			d.P90 = ms(before.Latency.P90, after.Latency.P90)
+			d.P99 = ms(before.Latency.P99, after.Latency.P99)
+			d.P999 = ms(before.Latency.P999, after.Latency.P999)
+		}
+		diff.Operations = append(diff.Operations, *d)
+	}
+	return diff
+}
+
+// WriteDiff writes d as a table with a row for each metric of each operation type.
+// Operation types only present in one run are listed without metrics.
+func WriteDiff(w io.Writer, d Diff) error {
+	if _, err := fmt.Fprintf(w, "%-10s %-14s %14s %14s %14s %9s\n", "Operation", "Metric", "Before", "After", "Change", "Percent"); err != nil {
+		return err
+	}
+	for _, o := range d.Operations {
+		if !o.InBoth() {
+			run := "before"
+			if o.After != nil {
+				run = "after"
+			}
+			if _, err := fmt.Fprintf(w, "%-10s only in %s run\n", o.OpType, run); err != nil {
+				return err
+			}
+			continue
+		}
+		rows := []struct {
+			name  string
+			d     Delta
+			scale float64
+			unit  string
+		}{
+			{"MiB/s", o.BytesPerSec, 1.0 / (1 << 20), ""},
+			{"obj/s", o.ObjectsPerSec, 1, ""},
+			{"errors", o.ErrorRate, 100, "%"},
+			{"latency mean", o.Mean, 1, "ms"},
+			{"latency p50", o.P50, 1, "ms"},
+			{"latency p90", o.P90, 1, "ms"},
+			{"latency p99", o.P99, 1, "ms"},
+			{"latency p99.9", o.P999, 1, "ms"},
+		}
+		for _, r := range rows {
+			pct := "-"
+			if p, ok := r.d.Percent(); ok {
+				pct = fmt.Sprintf("%+.1f%%", p)
+			}
+			_, err := fmt.Fprintf(w, "%-10s %-14s %14s %14s %14s %9s\n", o.OpType, r.name,
+				fmt.Sprintf("%.2f%s", r.d.Before*r.scale, r.unit),
+				fmt.Sprintf("%.2f%s", r.d.After*r.scale, r.unit),
+				fmt.Sprintf("%+.2f%s", r.d.Change()*r.scale, r.unit),
+				pct)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
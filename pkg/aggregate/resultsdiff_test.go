@@ -0,0 +1,125 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package aggregate
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReadJSON(t *testing.T) {
+	want := testResults()
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadJSON(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip mismatch.\ngot:  %+v\nwant: %+v", got, want)
+	}
+	if _, err := ReadJSON(bytes.NewBufferString("{")); err == nil {
+		t.Error("expected error for truncated JSON")
+	}
+}
+
+func testDiffResults() (before, after Results) {
+	before = testResults()
+	after = testResults()
+	// GET is twice as fast with half the latency and no errors.
+	get := &after.Operations[0]
+	get.Requests, get.Objects, get.Errors = 3000, 3000, 0
+	get.Bytes, get.BytesDownloaded = 3000<<20, 3000<<20
+	get.ErrorsByCategory = nil
+	get.Latency.Mean /= 2
+	get.Latency.P50 /= 2
+	get.Latency.P90 /= 2
+	get.Latency.P99 /= 2
+	get.Latency.P999 /= 2
+	// PUT is replaced by DELETE.
+	after.Operations[1] = OpResults{
+		OpType:   "DELETE",
+		Requests: 100,
+		Objects:  100,
+		Duration: time.Minute,
+	}
+	return before, after
+}
+
+func TestCompareResults(t *testing.T) {
+	before, after := testDiffResults()
+	d := CompareResults(before, after)
+	if len(d.Operations) != 3 {
+		t.Fatalf("got %d operation types, want 3", len(d.Operations))
+	}
+	del, get, put := d.Operations[0], d.Operations[1], d.Operations[2]
+	if del.OpType != "DELETE" || get.OpType != "GET" || put.OpType != "PUT" {
+		t.Fatalf("unexpected order: %s, %s, %s", del.OpType, get.OpType, put.OpType)
+	}
+	if del.InBoth() || del.Before != nil || del.After == nil {
+		t.Errorf("DELETE should only be in the after run: %+v", del)
+	}
+	if put.InBoth() || put.Before == nil || put.After != nil {
+		t.Errorf("PUT should only be in the before run: %+v", put)
+	}
+	if put.BytesPerSec != (Delta{}) {
+		t.Errorf("PUT deltas should not be set: %+v", put)
+	}
+	if !get.InBoth() {
+		t.Fatal("GET should be in both runs")
+	}
+	near := func(name string, got, want float64) {
+		t.Helper()
+		if math.Abs(got-want) > 1e-9*math.Max(1, math.Abs(want)) {
+			t.Errorf("%s: got %v, want %v", name, got, want)
+		}
+	}
+	pct := func(name string, d Delta, want float64) {
+		t.Helper()
+		p, ok := d.Percent()
+		if !ok {
+			t.Errorf("%s: no percent change", name)
+		}
+		near(name, p, want)
+	}
+	near("GET bytes/s change", get.BytesPerSec.Change(), float64(1502<<20)/300)
+	pct("GET obj/s", get.ObjectsPerSec, 100*(3000.0-1498)/1498)
+	pct("GET p50", get.P50, -50)
+	pct("GET p99.9", get.P999, -50)
+	near("GET p90 change", get.P90.Change(), -12.5)
+	near("GET error rate before", get.ErrorRate.Before, 2.0/1500)
+	pct("GET error rate", get.ErrorRate, -100)
+
+	if _, ok := (Delta{Before: 0, After: 1}).Percent(); ok {
+		t.Error("percent change from 0 should not be defined")
+	}
+}
+
+func TestWriteDiff(t *testing.T) {
+	before, after := testDiffResults()
+	var buf bytes.Buffer
+	if err := WriteDiff(&buf, CompareResults(before, after)); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "results_diff.txt", buf.Bytes())
+}
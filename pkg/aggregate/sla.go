@@ -0,0 +1,217 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package aggregate
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SLAThreshold contains the limits for an operation type.
+// Zero values are not checked.
+type SLAThreshold struct {
+	P50, P90, P99, P999, Max time.Duration
+
+	// ErrorRate is the maximum fraction of requests that may fail, 0 to 1.
+	ErrorRate float64
+
+	// NoErrors fails the SLA if any request fails.
+	// It is used for an error rate of 0, which is otherwise not checked.
+	NoErrors bool
+}
+
+// SLAAllOps is the SLA key for thresholds that apply to every operation type.
+const SLAAllOps = "*"
+
+// SLA contains thresholds keyed by operation type.
+// Thresholds keyed by SLAAllOps are checked for every operation type
+// in addition to thresholds for the specific type.
+// Operation types without results are not checked.
+type SLA map[string]SLAThreshold
+
+type slaLatencyMetric struct {
+	name  string
+	limit func(t *SLAThreshold) *time.Duration
+	value func(p LatencyPercentiles) time.Duration
+}
+
+// slaLatencyMetrics lists the latency metrics in the order they are checked.
+var slaLatencyMetrics = []slaLatencyMetric{
+	{"p50", func(t *SLAThreshold) *time.Duration { return &t.P50 }, func(p LatencyPercentiles) time.Duration { return p.P50 }},
+	{"p90", func(t *SLAThreshold) *time.Duration { return &t.P90 }, func(p LatencyPercentiles) time.Duration { return p.P90 }},
+	{"p99", func(t *SLAThreshold) *time.Duration { return &t.P99 }, func(p LatencyPercentiles) time.Duration { return p.P99 }},
+	{"p99.9", func(t *SLAThreshold) *time.Duration { return &t.P999 }, func(p LatencyPercentiles) time.Duration { return p.P999 }},
+	{"max", func(t *SLAThreshold) *time.Duration { return &t.Max }, func(p LatencyPercentiles) time.Duration { return p.Max }},
+}
+
+// SLAViolation is a threshold that was exceeded.
+type SLAViolation struct {
+	OpType string
+	// Metric is "p50", "p90", "p99", "p99.9", "max" or "errors".
+	Metric string
+	// Value and Limit are in milliseconds for latency metrics
+	// and a fraction for errors.
+	Value, Limit float64
+}
+
+// String returns a human readable description of the violation.
+func (v SLAViolation) String() string {
+	if v.Metric == "errors" {
+		return fmt.Sprintf("%s: error rate %.3g%% exceeds %.3g%%", v.OpType, v.Value*100, v.Limit*100)
+	}
+	return fmt.Sprintf("%s: %s latency %.3fms exceeds %.3fms", v.OpType, v.Metric, v.Value, v.Limit)
+}
+
+// SLAResult is the outcome of evaluating an SLA.
+type SLAResult struct {
+	// Violations are sorted by operation type, in the order thresholds are checked.
+	Violations []SLAViolation
+}
+
+// Passed returns true if no thresholds were exceeded.
+func (s SLAResult) Passed() bool {
+	return len(s.Violations) == 0
+}
+
+// Evaluate checks the results of each operation type against the thresholds.
+func (s SLA) Evaluate(r Results) SLAResult {
+	var res SLAResult
+	for _, o := range r.Operations {
+		for _, key := range []string{SLAAllOps, o.OpType} {
+			if t, ok := s[key]; ok {
+				res.Violations = append(res.Violations, t.check(o)...)
+			}
+		}
+	}
+	return res
+}
+
+// check returns the thresholds exceeded by o.
+func (t SLAThreshold) check(o OpResults) []SLAViolation {
+	var res []SLAViolation
+	for _, m := range slaLatencyMetrics {
+		limit, value := *m.limit(&t), m.value(o.Latency)
+		if limit > 0 && value > limit {
+			res = append(res, SLAViolation{
+				OpType: o.OpType,
+				Metric: m.name,
+				Value:  durToMillisF(value),
+				Limit:  durToMillisF(limit),
+			})
+		}
+	}
+	if (t.ErrorRate > 0 || t.NoErrors) && o.Requests > 0 {
+		if rate := float64(o.Errors) / float64(o.Requests); rate > t.ErrorRate {
+			res = append(res, SLAViolation{OpType: o.OpType, Metric: "errors", Value: rate, Limit: t.ErrorRate})
+		}
+	}
+	return res
+}
+
+// ParseSLA parses comma separated thresholds in the form [OP:]metric=value,
+// for example "p99=200ms,GET:p50=10ms,errors=1%".
+// Thresholds without an operation type apply to all operation types.
+// Latency metrics are p50, p90, p99, p99.9 and max and take a duration.
+// The errors metric takes a percentage or a fraction, and 0 allows no errors.
+func ParseSLA(s string) (SLA, error) {
+	sla := make(SLA)
+	for item := range strings.SplitSeq(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		op := SLAAllOps
+		if i := strings.IndexByte(item, ':'); i >= 0 {
+			op = strings.ToUpper(item[:i])
+			item = item[i+1:]
+		}
+		metric, value, ok := strings.Cut(item, "=")
+		if !ok || op == "" {
+			return nil, fmt.Errorf("sla: invalid threshold %q, want [OP:]metric=value", item)
+		}
+		t := sla[op]
+		if metric == "errors" {
+			rate, err := parseRate(value)
+			if err != nil {
+				return nil, fmt.Errorf("sla: %s: %w", item, err)
+			}
+			t.ErrorRate, t.NoErrors = rate, rate == 0
+			sla[op] = t
+			continue
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("sla: %s: %w", item, err)
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("sla: %s: duration must be > 0", item)
+		}
+		i := slices.IndexFunc(slaLatencyMetrics, func(m slaLatencyMetric) bool { return m.name == metric })
+		if i < 0 {
+			return nil, fmt.Errorf("sla: unknown metric %q", metric)
+		}
+		*slaLatencyMetrics[i].limit(&t) = d
+		sla[op] = t
+	}
+	if len(sla) == 0 {
+		return nil, errors.New("sla: no thresholds")
+	}
+	return sla, nil
+}
+
+// parseRate parses "1%" or "0.01" as a fraction in [0, 1].
+func parseRate(s string) (float64, error) {
+	pct := strings.HasSuffix(s, "%")
+	f, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+	if err != nil {
+		return 0, err
+	}
+	if pct {
+		f /= 100
+	}
+	if !(f >= 0 && f <= 1) {
+		return 0, fmt.Errorf("error rate %q must be between 0%% and 100%%", s)
+	}
+	return f, nil
+}
+
+// String returns the thresholds in the format accepted by ParseSLA.
+func (s SLA) String() string {
+	var parts []string
+	for _, op := range slices.Sorted(maps.Keys(s)) {
+		t := s[op]
+		prefix := op + ":"
+		if op == SLAAllOps {
+			prefix = ""
+		}
+		for _, m := range slaLatencyMetrics {
+			if limit := *m.limit(&t); limit > 0 {
+				parts = append(parts, prefix+m.name+"="+limit.String())
+			}
+		}
+		if t.ErrorRate > 0 || t.NoErrors {
+			parts = append(parts, prefix+"errors="+strconv.FormatFloat(t.ErrorRate*100, 'f', -1, 64)+"%")
+		}
+	}
+	return strings.Join(parts, ",")
+}
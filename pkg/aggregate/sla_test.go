@@ -0,0 +1,114 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package aggregate
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSLAEvaluate(t *testing.T) {
+	r := testResults()
+	pass := SLA{
+		SLAAllOps: {P99: 100 * time.Millisecond, ErrorRate: 0.01},
+		"GET":     {P50: 10 * time.Millisecond, Max: time.Second},
+	}
+	if res := pass.Evaluate(r); !res.Passed() {
+		t.Errorf("unexpected violations: %v", res.Violations)
+	}
+
+	// GET has 2 errors in 1500 requests, p99 60ms and p50 10ms.
+	// PUT has p99 90ms and p99.9 150ms.
+	fail := SLA{
+		SLAAllOps: {P99: 80 * time.Millisecond},
+		"GET":     {P50: 5 * time.Millisecond, ErrorRate: 0.001},
+		"PUT":     {P999: 100 * time.Millisecond, ErrorRate: 0.001},
+		"DELETE":  {P50: time.Millisecond},
+	}
+	res := fail.Evaluate(r)
+	if res.Passed() {
+		t.Fatal("expected violations")
+	}
+	want := []SLAViolation{
+		{OpType: "GET", Metric: "p50", Value: 10, Limit: 5},
+		{OpType: "GET", Metric: "errors", Value: 2.0 / 1500, Limit: 0.001},
+		{OpType: "PUT", Metric: "p99", Value: 90, Limit: 80},
+		{OpType: "PUT", Metric: "p99.9", Value: 150, Limit: 100},
+	}
+	if !reflect.DeepEqual(res.Violations, want) {
+		t.Errorf("got violations\n%v\nwant\n%v", res.Violations, want)
+	}
+	if got, want := res.Violations[1].String(), "GET: error rate 0.133% exceeds 0.1%"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := res.Violations[2].String(), "PUT: p99 latency 90.000ms exceeds 80.000ms"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// No errors allowed.
+	none := SLA{SLAAllOps: {NoErrors: true}}
+	res = none.Evaluate(r)
+	want = []SLAViolation{{OpType: "GET", Metric: "errors", Value: 2.0 / 1500, Limit: 0}}
+	if !reflect.DeepEqual(res.Violations, want) {
+		t.Errorf("no errors: got violations %v, want %v", res.Violations, want)
+	}
+}
+
+func TestParseSLA(t *testing.T) {
+	sla, err := ParseSLA("p99=200ms, get:p50=10ms,GET:errors=1%,errors=0.05,PUT:p99.9=1s,max=2s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := SLA{
+		SLAAllOps: {P99: 200 * time.Millisecond, Max: 2 * time.Second, ErrorRate: 0.05},
+		"GET":     {P50: 10 * time.Millisecond, ErrorRate: 0.01},
+		"PUT":     {P999: time.Second},
+	}
+	if !reflect.DeepEqual(sla, want) {
+		t.Errorf("got %+v, want %+v", sla, want)
+	}
+	// String is parsed back to the same thresholds.
+	again, err := ParseSLA(sla.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again, sla) {
+		t.Errorf("%q parsed as %+v, want %+v", sla.String(), again, sla)
+	}
+
+	// An error rate of 0 allows no errors.
+	for _, s := range []string{"errors=0%", "errors=0"} {
+		sla, err := ParseSLA(s)
+		if err != nil {
+			t.Fatalf("%q: %v", s, err)
+		}
+		if want := (SLA{SLAAllOps: {NoErrors: true}}); !reflect.DeepEqual(sla, want) {
+			t.Errorf("%q: got %+v, want %+v", s, sla, want)
+		}
+		if got := sla.String(); got != "errors=0%" {
+			t.Errorf("%q: String returned %q", s, got)
+		}
+	}
+
+	for _, s := range []string{"", "p99", "p98=1s", "p99=fast", "p99=-1s", "errors=-1%", "errors=150%", "errors=NaN", ":p99=1s"} {
+		if _, err := ParseSLA(s); err == nil {
+			t.Errorf("%q: expected error", s)
+		}
+	}
+}
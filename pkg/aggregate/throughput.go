@@ -65,11 +65,17 @@ func (t Throughput) Add(o bench.Operation) Throughput {
 
 // BytesPS returns the bytes per second throughput for the time segment.
 func (t Throughput) BytesPS() bench.Throughput {
+	if t.MeasureDurationMillis <= 0 {
+		return 0
+	}
 	return bench.Throughput(1000 * t.Bytes / float64(t.MeasureDurationMillis))
 }
 
 // ObjectsPS returns the objects per second for the segment.
 func (t Throughput) ObjectsPS() float64 {
+	if t.MeasureDurationMillis <= 0 {
+		return 0
+	}
 	return 1000 * float64(t.Objects) / float64(t.MeasureDurationMillis)
 }
 
@@ -0,0 +1,148 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"cmp"
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/minio/warp/pkg/generator"
+)
+
+// AccessPattern is the order in which benchmarks read existing objects.
+type AccessPattern uint8
+
+const (
+	// AccessRandom picks objects uniformly at random.
+	AccessRandom AccessPattern = iota
+
+	// AccessSequential reads objects in the order they were created,
+	// starting over after the last.
+	AccessSequential
+
+	// AccessShuffled reads every object once in a pseudo-random order
+	// given by the seed, starting over in the same order after the last.
+	// Objects must be sorted with SortObjects for the order to only depend on the seed.
+	AccessShuffled
+)
+
+func (a AccessPattern) String() string {
+	switch a {
+	case AccessRandom:
+		return "random"
+	case AccessSequential:
+		return "sequential"
+	case AccessShuffled:
+		return "shuffled"
+	}
+	return fmt.Sprintf("AccessPattern(%d)", a)
+}
+
+// ParseAccessPattern parses "random", "sequential" or "shuffled".
+func ParseAccessPattern(s string) (AccessPattern, error) {
+	switch strings.ToLower(s) {
+	case "random", "":
+		return AccessRandom, nil
+	case "sequential", "seq":
+		return AccessSequential, nil
+	case "shuffled", "shuffle":
+		return AccessShuffled, nil
+	}
+	return 0, fmt.Errorf("unknown access pattern %q, want random, sequential or shuffled", s)
+}
+
+// ShuffledOrder returns a permutation of 0 to n-1 given by seed.
+// The same n and seed always return the same order.
+func ShuffledOrder(n int, seed int64) []int {
+	return rand.New(rand.NewSource(seed)).Perm(n)
+}
+
+// SortObjects sorts objs by name and version,
+// so a ShuffledOrder of them does not depend on the order they were uploaded or listed in.
+func SortObjects(objs generator.Objects) {
+	slices.SortFunc(objs, func(a, b generator.Object) int {
+		return cmp.Or(strings.Compare(a.Name, b.Name), strings.Compare(a.VersionID, b.VersionID))
+	})
+}
+
+// KeySelector picks the position of the next object to read.
+// It is implemented by KeyAccess and ZipfSelector.
+type KeySelector interface {
+	// Index returns a position in a set of n objects. n must be > 0.
+	Index(n int) int
+}
+
+// KeyAccess picks positions in a set of objects following an AccessPattern.
+// Sequential and shuffled positions are shared by all callers,
+// so concurrent workers together read the objects in order.
+// It is safe for concurrent use.
+type KeyAccess struct {
+	pattern AccessPattern
+	seed    int64
+	next    atomic.Uint64
+
+	mu  sync.Mutex
+	rng *rand.Rand
+	// order is the shuffled order for the last n.
+	order []int
+}
+
+// NewKeyAccess returns a KeyAccess with the specified pattern.
+// seed is used for AccessRandom and AccessShuffled.
+func NewKeyAccess(p AccessPattern, seed int64) *KeyAccess {
+	return &KeyAccess{pattern: p, seed: seed, rng: rand.New(rand.NewSource(seed))}
+}
+
+// Index returns the position of the next object in a set of n objects.
+// n must be > 0. n may change between calls,
+// but for AccessShuffled that creates a new order, starting over.
+func (k *KeyAccess) Index(n int) int {
+	switch k.pattern {
+	case AccessSequential:
+		return int((k.next.Add(1) - 1) % uint64(n))
+	case AccessShuffled:
+		k.mu.Lock()
+		defer k.mu.Unlock()
+		if len(k.order) != n {
+			k.order = ShuffledOrder(n, k.seed)
+			k.next.Store(0)
+		}
+		return k.order[(k.next.Add(1)-1)%uint64(n)]
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.rng.Intn(n)
+}
+
+// Pick returns the next object from the set as selected by k.
+// Objects are in the order they were added as long as none have been removed,
+// since removing an object moves the last object into its place.
+// Returns false if the set is empty.
+func (s *ObjectSet) Pick(k KeySelector) (generator.Object, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.objects) == 0 {
+		return generator.Object{}, false
+	}
+	return s.objects[k.Index(len(s.objects))], true
+}
@@ -0,0 +1,188 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/minio/warp/pkg/generator"
+)
+
+func TestObjectSetPick(t *testing.T) {
+	const n = 50
+	s := NewObjectSet()
+	for i := range n {
+		s.Add(generator.Object{Name: fmt.Sprintf("obj-%03d", i)})
+	}
+
+	// Sequential returns objects in creation order and wraps around.
+	seq := NewKeyAccess(AccessSequential, 0)
+	for i := range 3 * n {
+		obj, ok := s.Pick(seq)
+		if !ok {
+			t.Fatal("empty set")
+		}
+		if want := fmt.Sprintf("obj-%03d", i%n); obj.Name != want {
+			t.Fatalf("pick %d: got %s, want %s", i, obj.Name, want)
+		}
+	}
+
+	// Concurrent callers together read every object once per round.
+	seq = NewKeyAccess(AccessSequential, 0)
+	var mu sync.Mutex
+	seen := make(map[string]int)
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 2 * n {
+				obj, _ := s.Pick(seq)
+				mu.Lock()
+				seen[obj.Name]++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	for name, cnt := range seen {
+		if cnt != 10 {
+			t.Errorf("%s read %d times, want 10", name, cnt)
+		}
+	}
+
+	// Random covers the set roughly uniformly.
+	const draws = 100000
+	random := NewKeyAccess(AccessRandom, 1)
+	counts := make(map[string]int)
+	for range draws {
+		obj, _ := s.Pick(random)
+		counts[obj.Name]++
+	}
+	if len(counts) != n {
+		t.Fatalf("random picked %d of %d objects", len(counts), n)
+	}
+	for name, cnt := range counts {
+		if want := draws / n; cnt < want*85/100 || cnt > want*115/100 {
+			t.Errorf("%s picked %d times, want about %d", name, cnt, want)
+		}
+	}
+
+	if _, ok := NewObjectSet().Pick(seq); ok {
+		t.Error("picked from an empty set")
+	}
+}
+
+func TestParseAccessPattern(t *testing.T) {
+	for s, want := range map[string]AccessPattern{"": AccessRandom, "random": AccessRandom, "Sequential": AccessSequential, "seq": AccessSequential, "shuffled": AccessShuffled} {
+		got, err := ParseAccessPattern(s)
+		if err != nil || got != want {
+			t.Errorf("%q: got %v, %v, want %v", s, got, err, want)
+		}
+	}
+	if _, err := ParseAccessPattern("zigzag"); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestShuffledOrder(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 1000} {
+		order := ShuffledOrder(n, 42)
+		if len(order) != n {
+			t.Fatalf("n %d: got %d positions", n, len(order))
+		}
+		// Every position exactly once.
+		sorted := slices.Sorted(slices.Values(order))
+		for i, v := range sorted {
+			if v != i {
+				t.Fatalf("n %d: not a permutation: %v", n, sorted)
+			}
+		}
+		if !slices.Equal(order, ShuffledOrder(n, 42)) {
+			t.Errorf("n %d: order differs for the same seed", n)
+		}
+	}
+	if slices.Equal(ShuffledOrder(1000, 1), ShuffledOrder(1000, 2)) {
+		t.Error("same order for different seeds")
+	}
+	if slices.IsSorted(ShuffledOrder(1000, 1)) {
+		t.Error("order is not shuffled")
+	}
+
+	// Concurrent callers together read every object once per pass,
+	// in the same order every pass.
+	const n = 50
+	s := NewObjectSet()
+	for i := range n {
+		s.Add(generator.Object{Name: fmt.Sprintf("obj-%03d", i)})
+	}
+	shuffled := NewKeyAccess(AccessShuffled, 7)
+	var mu sync.Mutex
+	seen := make(map[string]int)
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range n / 5 {
+				obj, _ := s.Pick(shuffled)
+				mu.Lock()
+				seen[obj.Name]++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if len(seen) != n {
+		t.Fatalf("first pass read %d of %d objects", len(seen), n)
+	}
+	want := ShuffledOrder(n, 7)
+	for pass := range 2 {
+		for i := range n {
+			if got := shuffled.Index(n); got != want[i] {
+				t.Fatalf("pass %d, pick %d: got %d, want %d", pass, i, got, want[i])
+			}
+		}
+	}
+}
+
+func TestSortObjects(t *testing.T) {
+	// The shuffled order of names only depends on the seed, not the upload order.
+	var objs generator.Objects
+	for i := range 20 {
+		objs = append(objs, generator.Object{Name: fmt.Sprintf("obj-%02d", i/2), VersionID: fmt.Sprint(i % 2)})
+	}
+	reversed := slices.Clone(objs)
+	slices.Reverse(reversed)
+	rand.New(rand.NewSource(1)).Shuffle(len(objs), func(i, j int) { objs[i], objs[j] = objs[j], objs[i] })
+	SortObjects(objs)
+	SortObjects(reversed)
+	order := ShuffledOrder(len(objs), 3)
+	for i, idx := range order {
+		if objs[idx] != reversed[idx] {
+			t.Fatalf("pick %d: %+v != %+v", i, objs[idx], reversed[idx])
+		}
+	}
+	if objs[0].Name != "obj-00" || objs[0].VersionID != "0" || objs[1].VersionID != "1" {
+		t.Errorf("not sorted: %+v", objs[:2])
+	}
+}
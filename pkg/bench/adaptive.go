@@ -0,0 +1,138 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// AdaptiveOptions configures an AdaptiveRate.
+// Zero values use the defaults given for each field.
+type AdaptiveOptions struct {
+	// Threshold is the fraction of throttled operations in an interval
+	// above which the rate is decreased. Default 0.05.
+	Threshold float64
+
+	// Decrease is the factor the rate is multiplied by when throttled. Default 0.5.
+	Decrease float64
+
+	// Increase is the number of operations per second the rate is increased by
+	// after an interval without throttling. Default Max/20.
+	Increase float64
+
+	// Min and Max bound the rate.
+	// Max defaults to the limit of the rate limiter, Min to Max/100.
+	Min, Max float64
+
+	// Interval is the time between adjustments. Default 1s.
+	Interval time.Duration
+}
+
+// AdaptiveRate adjusts the limit of a RateLimiter based on how many
+// operations are throttled by the server.
+// When the throttled fraction of operations in an interval exceeds a threshold
+// the rate is multiplied by a factor, otherwise it is increased by a fixed amount,
+// so the rate backs off quickly and recovers slowly (AIMD).
+// It is safe for concurrent use.
+type AdaptiveRate struct {
+	o         AdaptiveOptions
+	l         *RateLimiter
+	ops       atomic.Int64
+	throttled atomic.Int64
+	decreases atomic.Int64
+}
+
+// NewAdaptiveRate returns a controller of the limit of l.
+// If l is nil, nil is returned.
+func NewAdaptiveRate(l *RateLimiter, o AdaptiveOptions) *AdaptiveRate {
+	if l == nil {
+		return nil
+	}
+	if o.Threshold <= 0 {
+		o.Threshold = 0.05
+	}
+	if o.Decrease <= 0 || o.Decrease >= 1 {
+		o.Decrease = 0.5
+	}
+	if o.Max <= 0 {
+		o.Max = l.Limit()
+	}
+	if o.Min <= 0 {
+		o.Min = o.Max / 100
+	}
+	o.Min = min(o.Min, o.Max)
+	if o.Increase <= 0 {
+		o.Increase = o.Max / 20
+	}
+	if o.Interval <= 0 {
+		o.Interval = time.Second
+	}
+	return &AdaptiveRate{o: o, l: l}
+}
+
+// Observe counts a finished operation.
+func (a *AdaptiveRate) Observe(op Operation) {
+	a.ops.Add(1)
+	if op.ErrCategory() == ErrCatThrottled {
+		a.throttled.Add(1)
+	}
+}
+
+// Run adjusts the rate every interval until ctx is canceled.
+func (a *AdaptiveRate) Run(ctx context.Context) {
+	t := time.NewTicker(a.o.Interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			a.adjust()
+		}
+	}
+}
+
+// adjust sets the rate from the operations observed since the previous call
+// and returns the new rate.
+// The rate is unchanged if no operations were observed.
+func (a *AdaptiveRate) adjust() float64 {
+	ops, throttled := a.ops.Swap(0), a.throttled.Swap(0)
+	limit := a.l.Limit()
+	switch {
+	case ops == 0:
+		return limit
+	case float64(throttled)/float64(ops) > a.o.Threshold:
+		limit = max(limit*a.o.Decrease, a.o.Min)
+		a.decreases.Add(1)
+	default:
+		limit = min(limit+a.o.Increase, a.o.Max)
+	}
+	a.l.SetLimit(limit)
+	return limit
+}
+
+// Decreases returns the number of times the rate was decreased.
+// A nil AdaptiveRate returns 0.
+func (a *AdaptiveRate) Decreases() int64 {
+	if a == nil {
+		return 0
+	}
+	return a.decreases.Load()
+}
@@ -0,0 +1,161 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// concurrencyThrottler returns SlowDown when more than limit requests are in flight.
+type concurrencyThrottler struct {
+	ObjectClient
+	limit    atomic.Int64
+	inFlight atomic.Int64
+	delay    time.Duration
+}
+
+func (c *concurrencyThrottler) PutObject(ctx context.Context, bucket, object string, reader io.Reader, size int64, opts minio.PutObjectOptions) (minio.UploadInfo, error) {
+	n := c.inFlight.Add(1)
+	defer c.inFlight.Add(-1)
+	time.Sleep(c.delay)
+	if n > c.limit.Load() {
+		return minio.UploadInfo{}, minio.ErrorResponse{StatusCode: http.StatusServiceUnavailable, Code: "SlowDown", Message: "Please reduce your request rate."}
+	}
+	return c.ObjectClient.PutObject(ctx, bucket, object, reader, size, opts)
+}
+
+func TestAdaptiveRateAdjust(t *testing.T) {
+	if NewAdaptiveRate(nil, AdaptiveOptions{}) != nil {
+		t.Fatal("expected nil controller without limiter")
+	}
+	l := NewRateLimiter(1000, 1)
+	a := NewAdaptiveRate(l, AdaptiveOptions{Increase: 100, Min: 50})
+	observe := func(ok, throttled int) {
+		for range ok {
+			a.Observe(Operation{})
+		}
+		for range throttled {
+			a.Observe(Operation{Err: "Please reduce your request rate."})
+		}
+	}
+
+	// Nothing observed, no change.
+	if got := a.adjust(); got != 1000 {
+		t.Fatalf("got %v, want 1000", got)
+	}
+	// Below threshold, already at max.
+	observe(100, 4)
+	if got := a.adjust(); got != 1000 {
+		t.Fatalf("got %v, want 1000", got)
+	}
+	// Other errors are not throttling.
+	for range 50 {
+		a.Observe(Operation{Err: "connection reset by peer"})
+	}
+	if got := a.adjust(); got != 1000 {
+		t.Fatalf("got %v, want 1000", got)
+	}
+	// Multiplicative decrease, bounded by Min.
+	for _, want := range []float64{500, 250, 125, 62.5, 50, 50} {
+		observe(90, 10)
+		if got := a.adjust(); got != want {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+	if a.Decreases() != 6 || l.Limit() != 50 {
+		t.Fatalf("decreases %d, limit %v", a.Decreases(), l.Limit())
+	}
+	// Additive increase, bounded by Max.
+	for _, want := range []float64{150, 250, 350, 450, 550, 650, 750, 850, 950, 1000, 1000} {
+		observe(100, 0)
+		if got := a.adjust(); got != want {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+	// A 503 without a throttling message is throttling by status.
+	var op Operation
+	op.SetErr(minio.ErrorResponse{StatusCode: http.StatusServiceUnavailable, Message: "Injected fault: " + http.StatusText(http.StatusServiceUnavailable)})
+	for range 10 {
+		a.Observe(op)
+	}
+	observe(90, 0)
+	if got := a.adjust(); got != 500 {
+		t.Fatalf("got %v, want 500", got)
+	}
+}
+
+func TestAdaptiveRateThrottlingClient(t *testing.T) {
+	const (
+		start   = 4000
+		workers = 16
+		delay   = 2 * time.Millisecond
+	)
+	client := &concurrencyThrottler{ObjectClient: NewMemClient("bucket"), delay: delay}
+	// At the start rate every worker is busy, so most requests are throttled.
+	client.limit.Store(2)
+	l := NewRateLimiter(start, 1)
+	a := NewAdaptiveRate(l, AdaptiveOptions{Interval: 10 * time.Millisecond, Increase: start / 10})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go a.Run(ctx)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for l.Wait(ctx) == nil {
+				var op Operation
+				if _, err := client.PutObject(ctx, "bucket", "obj", bytes.NewReader(nil), 0, minio.PutObjectOptions{}); err != nil {
+					op.SetErr(err)
+				}
+				a.Observe(op)
+			}
+		}()
+	}
+	defer wg.Wait()
+	defer cancel()
+
+	waitFor := func(what string, cond func() bool) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for !cond() {
+			if time.Now().After(deadline) {
+				t.Fatalf("timeout waiting for %s, limit is %v", what, l.Limit())
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	waitFor("decrease", func() bool { return l.Limit() <= start/4 })
+	if a.Decreases() == 0 {
+		t.Fatal("no decreases counted")
+	}
+
+	// Throttling subsides, the rate must recover.
+	client.limit.Store(workers)
+	waitFor("recovery", func() bool { return l.Limit() == start })
+}
@@ -25,11 +25,18 @@ import (
 	"time"
 
 	"github.com/minio/minio-go/v7"
+	"github.com/minio/warp/pkg/generator"
 )
 
 // Append benchmarks upload speed via appends.
 type Append struct {
 	Common
+
+	// Pattern, if set, replaces the generated content,
+	// so every append continues the static pattern where the object ends
+	// and the complete object can be verified.
+	Pattern *generator.AppendPattern
+
 	prefixes map[string]struct{}
 }
 
@@ -56,7 +63,10 @@ func (u *Append) Start(ctx context.Context, wait chan struct{}) error {
 		src := u.Source()
 		u.prefixes[src.Prefix()] = struct{}{}
 		go func(i int) {
+			getClient := u.workerClient(i)
 			part := 1
+			// written is the size of the object after the successful uploads.
+			var written int64
 			tmp := src.Object()
 			masterObj := *tmp
 
@@ -80,11 +90,15 @@ func (u *Append) Start(ctx context.Context, wait chan struct{}) error {
 			done := ctx.Done()
 
 			<-wait
+			if u.RampUp.Wait(ctx, i) != nil {
+				return
+			}
 			for {
 				if part >= 10000 {
 					tmp := src.Object()
 					masterObj = *tmp
 					part = 1
+					written = 0
 				}
 
 				select {
@@ -93,6 +107,9 @@ func (u *Append) Start(ctx context.Context, wait chan struct{}) error {
 				default:
 				}
 
+				if u.ThinkTime.Wait(ctx) != nil {
+					return
+				}
 				if u.rpsLimit(ctx) != nil {
 					return
 				}
@@ -100,9 +117,17 @@ func (u *Append) Start(ctx context.Context, wait chan struct{}) error {
 				obj.Name = masterObj.Name
 				obj.Prefix = masterObj.Prefix
 				obj.ContentType = masterObj.ContentType
+				if u.Pattern != nil {
+					r, err := u.Pattern.Append(written, obj.Size)
+					if err != nil {
+						u.Error("append pattern: ", err)
+						return
+					}
+					obj.Reader = r
+				}
 
 				opts.ContentType = obj.ContentType
-				client, cldone := u.Client()
+				client, cldone := getClient()
 				op := Operation{
 					OpType:   "APPEND",
 					Thread:   uint32(i),
@@ -123,17 +148,23 @@ func (u *Append) Start(ctx context.Context, wait chan struct{}) error {
 				op.End = time.Now()
 				if err != nil {
 					u.Error("upload error: ", err)
-					op.Err = err.Error()
+					op.SetErr(err)
 				}
 
-				if res.Size != int64(part)*obj.Size && op.Err == "" {
-					err := fmt.Sprint("part ", part, " short upload. want:", int64(part)*obj.Size, ", got:", res.Size)
+				if res.Size != written+obj.Size && op.Err == "" {
+					err := fmt.Sprint("part ", part, " short upload. want:", written+obj.Size, ", got:", res.Size)
 					if op.Err == "" {
 						op.Err = err
 					}
 					u.Error(err)
+					// The object no longer continues the pattern, start a new one.
+					part = 10000
+				}
+				// Only successful uploads are part of the object.
+				if op.Err == "" {
+					written += obj.Size
+					part++
 				}
-				part++
 				cldone()
 				rcv <- op
 			}
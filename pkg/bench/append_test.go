@@ -0,0 +1,119 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/warp/pkg/generator"
+)
+
+func TestAppendPatternContinuous(t *testing.T) {
+	var mu sync.Mutex
+	objects := make(map[string][]byte)
+	appends := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		name := r.URL.Path
+		switch r.Method {
+		case http.MethodHead:
+			obj, ok := objects[name]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Length", strconv.Itoa(len(obj)))
+			w.Header().Set("ETag", `"d41d8cd98f00b204e9800998ecf8427e"`)
+			w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+			return
+		case http.MethodPut:
+		default:
+			w.WriteHeader(http.StatusNotImplemented)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if off := r.Header.Get("x-amz-write-offset-bytes"); off != "" {
+			// Every third append fails without changing the object.
+			if appends++; appends%3 == 0 {
+				w.WriteHeader(http.StatusBadRequest)
+				io.WriteString(w, `<Error><Code>InvalidRequest</Code><Message>Injected failure</Message></Error>`)
+				return
+			}
+			body = append(objects[name], body...)
+		}
+		objects[name] = body
+		w.Header().Set("x-amz-object-size", strconv.Itoa(len(body)))
+		w.Header().Set("ETag", `"d41d8cd98f00b204e9800998ecf8427e"`)
+	}))
+	defer srv.Close()
+	cl, err := minio.New(strings.TrimPrefix(srv.URL, "http://"), &minio.Options{
+		Creds:           credentials.NewStaticV4("access", "secret", ""),
+		Region:          "us-east-1",
+		BucketLookup:    minio.BucketLookupPath,
+		TrailingHeaders: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Random sizes, so appends do not start at a multiple of one size.
+	src, err := generator.NewFn(generator.WithRandomData().Apply(), generator.WithRandomSize(true), generator.WithMinMaxSize(256, 4000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pattern := generator.NewAppendPattern(1000)
+	b := &Append{
+		Common: Common{
+			Source:      src,
+			Bucket:      "bucket",
+			Concurrency: 2,
+			Client:      func() (*minio.Client, func()) { return cl, func() {} },
+			Error:       func(data ...any) {},
+			PutOpts:     minio.PutObjectOptions{DisableContentSha256: true},
+		},
+		Pattern: pattern,
+	}
+	if _, err := RunFor(context.Background(), b, 200*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if appends < 3 || len(objects) == 0 {
+		t.Fatalf("%d appends to %d objects", appends, len(objects))
+	}
+	for name, obj := range objects {
+		if err := generator.Verify(bytes.NewReader(obj), pattern.Pattern(), int64(len(obj))); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"maps"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+	"github.com/minio/warp/pkg/generator"
+)
+
+// ObjectAttrs are attributes sent with an uploaded object.
+type ObjectAttrs struct {
+	// ContentType overrides the content type of the object, if set.
+	ContentType string
+
+	// Metadata is added to the user metadata of the object.
+	Metadata map[string]string
+
+	// LockMode and RetainUntil set Object Lock retention, if LockMode is set.
+	LockMode    minio.RetentionMode
+	RetainUntil time.Time
+
+	// LegalHold places a legal hold on the object.
+	LegalHold bool
+
+	// SSE, if set, encrypts the object on the server instead of the
+	// encryption set in the upload options.
+	// Objects encrypted with SSE-C can only be read with GetOptions applied.
+	SSE encrypt.ServerSide
+}
+
+// Locked returns whether the object is uploaded with retention or a legal hold.
+// Deleting it may need a governance bypass, or may not be possible at all.
+func (a ObjectAttrs) Locked() bool {
+	return a.LockMode != "" || a.LegalHold
+}
+
+// AttrsSource returns attributes for uploaded objects.
+type AttrsSource struct {
+	// ContentType is set on all objects, if not empty.
+	ContentType string
+
+	// Metadata, if set, generates metadata for every object.
+	Metadata *generator.MetadataGenerator
+
+	// LockMode, if set, uploads objects with Object Lock retention
+	// until RetainFor after the upload starts.
+	// The bucket must have Object Lock enabled.
+	LockMode  minio.RetentionMode
+	RetainFor time.Duration
+
+	// LegalHold places a legal hold on all objects.
+	LegalHold bool
+
+	// SSE, if set, encrypts all objects with SSE-S3, SSE-KMS or SSE-C.
+	SSE encrypt.ServerSide
+}
+
+// Next returns attributes for the next object.
+// A nil *AttrsSource returns empty attributes.
+func (a *AttrsSource) Next() ObjectAttrs {
+	if a == nil {
+		return ObjectAttrs{}
+	}
+	attrs := ObjectAttrs{ContentType: a.ContentType, LegalHold: a.LegalHold, SSE: a.SSE}
+	if a.Metadata != nil {
+		attrs.Metadata = a.Metadata.Next()
+	}
+	if a.LockMode != "" {
+		attrs.LockMode = a.LockMode
+		attrs.RetainUntil = time.Now().Add(a.RetainFor)
+	}
+	return attrs
+}
+
+// Apply returns opts with the attributes applied.
+// The user metadata of opts is not modified.
+func (a ObjectAttrs) Apply(opts minio.PutObjectOptions) minio.PutObjectOptions {
+	if a.ContentType != "" {
+		opts.ContentType = a.ContentType
+	}
+	if len(a.Metadata) > 0 {
+		md := make(map[string]string, len(opts.UserMetadata)+len(a.Metadata))
+		maps.Copy(md, opts.UserMetadata)
+		maps.Copy(md, a.Metadata)
+		opts.UserMetadata = md
+	}
+	if a.LockMode != "" {
+		opts.Mode = a.LockMode
+		opts.RetainUntilDate = a.RetainUntil
+	}
+	if a.LegalHold {
+		opts.LegalHold = minio.LegalHoldEnabled
+	}
+	if a.SSE != nil {
+		opts.ServerSideEncryption = a.SSE
+	}
+	return opts
+}
+
+// GetOptions returns opts with the SSE-C key needed to read the object, if any.
+// SSE-S3 and SSE-KMS objects are decrypted by the server without headers,
+// so opts is returned unchanged for those.
+func (a ObjectAttrs) GetOptions(opts minio.GetObjectOptions) minio.GetObjectOptions {
+	if a.SSE != nil && a.SSE.Type() == encrypt.SSEC {
+		opts.ServerSideEncryption = a.SSE
+	}
+	return opts
+}
+
+// putObjectAttrs uploads obj with attrs applied to opts.
+func putObjectAttrs(ctx context.Context, client ObjectPutter, bucket string, obj *generator.Object, attrs ObjectAttrs, opts minio.PutObjectOptions) (minio.UploadInfo, error) {
+	opts.ContentType = obj.ContentType
+	return client.PutObject(ctx, bucket, obj.Name, obj.Reader, obj.Size, attrs.Apply(opts))
+}
+
+// putStream uploads obj.Size bytes of obj.Reader through a generator.FixedLengthStreamReader,
+// so the client cannot seek it, with the size given explicitly.
+// Multipart uploads are disabled, so objects of any size are sent in a single PUT.
+func putStream(ctx context.Context, client ObjectPutter, bucket string, obj *generator.Object, attrs ObjectAttrs, opts minio.PutObjectOptions) (minio.UploadInfo, error) {
+	r := generator.NewFixedLengthStreamReader(obj.Reader, obj.Size)
+	opts.ContentType = obj.ContentType
+	opts.DisableMultipart = true
+	return client.PutObject(ctx, bucket, obj.Name, r, r.Size(), attrs.Apply(opts))
+}
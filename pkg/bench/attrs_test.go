@@ -0,0 +1,174 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+	"github.com/minio/warp/pkg/generator"
+)
+
+func TestObjectAttrs(t *testing.T) {
+	md, err := generator.NewMetadataGenerator(4, 512, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := &AttrsSource{ContentType: "application/json", Metadata: md}
+	opts := minio.PutObjectOptions{UserMetadata: map[string]string{"fixed": "value"}}
+	client := &mockPutter{}
+	g, _ := generator.NewGenerator(generator.GeneratorStatic)
+	for _, name := range []string{"a", "b"} {
+		obj := &generator.Object{Name: name, Size: 100, ContentType: "application/octet-stream", Reader: g.Reader(100)}
+		if _, err := putObjectAttrs(context.Background(), client, "bucket", obj, src.Next(), opts); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"a", "b"} {
+		got := client.opts[name]
+		if got.ContentType != "application/json" {
+			t.Errorf("%s: content type %q", name, got.ContentType)
+		}
+		if got.UserMetadata["fixed"] != "value" {
+			t.Errorf("%s: fixed metadata missing", name)
+		}
+		delete(got.UserMetadata, "fixed")
+		if size := generator.MetadataSize(got.UserMetadata); size != 512 {
+			t.Errorf("%s: metadata size %d, want 512", name, size)
+		}
+	}
+	if len(opts.UserMetadata) != 1 {
+		t.Error("options metadata was modified")
+	}
+
+	// Without attributes the object content type is used.
+	var none *AttrsSource
+	obj := &generator.Object{Name: "c", Size: 10, ContentType: "text/plain", Reader: g.Reader(10)}
+	if _, err := putObjectAttrs(context.Background(), client, "bucket", obj, none.Next(), minio.PutObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := client.opts["c"]; got.ContentType != "text/plain" || len(got.UserMetadata) != 0 {
+		t.Errorf("unexpected options without attributes: %+v", got)
+	}
+}
+
+func TestObjectAttrsLock(t *testing.T) {
+	src := &AttrsSource{LockMode: minio.Governance, RetainFor: time.Hour, LegalHold: true}
+	client := &mockPutter{}
+	g, _ := generator.NewGenerator(generator.GeneratorStatic)
+	start := time.Now()
+	attrs := src.Next()
+	if !attrs.Locked() {
+		t.Fatal("attributes with retention should be locked")
+	}
+	obj := &generator.Object{Name: "locked", Size: 100, Reader: g.Reader(100)}
+	if _, err := putObjectAttrs(context.Background(), client, "bucket", obj, attrs, minio.PutObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	h := client.opts["locked"].Header()
+	if got := h.Get("X-Amz-Object-Lock-Mode"); got != "GOVERNANCE" {
+		t.Errorf("lock mode header %q, want GOVERNANCE", got)
+	}
+	until, err := time.Parse(time.RFC3339, h.Get("X-Amz-Object-Lock-Retain-Until-Date"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := start.Add(time.Hour); until.Before(want.Add(-time.Second)) || until.After(want.Add(time.Minute)) {
+		t.Errorf("retain until %v, want about %v", until, want)
+	}
+	if got := h.Get("X-Amz-Object-Lock-Legal-Hold"); got != "ON" {
+		t.Errorf("legal hold header %q, want ON", got)
+	}
+
+	// Only a legal hold.
+	attrs = (&AttrsSource{LegalHold: true}).Next()
+	if !attrs.Locked() || attrs.LockMode != "" {
+		t.Errorf("unexpected legal hold attributes %+v", attrs)
+	}
+	opts := attrs.Apply(minio.PutObjectOptions{})
+	if opts.Header().Get("X-Amz-Object-Lock-Mode") != "" || opts.LegalHold != minio.LegalHoldEnabled {
+		t.Errorf("unexpected legal hold options %+v", opts)
+	}
+	if (ObjectAttrs{}).Locked() {
+		t.Error("empty attributes should not be locked")
+	}
+}
+
+func TestObjectAttrsSSE(t *testing.T) {
+	ssec, err := encrypt.NewSSEC(make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	kms, err := encrypt.NewSSEKMS("my-key", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		sse     encrypt.ServerSide
+		put     map[string]string
+		getKeys bool
+	}{
+		{name: "sse-s3", sse: encrypt.NewSSE(), put: map[string]string{"X-Amz-Server-Side-Encryption": "AES256"}},
+		{name: "sse-kms", sse: kms, put: map[string]string{
+			"X-Amz-Server-Side-Encryption":                "aws:kms",
+			"X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id": "my-key",
+		}},
+		{name: "sse-c", sse: ssec, getKeys: true, put: map[string]string{
+			"X-Amz-Server-Side-Encryption-Customer-Algorithm": "AES256",
+			"X-Amz-Server-Side-Encryption-Customer-Key":       "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=",
+			"X-Amz-Server-Side-Encryption-Customer-Key-Md5":   "cLyPS3KoaSFGi/joRB3OUQ==",
+		}},
+	}
+	g, _ := generator.NewGenerator(generator.GeneratorStatic)
+	for _, test := range tests {
+		client := &mockPutter{}
+		attrs := (&AttrsSource{SSE: test.sse}).Next()
+		obj := &generator.Object{Name: test.name, Size: 10, Reader: g.Reader(10)}
+		if _, err := putObjectAttrs(context.Background(), client, "bucket", obj, attrs, minio.PutObjectOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		h := client.opts[test.name].Header()
+		for k, want := range test.put {
+			if got := h.Get(k); got != want {
+				t.Errorf("%s: put header %s = %q, want %q", test.name, k, got, want)
+			}
+		}
+		// Only SSE-C needs the key to read the object back.
+		opts := attrs.GetOptions(minio.GetObjectOptions{})
+		gh := opts.Header()
+		if got := gh.Get("X-Amz-Server-Side-Encryption-Customer-Key") != ""; got != test.getKeys {
+			t.Errorf("%s: get sends customer key: %v, want %v", test.name, got, test.getKeys)
+		}
+		if gh.Get("X-Amz-Server-Side-Encryption") != "" {
+			t.Errorf("%s: get sends encryption header %v", test.name, gh)
+		}
+	}
+
+	// Attributes override the encryption in the options.
+	opts := (&AttrsSource{SSE: ssec}).Next().Apply(minio.PutObjectOptions{ServerSideEncryption: encrypt.NewSSE()})
+	if opts.ServerSideEncryption.Type() != encrypt.SSEC {
+		t.Errorf("encryption %v, want SSE-C", opts.ServerSideEncryption.Type())
+	}
+	if opts := (ObjectAttrs{}).Apply(minio.PutObjectOptions{ServerSideEncryption: kms}); opts.ServerSideEncryption.Type() != encrypt.KMS {
+		t.Error("options encryption was dropped")
+	}
+}
@@ -0,0 +1,179 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/warp/pkg/generator"
+)
+
+// maxDeleteBatch is the maximum number of objects in a DeleteObjects request.
+const maxDeleteBatch = 1000
+
+// ObjectRemover removes objects in batches.
+// It is implemented by *minio.Client.
+type ObjectRemover interface {
+	RemoveObjects(ctx context.Context, bucketName string, objectsCh <-chan minio.ObjectInfo, opts minio.RemoveObjectsOptions) <-chan minio.RemoveObjectError
+}
+
+// BatchDeleteResult is the result of a single BatchDelete.
+type BatchDeleteResult struct {
+	Start, End time.Time
+
+	// Requested is the number of objects in the batch.
+	Requested int
+
+	// Deleted is the number of objects that were deleted.
+	Deleted int
+
+	// Failed contains the errors of the objects that could not be deleted.
+	Failed []minio.RemoveObjectError
+
+	// Remaining contains the objects that could not be deleted.
+	Remaining generator.Objects
+}
+
+// BatchDelete takes up to batchSize objects from set and deletes them with a single DeleteObjects request.
+// Objects that fail to delete are not returned to the set,
+// so persistent failures are only attempted once. They are listed in Remaining.
+// batchSize is capped at 1000.
+func BatchDelete(ctx context.Context, client ObjectRemover, bucket string, set *ObjectSet, batchSize int) BatchDeleteResult {
+	return deleteBatch(ctx, client, bucket, set.Take(min(batchSize, maxDeleteBatch)))
+}
+
+// deleteBatch deletes objs with a single DeleteObjects request.
+func deleteBatch(ctx context.Context, client ObjectRemover, bucket string, objs generator.Objects) BatchDeleteResult {
+	res := BatchDeleteResult{Requested: len(objs)}
+	if len(objs) == 0 {
+		return res
+	}
+	objects := make(chan minio.ObjectInfo, len(objs))
+	for _, obj := range objs {
+		objects <- minio.ObjectInfo{Key: obj.Name, VersionID: obj.VersionID}
+	}
+	close(objects)
+
+	res.Start = time.Now()
+	failed := make(map[objectVersion]struct{})
+	opts := minio.RemoveObjectsOptions{GovernanceBypass: slices.ContainsFunc(objs, func(obj generator.Object) bool { return obj.Locked })}
+	for err := range client.RemoveObjects(ctx, bucket, objects, opts) {
+		if err.Err == nil {
+			continue
+		}
+		res.Failed = append(res.Failed, err)
+		failed[objectVersion{name: err.ObjectName, versionID: err.VersionID}] = struct{}{}
+	}
+	res.End = time.Now()
+
+	for _, obj := range objs {
+		if _, ok := failed[objectVersion{name: obj.Name, versionID: obj.VersionID}]; ok {
+			res.Remaining = append(res.Remaining, obj)
+			continue
+		}
+		res.Deleted++
+	}
+	return res
+}
+
+// DeleteAllResult is the result of DeleteAll.
+type DeleteAllResult struct {
+	// Deleted is the number of objects that were deleted.
+	Deleted int
+
+	// Failed contains the objects that could not be deleted.
+	Failed []minio.RemoveObjectError
+
+	// Compliance contains the objects that were not deleted,
+	// because they have COMPLIANCE retention that has not expired.
+	Compliance generator.Objects
+}
+
+// DeleteAll deletes all objects in set, with up to concurrency DeleteObjects
+// requests of at most batchSize objects running at once.
+// Failures do not stop the remaining objects from being deleted.
+// Every object is attempted once, and objects that fail to delete are returned to the set.
+// Legal holds of locked objects are removed and GOVERNANCE retention is bypassed.
+// Objects with COMPLIANCE retention are not attempted and remain in the set.
+func DeleteAll(ctx context.Context, client ObjectRemover, bucket string, set *ObjectSet, concurrency, batchSize int) DeleteAllResult {
+	res, remaining := deleteObjects(ctx, client, bucket, set.Take(set.Len()), concurrency, batchSize)
+	for _, obj := range remaining {
+		set.Add(obj)
+	}
+	return res
+}
+
+// deleteObjects deletes objs like DeleteAll and returns the objects that could not be deleted.
+func deleteObjects(ctx context.Context, client ObjectRemover, bucket string, objs generator.Objects, concurrency, batchSize int) (DeleteAllResult, generator.Objects) {
+	var res DeleteAllResult
+	if compliance := releaseLocks(ctx, client, bucket, objs); len(compliance) > 0 {
+		skip := make(map[objectVersion]struct{}, len(compliance))
+		for _, obj := range compliance {
+			skip[objectVersion{name: obj.Name, versionID: obj.VersionID}] = struct{}{}
+		}
+		objs = slices.DeleteFunc(slices.Clone(objs), func(obj generator.Object) bool {
+			_, ok := skip[objectVersion{name: obj.Name, versionID: obj.VersionID}]
+			return ok
+		})
+		res.Compliance = compliance
+	}
+	// Locked objects are deleted last, in batches with a governance bypass.
+	slices.SortStableFunc(objs, func(a, b generator.Object) int {
+		switch {
+		case a.Locked == b.Locked:
+			return 0
+		case b.Locked:
+			return -1
+		}
+		return 1
+	})
+	batchSize = min(max(batchSize, 1), maxDeleteBatch)
+	batches := make(chan generator.Objects)
+	go func() {
+		defer close(batches)
+		for len(objs) > 0 {
+			n := min(batchSize, len(objs))
+			batches <- objs[:n]
+			objs = objs[n:]
+		}
+	}()
+
+	remaining := slices.Clone(res.Compliance)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for range max(concurrency, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range batches {
+				r := deleteBatch(ctx, client, bucket, batch)
+				mu.Lock()
+				res.Deleted += r.Deleted
+				res.Failed = append(res.Failed, r.Failed...)
+				remaining = append(remaining, r.Remaining...)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return res, remaining
+}
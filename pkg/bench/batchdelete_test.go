@@ -0,0 +1,323 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/warp/pkg/generator"
+)
+
+// mockRemover fails to delete objects with names containing "fail".
+type mockRemover struct {
+	calls   int
+	batches []int
+}
+
+func (m *mockRemover) RemoveObjects(ctx context.Context, bucketName string, objectsCh <-chan minio.ObjectInfo, opts minio.RemoveObjectsOptions) <-chan minio.RemoveObjectError {
+	m.calls++
+	errCh := make(chan minio.RemoveObjectError, maxDeleteBatch)
+	n := 0
+	for obj := range objectsCh {
+		n++
+		if strings.Contains(obj.Key, "fail") {
+			errCh <- minio.RemoveObjectError{ObjectName: obj.Key, Err: errors.New("access denied")}
+		}
+	}
+	m.batches = append(m.batches, n)
+	close(errCh)
+	return errCh
+}
+
+func TestBatchDelete(t *testing.T) {
+	set := NewObjectSet()
+	for i := range 25 {
+		name := fmt.Sprintf("obj-%d", i)
+		if i%5 == 0 {
+			name += "-fail"
+		}
+		set.Add(generator.Object{Name: name})
+	}
+	client := &mockRemover{}
+	var deleted, failed int
+	var remaining generator.Objects
+	for range 3 {
+		res := BatchDelete(context.Background(), client, "bucket", set, 10)
+		deleted += res.Deleted
+		failed += len(res.Failed)
+		remaining = append(remaining, res.Remaining...)
+		if res.Deleted+len(res.Failed) != res.Requested || len(res.Remaining) != len(res.Failed) {
+			t.Errorf("deleted %d + failed %d != requested %d, %d remaining", res.Deleted, len(res.Failed), res.Requested, len(res.Remaining))
+		}
+		if res.End.Before(res.Start) {
+			t.Error("end before start")
+		}
+	}
+	if client.calls != 3 {
+		t.Errorf("got %d requests, want 3", client.calls)
+	}
+	for _, n := range client.batches {
+		if n > 10 {
+			t.Errorf("batch of %d objects, want at most 10", n)
+		}
+	}
+	if deleted != 20 {
+		t.Errorf("deleted %d objects, want 20", deleted)
+	}
+	// Every failing object is attempted and counted once.
+	if failed != 5 || set.Len() != 0 {
+		t.Errorf("got %d failures, %d objects left in set, want 5 and 0", failed, set.Len())
+	}
+	if len(remaining) != 5 {
+		t.Errorf("got %d remaining objects, want 5", len(remaining))
+	}
+	for _, obj := range remaining {
+		if !strings.HasSuffix(obj.Name, "-fail") {
+			t.Errorf("deleted object %s returned as remaining", obj.Name)
+		}
+	}
+
+	// Batch size is capped.
+	for i := range 1500 {
+		set.Add(generator.Object{Name: fmt.Sprint("big-", i)})
+	}
+	if res := BatchDelete(context.Background(), client, "bucket", set, 5000); res.Requested != maxDeleteBatch {
+		t.Errorf("got batch of %d, want %d", res.Requested, maxDeleteBatch)
+	}
+
+	empty := BatchDelete(context.Background(), client, "bucket", NewObjectSet(), 10)
+	if empty.Requested != 0 || client.calls != 4 {
+		t.Errorf("empty set: requested %d, %d calls", empty.Requested, client.calls)
+	}
+}
+
+// failingRemover deletes from a MemClient, except objects with names containing "fail".
+type failingRemover struct {
+	*MemClient
+	mu                 sync.Mutex
+	inFlight, maxCalls int
+}
+
+func (f *failingRemover) RemoveObjects(ctx context.Context, bucketName string, objectsCh <-chan minio.ObjectInfo, opts minio.RemoveObjectsOptions) <-chan minio.RemoveObjectError {
+	f.mu.Lock()
+	f.inFlight++
+	f.maxCalls = max(f.maxCalls, f.inFlight)
+	f.mu.Unlock()
+	defer func() {
+		f.mu.Lock()
+		f.inFlight--
+		f.mu.Unlock()
+	}()
+	time.Sleep(time.Millisecond)
+	errCh := make(chan minio.RemoveObjectError, maxDeleteBatch)
+	defer close(errCh)
+	for obj := range objectsCh {
+		if strings.Contains(obj.Key, "fail") {
+			errCh <- minio.RemoveObjectError{ObjectName: obj.Key, Err: errors.New("access denied")}
+			continue
+		}
+		if err := f.RemoveObject(ctx, bucketName, obj.Key, minio.RemoveObjectOptions{}); err != nil {
+			errCh <- minio.RemoveObjectError{ObjectName: obj.Key, Err: err}
+		}
+	}
+	return errCh
+}
+
+func TestDeleteAll(t *testing.T) {
+	mc := NewMemClient("bucket")
+	set := NewObjectSet()
+	for i := range 2500 {
+		name := fmt.Sprintf("obj-%d", i)
+		if i%100 == 0 {
+			name += "-fail"
+		}
+		if _, err := mc.PutObject(context.Background(), "bucket", name, strings.NewReader("data"), 4, minio.PutObjectOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		set.Add(generator.Object{Name: name, Size: 4})
+	}
+	client := &failingRemover{MemClient: mc}
+	res := DeleteAll(context.Background(), client, "bucket", set, 4, 50)
+	if res.Deleted != 2475 || len(res.Failed) != 25 {
+		t.Fatalf("deleted %d, failed %d, want 2475 and 25", res.Deleted, len(res.Failed))
+	}
+	if client.maxCalls > 4 {
+		t.Errorf("%d concurrent requests, want at most 4", client.maxCalls)
+	}
+	if client.maxCalls < 2 {
+		t.Errorf("requests were not concurrent")
+	}
+	// Failed objects stay in the bucket and the set, everything else is gone.
+	if mc.Len("bucket") != 25 || set.Len() != 25 {
+		t.Errorf("%d objects in bucket, %d in set, want 25", mc.Len("bucket"), set.Len())
+	}
+	for _, obj := range set.Objects() {
+		if !strings.HasSuffix(obj.Name, "-fail") {
+			t.Errorf("deleted object %s still tracked", obj.Name)
+		}
+	}
+
+	if res := DeleteAll(context.Background(), client, "bucket", NewObjectSet(), 4, 50); res.Deleted != 0 || len(res.Failed) != 0 {
+		t.Errorf("empty set: %+v", res)
+	}
+}
+
+func TestDeleteFailures(t *testing.T) {
+	// Objects with names containing "fail" can never be deleted.
+	var requests atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		var req struct {
+			Objects []struct{ Key string } `xml:"Object"`
+		}
+		if err := xml.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+		}
+		var b strings.Builder
+		b.WriteString(`<DeleteResult xmlns="http://s3.amazonaws.com/doc/2006-03-01/">`)
+		for _, obj := range req.Objects {
+			if strings.Contains(obj.Key, "fail") {
+				fmt.Fprintf(&b, "<Error><Key>%s</Key><Code>AccessDenied</Code><Message>Access Denied.</Message></Error>", obj.Key)
+				continue
+			}
+			fmt.Fprintf(&b, "<Deleted><Key>%s</Key></Deleted>", obj.Key)
+		}
+		b.WriteString("</DeleteResult>")
+		w.Write([]byte(b.String()))
+	}))
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+	cl, err := minio.New(u.Host, &minio.Options{Creds: credentials.NewStaticV4("access", "secret", ""), Region: "us-east-1", BucketLookup: minio.BucketLookupPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var objs generator.Objects
+	for i := range 25 {
+		name := fmt.Sprintf("obj-%d", i)
+		if i%5 == 0 {
+			name += "-fail"
+		}
+		objs = append(objs, generator.Object{Name: name})
+	}
+	var errs atomic.Int64
+	d := &Delete{
+		Common: Common{
+			Client:      func() (*minio.Client, func()) { return cl, func() {} },
+			Bucket:      "bucket",
+			Concurrency: 1,
+			Error:       func(data ...any) { errs.Add(1) },
+		},
+		objects:   objs,
+		BatchSize: 10,
+	}
+	ops, err := RunFor(context.Background(), d, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// One operation per request, and failed objects are not retried.
+	if len(ops) != 3 || requests.Load() != 3 {
+		t.Fatalf("got %d operations for %d requests, want 3", len(ops), requests.Load())
+	}
+	var deleted, withErr int
+	for _, op := range ops {
+		deleted += op.ObjPerOp
+		if op.Err != "" {
+			withErr++
+			if op.ErrCat == ErrCatNone || !strings.HasSuffix(op.Err, "Access Denied.") {
+				t.Errorf("error %q stored as %v", op.Err, op.ErrCat)
+			}
+		}
+	}
+	if deleted != 20 || withErr == 0 || errs.Load() != int64(withErr) {
+		t.Errorf("deleted %d, %d failed requests, %d errors reported", deleted, withErr, errs.Load())
+	}
+	// Failed objects are left for cleanup.
+	if d.failed.Len() != 5 {
+		t.Errorf("%d objects left for cleanup, want 5", d.failed.Len())
+	}
+}
+
+// lockingRemover is a mockRemover with Object Lock retention and legal holds.
+type lockingRemover struct {
+	mockRemover
+	retention map[string]minio.RetentionMode
+	released  []string
+	bypassed  []bool
+}
+
+func (l *lockingRemover) RemoveObjects(ctx context.Context, bucketName string, objectsCh <-chan minio.ObjectInfo, opts minio.RemoveObjectsOptions) <-chan minio.RemoveObjectError {
+	l.bypassed = append(l.bypassed, opts.GovernanceBypass)
+	return l.mockRemover.RemoveObjects(ctx, bucketName, objectsCh, opts)
+}
+
+func (l *lockingRemover) PutObjectLegalHold(ctx context.Context, bucketName, objectName string, opts minio.PutObjectLegalHoldOptions) error {
+	if *opts.Status == minio.LegalHoldDisabled {
+		l.released = append(l.released, objectName)
+	}
+	return nil
+}
+
+func (l *lockingRemover) GetObjectRetention(ctx context.Context, bucketName, objectName, versionID string) (*minio.RetentionMode, *time.Time, error) {
+	mode, ok := l.retention[objectName]
+	if !ok {
+		return nil, nil, errors.New("no retention")
+	}
+	until := time.Now().Add(time.Hour)
+	return &mode, &until, nil
+}
+
+func TestDeleteAllLocked(t *testing.T) {
+	set := NewObjectSet()
+	for _, obj := range []generator.Object{{Name: "gov", Locked: true}, {Name: "plain"}, {Name: "hold", Locked: true}, {Name: "comp", Locked: true}} {
+		set.Add(obj)
+	}
+	client := &lockingRemover{retention: map[string]minio.RetentionMode{"gov": minio.Governance, "comp": minio.Compliance}}
+	res := DeleteAll(context.Background(), client, "bucket", set, 1, 1)
+	if res.Deleted != 3 || len(res.Failed) != 0 {
+		t.Fatalf("deleted %d, failed %d, want 3 and 0", res.Deleted, len(res.Failed))
+	}
+	slices.Sort(client.released)
+	if want := []string{"comp", "gov", "hold"}; !slices.Equal(client.released, want) {
+		t.Errorf("released legal holds of %v, want %v", client.released, want)
+	}
+	// Unlocked objects are deleted first without a bypass.
+	if want := []bool{false, true, true}; !slices.Equal(client.bypassed, want) {
+		t.Errorf("governance bypass %v, want %v", client.bypassed, want)
+	}
+	// COMPLIANCE objects are not attempted and stay in the set.
+	if len(res.Compliance) != 1 || res.Compliance[0].Name != "comp" || set.Len() != 1 || !set.Contains("comp") {
+		t.Errorf("compliance %+v, %d left in set", res.Compliance, set.Len())
+	}
+	if locked := set.Locked(); len(locked) != 1 {
+		t.Errorf("got %d locked objects, want 1", len(locked))
+	}
+}
@@ -28,8 +28,6 @@ import (
 
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/warp/pkg/generator"
-
-	"golang.org/x/time/rate"
 )
 
 type Benchmark interface {
@@ -47,6 +45,31 @@ type Benchmark interface {
 	GetCommon() *Common
 }
 
+// bucketSpreader is implemented by benchmarks that use Common.Buckets.
+type bucketSpreader interface {
+	spreadsBuckets()
+}
+
+// Validate returns an error if b has settings that it does not use.
+func Validate(b Benchmark) error {
+	if b.GetCommon().Buckets != nil {
+		if _, ok := b.(bucketSpreader); !ok {
+			return fmt.Errorf("%T does not support multiple buckets", b)
+		}
+	}
+	if b.GetCommon().Retry != nil {
+		if _, ok := b.(opRetrier); !ok {
+			return fmt.Errorf("%T does not support retries", b)
+		}
+	}
+	if b.GetCommon().OpTimeout > 0 {
+		if _, ok := b.(opTimeouter); !ok {
+			return fmt.Errorf("%T does not support operation timeouts", b)
+		}
+	}
+	return nil
+}
+
 // Common contains common benchmark parameters.
 type Common struct {
 	// Default Put options.
@@ -71,11 +94,20 @@ type Common struct {
 
 	Client func() (cl *minio.Client, done func())
 
+	// WorkerClient, if set, returns the client function used by each benchmark worker.
+	// This allows workers to use separate connection pools.
+	// If nil, all workers use Client.
+	WorkerClient func(worker int) func() (cl *minio.Client, done func())
+
 	Collector Collector
 
 	Location string
 	Bucket   string
 
+	// Buckets, if set, picks the bucket of each operation instead of Bucket.
+	// Only the PUT benchmark uses it, Validate rejects it for other benchmarks.
+	Buckets *generator.BucketSelector
+
 	// Auto termination is set when this is > 0.
 	AutoTermDur time.Duration
 
@@ -101,7 +133,21 @@ type Common struct {
 	Versioned bool
 
 	// ratelimiting
-	RpsLimiter *rate.Limiter
+	RpsLimiter *RateLimiter
+
+	// Retry transient failures, if set.
+	Retry *Retrier
+
+	// ThinkTime is the pause of each worker before every operation, if set.
+	ThinkTime *ThinkTime
+
+	// RampUp staggers the start of workers, if set.
+	RampUp *RampUp
+
+	// OpTimeout cancels operations that do not complete within this duration, if > 0.
+	// Canceled operations are recorded as timeout errors.
+	// Only the GET, PUT and STAT benchmarks use it.
+	OpTimeout time.Duration
 
 	// Transport used.
 	Transport http.RoundTripper
@@ -119,6 +165,14 @@ const (
 	autoTermCheck = 7
 )
 
+// workerClient returns the client function for benchmark worker i.
+func (c *Common) workerClient(i int) func() (*minio.Client, func()) {
+	if c.WorkerClient == nil {
+		return c.Client
+	}
+	return c.WorkerClient(i)
+}
+
 // GetCommon implements interface compatible implementation
 func (c *Common) GetCommon() *Common {
 	return c
@@ -129,28 +183,60 @@ func (c *Common) ErrorF(format string, data ...any) {
 	c.Error(fmt.Sprintf(format, data...))
 }
 
-// createEmptyBucket will create an empty bucket
-// or delete all content if it already exists.
+// createEmptyBucket will create the benchmark buckets
+// or delete all content if they already exist.
 func (c *Common) createEmptyBucket(ctx context.Context) error {
+	for _, bucket := range c.buckets() {
+		if err := c.createEmptyBucketNamed(ctx, bucket); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buckets returns all buckets used by the benchmark.
+func (c *Common) buckets() []string {
+	if c.Buckets != nil {
+		return c.Buckets.Buckets()
+	}
+	return []string{c.Bucket}
+}
+
+// BucketNames returns all buckets used by the benchmark.
+func (c *Common) BucketNames() []string {
+	return c.buckets()
+}
+
+// nextBucket returns the bucket for the next operation.
+func (c *Common) nextBucket() string {
+	if c.Buckets != nil {
+		return c.Buckets.Next()
+	}
+	return c.Bucket
+}
+
+// createEmptyBucketNamed will create an empty bucket
+// or delete all content if it already exists.
+func (c *Common) createEmptyBucketNamed(ctx context.Context, bucket string) error {
 	cl, done := c.Client()
 	defer done()
-	x, err := cl.BucketExists(ctx, c.Bucket)
+	x, err := cl.BucketExists(ctx, bucket)
 	if err != nil {
 		return err
 	}
 
 	if x && c.Locking {
-		_, _, _, err := cl.GetBucketObjectLockConfig(ctx, c.Bucket)
+		_, _, _, err := cl.GetBucketObjectLockConfig(ctx, bucket)
 		if err != nil {
 			if !c.Clear {
 				return errors.New("not allowed to clear bucket to re-create bucket with locking")
 			}
-			if bvc, err := cl.GetBucketVersioning(ctx, c.Bucket); err == nil {
+			if bvc, err := cl.GetBucketVersioning(ctx, bucket); err == nil {
 				c.Versioned = bvc.Status == "Enabled"
 			}
-			c.UpdateStatus(fmt.Sprintf("Clearing Bucket %q to enable locking", c.Bucket))
-			c.deleteAllInBucket(ctx)
-			err = cl.RemoveBucket(ctx, c.Bucket)
+			c.UpdateStatus(fmt.Sprintf("Clearing Bucket %q to enable locking", bucket))
+			c.deleteAllIn(ctx, bucket)
+			err = cl.RemoveBucket(ctx, bucket)
 			if err != nil {
 				return err
 			}
@@ -160,8 +246,8 @@ func (c *Common) createEmptyBucket(ctx context.Context) error {
 	}
 
 	if !x {
-		c.UpdateStatus(fmt.Sprintf("Creating Bucket %q", c.Bucket))
-		err := cl.MakeBucket(ctx, c.Bucket, minio.MakeBucketOptions{
+		c.UpdateStatus(fmt.Sprintf("Creating Bucket %q", bucket))
+		err := cl.MakeBucket(ctx, bucket, minio.MakeBucketOptions{
 			Region:        c.Location,
 			ObjectLocking: c.Locking,
 		})
@@ -169,7 +255,7 @@ func (c *Common) createEmptyBucket(ctx context.Context) error {
 		// Check if it exists now.
 		// We don't test against a specific error since we might run against many different servers.
 		if err != nil {
-			x, err2 := cl.BucketExists(ctx, c.Bucket)
+			x, err2 := cl.BucketExists(ctx, bucket)
 			if err2 != nil {
 				return err2
 			}
@@ -179,20 +265,28 @@ func (c *Common) createEmptyBucket(ctx context.Context) error {
 			}
 		}
 	}
-	if bvc, err := cl.GetBucketVersioning(ctx, c.Bucket); err == nil {
+	if bvc, err := cl.GetBucketVersioning(ctx, bucket); err == nil {
 		c.Versioned = bvc.Status == "Enabled"
 	}
 
 	if c.Clear {
-		c.UpdateStatus(fmt.Sprintf("Clearing Bucket %q", c.Bucket))
-		c.deleteAllInBucket(ctx)
+		c.UpdateStatus(fmt.Sprintf("Clearing Bucket %q", bucket))
+		c.deleteAllIn(ctx, bucket)
 	}
 	return nil
 }
 
-// deleteAllInBucket will delete all content in a bucket.
-// If no prefixes are specified everything in bucket is deleted.
+// deleteAllInBucket will delete all content in the benchmark buckets.
+// If no prefixes are specified everything in the buckets is deleted.
 func (c *Common) deleteAllInBucket(ctx context.Context, prefixes ...string) {
+	for _, bucket := range c.buckets() {
+		c.deleteAllIn(ctx, bucket, prefixes...)
+	}
+}
+
+// deleteAllIn will delete all content in bucket.
+// If no prefixes are specified everything in bucket is deleted.
+func (c *Common) deleteAllIn(ctx context.Context, bucket string, prefixes ...string) {
 	if len(prefixes) == 0 {
 		prefixes = []string{""}
 	}
@@ -216,8 +310,8 @@ func (c *Common) deleteAllInBucket(ctx context.Context, prefixes ...string) {
 				opts.Prefix = prefix + "/"
 			}
 			removed := 0
-			c.UpdateStatus(fmt.Sprintf("Clearing Prefix %q", strings.Join([]string{c.Bucket, opts.Prefix}, "/")))
-			for object := range cl.ListObjects(ctx, c.Bucket, opts) {
+			c.UpdateStatus(fmt.Sprintf("Clearing Prefix %q", strings.Join([]string{bucket, opts.Prefix}, "/")))
+			for object := range cl.ListObjects(ctx, bucket, opts) {
 				if object.Err != nil {
 					c.Error(object.Err)
 					return
@@ -225,19 +319,19 @@ func (c *Common) deleteAllInBucket(ctx context.Context, prefixes ...string) {
 				removed++
 				objectsCh <- object
 				if removed%1000 == 0 {
-					c.UpdateStatus(fmt.Sprintf("Clearing Prefix %q. Deleted %d objects", strings.Join([]string{c.Bucket, opts.Prefix}, "/"), removed))
+					c.UpdateStatus(fmt.Sprintf("Clearing Prefix %q. Deleted %d objects", strings.Join([]string{bucket, opts.Prefix}, "/"), removed))
 				}
 			}
 		}
 	}()
 
 	delOpts := minio.RemoveObjectsOptions{}
-	_, _, _, errLock := cl.GetBucketObjectLockConfig(ctx, c.Bucket)
+	_, _, _, errLock := cl.GetBucketObjectLockConfig(ctx, bucket)
 	if errLock == nil {
 		delOpts.GovernanceBypass = true
 	}
 
-	errCh := cl.RemoveObjects(ctx, c.Bucket, objectsCh, delOpts)
+	errCh := cl.RemoveObjects(ctx, bucket, objectsCh, delOpts)
 	for err := range errCh {
 		if err.Err != nil {
 			c.Error(err.Err)
@@ -260,10 +354,6 @@ func (c *Common) prepareProgress(progress float64) {
 }
 
 func (c *Common) rpsLimit(ctx context.Context) error {
-	if c.RpsLimiter == nil {
-		return nil
-	}
-
 	return c.RpsLimiter.Wait(ctx)
 }
 
@@ -0,0 +1,59 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// BucketMaker checks and creates buckets.
+// It is implemented by *minio.Client.
+type BucketMaker interface {
+	BucketExists(ctx context.Context, bucket string) (bool, error)
+	MakeBucket(ctx context.Context, bucket string, opts minio.MakeBucketOptions) error
+}
+
+// EnsureBuckets creates the buckets in names that do not exist.
+// Existing buckets are left untouched, so it can be called before every run.
+// Set opts.Region for the location constraint of created buckets.
+// The buckets that were created are returned.
+// The first failure is returned with the bucket name,
+// for example when the credentials are not allowed to create buckets.
+func EnsureBuckets(ctx context.Context, client BucketMaker, names []string, opts minio.MakeBucketOptions) (created []string, err error) {
+	for _, name := range names {
+		exists, err := client.BucketExists(ctx, name)
+		if err != nil {
+			return created, fmt.Errorf("checking bucket %q: %w", name, err)
+		}
+		if exists {
+			continue
+		}
+		if err := client.MakeBucket(ctx, name, opts); err != nil {
+			// Another client may have created it first.
+			if exists, err2 := client.BucketExists(ctx, name); err2 != nil || !exists {
+				return created, fmt.Errorf("creating bucket %q: %w", name, err)
+			}
+			continue
+		}
+		created = append(created, name)
+	}
+	return created, nil
+}
@@ -0,0 +1,92 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// memBucketMaker creates buckets in a MemClient.
+// Buckets in denied cannot be created.
+type memBucketMaker struct {
+	*MemClient
+	denied  map[string]bool
+	regions map[string]string
+}
+
+func (m *memBucketMaker) MakeBucket(ctx context.Context, bucket string, opts minio.MakeBucketOptions) error {
+	if m.denied[bucket] {
+		return memError(http.StatusForbidden, "AccessDenied", bucket, "")
+	}
+	if ok, _ := m.BucketExists(ctx, bucket); ok {
+		return memError(http.StatusConflict, "BucketAlreadyOwnedByYou", bucket, "")
+	}
+	m.MemClient.MakeBucket(bucket)
+	m.regions[bucket] = opts.Region
+	return nil
+}
+
+func TestEnsureBuckets(t *testing.T) {
+	ctx := context.Background()
+	c := &memBucketMaker{MemClient: NewMemClient("existing"), regions: map[string]string{}}
+	opts := minio.MakeBucketOptions{Region: "eu-west-1"}
+	created, err := EnsureBuckets(ctx, c, []string{"existing", "new-1", "new-2"}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(created, []string{"new-1", "new-2"}) {
+		t.Errorf("created %v", created)
+	}
+	for _, b := range created {
+		if c.regions[b] != "eu-west-1" {
+			t.Errorf("%s: region %q", b, c.regions[b])
+		}
+	}
+	if _, ok := c.regions["existing"]; ok {
+		t.Error("existing bucket was created")
+	}
+
+	// Running again creates nothing.
+	created, err = EnsureBuckets(ctx, c, []string{"existing", "new-1", "new-2"}, opts)
+	if err != nil || len(created) != 0 {
+		t.Errorf("second run created %v, err %v", created, err)
+	}
+
+	// Denied creation stops with the bucket name and the server error.
+	c.denied = map[string]bool{"denied": true}
+	created, err = EnsureBuckets(ctx, c, []string{"new-3", "denied", "new-4"}, opts)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	var resp minio.ErrorResponse
+	if !errors.As(err, &resp) || resp.Code != "AccessDenied" || err.Error() != `creating bucket "denied": Forbidden` {
+		t.Errorf("unexpected error %v", err)
+	}
+	if !slices.Equal(created, []string{"new-3"}) {
+		t.Errorf("created %v before the error", created)
+	}
+	if ok, _ := c.BucketExists(ctx, "new-4"); ok {
+		t.Error("bucket after the error was created")
+	}
+}
@@ -0,0 +1,79 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// ByteBudget stops a benchmark once operations have transferred a number of bytes.
+// It is safe for concurrent use.
+type ByteBudget struct {
+	limit  int64
+	used   atomic.Int64
+	cancel func()
+	once   sync.Once
+}
+
+// NewByteBudget returns a budget of limit bytes.
+// cancel is called once when the limit is reached and should cancel the benchmark context.
+func NewByteBudget(limit int64, cancel func()) *ByteBudget {
+	return &ByteBudget{limit: limit, cancel: cancel}
+}
+
+// Add counts n transferred bytes and calls cancel if the limit is reached.
+// Returns whether the limit has been reached.
+func (b *ByteBudget) Add(n int64) bool {
+	if b.used.Add(n) < b.limit {
+		return false
+	}
+	b.once.Do(b.cancel)
+	return true
+}
+
+// Used returns the number of bytes counted.
+// A nil budget returns 0.
+func (b *ByteBudget) Used() int64 {
+	if b == nil {
+		return 0
+	}
+	return b.used.Load()
+}
+
+// Exhausted returns whether the limit has been reached.
+// A nil budget is never exhausted.
+func (b *ByteBudget) Exhausted() bool {
+	if b == nil {
+		return false
+	}
+	return b.used.Load() >= b.limit
+}
+
+// Collector returns a collector that adds the size of every successful operation
+// to the budget before forwarding it to c.
+// Operations are counted as they are sent, so each worker will complete
+// about one operation after the limit is reached.
+// Closing the returned collector will also close c.
+func (b *ByteBudget) Collector(c Collector) Collector {
+	return newObserveCollector(c, func(op Operation) {
+		if op.Err == "" {
+			b.Add(op.Size)
+		}
+	})
+}
@@ -0,0 +1,127 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/warp/pkg/generator"
+)
+
+func TestByteBudget(t *testing.T) {
+	var calls int
+	b := NewByteBudget(100, func() { calls++ })
+	if b.Add(60) || b.Exhausted() {
+		t.Fatal("exhausted early")
+	}
+	if !b.Add(40) || !b.Exhausted() || !b.Add(1) {
+		t.Fatal("not exhausted at limit")
+	}
+	if calls != 1 || b.Used() != 101 {
+		t.Errorf("cancel called %d times, used %d", calls, b.Used())
+	}
+
+	// Concurrent adds cross the limit once.
+	calls = 0
+	b = NewByteBudget(1000, func() { calls++ })
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 1000 {
+				b.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if calls != 1 || b.Used() != 8000 {
+		t.Errorf("cancel called %d times, used %d", calls, b.Used())
+	}
+}
+
+func TestByteBudgetStopsRun(t *testing.T) {
+	u := newPutServer(t)
+	cl, err := minio.New(u.Host, &minio.Options{
+		Creds:  credentials.NewStaticV4("access", "secret", ""),
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const (
+		objSize     = 4 << 10
+		concurrency = 4
+		limit       = 200 * objSize
+	)
+	src, err := generator.NewFn(generator.WithRandomData().Apply(), generator.WithSize(objSize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The timeout is only reached if the budget fails.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	budget := NewByteBudget(limit, cancel)
+	collector, ops := NewOpsCollector()
+	b := &Put{Common: Common{
+		Source:      src,
+		Bucket:      "bucket",
+		Concurrency: concurrency,
+		Client:      func() (*minio.Client, func()) { return cl, func() {} },
+		Collector:   budget.Collector(collector),
+		Error:       func(data ...any) { t.Log(data...) },
+	}}
+	start := make(chan struct{})
+	close(start)
+	began := time.Now()
+	if err := b.Start(ctx, start); err != nil {
+		t.Fatal(err)
+	}
+	b.Collector.Close()
+	if time.Since(began) > 5*time.Second {
+		t.Fatal("run was not stopped by the budget")
+	}
+
+	var total int64
+	for _, op := range ops() {
+		if op.Err != "" {
+			if !strings.Contains(op.Err, "context canceled") {
+				t.Errorf("unexpected error: %s", op.Err)
+			}
+			continue
+		}
+		if op.OpType != http.MethodPut {
+			t.Errorf("unexpected op type %s", op.OpType)
+		}
+		total += op.Size
+	}
+	if !budget.Exhausted() || budget.Used() != total {
+		t.Errorf("budget used %d, ops total %d", budget.Used(), total)
+	}
+	// Each worker may finish one more object after the limit is reached.
+	if total < limit || total > limit+concurrency*objSize {
+		t.Errorf("transferred %d bytes, want %d to %d", total, limit, limit+concurrency*objSize)
+	}
+}
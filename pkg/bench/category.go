@@ -34,6 +34,21 @@ const (
 	// CatCacheHit means that caching was detected and the object was cached.
 	CatCacheHit
 
+	// CatFound means that a requested object existed.
+	CatFound
+
+	// CatNotFound means that a requested object did not exist.
+	CatNotFound
+
+	// CatNotModified means that a conditional request returned 304 Not Modified.
+	CatNotModified
+
+	// CatModified means that a conditional request returned the object.
+	CatModified
+
+	// CatLocked means that an object was uploaded with Object Lock retention or a legal hold.
+	CatLocked
+
 	catLength
 )
 
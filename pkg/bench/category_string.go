@@ -10,12 +10,17 @@ func _() {
 	var x [1]struct{}
 	_ = x[CatCacheMiss-0]
 	_ = x[CatCacheHit-1]
-	_ = x[catLength-2]
+	_ = x[CatFound-2]
+	_ = x[CatNotFound-3]
+	_ = x[CatNotModified-4]
+	_ = x[CatModified-5]
+	_ = x[CatLocked-6]
+	_ = x[catLength-7]
 }
 
-const _Category_name = "CacheMissCacheHitcatLength"
+const _Category_name = "CacheMissCacheHitFoundNotFoundNotModifiedModifiedLockedcatLength"
 
-var _Category_index = [...]uint8{0, 9, 17, 26}
+var _Category_index = [...]uint8{0, 9, 17, 22, 30, 41, 49, 55, 64}
 
 func (i Category) String() string {
 	if i >= Category(len(_Category_index)-1) {
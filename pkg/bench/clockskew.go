@@ -0,0 +1,120 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// maxSkewSamples is the number of samples kept for the median.
+const maxSkewSamples = 10000
+
+// ServerClockSkew returns how far the server clock is ahead of the local clock,
+// based on the Date header of a response to a request sent at sent and
+// answered at received. The request is assumed to have been handled halfway between.
+// Since Date has a resolution of one second, it is assumed to be half a second
+// behind the server time.
+// ok is false if date is not a valid HTTP date.
+func ServerClockSkew(date string, sent, received time.Time) (skew time.Duration, ok bool) {
+	t, err := http.ParseTime(date)
+	if err != nil {
+		return 0, false
+	}
+	mid := sent.Add(received.Sub(sent) / 2)
+	return t.Add(500 * time.Millisecond).Sub(mid), true
+}
+
+// ClockSkew collects observed clock skew between client and servers.
+// A nil ClockSkew ignores all observations.
+// It is safe for concurrent use.
+type ClockSkew struct {
+	mu       sync.Mutex
+	rng      *rand.Rand
+	samples  []time.Duration
+	count    int64
+	min, max time.Duration
+}
+
+// NewClockSkew returns an empty collection.
+// If more than 10000 values are added, the median is estimated from a random sample.
+func NewClockSkew(seed int64) *ClockSkew {
+	return &ClockSkew{rng: rand.New(rand.NewSource(seed))}
+}
+
+// Add records an observed skew.
+func (c *ClockSkew) Add(skew time.Duration) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.count == 0 || skew < c.min {
+		c.min = skew
+	}
+	if c.count == 0 || skew > c.max {
+		c.max = skew
+	}
+	c.count++
+	if len(c.samples) < maxSkewSamples {
+		c.samples = append(c.samples, skew)
+		return
+	}
+	// Reservoir sampling keeps a uniform sample.
+	if i := c.rng.Int63n(c.count); i < maxSkewSamples {
+		c.samples[i] = skew
+	}
+}
+
+// ClockSkewStats is a summary of observed clock skew.
+// Positive values mean the server clock is ahead.
+type ClockSkewStats struct {
+	Count            int64
+	Min, Median, Max time.Duration
+}
+
+// String returns a human readable summary.
+func (s ClockSkewStats) String() string {
+	r := func(d time.Duration) time.Duration { return d.Round(time.Millisecond) }
+	return fmt.Sprintf("min %v, median %v, max %v (%d responses)", r(s.Min), r(s.Median), r(s.Max), s.Count)
+}
+
+// Stats returns a summary of the observed skew.
+// A nil ClockSkew returns zero values.
+func (c *ClockSkew) Stats() ClockSkewStats {
+	if c == nil {
+		return ClockSkewStats{}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.count == 0 {
+		return ClockSkewStats{}
+	}
+	sorted := slices.Clone(c.samples)
+	slices.Sort(sorted)
+	return ClockSkewStats{
+		Count:  c.count,
+		Min:    c.min,
+		Median: sorted[len(sorted)/2],
+		Max:    c.max,
+	}
+}
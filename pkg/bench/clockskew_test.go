@@ -0,0 +1,85 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestServerClockSkew(t *testing.T) {
+	sent := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		date     string
+		received time.Time
+		want     time.Duration
+		ok       bool
+	}{
+		{name: "in sync", date: sent.Format(http.TimeFormat), received: sent.Add(time.Second), want: 0, ok: true},
+		{name: "ahead", date: sent.Add(5 * time.Second).Format(http.TimeFormat), received: sent.Add(200 * time.Millisecond), want: 5400 * time.Millisecond, ok: true},
+		{name: "behind", date: sent.Add(-3 * time.Second).Format(http.TimeFormat), received: sent, want: -2500 * time.Millisecond, ok: true},
+		{name: "rfc850", date: "Saturday, 01-Mar-25 12:00:10 GMT", received: sent, want: 10500 * time.Millisecond, ok: true},
+		{name: "missing", date: "", received: sent},
+		{name: "invalid", date: "yesterday", received: sent},
+	}
+	for _, tt := range tests {
+		got, ok := ServerClockSkew(tt.date, sent, tt.received)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("%s: got %v, %v, want %v, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestClockSkew(t *testing.T) {
+	var nilSkew *ClockSkew
+	nilSkew.Add(time.Second)
+	if s := nilSkew.Stats(); s.Count != 0 {
+		t.Errorf("nil collection returned %+v", s)
+	}
+
+	c := NewClockSkew(1)
+	if s := c.Stats(); s != (ClockSkewStats{}) {
+		t.Errorf("empty collection returned %+v", s)
+	}
+	for _, ms := range []int{300, -1200, 50, 2000, 400} {
+		c.Add(time.Duration(ms) * time.Millisecond)
+	}
+	want := ClockSkewStats{Count: 5, Min: -1200 * time.Millisecond, Median: 300 * time.Millisecond, Max: 2 * time.Second}
+	if s := c.Stats(); s != want {
+		t.Errorf("got %+v, want %+v", s, want)
+	}
+	if got, want := c.Stats().String(), "min -1.2s, median 300ms, max 2s (5 responses)"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// Beyond the sample size min and max stay exact and the median is estimated.
+	c = NewClockSkew(1)
+	const n = 5 * maxSkewSamples
+	for i := range n {
+		c.Add(time.Duration(i) * time.Millisecond)
+	}
+	s := c.Stats()
+	if s.Count != n || s.Min != 0 || s.Max != (n-1)*time.Millisecond {
+		t.Fatalf("got %+v", s)
+	}
+	if mid := n / 2 * time.Millisecond; s.Median < mid*95/100 || s.Median > mid*105/100 {
+		t.Errorf("median %v, want about %v", s.Median, mid)
+	}
+}
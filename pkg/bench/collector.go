@@ -192,3 +192,54 @@ func (c *collector) Close() {
 		c.extra = nil
 	}
 }
+
+// newObserveCollector returns a collector that calls fn with every operation
+// before forwarding it to c.
+// Closing the returned collector will also close c.
+func newObserveCollector(c Collector, fn func(Operation)) Collector {
+	oc := &observeCollector{
+		c:    c,
+		fn:   fn,
+		rcv:  make(chan Operation),
+		done: make(chan struct{}),
+	}
+	go oc.forward()
+	return oc
+}
+
+type observeCollector struct {
+	c    Collector
+	fn   func(Operation)
+	rcv  chan Operation
+	done chan struct{}
+	once sync.Once
+}
+
+func (oc *observeCollector) forward() {
+	defer close(oc.done)
+	dst := oc.c.Receiver()
+	for op := range oc.rcv {
+		oc.fn(op)
+		dst <- op
+	}
+}
+
+// AutoTerm forwards to the wrapped collector.
+func (oc *observeCollector) AutoTerm(ctx context.Context, op string, threshold float64, wantSamples, splitInto int, minDur time.Duration) context.Context {
+	return oc.c.AutoTerm(ctx, op, threshold, wantSamples, splitInto, minDur)
+}
+
+// Receiver returns the receiver of input.
+// It is unbuffered, so operations are observed before the sender continues.
+func (oc *observeCollector) Receiver() chan<- Operation {
+	return oc.rcv
+}
+
+// Close closes the wrapped collector.
+func (oc *observeCollector) Close() {
+	oc.once.Do(func() {
+		close(oc.rcv)
+		<-oc.done
+		oc.c.Close()
+	})
+}
@@ -0,0 +1,110 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/warp/pkg/generator"
+)
+
+// ObjectGetter returns object content.
+// It is implemented by ObjectClient.
+type ObjectGetter interface {
+	GetObject(ctx context.Context, bucket, object string, opts minio.GetObjectOptions) (io.ReadCloser, minio.ObjectInfo, error)
+}
+
+// ConditionalGet sends GET requests with If-None-Match set to the known ETag of the object,
+// like a caching client revalidating its copy.
+// A 304 Not Modified response is the expected outcome.
+// A 200 response, where the server did not honor the condition, is counted
+// separately and is not an error.
+// A nil *ConditionalGet does not send conditional requests.
+// It is safe for concurrent use.
+type ConditionalGet struct {
+	notModified, modified atomic.Int64
+}
+
+// ConditionalGetStats contains the outcomes of conditional GET requests.
+type ConditionalGetStats struct {
+	// NotModified is the number of 304 responses.
+	NotModified int64
+	// Modified is the number of 200 responses.
+	Modified int64
+}
+
+// String returns a human readable summary.
+func (s ConditionalGetStats) String() string {
+	total := s.NotModified + s.Modified
+	if total == 0 {
+		return "no responses"
+	}
+	return fmt.Sprintf("%d not modified (304), %d modified (200), %.2f%% not modified",
+		s.NotModified, s.Modified, 100*float64(s.NotModified)/float64(total))
+}
+
+// Stats returns the outcomes so far.
+func (c *ConditionalGet) Stats() ConditionalGetStats {
+	if c == nil {
+		return ConditionalGetStats{}
+	}
+	return ConditionalGetStats{NotModified: c.notModified.Load(), Modified: c.modified.Load()}
+}
+
+// get requests obj if its ETag does not match and records the result in op.
+// A 304 response has no content, so the size of op is set to 0 for those.
+// The CatNotModified or CatModified category is set on op when the server answered.
+func (c *ConditionalGet) get(ctx context.Context, client ObjectGetter, bucket string, obj generator.Object, opts minio.GetObjectOptions, op *Operation) {
+	op.Start = time.Now()
+	if err := opts.SetMatchETagExcept(obj.ETag); err != nil {
+		op.End = op.Start
+		op.SetErr(err)
+		return
+	}
+	r, _, err := client.GetObject(ctx, bucket, obj.Name, opts)
+	if err != nil {
+		op.End = time.Now()
+		if minio.ToErrorResponse(err).StatusCode == http.StatusNotModified {
+			op.Size = 0
+			op.Categories = NewCategories(CatNotModified)
+			c.notModified.Add(1)
+			return
+		}
+		op.SetErr(err)
+		return
+	}
+	defer r.Close()
+	fbr := firstByteRecorder{r: r}
+	n, err := io.Copy(io.Discard, &fbr)
+	op.FirstByte = fbr.t
+	op.End = time.Now()
+	op.Categories = NewCategories(CatModified)
+	c.modified.Add(1)
+	switch {
+	case err != nil:
+		op.SetErr(err)
+	case n != op.Size:
+		op.Err = fmt.Sprint("unexpected download size. want:", op.Size, ", got:", n)
+	}
+}
@@ -0,0 +1,166 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/warp/pkg/generator"
+)
+
+// conditionalClient returns 304 for objects in notModified and the content for all others,
+// regardless of the condition.
+type conditionalClient struct {
+	notModified map[string]bool
+	content     []byte
+
+	mu    sync.Mutex
+	etags []string
+}
+
+func (c *conditionalClient) GetObject(ctx context.Context, bucket, object string, opts minio.GetObjectOptions) (io.ReadCloser, minio.ObjectInfo, error) {
+	c.mu.Lock()
+	c.etags = append(c.etags, opts.Header().Get("If-None-Match"))
+	c.mu.Unlock()
+	if c.notModified[object] {
+		return nil, minio.ObjectInfo{}, memError(http.StatusNotModified, "NotModified", bucket, object)
+	}
+	return io.NopCloser(bytes.NewReader(c.content)), minio.ObjectInfo{Size: int64(len(c.content))}, nil
+}
+
+func TestConditionalGet(t *testing.T) {
+	ctx := context.Background()
+	content := []byte("conditional content")
+	client := &conditionalClient{
+		notModified: map[string]bool{"a": true, "b": true, "c": true},
+		content:     content,
+	}
+	var cond ConditionalGet
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		obj := generator.Object{Name: name, Size: int64(len(content)), ETag: "etag-" + name}
+		op := Operation{Size: obj.Size}
+		cond.get(ctx, client, "bucket", obj, minio.GetObjectOptions{}, &op)
+		if op.Err != "" {
+			t.Fatalf("%s: %v", name, op.Err)
+		}
+		if op.Start.IsZero() || op.End.Before(op.Start) {
+			t.Errorf("%s: invalid operation times %v - %v", name, op.Start, op.End)
+		}
+		want, wantSize := CatModified, obj.Size
+		if client.notModified[name] {
+			want, wantSize = CatNotModified, 0
+		}
+		if op.Categories != NewCategories(want) {
+			t.Errorf("%s: got categories %v, want %v", name, op.Categories, want)
+		}
+		if op.Size != wantSize {
+			t.Errorf("%s: got size %d, want %d", name, op.Size, wantSize)
+		}
+		if got := client.etags[len(client.etags)-1]; got != `"etag-`+name+`"` {
+			t.Errorf("%s: sent If-None-Match %s", name, got)
+		}
+	}
+	stats := cond.Stats()
+	if stats.NotModified != 3 || stats.Modified != 2 {
+		t.Errorf("got %+v, want 3 not modified and 2 modified", stats)
+	}
+	if got, want := stats.String(), "3 not modified (304), 2 modified (200), 60.00% not modified"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// A short response is still an error.
+	op := Operation{Size: 1000}
+	cond.get(ctx, client, "bucket", generator.Object{Name: "d", Size: 1000, ETag: "x"}, minio.GetObjectOptions{}, &op)
+	if op.Err == "" {
+		t.Error("want error for short download")
+	}
+	// Without an ETag no request can be made.
+	op = Operation{}
+	cond.get(ctx, client, "bucket", generator.Object{Name: "d"}, minio.GetObjectOptions{}, &op)
+	if op.Err == "" {
+		t.Error("want error for missing ETag")
+	}
+	if (*ConditionalGet)(nil).Stats() != (ConditionalGetStats{}) {
+		t.Error("nil stats not empty")
+	}
+}
+
+func TestConditionalGetMemClient(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemClient("bucket")
+	info, err := c.PutObject(ctx, "bucket", "obj", bytes.NewReader([]byte("data")), 4, minio.PutObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cond ConditionalGet
+	for _, etag := range []string{info.ETag, "stale"} {
+		op := Operation{Size: 4}
+		cond.get(ctx, c, "bucket", generator.Object{Name: "obj", Size: 4, ETag: etag}, minio.GetObjectOptions{}, &op)
+		if op.Err != "" {
+			t.Fatal(op.Err)
+		}
+	}
+	if s := cond.Stats(); s.NotModified != 1 || s.Modified != 1 {
+		t.Errorf("got %+v", s)
+	}
+}
+
+func TestConditionalGetServer(t *testing.T) {
+	const etag = "0123456789abcdef0123456789abcdef"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"`+etag+`"`)
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		if r.Header.Get("If-None-Match") == `"`+etag+`"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(4))
+		w.Write([]byte("data"))
+	}))
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+	mc, err := minio.New(u.Host, &minio.Options{
+		Creds:  credentials.NewStaticV4("access", "secret", ""),
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cond ConditionalGet
+	for _, tag := range []string{etag, "stale"} {
+		op := Operation{Size: 4}
+		cond.get(context.Background(), NewObjectClient(mc), "bucket", generator.Object{Name: "obj", Size: 4, ETag: tag}, minio.GetObjectOptions{}, &op)
+		if op.Err != "" {
+			t.Fatalf("etag %s: %v", tag, op.Err)
+		}
+	}
+	if s := cond.Stats(); s.NotModified != 1 || s.Modified != 1 {
+		t.Errorf("got %+v", s)
+	}
+}
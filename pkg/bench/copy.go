@@ -0,0 +1,250 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/warp/pkg/generator"
+)
+
+// Copy benchmarks server side copy speed.
+// Each operation copies an uploaded object to a new key.
+type Copy struct {
+	Common
+
+	// DestBucket is the bucket copies are written to.
+	// If empty, objects are copied within Bucket.
+	DestBucket string
+
+	CreateObjects int
+
+	// Copies contains all objects successfully copied to the destination bucket.
+	// It is set by Start and can be used for later GET or DELETE operations.
+	Copies *ObjectSet
+
+	sources generator.Objects
+	mu      sync.Mutex
+}
+
+// ObjectCopier copies objects on the server.
+// It is implemented by *minio.Client.
+type ObjectCopier interface {
+	CopyObject(ctx context.Context, dst minio.CopyDestOptions, src minio.CopySrcOptions) (minio.UploadInfo, error)
+}
+
+// copyObject copies src in bucket to dst in dstBucket and records the result in op.
+// Only the name and prefix of dst are used.
+// The operation size is the size of the source.
+// Returns the new object if the copy succeeded.
+func copyObject(ctx context.Context, client ObjectCopier, bucket string, src generator.Object, dstBucket string, dst generator.Object, op *Operation) (generator.Object, bool) {
+	op.Size = src.Size
+	op.Start = time.Now()
+	info, err := client.CopyObject(ctx,
+		minio.CopyDestOptions{Bucket: dstBucket, Object: dst.Name},
+		minio.CopySrcOptions{Bucket: bucket, Object: src.Name, VersionID: src.VersionID})
+	op.End = time.Now()
+	if err != nil {
+		op.SetErr(err)
+		return generator.Object{}, false
+	}
+	if info.Size != 0 && info.Size != src.Size {
+		op.Err = fmt.Sprint("unexpected copy size. want:", src.Size, ", got:", info.Size)
+		return generator.Object{}, false
+	}
+	return generator.Object{Name: dst.Name, Prefix: dst.Prefix, Size: src.Size, VersionID: info.VersionID}, true
+}
+
+// destBucket returns the bucket copies are written to.
+func (g *Copy) destBucket() string {
+	if g.DestBucket != "" {
+		return g.DestBucket
+	}
+	return g.Bucket
+}
+
+// Prepare will create empty buckets or delete any content already there
+// and upload a number of objects to copy.
+func (g *Copy) Prepare(ctx context.Context) error {
+	if err := g.createEmptyBucket(ctx); err != nil {
+		return err
+	}
+	if dst := g.destBucket(); dst != g.Bucket {
+		if err := g.createEmptyBucketNamed(ctx, dst); err != nil {
+			return err
+		}
+	}
+	src := g.Source()
+	g.UpdateStatus(fmt.Sprint("Uploading ", g.CreateObjects, " objects of ", src.String()))
+
+	var wg sync.WaitGroup
+	wg.Add(g.Concurrency)
+	objs := splitObjs(g.CreateObjects, g.Concurrency)
+	rcv := g.Collector.Receiver()
+	var groupErr error
+
+	for i, obj := range objs {
+		go func(i int, obj []struct{}) {
+			defer wg.Done()
+			src := g.Source()
+			opts := g.PutOpts
+
+			for range obj {
+				select {
+				case <-ctx.Done():
+					return
+				default:
+				}
+
+				if g.rpsLimit(ctx) != nil {
+					return
+				}
+
+				obj := src.Object()
+				client, cldone := g.Client()
+				op := Operation{
+					OpType:   http.MethodPut,
+					Thread:   uint32(i),
+					Size:     obj.Size,
+					File:     obj.Name,
+					ObjPerOp: 1,
+					Endpoint: client.EndpointURL().String(),
+				}
+
+				opts.ContentType = obj.ContentType
+				op.Start = time.Now()
+				res, err := client.PutObject(ctx, g.Bucket, obj.Name, obj.Reader, obj.Size, opts)
+				op.End = time.Now()
+				cldone()
+				if err != nil {
+					err = fmt.Errorf("upload error: %w", err)
+				} else if res.Size != obj.Size {
+					err = fmt.Errorf("short upload. want: %d, got %d", obj.Size, res.Size)
+				}
+				if err != nil {
+					g.Error(err)
+					g.mu.Lock()
+					if groupErr == nil {
+						groupErr = err
+					}
+					g.mu.Unlock()
+					return
+				}
+				obj.VersionID = res.VersionID
+				obj.Reader = nil
+				g.mu.Lock()
+				g.sources = append(g.sources, *obj)
+				g.prepareProgress(float64(len(g.sources)) / float64(g.CreateObjects))
+				g.mu.Unlock()
+				rcv <- op
+			}
+		}(i, obj)
+	}
+	wg.Wait()
+	return groupErr
+}
+
+// Start will execute the main benchmark.
+// Operations should begin executing when the start channel is closed.
+func (g *Copy) Start(ctx context.Context, wait chan struct{}) error {
+	if len(g.sources) == 0 {
+		return fmt.Errorf("no objects to copy")
+	}
+	var wg sync.WaitGroup
+	wg.Add(g.Concurrency)
+	c := g.Collector
+	if g.AutoTermDur > 0 {
+		ctx = c.AutoTerm(ctx, "COPY", g.AutoTermScale, autoTermCheck, autoTermSamples, g.AutoTermDur)
+	}
+	// Non-terminating context.
+	nonTerm := context.Background()
+	dstBucket := g.destBucket()
+	g.Copies = NewObjectSet()
+
+	for i := 0; i < g.Concurrency; i++ {
+		go func(i int) {
+			getClient := g.workerClient(i)
+			rng := rand.New(rand.NewSource(int64(i)))
+			rcv := c.Receiver()
+			src := g.Source()
+			defer wg.Done()
+			done := ctx.Done()
+
+			<-wait
+			if g.RampUp.Wait(ctx, i) != nil {
+				return
+			}
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				if g.ThinkTime.Wait(ctx) != nil {
+					return
+				}
+				if g.rpsLimit(ctx) != nil {
+					return
+				}
+
+				from := g.sources[rng.Intn(len(g.sources))]
+				to := *src.Object()
+				client, cldone := getClient()
+				op := Operation{
+					OpType:   "COPY",
+					Thread:   uint32(i),
+					File:     to.Name,
+					ObjPerOp: 1,
+					Endpoint: client.EndpointURL().String(),
+				}
+				copied, ok := copyObject(nonTerm, client, g.Bucket, from, dstBucket, to, &op)
+				cldone()
+				if ok {
+					g.Copies.Add(copied)
+				} else {
+					g.Error("CopyObject error: ", op.Err)
+				}
+				rcv <- op
+			}
+		}(i)
+	}
+	wg.Wait()
+	return nil
+}
+
+// Cleanup deletes everything uploaded and copied.
+func (g *Copy) Cleanup(ctx context.Context) {
+	var copies generator.Objects
+	if g.Copies != nil {
+		copies = g.Copies.Objects()
+	}
+	if dst := g.destBucket(); dst != g.Bucket {
+		g.deleteAllInBucket(ctx, g.sources.Prefixes()...)
+		g.deleteAllIn(ctx, dst, copies.Prefixes()...)
+		return
+	}
+	// Copies are named by other sources, so they may have other prefixes.
+	g.deleteAllInBucket(ctx, generator.MergeObjectPrefixes([]generator.Objects{g.sources, copies})...)
+}
@@ -0,0 +1,145 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/warp/pkg/generator"
+)
+
+// recordingCopier records every copy request.
+type recordingCopier struct {
+	ObjectCopier
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *recordingCopier) CopyObject(ctx context.Context, dst minio.CopyDestOptions, src minio.CopySrcOptions) (minio.UploadInfo, error) {
+	r.mu.Lock()
+	r.calls = append(r.calls, src.Bucket+"/"+src.Object+"->"+dst.Bucket+"/"+dst.Object)
+	r.mu.Unlock()
+	return r.ObjectCopier.CopyObject(ctx, dst, src)
+}
+
+func TestCopyObject(t *testing.T) {
+	ctx := context.Background()
+	mem := NewMemClient("src", "dst")
+	content := []byte("copy me")
+	if _, err := mem.PutObject(ctx, "src", "obj", bytes.NewReader(content), int64(len(content)), minio.PutObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	source := generator.Object{Name: "obj", Size: int64(len(content))}
+	client := &recordingCopier{ObjectCopier: mem}
+
+	var op Operation
+	got, ok := copyObject(ctx, client, "src", source, "dst", generator.Object{Name: "copy", Prefix: "pre"}, &op)
+	if !ok || op.Err != "" {
+		t.Fatalf("copy failed: %s", op.Err)
+	}
+	if want := "src/obj->dst/copy"; len(client.calls) != 1 || client.calls[0] != want {
+		t.Fatalf("calls %v, want [%s]", client.calls, want)
+	}
+	if got.Name != "copy" || got.Prefix != "pre" || got.Size != source.Size {
+		t.Errorf("got object %+v", got)
+	}
+	if op.Size != source.Size {
+		t.Errorf("op size %d, want source size %d", op.Size, source.Size)
+	}
+	if op.End.Before(op.Start) || op.Start.IsZero() {
+		t.Errorf("bad op times: %v - %v", op.Start, op.End)
+	}
+	if _, err := mem.StatObject(ctx, "dst", "copy", minio.StatObjectOptions{}); err != nil {
+		t.Errorf("destination not found: %v", err)
+	}
+	if _, err := mem.StatObject(ctx, "src", "obj", minio.StatObjectOptions{}); err != nil {
+		t.Errorf("source removed: %v", err)
+	}
+
+	// Missing source.
+	op = Operation{}
+	if _, ok := copyObject(ctx, client, "src", generator.Object{Name: "missing"}, "dst", generator.Object{Name: "copy2"}, &op); ok || op.Err == "" {
+		t.Error("expected error copying missing source")
+	}
+	// Unexpected size.
+	op = Operation{}
+	if _, ok := copyObject(ctx, client, "src", generator.Object{Name: "obj", Size: 100}, "dst", generator.Object{Name: "copy3"}, &op); ok || op.Err == "" {
+		t.Error("expected size mismatch error")
+	}
+	if mem.Len("dst") != 2 {
+		t.Errorf("dst has %d objects, want 2", mem.Len("dst"))
+	}
+}
+
+func TestCopyTracksDestinations(t *testing.T) {
+	ctx := context.Background()
+	mem := NewMemClient("bucket")
+	source := generator.Object{Name: "source", Size: 3}
+	if _, err := mem.PutObject(ctx, "bucket", source.Name, bytes.NewReader([]byte("abc")), 3, minio.PutObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	set := NewObjectSet()
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := range 10 {
+				var op Operation
+				if obj, ok := copyObject(ctx, mem, "bucket", source, "bucket", generator.Object{Name: fmt.Sprintf("copy-%d-%d", i, j)}, &op); ok {
+					set.Add(obj)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	if set.Len() != 40 {
+		t.Fatalf("tracked %d copies, want 40", set.Len())
+	}
+
+	// Tracked copies can be read and deleted.
+	for _, obj := range set.Objects() {
+		rc, info, err := mem.GetObject(ctx, "bucket", obj.Name, minio.GetObjectOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		rc.Close()
+		if info.Size != source.Size {
+			t.Errorf("%s: size %d, want %d", obj.Name, info.Size, source.Size)
+		}
+	}
+	deleted := 0
+	for set.Len() > 0 {
+		res := BatchDelete(ctx, mem, "bucket", set, 7)
+		if res.Deleted == 0 {
+			t.Fatalf("nothing deleted, %d left", set.Len())
+		}
+		deleted += res.Deleted
+	}
+	if deleted != 40 {
+		t.Errorf("deleted %d, want 40", deleted)
+	}
+	if mem.Len("bucket") != 1 {
+		t.Errorf("bucket has %d objects, want only the source", mem.Len("bucket"))
+	}
+}
@@ -33,6 +33,9 @@ import (
 type Delete struct {
 	Common
 	objects generator.Objects
+	set     *ObjectSet
+	// failed are objects that could not be deleted.
+	failed *ObjectSet
 
 	CreateObjects int
 	BatchSize     int
@@ -198,14 +201,23 @@ func (d *Delete) Start(ctx context.Context, wait chan struct{}) error {
 	// Non-terminating context.
 	nonTerm := context.Background()
 
-	var mu sync.Mutex
+	d.set = NewObjectSet()
+	d.failed = NewObjectSet()
+	for _, obj := range d.objects {
+		d.set.Add(obj)
+	}
+	d.objects = nil
 	for i := 0; i < d.Concurrency; i++ {
 		go func(i int) {
+			getClient := d.workerClient(i)
 			rcv := c.Receiver()
 			defer wg.Done()
 			done := ctx.Done()
 
 			<-wait
+			if d.RampUp.Wait(ctx, i) != nil {
+				return
+			}
 			for {
 				select {
 				case <-done:
@@ -213,60 +225,42 @@ func (d *Delete) Start(ctx context.Context, wait chan struct{}) error {
 				default:
 				}
 
-				if d.rpsLimit(ctx) != nil {
+				if d.ThinkTime.Wait(ctx) != nil {
 					return
 				}
-
-				// Fetch d.BatchSize objects
-				mu.Lock()
-				if len(d.objects) == 0 {
-					mu.Unlock()
+				if d.rpsLimit(ctx) != nil {
 					return
 				}
-				objs := d.objects
-				if len(objs) > d.BatchSize {
-					objs = objs[:d.BatchSize]
+				if d.set.Len() == 0 {
+					return
 				}
-				d.objects = d.objects[len(objs):]
-				mu.Unlock()
 
-				// Queue all in batch.
-				objects := make(chan minio.ObjectInfo, len(objs))
-				for _, obj := range objs {
-					objects <- minio.ObjectInfo{Key: obj.Name, VersionID: obj.VersionID}
+				client, cldone := getClient()
+				endpoint := client.EndpointURL().String()
+				res := BatchDelete(nonTerm, client, d.Bucket, d.set, d.BatchSize)
+				cldone()
+				if res.Requested == 0 {
+					return
 				}
-				close(objects)
-
-				client, cldone := d.Client()
 				op := Operation{
 					OpType:   http.MethodDelete,
 					Thread:   uint32(i),
 					Size:     0,
 					File:     "",
-					ObjPerOp: len(objs),
-					Endpoint: client.EndpointURL().String(),
+					ObjPerOp: res.Deleted,
+					Endpoint: endpoint,
+					Start:    res.Start,
+					End:      res.End,
 				}
-				if d.DiscardOutput {
-					op.File = ""
-				}
-
-				op.Start = time.Now()
-				// RemoveObjectsWithContext will split any batches > 1000 into separate requests.
-				errCh := client.RemoveObjects(nonTerm, d.Bucket, objects, minio.RemoveObjectsOptions{})
-
-				// Wait for errCh to close.
-				for {
-					err, ok := <-errCh
-					if !ok {
-						break
-					}
-					if err.Err != nil {
-						d.Error(err.Err)
-						op.Err = err.Err.Error()
+				// A request with failures is recorded once, with the number of failed objects.
+				if len(res.Failed) > 0 {
+					first := res.Failed[0]
+					op.SetErr(fmt.Errorf("%d of %d objects failed. First error: %s: %w", len(res.Failed), res.Requested, first.ObjectName, first.Err))
+					d.Error(op.Err)
+					for _, obj := range res.Remaining {
+						d.failed.Add(obj)
 					}
 				}
-				op.End = time.Now()
-				cldone()
 				rcv <- op
 			}
 		}(i)
@@ -277,7 +271,11 @@ func (d *Delete) Start(ctx context.Context, wait chan struct{}) error {
 
 // Cleanup deletes everything uploaded to the bucket.
 func (d *Delete) Cleanup(ctx context.Context) {
-	if len(d.objects) > 0 && !d.ListExisting {
-		d.deleteAllInBucket(ctx, d.objects.Prefixes()...)
+	objs := d.objects
+	if d.set != nil {
+		objs = append(d.set.Objects(), d.failed.Objects()...)
+	}
+	if len(objs) > 0 && !d.ListExisting {
+		d.deleteAllInBucket(ctx, objs.Prefixes()...)
 	}
 }
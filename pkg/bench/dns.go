@@ -0,0 +1,198 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Resolver looks up the addresses of a host.
+// It is implemented by *net.Resolver.
+type Resolver interface {
+	LookupHost(ctx context.Context, host string) (addrs []string, err error)
+}
+
+// DNSOptions configures a DNSDialer.
+type DNSOptions struct {
+	// Resolver looks up host names. If nil, net.DefaultResolver is used.
+	Resolver Resolver
+
+	// TTL is how long lookups are cached.
+	// If 0, hosts are looked up for every connection.
+	TTL time.Duration
+
+	// Pin maps host names to the IP connections are made to, without lookups.
+	Pin map[string]string
+}
+
+// DNSDialer dials connections after resolving host names itself,
+// so lookups can be cached, pinned and timed.
+// It is safe for concurrent use.
+type DNSDialer struct {
+	dial func(ctx context.Context, network, addr string) (net.Conn, error)
+	o    DNSOptions
+
+	mu    sync.Mutex
+	cache map[string]*dnsEntry
+
+	lookups, cached, lookupNanos atomic.Int64
+}
+
+// dnsEntry is a cached lookup.
+// done is closed when the lookup has completed.
+type dnsEntry struct {
+	done    chan struct{}
+	addrs   []string
+	err     error
+	expires time.Time
+}
+
+// NewDNSDialer returns a dialer that resolves addresses with the options
+// and connects to the resolved addresses using dial.
+func NewDNSDialer(dial func(ctx context.Context, network, addr string) (net.Conn, error), o DNSOptions) *DNSDialer {
+	if o.Resolver == nil {
+		o.Resolver = net.DefaultResolver
+	}
+	return &DNSDialer{dial: dial, o: o, cache: make(map[string]*dnsEntry)}
+}
+
+// DialContext resolves the host of addr and connects to the first address that accepts the connection.
+// Addresses with an IP are dialed directly.
+func (d *DNSDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	if net.ParseIP(host) != nil {
+		return d.dial(ctx, network, addr)
+	}
+	addrs, err := d.resolve(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	var errs []error
+	for _, a := range addrs {
+		conn, err := d.dial(ctx, network, net.JoinHostPort(a, port))
+		if err == nil {
+			return conn, nil
+		}
+		errs = append(errs, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, errors.Join(errs...)
+}
+
+// resolve returns the addresses of host.
+func (d *DNSDialer) resolve(ctx context.Context, host string) ([]string, error) {
+	if ip, ok := d.o.Pin[host]; ok {
+		return []string{ip}, nil
+	}
+	if d.o.TTL <= 0 {
+		return d.lookup(ctx, host)
+	}
+
+	d.mu.Lock()
+	if e := d.cache[host]; e != nil {
+		select {
+		case <-e.done:
+			if time.Now().Before(e.expires) {
+				d.mu.Unlock()
+				d.cached.Add(1)
+				return e.addrs, nil
+			}
+		default:
+			// Wait for the lookup in progress.
+			d.mu.Unlock()
+			select {
+			case <-e.done:
+				d.cached.Add(1)
+				return e.addrs, e.err
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+	}
+	e := &dnsEntry{done: make(chan struct{})}
+	d.cache[host] = e
+	d.mu.Unlock()
+
+	e.addrs, e.err = d.lookup(ctx, host)
+	e.expires = time.Now().Add(d.o.TTL)
+	if e.err != nil {
+		// Failed lookups are not cached.
+		d.mu.Lock()
+		if d.cache[host] == e {
+			delete(d.cache, host)
+		}
+		d.mu.Unlock()
+	}
+	close(e.done)
+	return e.addrs, e.err
+}
+
+// lookup resolves host and records the time it took.
+func (d *DNSDialer) lookup(ctx context.Context, host string) ([]string, error) {
+	start := time.Now()
+	addrs, err := d.o.Resolver.LookupHost(ctx, host)
+	d.lookupNanos.Add(int64(time.Since(start)))
+	d.lookups.Add(1)
+	if err == nil && len(addrs) == 0 {
+		err = fmt.Errorf("no addresses found for %s", host)
+	}
+	return addrs, err
+}
+
+// DNSStats contains the lookups made by a DNSDialer.
+type DNSStats struct {
+	// Lookups is the number of lookups sent to the resolver.
+	Lookups int64
+	// Cached is the number of connections that used a cached lookup.
+	Cached int64
+	// Total is the time spent on lookups.
+	Total time.Duration
+}
+
+// String returns a human readable summary.
+func (s DNSStats) String() string {
+	if s.Lookups == 0 {
+		return fmt.Sprintf("no lookups, %d cached", s.Cached)
+	}
+	return fmt.Sprintf("%d lookups, mean %v, total %v, %d cached",
+		s.Lookups, (s.Total / time.Duration(s.Lookups)).Round(time.Microsecond), s.Total.Round(time.Microsecond), s.Cached)
+}
+
+// Stats returns the lookups made so far.
+// A nil *DNSDialer returns empty stats.
+func (d *DNSDialer) Stats() DNSStats {
+	if d == nil {
+		return DNSStats{}
+	}
+	return DNSStats{
+		Lookups: d.lookups.Load(),
+		Cached:  d.cached.Load(),
+		Total:   time.Duration(d.lookupNanos.Load()),
+	}
+}
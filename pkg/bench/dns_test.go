@@ -0,0 +1,178 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"errors"
+	"net"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeResolver returns addrs for every host after delay and counts lookups.
+type fakeResolver struct {
+	addrs []string
+	delay time.Duration
+	err   error
+
+	mu      sync.Mutex
+	lookups []string
+}
+
+func (f *fakeResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	time.Sleep(f.delay)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.lookups = append(f.lookups, host)
+	return f.addrs, f.err
+}
+
+// fakeDialer records dialed addresses and fails for addresses in fail.
+type fakeDialer struct {
+	fail map[string]bool
+
+	mu     sync.Mutex
+	dialed []string
+}
+
+func (f *fakeDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	f.mu.Lock()
+	f.dialed = append(f.dialed, addr)
+	f.mu.Unlock()
+	if f.fail[addr] {
+		return nil, errors.New("connection refused")
+	}
+	c1, c2 := net.Pipe()
+	c2.Close()
+	return c1, nil
+}
+
+func TestDNSDialerCached(t *testing.T) {
+	res := &fakeResolver{addrs: []string{"10.0.0.1"}, delay: 10 * time.Millisecond}
+	dialer := &fakeDialer{}
+	d := NewDNSDialer(dialer.DialContext, DNSOptions{Resolver: res, TTL: time.Hour})
+	const conns = 50
+	var wg sync.WaitGroup
+	for range conns {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			conn, err := d.DialContext(context.Background(), "tcp", "s3.example.com:9000")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			conn.Close()
+		}()
+	}
+	wg.Wait()
+	if len(res.lookups) != 1 {
+		t.Errorf("got %d lookups, want 1", len(res.lookups))
+	}
+	if s := d.Stats(); s.Lookups != 1 || s.Cached != conns-1 {
+		t.Errorf("got stats %+v", s)
+	}
+	for _, addr := range dialer.dialed {
+		if addr != "10.0.0.1:9000" {
+			t.Fatalf("dialed %s", addr)
+		}
+	}
+
+	// Lookups expire.
+	res.delay = 0
+	d = NewDNSDialer(dialer.DialContext, DNSOptions{Resolver: res, TTL: 10 * time.Millisecond})
+	res.lookups = nil
+	d.DialContext(context.Background(), "tcp", "s3.example.com:9000")
+	d.DialContext(context.Background(), "tcp", "s3.example.com:9000")
+	time.Sleep(20 * time.Millisecond)
+	d.DialContext(context.Background(), "tcp", "s3.example.com:9000")
+	if len(res.lookups) != 2 {
+		t.Errorf("got %d lookups with expiry, want 2", len(res.lookups))
+	}
+
+	// Failed lookups are not cached.
+	res.err = errors.New("no such host")
+	d = NewDNSDialer(dialer.DialContext, DNSOptions{Resolver: res, TTL: time.Hour})
+	res.lookups = nil
+	for range 2 {
+		if _, err := d.DialContext(context.Background(), "tcp", "s3.example.com:9000"); err == nil {
+			t.Error("want lookup error")
+		}
+	}
+	if len(res.lookups) != 2 {
+		t.Errorf("got %d lookups after errors, want 2", len(res.lookups))
+	}
+}
+
+func TestDNSDialerUncached(t *testing.T) {
+	res := &fakeResolver{addrs: []string{"10.0.0.1", "10.0.0.2"}, delay: time.Millisecond}
+	dialer := &fakeDialer{fail: map[string]bool{"10.0.0.1:80": true}}
+	d := NewDNSDialer(dialer.DialContext, DNSOptions{Resolver: res})
+	for range 5 {
+		conn, err := d.DialContext(context.Background(), "tcp", "s3.example.com:80")
+		if err != nil {
+			t.Fatal(err)
+		}
+		conn.Close()
+	}
+	s := d.Stats()
+	if s.Lookups != 5 || s.Cached != 0 {
+		t.Errorf("got stats %+v, want 5 lookups", s)
+	}
+	if s.Total < 5*time.Millisecond {
+		t.Errorf("lookup time %v, want at least 5ms", s.Total)
+	}
+	// The first address fails, so the second is used.
+	if got := dialer.dialed[:2]; !slices.Equal(got, []string{"10.0.0.1:80", "10.0.0.2:80"}) {
+		t.Errorf("dialed %v", got)
+	}
+
+	// IP addresses are not looked up.
+	res.lookups = nil
+	if _, err := d.DialContext(context.Background(), "tcp", "10.0.0.9:80"); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.lookups) != 0 {
+		t.Errorf("looked up %v", res.lookups)
+	}
+}
+
+func TestDNSDialerPinned(t *testing.T) {
+	res := &fakeResolver{addrs: []string{"10.0.0.1"}}
+	dialer := &fakeDialer{}
+	d := NewDNSDialer(dialer.DialContext, DNSOptions{Resolver: res, Pin: map[string]string{"s3.example.com": "192.168.1.5"}})
+	for _, addr := range []string{"s3.example.com:9000", "other.example.com:9000"} {
+		conn, err := d.DialContext(context.Background(), "tcp", addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		conn.Close()
+	}
+	if want := []string{"192.168.1.5:9000", "10.0.0.1:9000"}; !slices.Equal(dialer.dialed, want) {
+		t.Errorf("dialed %v, want %v", dialer.dialed, want)
+	}
+	if !slices.Equal(res.lookups, []string{"other.example.com"}) {
+		t.Errorf("looked up %v, want only the host that is not pinned", res.lookups)
+	}
+	if (*DNSDialer)(nil).Stats() != (DNSStats{}) {
+		t.Error("nil stats not empty")
+	}
+}
@@ -0,0 +1,117 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DrainCollector forwards operations to another collector until it is closed.
+// Unlike other collectors, operations may still be sent after Close.
+// They are dropped, so a report can be produced while operations that
+// did not finish in time are still running.
+// Call Finish when all workers have returned.
+type DrainCollector struct {
+	c        Collector
+	rcv      chan Operation
+	closing  chan struct{}
+	closed   chan struct{}
+	finished chan struct{}
+	once     sync.Once
+	finish   sync.Once
+	dropped  atomic.Int64
+}
+
+// NewDrainCollector returns a collector that forwards operations to c.
+// Closing the returned collector will also close c.
+func NewDrainCollector(c Collector) *DrainCollector {
+	d := &DrainCollector{
+		c:        c,
+		rcv:      make(chan Operation, 1000),
+		closing:  make(chan struct{}),
+		closed:   make(chan struct{}),
+		finished: make(chan struct{}),
+	}
+	go d.forward()
+	return d
+}
+
+// forward sends operations to the wrapped collector until closed,
+// after which operations are dropped until Finish is called.
+func (d *DrainCollector) forward() {
+	defer close(d.finished)
+	dst := d.c.Receiver()
+loop:
+	for {
+		select {
+		case op := <-d.rcv:
+			dst <- op
+		case <-d.closing:
+			break loop
+		}
+	}
+	// Forward what has already been received.
+	for len(d.rcv) > 0 {
+		dst <- <-d.rcv
+	}
+	d.c.Close()
+	close(d.closed)
+
+	// Drop operations from workers that are still running.
+	for range d.rcv {
+		d.dropped.Add(1)
+	}
+}
+
+// AutoTerm forwards to the wrapped collector.
+func (d *DrainCollector) AutoTerm(ctx context.Context, op string, threshold float64, wantSamples, splitInto int, minDur time.Duration) context.Context {
+	return d.c.AutoTerm(ctx, op, threshold, wantSamples, splitInto, minDur)
+}
+
+// Receiver returns the receiver of input.
+func (d *DrainCollector) Receiver() chan<- Operation {
+	return d.rcv
+}
+
+// Close closes the wrapped collector.
+// Operations received before Close are forwarded first.
+func (d *DrainCollector) Close() {
+	d.once.Do(func() {
+		close(d.closing)
+		<-d.closed
+	})
+}
+
+// Finish closes the collector and stops dropping operations.
+// It must be called once all workers have returned,
+// since sending operations after Finish panics.
+func (d *DrainCollector) Finish() {
+	d.Close()
+	d.finish.Do(func() {
+		close(d.rcv)
+		<-d.finished
+	})
+}
+
+// Dropped returns the number of operations received after Close.
+func (d *DrainCollector) Dropped() int64 {
+	return d.dropped.Load()
+}
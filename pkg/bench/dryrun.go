@@ -0,0 +1,256 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math/rand"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/warp/pkg/generator"
+)
+
+// PlannedOp is an operation that would have been sent to the server.
+type PlannedOp struct {
+	OpType string
+	Key    string
+	// Size is the size of the object.
+	Size int64
+}
+
+// DryRunClient records requests instead of sending them.
+// It implements ObjectPutter and ObjectRemover.
+// Object content is not read.
+type DryRunClient struct {
+	mu  sync.Mutex
+	ops []PlannedOp
+}
+
+// Record adds an operation.
+func (d *DryRunClient) Record(opType, key string, size int64) {
+	d.mu.Lock()
+	d.ops = append(d.ops, PlannedOp{OpType: opType, Key: key, Size: size})
+	d.mu.Unlock()
+}
+
+// PutObject records a PUT of the object.
+func (d *DryRunClient) PutObject(ctx context.Context, bucket, object string, reader io.Reader, size int64, opts minio.PutObjectOptions) (minio.UploadInfo, error) {
+	d.Record(http.MethodPut, object, size)
+	return minio.UploadInfo{Bucket: bucket, Key: object, Size: size}, nil
+}
+
+// RemoveObjects records a DELETE of every object sent on objectsCh.
+func (d *DryRunClient) RemoveObjects(ctx context.Context, bucketName string, objectsCh <-chan minio.ObjectInfo, opts minio.RemoveObjectsOptions) <-chan minio.RemoveObjectError {
+	for obj := range objectsCh {
+		d.Record(http.MethodDelete, obj.Key, obj.Size)
+	}
+	errCh := make(chan minio.RemoveObjectError)
+	close(errCh)
+	return errCh
+}
+
+// Ops returns the recorded operations and clears the recording.
+func (d *DryRunClient) Ops() []PlannedOp {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	ops := d.ops
+	d.ops = nil
+	return ops
+}
+
+// DryRunOptions describes the operations to plan.
+type DryRunOptions struct {
+	// Bucket of the operations.
+	Bucket string
+
+	// OpType is the type of all benchmark operations when Dist is nil.
+	// PUT, GET, STAT and DELETE are supported.
+	OpType string
+
+	// Dist, if set, selects the type of every operation.
+	// Generate must have been called.
+	Dist *MixedDistribution
+
+	// Prepare is the number of objects uploaded before the benchmark.
+	Prepare int
+
+	// Ops is the number of benchmark operations to plan.
+	Ops int
+
+	// Seed selects the objects for GET, STAT and DELETE operations.
+	Seed int64
+
+	// Source returns the source of uploaded objects.
+	Source func() generator.Source
+}
+
+// DryRunPlan contains the operations of a dry run.
+type DryRunPlan struct {
+	// Prepare contains the uploads made before the benchmark.
+	Prepare []PlannedOp
+
+	// Ops contains the benchmark operations.
+	Ops []PlannedOp
+}
+
+// DryRun walks the operations described by o without contacting a server.
+// Objects are taken from the source but their content is not generated.
+// A DELETE plan ends early when there are no objects left.
+// Mixed plans draw another operation type instead.
+func DryRun(ctx context.Context, o DryRunOptions) (*DryRunPlan, error) {
+	if o.Source == nil {
+		return nil, errors.New("dry run: no object source")
+	}
+	if o.Dist == nil {
+		switch o.OpType {
+		case http.MethodPut, http.MethodGet, "STAT", http.MethodDelete:
+		default:
+			return nil, fmt.Errorf("dry run: unsupported operation %q", o.OpType)
+		}
+	}
+	var client DryRunClient
+	var plan DryRunPlan
+	set := NewObjectSet()
+	src := o.Source()
+	put := func() error {
+		obj := src.Object()
+		if _, err := client.PutObject(ctx, o.Bucket, obj.Name, obj.Reader, obj.Size, minio.PutObjectOptions{}); err != nil {
+			return err
+		}
+		set.Add(*obj)
+		return nil
+	}
+	for range o.Prepare {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := put(); err != nil {
+			return nil, err
+		}
+	}
+	plan.Prepare = client.Ops()
+
+	rng := rand.New(rand.NewSource(o.Seed))
+	for range o.Ops {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		op := o.OpType
+		if o.Dist != nil {
+			op = o.Dist.getOp()
+			// Without objects only PUT is possible, so draw another operation.
+			for tries := 0; op != http.MethodPut && set.Len() == 0; tries++ {
+				if tries >= len(o.Dist.ops) {
+					return nil, fmt.Errorf("dry run: no objects for %s", op)
+				}
+				op = o.Dist.getOp()
+			}
+		}
+		switch op {
+		case http.MethodPut:
+			if err := put(); err != nil {
+				return nil, err
+			}
+		case http.MethodDelete:
+			objs := set.Take(1)
+			if len(objs) == 0 {
+				plan.Ops = client.Ops()
+				return &plan, nil
+			}
+			client.Record(op, objs[0].Name, objs[0].Size)
+		default:
+			obj, ok := set.Random(rng)
+			if !ok {
+				return nil, fmt.Errorf("dry run: no objects for %s", op)
+			}
+			client.Record(op, obj.Name, obj.Size)
+		}
+	}
+	plan.Ops = client.Ops()
+	return &plan, nil
+}
+
+// PlanSummary summarizes planned operations of a single type.
+type PlanSummary struct {
+	OpType string
+	Count  int
+	// Bytes is the amount of object data transferred.
+	// Only PUT and GET operations transfer data.
+	Bytes            int64
+	MinSize, MaxSize int64
+}
+
+// Summary returns a summary of the benchmark operations, sorted by operation type.
+func (p *DryRunPlan) Summary() []PlanSummary {
+	byType := make(map[string]*PlanSummary)
+	for _, op := range p.Ops {
+		s := byType[op.OpType]
+		if s == nil {
+			s = &PlanSummary{OpType: op.OpType, MinSize: op.Size, MaxSize: op.Size}
+			byType[op.OpType] = s
+		}
+		s.Count++
+		if op.OpType == http.MethodPut || op.OpType == http.MethodGet {
+			s.Bytes += op.Size
+		}
+		s.MinSize = min(s.MinSize, op.Size)
+		s.MaxSize = max(s.MaxSize, op.Size)
+	}
+	res := make([]PlanSummary, 0, len(byType))
+	for _, k := range slices.Sorted(maps.Keys(byType)) {
+		res = append(res, *byType[k])
+	}
+	return res
+}
+
+// WriteSummary writes a human readable summary of the plan to w.
+// If opsPerSec > 0 the duration of the benchmark operations at that rate is estimated.
+func (p *DryRunPlan) WriteSummary(w io.Writer, opsPerSec float64) {
+	var prepBytes int64
+	for _, op := range p.Prepare {
+		prepBytes += op.Size
+	}
+	if len(p.Prepare) > 0 {
+		fmt.Fprintf(w, "Prepare: %d objects, %s\n", len(p.Prepare), humanize.IBytes(uint64(prepBytes)))
+	}
+	fmt.Fprintf(w, "Operations: %d\n", len(p.Ops))
+	for _, s := range p.Summary() {
+		fmt.Fprintf(w, " * %s: %d ops (%.1f%%), %s, size %s-%s\n", s.OpType, s.Count,
+			100*float64(s.Count)/float64(len(p.Ops)), humanize.IBytes(uint64(s.Bytes)),
+			humanize.IBytes(uint64(s.MinSize)), humanize.IBytes(uint64(s.MaxSize)))
+	}
+	for i, op := range p.Ops[:min(len(p.Ops), 5)] {
+		if i == 0 {
+			fmt.Fprintln(w, "First operations:")
+		}
+		fmt.Fprintf(w, " * %s %s (%s)\n", op.OpType, op.Key, humanize.IBytes(uint64(op.Size)))
+	}
+	if opsPerSec > 0 {
+		d := time.Duration(float64(len(p.Ops)) / opsPerSec * float64(time.Second))
+		fmt.Fprintf(w, "Estimated duration: %v at %.1f ops/s\n", d.Round(time.Second), opsPerSec)
+	}
+}
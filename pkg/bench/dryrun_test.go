@@ -0,0 +1,193 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/minio/warp/pkg/generator"
+)
+
+func TestDryRun(t *testing.T) {
+	const maxSize = 1 << 20
+	plan := func(seed int64) *DryRunPlan {
+		src, err := generator.NewFn(generator.WithSeedSource(generator.NewSeedSource(seed)),
+			generator.WithRandomData().Apply(), generator.WithSize(maxSize), generator.WithRandomSize(true))
+		if err != nil {
+			t.Fatal(err)
+		}
+		dist := &MixedDistribution{
+			Distribution: map[string]float64{
+				http.MethodGet:    45,
+				"STAT":            30,
+				http.MethodPut:    15,
+				http.MethodDelete: 10,
+			},
+			Seed: seed,
+		}
+		if err := dist.Generate(100); err != nil {
+			t.Fatal(err)
+		}
+		p, err := DryRun(context.Background(), DryRunOptions{
+			Bucket:  "bucket",
+			Dist:    dist,
+			Prepare: 100,
+			Ops:     10000,
+			Seed:    seed,
+			Source:  src,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	p := plan(1)
+	if len(p.Prepare) != 100 || len(p.Ops) != 10000 {
+		t.Fatalf("got %d prepare and %d ops, want 100 and 10000", len(p.Prepare), len(p.Ops))
+	}
+	for _, op := range p.Prepare {
+		if op.OpType != http.MethodPut {
+			t.Fatalf("prepare op %s, want PUT", op.OpType)
+		}
+	}
+	want := map[string]float64{http.MethodGet: 0.45, "STAT": 0.3, http.MethodPut: 0.15, http.MethodDelete: 0.1}
+	sum := p.Summary()
+	if len(sum) != len(want) {
+		t.Fatalf("got %d op types, want %d", len(sum), len(want))
+	}
+	for _, s := range sum {
+		if share := float64(s.Count) / float64(len(p.Ops)); math.Abs(share-want[s.OpType]) > 0.005 {
+			t.Errorf("%s: share %.3f, want %.3f", s.OpType, share, want[s.OpType])
+		}
+		if s.MinSize < 0 || s.MaxSize > maxSize || s.MinSize == s.MaxSize {
+			t.Errorf("%s: unexpected size range %d-%d", s.OpType, s.MinSize, s.MaxSize)
+		}
+	}
+
+	// Only existing objects are read or deleted.
+	existing := make(map[string]bool)
+	for _, op := range slices.Concat(p.Prepare, p.Ops) {
+		switch op.OpType {
+		case http.MethodPut:
+			existing[op.Key] = true
+		case http.MethodDelete:
+			if !existing[op.Key] {
+				t.Fatalf("deleted missing object %s", op.Key)
+			}
+			delete(existing, op.Key)
+		default:
+			if !existing[op.Key] {
+				t.Fatalf("%s of missing object %s", op.OpType, op.Key)
+			}
+		}
+	}
+
+	// The same seed gives the same plan.
+	if p2 := plan(1); !slices.Equal(p.Ops, p2.Ops) || !slices.Equal(p.Prepare, p2.Prepare) {
+		t.Error("same seed gave different plans")
+	}
+	if p3 := plan(2); slices.Equal(p.Ops, p3.Ops) {
+		t.Error("different seeds gave the same plan")
+	}
+
+	var sb strings.Builder
+	p.WriteSummary(&sb, 100)
+	for _, s := range []string{"Prepare: 100 objects", "Operations: 10000", " * GET: ", "Estimated duration: 1m40s"} {
+		if !strings.Contains(sb.String(), s) {
+			t.Errorf("summary missing %q:\n%s", s, sb.String())
+		}
+	}
+}
+
+func TestDryRunMixedDrained(t *testing.T) {
+	src, err := generator.NewFn(generator.WithRandomData().Apply(), generator.WithSize(1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Deletes as often as uploads empty the prepared object set.
+	dist := &MixedDistribution{
+		Distribution: map[string]float64{http.MethodPut: 40, http.MethodDelete: 40, http.MethodGet: 20},
+		Seed:         1,
+	}
+	if err := dist.Generate(10); err != nil {
+		t.Fatal(err)
+	}
+	p, err := DryRun(context.Background(), DryRunOptions{
+		Bucket:  "bucket",
+		Dist:    dist,
+		Prepare: 1,
+		Ops:     1000,
+		Seed:    1,
+		Source:  src,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Ops) != 1000 {
+		t.Fatalf("got %d ops, want 1000", len(p.Ops))
+	}
+	objects := 1
+	drained := false
+	for _, op := range p.Ops {
+		switch op.OpType {
+		case http.MethodPut:
+			objects++
+		case http.MethodDelete:
+			objects--
+		}
+		if objects < 0 {
+			t.Fatal("deleted more objects than uploaded")
+		}
+		drained = drained || objects == 0
+	}
+	if !drained {
+		t.Error("object set was never drained")
+	}
+}
+
+func TestDryRunSingleOp(t *testing.T) {
+	src, err := generator.NewFn(generator.WithRandomData().Apply(), generator.WithSize(1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := DryRun(context.Background(), DryRunOptions{OpType: http.MethodDelete, Prepare: 10, Ops: 100, Source: src})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Ops) != 10 {
+		t.Errorf("got %d deletes, want 10", len(p.Ops))
+	}
+	p, err = DryRun(context.Background(), DryRunOptions{OpType: http.MethodPut, Ops: 5, Source: src})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := p.Summary(); len(s) != 1 || s[0].Count != 5 || s[0].Bytes != 5000 {
+		t.Errorf("unexpected summary %+v", s)
+	}
+	if _, err := DryRun(context.Background(), DryRunOptions{OpType: http.MethodGet, Ops: 1, Source: src}); err == nil {
+		t.Error("expected error for GET without objects")
+	}
+	if _, err := DryRun(context.Background(), DryRunOptions{OpType: "LIST", Source: src}); err == nil {
+		t.Error("expected error for unsupported operation")
+	}
+}
@@ -0,0 +1,68 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"math/rand"
+	"slices"
+	"sync"
+)
+
+// EndpointSelector picks one of a fixed number of endpoints for each request,
+// either in turn or at random, and counts how often each was picked.
+// It is safe for concurrent use.
+type EndpointSelector struct {
+	mu     sync.Mutex
+	rng    *rand.Rand
+	next   int
+	counts []int64
+}
+
+// NewRoundRobinSelector returns a selector that picks n endpoints in turn.
+// n must be > 0.
+func NewRoundRobinSelector(n int) *EndpointSelector {
+	return &EndpointSelector{counts: make([]int64, n)}
+}
+
+// NewRandomSelector returns a selector that picks one of n endpoints at random.
+// The same seed gives the same sequence. n must be > 0.
+func NewRandomSelector(n int, seed int64) *EndpointSelector {
+	return &EndpointSelector{rng: rand.New(rand.NewSource(seed)), counts: make([]int64, n)}
+}
+
+// Next returns the index of the endpoint to use for the next request.
+func (s *EndpointSelector) Next() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var idx int
+	if s.rng != nil {
+		idx = s.rng.Intn(len(s.counts))
+	} else {
+		idx = s.next
+		s.next = (s.next + 1) % len(s.counts)
+	}
+	s.counts[idx]++
+	return idx
+}
+
+// Counts returns the number of times each endpoint has been picked.
+func (s *EndpointSelector) Counts() []int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.counts)
+}
@@ -0,0 +1,75 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestEndpointSelector(t *testing.T) {
+	const endpoints = 5
+	rr := NewRoundRobinSelector(endpoints)
+	for i := range 3 * endpoints {
+		if got := rr.Next(); got != i%endpoints {
+			t.Fatalf("request %d: got endpoint %d, want %d", i, got, i%endpoints)
+		}
+	}
+
+	// Concurrent use still spreads requests evenly.
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 1000 {
+				rr.Next()
+			}
+		}()
+	}
+	wg.Wait()
+	for i, n := range rr.Counts() {
+		if n != 2003 {
+			t.Errorf("endpoint %d: %d requests, want 2003", i, n)
+		}
+	}
+
+	const requests = 10000
+	random := func(seed int64) []int {
+		s := NewRandomSelector(endpoints, seed)
+		seq := make([]int, requests)
+		for i := range seq {
+			seq[i] = s.Next()
+		}
+		for i, n := range s.Counts() {
+			// All endpoints are used, roughly evenly.
+			if n < requests/endpoints*8/10 {
+				t.Errorf("seed %d, endpoint %d: only %d of %d requests", seed, i, n, requests)
+			}
+		}
+		return seq
+	}
+	a := random(1)
+	if !slices.Equal(a, random(1)) {
+		t.Error("same seed gave different sequences")
+	}
+	if slices.Equal(a, random(2)) {
+		t.Error("different seeds gave the same sequence")
+	}
+}
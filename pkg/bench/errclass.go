@@ -0,0 +1,190 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// ErrorCategory is a coarse classification of an operation error.
+type ErrorCategory uint8
+
+const (
+	// ErrCatNone is used when there is no error.
+	ErrCatNone ErrorCategory = iota
+
+	// ErrCatThrottled means the server asked the client to slow down.
+	ErrCatThrottled
+
+	// ErrCatTimeout means the operation timed out.
+	ErrCatTimeout
+
+	// ErrCatConnection means the connection could not be established or was lost.
+	ErrCatConnection
+
+	// ErrCatClient is a 4xx response from the server.
+	ErrCatClient
+
+	// ErrCatServer is a 5xx response from the server.
+	ErrCatServer
+
+	// ErrCatIntegrity means the server returned data or an ETag that did not match what was sent.
+	ErrCatIntegrity
+
+	// ErrCatOther is any other error.
+	ErrCatOther
+
+	errCatLength
+)
+
+var errorCategoryNames = [errCatLength]string{
+	ErrCatNone:       "none",
+	ErrCatThrottled:  "throttled",
+	ErrCatTimeout:    "timeout",
+	ErrCatConnection: "connection",
+	ErrCatClient:     "client",
+	ErrCatServer:     "server",
+	ErrCatIntegrity:  "integrity",
+	ErrCatOther:      "other",
+}
+
+func (c ErrorCategory) String() string {
+	if c >= errCatLength {
+		return "unknown"
+	}
+	return errorCategoryNames[c]
+}
+
+// ErrorCategories returns all error categories, excluding ErrCatNone.
+func ErrorCategories() []ErrorCategory {
+	res := make([]ErrorCategory, 0, errCatLength-1)
+	for c := ErrCatNone + 1; c < errCatLength; c++ {
+		res = append(res, c)
+	}
+	return res
+}
+
+// ErrorClassifier maps operation errors to categories.
+// The zero value is ready for use.
+type ErrorClassifier struct {
+	// ThrottleCodes are additional S3 error codes that should be treated as throttling.
+	ThrottleCodes []string
+}
+
+// throttleCodes are S3 error codes that indicate throttling.
+var throttleCodes = []string{"SlowDown", "SlowDownRead", "SlowDownWrite", "Throttling", "ThrottlingException", "RequestLimitExceeded", "TooManyRequests"}
+
+// Classify returns the category of err.
+// Wrapped errors are inspected, so S3 responses and network errors
+// are classified regardless of how they have been annotated.
+func (c ErrorClassifier) Classify(err error) ErrorCategory {
+	if err == nil {
+		return ErrCatNone
+	}
+	var resp minio.ErrorResponse
+	if errors.As(err, &resp) && (resp.StatusCode != 0 || resp.Code != "") {
+		if c.isThrottle(resp.Code) {
+			return ErrCatThrottled
+		}
+		return c.ClassifyStatus(resp.StatusCode)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrCatTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return ErrCatTimeout
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNABORTED) || errors.Is(err, syscall.EPIPE) {
+		return ErrCatConnection
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return ErrCatConnection
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return ErrCatConnection
+	}
+	return c.ClassifyMessage(err.Error())
+}
+
+// ClassifyStatus returns the category of an HTTP status code.
+// Status codes below 400 return ErrCatNone.
+func (c ErrorClassifier) ClassifyStatus(status int) ErrorCategory {
+	switch {
+	case status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable:
+		return ErrCatThrottled
+	case status == http.StatusRequestTimeout || status == http.StatusGatewayTimeout:
+		return ErrCatTimeout
+	case status >= 500:
+		return ErrCatServer
+	case status >= 400:
+		return ErrCatClient
+	case status == 0:
+		return ErrCatOther
+	}
+	return ErrCatNone
+}
+
+// ClassifyMessage returns the category of an error message.
+// This is used for errors that are only available as text,
+// for example Operation.Err, and is best effort.
+func (c ErrorClassifier) ClassifyMessage(msg string) ErrorCategory {
+	if msg == "" {
+		return ErrCatNone
+	}
+	m := strings.ToLower(msg)
+	switch {
+	case strings.HasPrefix(m, integrityErrPrefix):
+		return ErrCatIntegrity
+	case strings.Contains(m, "reduce your request rate"), strings.Contains(m, "slow down"),
+		strings.Contains(m, "too many requests"), strings.Contains(m, "throttl"):
+		return ErrCatThrottled
+	case strings.Contains(m, "deadline exceeded"), strings.Contains(m, "timeout"),
+		strings.Contains(m, "timed out"):
+		return ErrCatTimeout
+	case strings.Contains(m, "connection reset"), strings.Contains(m, "connection refused"),
+		strings.Contains(m, "broken pipe"), strings.Contains(m, "no such host"),
+		strings.Contains(m, "unexpected eof"):
+		return ErrCatConnection
+	}
+	return ErrCatOther
+}
+
+func (c ErrorClassifier) isThrottle(code string) bool {
+	for _, tc := range throttleCodes {
+		if strings.EqualFold(code, tc) {
+			return true
+		}
+	}
+	for _, tc := range c.ThrottleCodes {
+		if strings.EqualFold(code, tc) {
+			return true
+		}
+	}
+	return false
+}
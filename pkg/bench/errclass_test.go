@@ -0,0 +1,127 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+)
+
+func TestErrorClassifier(t *testing.T) {
+	s3err := func(status int, code string) error {
+		return minio.ErrorResponse{StatusCode: status, Code: code, Message: "msg"}
+	}
+	tests := []struct {
+		name string
+		err  error
+		want ErrorCategory
+	}{
+		{name: "nil", err: nil, want: ErrCatNone},
+		{name: "slowdown", err: s3err(503, "SlowDown"), want: ErrCatThrottled},
+		{name: "slowdown-wrapped", err: fmt.Errorf("upload: %w", s3err(503, "SlowDown")), want: ErrCatThrottled},
+		{name: "429", err: s3err(429, ""), want: ErrCatThrottled},
+		{name: "not-found", err: s3err(404, "NoSuchKey"), want: ErrCatClient},
+		{name: "denied-wrapped", err: fmt.Errorf("a: %w", fmt.Errorf("b: %w", s3err(403, "AccessDenied"))), want: ErrCatClient},
+		{name: "internal", err: s3err(500, "InternalError"), want: ErrCatServer},
+		{name: "gateway-timeout", err: s3err(504, ""), want: ErrCatTimeout},
+		{name: "deadline", err: context.DeadlineExceeded, want: ErrCatTimeout},
+		{name: "deadline-wrapped", err: fmt.Errorf("get: %w", context.DeadlineExceeded), want: ErrCatTimeout},
+		{name: "net-timeout", err: &url.Error{Op: "Get", URL: "http://x", Err: os.ErrDeadlineExceeded}, want: ErrCatTimeout},
+		{name: "reset", err: &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, want: ErrCatConnection},
+		{name: "refused-wrapped", err: fmt.Errorf("dial: %w", syscall.ECONNREFUSED), want: ErrCatConnection},
+		{name: "dns", err: &url.Error{Op: "Get", URL: "http://x", Err: &net.DNSError{Err: "no such host", Name: "x"}}, want: ErrCatConnection},
+		{name: "message-throttle", err: errors.New("Please reduce your request rate."), want: ErrCatThrottled},
+		{name: "other", err: errors.New("object size mismatch"), want: ErrCatOther},
+	}
+	var c ErrorClassifier
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := c.Classify(test.err); got != test.want {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+
+	c.ThrottleCodes = []string{"Busy"}
+	if got := c.Classify(s3err(400, "Busy")); got != ErrCatThrottled {
+		t.Errorf("custom throttle code: got %v, want %v", got, ErrCatThrottled)
+	}
+}
+
+func TestErrorClassifierMessage(t *testing.T) {
+	tests := map[string]ErrorCategory{
+		"":                                  ErrCatNone,
+		"context deadline exceeded":         ErrCatTimeout,
+		"read tcp: i/o timeout":             ErrCatTimeout,
+		"read: connection reset by peer":    ErrCatConnection,
+		"dial tcp: connection refused":      ErrCatConnection,
+		"Please reduce your request rate.":  ErrCatThrottled,
+		"The specified key does not exist.": ErrCatOther,
+		"integrity: etag mismatch":          ErrCatIntegrity,
+	}
+	var c ErrorClassifier
+	for msg, want := range tests {
+		if got := c.ClassifyMessage(msg); got != want {
+			t.Errorf("%q: got %v, want %v", msg, got, want)
+		}
+	}
+}
+
+func TestOperationErrCategory(t *testing.T) {
+	var op Operation
+	op.SetErr(fmt.Errorf("upload error: %w", minio.ErrorResponse{StatusCode: 500, Code: "InternalError", Message: "We encountered an internal error"}))
+	if op.ErrCat != ErrCatServer || op.ErrCategory() != ErrCatServer {
+		t.Errorf("server error stored as %v", op.ErrCat)
+	}
+	// The message alone does not identify the category.
+	if got := (ErrorClassifier{}).ClassifyMessage(op.Err); got == ErrCatServer {
+		t.Fatalf("message classified as %v", got)
+	}
+
+	// Errors only available as text fall back to the message.
+	text := Operation{Err: "Please reduce your request rate."}
+	if got := text.ErrCategory(); got != ErrCatThrottled {
+		t.Errorf("text error: got %v, want %v", got, ErrCatThrottled)
+	}
+	if got := (Operation{}).ErrCategory(); got != ErrCatNone {
+		t.Errorf("no error: got %v", got)
+	}
+
+	// The category is kept when written as CSV.
+	op.OpType = "PUT"
+	var buf bytes.Buffer
+	if err := (Operations{op, text}).CSV(&buf, ""); err != nil {
+		t.Fatal(err)
+	}
+	ops, err := OperationsFromCSV(&buf, false, 0, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ops) != 2 || ops[0].ErrCat != ErrCatServer || ops[1].ErrCat != ErrCatNone {
+		t.Fatalf("read back %+v", ops)
+	}
+}
@@ -0,0 +1,130 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/warp/pkg/generator"
+)
+
+// integrityErrPrefix starts the message of errors classified as ErrCatIntegrity.
+const integrityErrPrefix = "integrity:"
+
+// ETagCheck is the result of comparing a returned ETag to the uploaded content.
+type ETagCheck uint8
+
+const (
+	// ETagMatch means the ETag matched the content.
+	ETagMatch ETagCheck = iota
+
+	// ETagMismatch means the ETag did not match the content.
+	ETagMismatch
+
+	// ETagSkipped means the ETag could not be verified.
+	// This is the case for multipart ETags when part checksums are unknown.
+	ETagSkipped
+)
+
+// String returns a description of the result.
+func (e ETagCheck) String() string {
+	switch e {
+	case ETagMatch:
+		return "match"
+	case ETagMismatch:
+		return "mismatch"
+	case ETagSkipped:
+		return "skipped"
+	}
+	return fmt.Sprintf("ETagCheck(%d)", int(e))
+}
+
+// CheckETag compares etag to the MD5 of the uploaded content.
+// Multipart ETags ("<md5 of part md5s>-<parts>") are compared using partSums,
+// the MD5 of each part in order, and skipped if they are not provided.
+// An empty etag or a nil sum is skipped.
+func CheckETag(etag string, sum []byte, partSums [][]byte) ETagCheck {
+	etag = strings.ToLower(strings.Trim(etag, `"`))
+	if etag == "" {
+		return ETagSkipped
+	}
+	if idx := strings.IndexByte(etag, '-'); idx >= 0 {
+		if _, err := strconv.Atoi(etag[idx+1:]); err != nil || len(partSums) == 0 {
+			return ETagSkipped
+		}
+		if etag != MultipartETag(partSums) {
+			return ETagMismatch
+		}
+		return ETagMatch
+	}
+	if sum == nil {
+		return ETagSkipped
+	}
+	if etag != hex.EncodeToString(sum) {
+		return ETagMismatch
+	}
+	return ETagMatch
+}
+
+// MultipartETag returns the ETag S3 assigns to a multipart upload with the given part MD5 sums.
+func MultipartETag(partSums [][]byte) string {
+	h := md5.New()
+	for _, s := range partSums {
+		h.Write(s)
+	}
+	return fmt.Sprintf("%x-%d", h.Sum(nil), len(partSums))
+}
+
+// verifyETag returns an integrity error if etag does not match sum.
+func verifyETag(etag string, sum []byte) error {
+	if CheckETag(etag, sum, nil) != ETagMismatch {
+		return nil
+	}
+	return fmt.Errorf("%s etag mismatch. want: %x, got: %s", integrityErrPrefix, sum, strings.Trim(etag, `"`))
+}
+
+// putObjectVerified uploads obj like putObjectAttrs.
+// If verify is set, the MD5 of the uploaded content is compared to the returned ETag
+// and an integrity error is returned on mismatch.
+func putObjectVerified(ctx context.Context, client ObjectPutter, bucket string, obj *generator.Object, attrs ObjectAttrs, opts minio.PutObjectOptions, verify bool) (minio.UploadInfo, error) {
+	if !verify {
+		return putObjectAttrs(ctx, client, bucket, obj, attrs, opts)
+	}
+	cr, err := generator.NewChecksumReader(obj.Reader, generator.ChecksumMD5)
+	if err != nil {
+		return minio.UploadInfo{}, err
+	}
+	o := *obj
+	o.Reader = cr
+	res, err := putObjectAttrs(ctx, client, bucket, &o, attrs, opts)
+	if err != nil {
+		return res, err
+	}
+	sum := cr.Sum()
+	if sum == nil && obj.Size == 0 {
+		// The client may not read an empty body at all.
+		sum = md5.New().Sum(nil)
+	}
+	return res, verifyETag(res.ETag, sum)
+}
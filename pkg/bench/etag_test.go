@@ -0,0 +1,117 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"io"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/warp/pkg/generator"
+)
+
+// etagPutter returns the ETag computed by etag for the uploaded content.
+type etagPutter struct {
+	etag func(body []byte) string
+}
+
+func (m etagPutter) PutObject(ctx context.Context, bucket, object string, reader io.Reader, size int64, opts minio.PutObjectOptions) (minio.UploadInfo, error) {
+	b, err := io.ReadAll(reader)
+	if err != nil {
+		return minio.UploadInfo{}, err
+	}
+	return minio.UploadInfo{Bucket: bucket, Key: object, Size: int64(len(b)), ETag: m.etag(b)}, nil
+}
+
+func TestPutObjectVerified(t *testing.T) {
+	md5Hex := func(b []byte) string { return hex.EncodeToString(md5Sum(b)) }
+	tests := []struct {
+		name string
+		etag func(b []byte) string
+		want ErrorCategory
+	}{
+		{name: "match", etag: md5Hex, want: ErrCatNone},
+		{name: "match-quoted", etag: func(b []byte) string { return `"` + md5Hex(b) + `"` }, want: ErrCatNone},
+		{name: "mismatch", etag: func(b []byte) string { return md5Hex(append(b, 0)) }, want: ErrCatIntegrity},
+		{name: "multipart", etag: func(b []byte) string { return md5Hex(b) + "-3" }, want: ErrCatNone},
+		{name: "empty", etag: func(b []byte) string { return "" }, want: ErrCatNone},
+	}
+	body := bytes.Repeat([]byte("warp"), 1000)
+	var c ErrorClassifier
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj := &generator.Object{Name: "obj", Size: int64(len(body)), Reader: bytes.NewReader(body)}
+			res, err := putObjectVerified(context.Background(), etagPutter{etag: test.etag}, "bucket", obj, ObjectAttrs{}, minio.PutObjectOptions{}, true)
+			if got := c.Classify(err); got != test.want {
+				t.Errorf("got %v (%v), want %v", got, err, test.want)
+			}
+			if res.Size != int64(len(body)) {
+				t.Errorf("size %d, want %d", res.Size, len(body))
+			}
+		})
+	}
+
+	// Without verification mismatches are not detected.
+	obj := &generator.Object{Name: "obj", Size: int64(len(body)), Reader: bytes.NewReader(body)}
+	_, err := putObjectVerified(context.Background(), etagPutter{etag: func([]byte) string { return "bad" }}, "bucket", obj, ObjectAttrs{}, minio.PutObjectOptions{}, false)
+	if err != nil {
+		t.Errorf("unverified upload: %v", err)
+	}
+}
+
+func TestCheckETag(t *testing.T) {
+	parts := [][]byte{
+		md5Sum([]byte("part1")),
+		md5Sum([]byte("part2")),
+	}
+	mp := MultipartETag(parts)
+	sum := md5Sum([]byte("single"))
+	tests := []struct {
+		name  string
+		etag  string
+		sum   []byte
+		parts [][]byte
+		want  ETagCheck
+	}{
+		{name: "single", etag: hex.EncodeToString(sum), sum: sum, want: ETagMatch},
+		{name: "single-quoted", etag: `"` + hex.EncodeToString(sum) + `"`, sum: sum, want: ETagMatch},
+		{name: "single-mismatch", etag: hex.EncodeToString(parts[0]), sum: sum, want: ETagMismatch},
+		{name: "no-sum", etag: hex.EncodeToString(sum), want: ETagSkipped},
+		{name: "multipart", etag: mp, parts: parts, want: ETagMatch},
+		{name: "multipart-count", etag: mp[:len(mp)-1] + "3", parts: parts, want: ETagMismatch},
+		{name: "multipart-order", etag: mp, parts: [][]byte{parts[1], parts[0]}, want: ETagMismatch},
+		{name: "multipart-unknown", etag: mp, sum: sum, want: ETagSkipped},
+		{name: "empty", etag: "", sum: sum, want: ETagSkipped},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := CheckETag(test.etag, test.sum, test.parts); got != test.want {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func md5Sum(b []byte) []byte {
+	s := md5.Sum(b)
+	return s[:]
+}
@@ -57,6 +57,7 @@ func (u *Fanout) Start(ctx context.Context, wait chan struct{}) error {
 		src := u.Source()
 		u.prefixes[src.Prefix()] = struct{}{}
 		go func(i int) {
+			getClient := u.workerClient(i)
 			rcv := c.Receiver()
 			defer wg.Done()
 			opts := minio.PutObjectFanOutRequest{
@@ -67,12 +68,18 @@ func (u *Fanout) Start(ctx context.Context, wait chan struct{}) error {
 			done := ctx.Done()
 
 			<-wait
+			if u.RampUp.Wait(ctx, i) != nil {
+				return
+			}
 			for {
 				select {
 				case <-done:
 					return
 				default:
 				}
+				if u.ThinkTime.Wait(ctx) != nil {
+					return
+				}
 				obj := src.Object()
 				for i := range opts.Entries {
 					opts.Entries[i] = minio.PutObjectFanOutEntry{
@@ -86,7 +93,7 @@ func (u *Fanout) Start(ctx context.Context, wait chan struct{}) error {
 						CacheControl:       u.PutOpts.CacheControl,
 					}
 				}
-				client, cldone := u.Client()
+				client, cldone := getClient()
 				op := Operation{
 					OpType:   http.MethodPost,
 					Thread:   uint32(i),
@@ -101,7 +108,7 @@ func (u *Fanout) Start(ctx context.Context, wait chan struct{}) error {
 				op.End = time.Now()
 				if err != nil {
 					u.Error("upload error: ", err)
-					op.Err = err.Error()
+					op.SetErr(err)
 				}
 
 				var firstErr string
@@ -0,0 +1,193 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// FaultConfig configures the faults injected by FaultyClient.
+// Rates are probabilities from 0 to 1 and are drawn independently for each call.
+type FaultConfig struct {
+	// ErrorRate is the probability that a call fails with a 503 response
+	// without reaching the wrapped client.
+	ErrorRate float64
+
+	// LatencyRate is the probability that Latency is added before a call.
+	LatencyRate float64
+	Latency     time.Duration
+
+	// TruncateRate is the probability that a GetObject body ends early.
+	// Reading a truncated body returns io.ErrUnexpectedEOF.
+	TruncateRate float64
+
+	// Seed seeds the fault decisions, so runs with the same calls are reproducible.
+	Seed int64
+}
+
+// FaultyClient is an ObjectClient that injects failures into calls to another ObjectClient.
+// It is used to test retries and error accounting.
+// It is safe for concurrent use if the wrapped client is.
+type FaultyClient struct {
+	inner ObjectClient
+	cfg   FaultConfig
+
+	mu  sync.Mutex
+	rng *rand.Rand
+
+	errors, delays, truncated atomic.Int64
+}
+
+// NewFaultyClient returns a client that forwards calls to inner and injects faults as configured in cfg.
+// Rates outside 0 to 1 are clamped.
+func NewFaultyClient(inner ObjectClient, cfg FaultConfig) *FaultyClient {
+	cfg.ErrorRate = min(max(cfg.ErrorRate, 0), 1)
+	cfg.LatencyRate = min(max(cfg.LatencyRate, 0), 1)
+	cfg.TruncateRate = min(max(cfg.TruncateRate, 0), 1)
+	return &FaultyClient{
+		inner: inner,
+		cfg:   cfg,
+		rng:   rand.New(rand.NewSource(cfg.Seed)),
+	}
+}
+
+// Errors returns the number of injected 503 responses.
+func (f *FaultyClient) Errors() int64 {
+	return f.errors.Load()
+}
+
+// Delays returns the number of calls that had latency injected.
+func (f *FaultyClient) Delays() int64 {
+	return f.delays.Load()
+}
+
+// Truncated returns the number of GetObject bodies that were truncated.
+func (f *FaultyClient) Truncated() int64 {
+	return f.truncated.Load()
+}
+
+// hit returns true with probability p.
+func (f *FaultyClient) hit(p float64) bool {
+	if p <= 0 {
+		return false
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.rng.Float64() < p
+}
+
+// inject applies latency and error faults before a call.
+// A non-nil error should be returned instead of calling the wrapped client.
+func (f *FaultyClient) inject(ctx context.Context, bucket, object string) error {
+	if f.cfg.Latency > 0 && f.hit(f.cfg.LatencyRate) {
+		f.delays.Add(1)
+		if !sleepCtx(ctx, f.cfg.Latency) {
+			return ctx.Err()
+		}
+	}
+	if f.hit(f.cfg.ErrorRate) {
+		f.errors.Add(1)
+		return minio.ErrorResponse{
+			StatusCode: http.StatusServiceUnavailable,
+			Code:       "ServiceUnavailable",
+			Message:    "Injected fault: " + http.StatusText(http.StatusServiceUnavailable),
+			BucketName: bucket,
+			Key:        object,
+		}
+	}
+	return nil
+}
+
+// PutObject forwards to the wrapped client unless an error is injected.
+func (f *FaultyClient) PutObject(ctx context.Context, bucket, object string, r io.Reader, size int64, opts minio.PutObjectOptions) (minio.UploadInfo, error) {
+	if err := f.inject(ctx, bucket, object); err != nil {
+		return minio.UploadInfo{}, err
+	}
+	return f.inner.PutObject(ctx, bucket, object, r, size, opts)
+}
+
+// GetObject forwards to the wrapped client unless an error is injected.
+// The returned body may be truncated at a random offset.
+func (f *FaultyClient) GetObject(ctx context.Context, bucket, object string, opts minio.GetObjectOptions) (io.ReadCloser, minio.ObjectInfo, error) {
+	if err := f.inject(ctx, bucket, object); err != nil {
+		return nil, minio.ObjectInfo{}, err
+	}
+	rc, info, err := f.inner.GetObject(ctx, bucket, object, opts)
+	if err != nil || info.Size <= 0 || !f.hit(f.cfg.TruncateRate) {
+		return rc, info, err
+	}
+	f.truncated.Add(1)
+	f.mu.Lock()
+	n := f.rng.Int63n(info.Size)
+	f.mu.Unlock()
+	return &truncatedBody{ReadCloser: rc, remain: n}, info, nil
+}
+
+// StatObject forwards to the wrapped client unless an error is injected.
+func (f *FaultyClient) StatObject(ctx context.Context, bucket, object string, opts minio.StatObjectOptions) (minio.ObjectInfo, error) {
+	if err := f.inject(ctx, bucket, object); err != nil {
+		return minio.ObjectInfo{}, err
+	}
+	return f.inner.StatObject(ctx, bucket, object, opts)
+}
+
+// RemoveObject forwards to the wrapped client unless an error is injected.
+func (f *FaultyClient) RemoveObject(ctx context.Context, bucket, object string, opts minio.RemoveObjectOptions) error {
+	if err := f.inject(ctx, bucket, object); err != nil {
+		return err
+	}
+	return f.inner.RemoveObject(ctx, bucket, object, opts)
+}
+
+// ListObjects forwards to the wrapped client unless an error is injected.
+// An injected error is sent as the only ObjectInfo.
+func (f *FaultyClient) ListObjects(ctx context.Context, bucket string, opts minio.ListObjectsOptions) <-chan minio.ObjectInfo {
+	if err := f.inject(ctx, bucket, ""); err != nil {
+		ch := make(chan minio.ObjectInfo, 1)
+		ch <- minio.ObjectInfo{Err: err}
+		close(ch)
+		return ch
+	}
+	return f.inner.ListObjects(ctx, bucket, opts)
+}
+
+// truncatedBody returns io.ErrUnexpectedEOF after remain bytes have been read.
+type truncatedBody struct {
+	io.ReadCloser
+	remain int64
+}
+
+func (t *truncatedBody) Read(p []byte) (int, error) {
+	if t.remain <= 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	if int64(len(p)) > t.remain {
+		p = p[:t.remain]
+	}
+	n, err := t.ReadCloser.Read(p)
+	t.remain -= int64(n)
+	return n, err
+}
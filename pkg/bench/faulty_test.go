@@ -0,0 +1,139 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+var _ ObjectClient = (*FaultyClient)(nil)
+
+func TestFaultyClientErrorRate(t *testing.T) {
+	ctx := context.Background()
+	mem := NewMemClient("bucket")
+	body := []byte("faulty")
+	if _, err := mem.PutObject(ctx, "bucket", "obj", bytes.NewReader(body), int64(len(body)), minio.PutObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	const n, rate = 10000, 0.2
+	run := func(seed int64) []bool {
+		c := NewFaultyClient(mem, FaultConfig{ErrorRate: rate, Seed: seed})
+		var classifier ErrorClassifier
+		failed := make([]bool, n)
+		for i := range failed {
+			_, err := c.StatObject(ctx, "bucket", "obj", minio.StatObjectOptions{})
+			if err != nil {
+				if cat := classifier.Classify(err); cat != ErrCatThrottled {
+					t.Fatalf("injected error classified as %v: %v", cat, err)
+				}
+				failed[i] = true
+			}
+		}
+		if c.Errors() < n*(rate-0.02) || c.Errors() > n*(rate+0.02) {
+			t.Errorf("seed %d: %d of %d calls failed, want about %v", seed, c.Errors(), n, rate*n)
+		}
+		return failed
+	}
+	a, b := run(1), run(1)
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("same seed injected different faults at call %d", i)
+		}
+	}
+
+	// Retries recover from injected errors.
+	c := NewFaultyClient(mem, FaultConfig{ErrorRate: 0.5, Seed: 2})
+	r := NewRetrier(20, 0, 0, 0)
+	for range 100 {
+		err := r.Do(ctx, func() error {
+			_, err := c.StatObject(ctx, "bucket", "obj", minio.StatObjectOptions{})
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if r.Retries() != c.Errors() || r.Retries() == 0 {
+		t.Errorf("got %d retries for %d injected errors", r.Retries(), c.Errors())
+	}
+}
+
+func TestFaultyClientTruncate(t *testing.T) {
+	ctx := context.Background()
+	mem := NewMemClient("bucket")
+	body := bytes.Repeat([]byte("0123456789"), 100)
+	c := NewFaultyClient(mem, FaultConfig{TruncateRate: 1, Seed: 1})
+	if _, err := c.PutObject(ctx, "bucket", "obj", bytes.NewReader(body), int64(len(body)), minio.PutObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	for range 10 {
+		rc, info, err := c.GetObject(ctx, "bucket", "obj", minio.GetObjectOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Size != int64(len(body)) {
+			t.Errorf("got size %d, want %d", info.Size, len(body))
+		}
+		got, err := io.ReadAll(rc)
+		rc.Close()
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Fatalf("got error %v, want io.ErrUnexpectedEOF", err)
+		}
+		if len(got) >= len(body) || !bytes.Equal(got, body[:len(got)]) {
+			t.Errorf("got %d bytes of a %d byte body", len(got), len(body))
+		}
+		var classifier ErrorClassifier
+		if cat := classifier.Classify(err); cat != ErrCatConnection {
+			t.Errorf("truncated body classified as %v", cat)
+		}
+	}
+	if c.Truncated() != 10 {
+		t.Errorf("got %d truncated bodies, want 10", c.Truncated())
+	}
+}
+
+func TestFaultyClientLatency(t *testing.T) {
+	ctx := context.Background()
+	c := NewFaultyClient(NewMemClient("bucket"), FaultConfig{LatencyRate: 1, Latency: 20 * time.Millisecond})
+	start := time.Now()
+	for range 3 {
+		c.StatObject(ctx, "bucket", "missing", minio.StatObjectOptions{})
+	}
+	if d := time.Since(start); d < 60*time.Millisecond {
+		t.Errorf("3 calls took %v, want at least 60ms", d)
+	}
+	if c.Delays() != 3 {
+		t.Errorf("got %d delays, want 3", c.Delays())
+	}
+
+	// Canceled while delayed.
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	for obj := range c.ListObjects(ctx, "bucket", minio.ListObjectsOptions{}) {
+		if !errors.Is(obj.Err, context.Canceled) {
+			t.Errorf("got %v, want context.Canceled", obj.Err)
+		}
+	}
+}
@@ -19,6 +19,7 @@ package bench
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"math/rand"
@@ -45,6 +46,24 @@ type Get struct {
 	RangeSize     int64
 	ListExisting  bool
 	ListFlat      bool
+
+	// Access selects the order objects are read in.
+	// Sequential access follows the order objects were uploaded or listed.
+	Access AccessPattern
+
+	// AccessSeed seeds the order of AccessShuffled.
+	AccessSeed int64
+
+	// Zipf, if set, picks objects with Zipf distributed popularity instead of Access,
+	// so the first uploaded or listed objects are read the most.
+	Zipf *ZipfSelector
+
+	// Conditional, if set, sends every GET with If-None-Match set to the ETag of the object.
+	// Ranges are not requested.
+	Conditional *ConditionalGet
+
+	// ReadBuffer, if set, reads response bodies in chunks of its size.
+	ReadBuffer *ReadBuffer
 }
 
 // Prepare will create an empty bucket or delete any content already there
@@ -84,6 +103,7 @@ func (g *Get) Prepare(ctx context.Context) error {
 			obj := generator.Object{
 				Name: object.Key,
 				Size: object.Size,
+				ETag: object.ETag,
 			}
 
 			if g.Versions > 1 {
@@ -192,6 +212,7 @@ func (g *Get) Prepare(ctx context.Context) error {
 						return
 					}
 					obj.VersionID = res.VersionID
+					obj.ETag = res.ETag
 					if res.Size != obj.Size {
 						err := fmt.Errorf("short upload. want: %d, got %d", obj.Size, res.Size)
 						g.Error(err)
@@ -217,6 +238,8 @@ func (g *Get) Prepare(ctx context.Context) error {
 	return groupErr
 }
 
+// firstByteRecorder records the time the first byte is read from r,
+// so the time to first byte can be reported separately from the total duration.
 type firstByteRecorder struct {
 	t *time.Time
 	r io.Reader
@@ -227,8 +250,9 @@ func (f *firstByteRecorder) Read(p []byte) (n int, err error) {
 		return f.r.Read(p)
 	}
 	// Read a single byte.
+	// Zero byte objects record the time the empty body was complete.
 	n, err = f.r.Read(p[:1])
-	if n > 0 {
+	if n > 0 || err == io.EOF {
 		t := time.Now()
 		f.t = &t
 	}
@@ -247,9 +271,15 @@ func (g *Get) Start(ctx context.Context, wait chan struct{}) error {
 
 	// Non-terminating context.
 	nonTerm := context.Background()
+	keys := NewKeyAccess(g.Access, g.AccessSeed)
+	if g.Access == AccessShuffled {
+		// Uploads complete in any order.
+		SortObjects(g.objects)
+	}
 
 	for i := 0; i < g.Concurrency; i++ {
 		go func(i int) {
+			getClient := g.workerClient(i)
 			rng := rand.New(rand.NewSource(int64(i)))
 			rcv := c.Receiver()
 			defer wg.Done()
@@ -257,6 +287,9 @@ func (g *Get) Start(ctx context.Context, wait chan struct{}) error {
 			done := ctx.Done()
 
 			<-wait
+			if g.RampUp.Wait(ctx, i) != nil {
+				return
+			}
 			for {
 				select {
 				case <-done:
@@ -264,13 +297,23 @@ func (g *Get) Start(ctx context.Context, wait chan struct{}) error {
 				default:
 				}
 
+				if g.ThinkTime.Wait(ctx) != nil {
+					return
+				}
 				if g.rpsLimit(ctx) != nil {
 					return
 				}
 
 				fbr := firstByteRecorder{}
-				obj := g.objects[rng.Intn(len(g.objects))]
-				client, cldone := g.Client()
+				idx := rng.Intn(len(g.objects))
+				switch {
+				case g.Zipf != nil:
+					idx = g.Zipf.Index(len(g.objects))
+				case g.Access != AccessRandom:
+					idx = keys.Index(len(g.objects))
+				}
+				obj := g.objects[idx]
+				client, cldone := getClient()
 				op := Operation{
 					OpType:   http.MethodGet,
 					Thread:   uint32(i),
@@ -283,6 +326,26 @@ func (g *Get) Start(ctx context.Context, wait chan struct{}) error {
 					op.File = ""
 				}
 
+				if g.Conditional != nil {
+					if g.Versions > 1 {
+						opts.VersionID = obj.VersionID
+					}
+					err := g.withOpTimeout(nonTerm, func(ctx context.Context) error {
+						g.Conditional.get(ctx, NewObjectClient(client), g.Bucket, obj, opts, &op)
+						if op.Err != "" {
+							return errors.New(op.Err)
+						}
+						return nil
+					})
+					opTimedOut(&op, err)
+					if op.Err != "" {
+						g.Error("download error:", op.Err)
+					}
+					rcv <- op
+					cldone()
+					continue
+				}
+
 				if g.RandomRanges && op.Size > 2 {
 					var start, end int64
 					if g.RangeSize <= 0 {
@@ -302,30 +365,32 @@ func (g *Get) Start(ctx context.Context, wait chan struct{}) error {
 				if g.Versions > 1 {
 					opts.VersionID = obj.VersionID
 				}
-				o, err := client.GetObject(nonTerm, g.Bucket, obj.Name, opts)
-				if err != nil {
-					g.Error("download error:", err)
-					op.Err = err.Error()
-					op.End = time.Now()
-					rcv <- op
-					cldone()
-					continue
-				}
-				fbr.r = o
-				n, err := io.Copy(io.Discard, &fbr)
-				if err != nil {
-					g.Error("download error:", err)
-					op.Err = err.Error()
-				}
+				var n int64
+				err = g.Retry.DoOp(ctx, &op, func() error {
+					return g.withOpTimeout(nonTerm, func(ctx context.Context) error {
+						fbr = firstByteRecorder{}
+						o, err := client.GetObject(ctx, g.Bucket, obj.Name, opts)
+						if err != nil {
+							return err
+						}
+						defer o.Close()
+						fbr.r = o
+						n, err = g.ReadBuffer.Copy(io.Discard, &fbr)
+						return err
+					})
+				})
 				op.FirstByte = fbr.t
 				op.End = time.Now()
+				if err != nil {
+					op.SetErr(err)
+					g.Error("download error:", op.Err)
+				}
 				if n != op.Size && op.Err == "" {
 					op.Err = fmt.Sprint("unexpected download size. want:", op.Size, ", got:", n)
 					g.Error(op.Err)
 				}
 				rcv <- op
 				cldone()
-				o.Close()
 			}
 		}(i)
 	}
@@ -333,6 +398,12 @@ func (g *Get) Start(ctx context.Context, wait chan struct{}) error {
 	return nil
 }
 
+// timesOutOps implements opTimeouter.
+func (g *Get) timesOutOps() {}
+
+// retriesOps implements opRetrier.
+func (g *Get) retriesOps() {}
+
 // Cleanup deletes everything uploaded to the bucket.
 func (g *Get) Cleanup(ctx context.Context) {
 	if !g.ListExisting {
@@ -0,0 +1,96 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/warp/pkg/generator"
+)
+
+func TestFirstByteRecorder(t *testing.T) {
+	const headerDelay, bodyDelay = 50 * time.Millisecond, 100 * time.Millisecond
+	body := bytes.Repeat([]byte("ttfb"), 1000)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.Header().Set("ETag", `"d41d8cd98f00b204e9800998ecf8427e"`)
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		time.Sleep(headerDelay)
+		w.Write(body[:len(body)/2])
+		w.(http.Flusher).Flush()
+		time.Sleep(bodyDelay)
+		w.Write(body[len(body)/2:])
+	}))
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+	client, err := minio.New(u.Host, &minio.Options{
+		Creds:  credentials.NewStaticV4("access", "secret", ""),
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := &Get{
+		Common: Common{
+			Client:      func() (*minio.Client, func()) { return client, func() {} },
+			Bucket:      "bucket",
+			Concurrency: 2,
+			Error:       func(data ...any) {},
+		},
+		objects: generator.Objects{{Name: "obj", Size: int64(len(body))}},
+	}
+	ops, err := RunFor(context.Background(), g, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var done int
+	for _, op := range ops {
+		if op.Err != "" {
+			// Interrupted at the end of the run.
+			continue
+		}
+		done++
+		if op.Size != int64(len(body)) {
+			t.Fatalf("got %d bytes, want %d", op.Size, len(body))
+		}
+		if op.FirstByte == nil {
+			t.Fatal("first byte not recorded")
+		}
+		// The body cannot arrive before the server sends it,
+		// and half of it is sent well before the end.
+		ttfb, total := op.FirstByte.Sub(op.Start), op.Duration()
+		if ttfb < headerDelay || total-ttfb < bodyDelay/10 {
+			t.Errorf("ttfb %v, total %v", ttfb, total)
+		}
+	}
+	if done == 0 {
+		t.Fatal("no completed downloads")
+	}
+}
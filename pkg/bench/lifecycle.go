@@ -0,0 +1,202 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/warp/pkg/generator"
+)
+
+// Lifecycle benchmarks the lifecycle of short-lived objects.
+// Each operation uploads an object, downloads and verifies it, and deletes it.
+// Every phase is recorded as an operation of its own type,
+// and the whole sequence as a LIFECYCLE operation.
+type Lifecycle struct {
+	Common
+
+	prefixes map[string]struct{}
+}
+
+// lifecycleOpType is the operation type for a whole lifecycle.
+const lifecycleOpType = "LIFECYCLE"
+
+// objectLifecycle uploads obj, downloads and verifies it, then deletes it.
+// op is used as a template for the returned operations.
+// An operation is returned for every phase that ran, in order,
+// followed by one covering all phases.
+// An error is set on the phase where it happened,
+// and on the lifecycle operation, prefixed by the phase.
+// The object is deleted even if downloading it fails.
+func objectLifecycle(ctx context.Context, client ObjectClient, bucket string, obj *generator.Object, opts minio.PutObjectOptions, op Operation) []Operation {
+	total := op
+	total.OpType = lifecycleOpType
+	total.Size = obj.Size
+	ops := make([]Operation, 0, 4)
+	phase := func(typ string, size int64, fn func() error) bool {
+		p := op
+		p.OpType = typ
+		p.Size = size
+		p.Start = time.Now()
+		err := fn()
+		p.End = time.Now()
+		if err != nil {
+			p.SetErr(err)
+			if total.Err == "" {
+				total.Err, total.ErrCat = typ+": "+p.Err, p.ErrCat
+			}
+		}
+		ops = append(ops, p)
+		return err == nil
+	}
+
+	total.Start = time.Now()
+	var sum []byte
+	put := phase(http.MethodPut, obj.Size, func() error {
+		cr, err := generator.NewChecksumReader(obj.Reader, generator.ChecksumMD5)
+		if err != nil {
+			return err
+		}
+		o := *obj
+		o.Reader = cr
+		res, err := putObjectAttrs(ctx, client, bucket, &o, ObjectAttrs{}, opts)
+		if err != nil {
+			return err
+		}
+		if res.Size != obj.Size {
+			return fmt.Errorf("short upload. want: %d, got %d", obj.Size, res.Size)
+		}
+		obj.VersionID = res.VersionID
+		sum = cr.Sum()
+		return nil
+	})
+	if put {
+		phase(http.MethodGet, obj.Size, func() error {
+			rc, _, err := client.GetObject(ctx, bucket, obj.Name, minio.GetObjectOptions{})
+			if err != nil {
+				return err
+			}
+			defer rc.Close()
+			h := md5.New()
+			n, err := io.Copy(h, rc)
+			switch {
+			case err != nil:
+				return err
+			case n != obj.Size:
+				return fmt.Errorf("unexpected download size. want: %d, got: %d", obj.Size, n)
+			case !bytes.Equal(h.Sum(nil), sum):
+				return fmt.Errorf("%s downloaded content does not match upload", integrityErrPrefix)
+			}
+			return nil
+		})
+		phase(http.MethodDelete, 0, func() error {
+			return client.RemoveObject(ctx, bucket, obj.Name, minio.RemoveObjectOptions{VersionID: obj.VersionID})
+		})
+	}
+	total.End = time.Now()
+	return append(ops, total)
+}
+
+// Prepare will create an empty bucket or delete any content already there.
+func (g *Lifecycle) Prepare(ctx context.Context) error {
+	return g.createEmptyBucket(ctx)
+}
+
+// Start will execute the main benchmark.
+// Operations should begin executing when the start channel is closed.
+func (g *Lifecycle) Start(ctx context.Context, wait chan struct{}) error {
+	var wg sync.WaitGroup
+	wg.Add(g.Concurrency)
+	c := g.Collector
+	if g.AutoTermDur > 0 {
+		ctx = c.AutoTerm(ctx, lifecycleOpType, g.AutoTermScale, autoTermCheck, autoTermSamples, g.AutoTermDur)
+	}
+	g.prefixes = make(map[string]struct{}, g.Concurrency)
+
+	// Non-terminating context.
+	nonTerm := context.Background()
+
+	for i := 0; i < g.Concurrency; i++ {
+		src := g.Source()
+		g.prefixes[src.Prefix()] = struct{}{}
+		go func(i int) {
+			getClient := g.workerClient(i)
+			rcv := c.Receiver()
+			defer wg.Done()
+			done := ctx.Done()
+
+			<-wait
+			if g.RampUp.Wait(ctx, i) != nil {
+				return
+			}
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				if g.ThinkTime.Wait(ctx) != nil {
+					return
+				}
+				if g.rpsLimit(ctx) != nil {
+					return
+				}
+
+				obj := src.Object()
+				client, cldone := getClient()
+				op := Operation{
+					Thread:   uint32(i),
+					File:     obj.Name,
+					ObjPerOp: 1,
+					Endpoint: client.EndpointURL().String(),
+				}
+				if g.DiscardOutput {
+					op.File = ""
+				}
+				ops := objectLifecycle(nonTerm, NewObjectClient(client), g.Bucket, obj, g.PutOpts, op)
+				cldone()
+				for _, op := range ops {
+					if op.Err != "" && op.OpType == lifecycleOpType {
+						g.Error(op.Err)
+					}
+					rcv <- op
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	return nil
+}
+
+// Cleanup deletes objects that were not deleted by the benchmark.
+func (g *Lifecycle) Cleanup(ctx context.Context) {
+	pf := make([]string, 0, len(g.prefixes))
+	for p := range g.prefixes {
+		pf = append(pf, p)
+	}
+	g.deleteAllInBucket(ctx, pf...)
+}
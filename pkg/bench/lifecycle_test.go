@@ -0,0 +1,156 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/warp/pkg/generator"
+)
+
+// phaseClient records the order of calls, delays each of them,
+// and can fail or corrupt a phase.
+type phaseClient struct {
+	ObjectClient
+	delay   time.Duration
+	fail    string
+	corrupt bool
+
+	mu    sync.Mutex
+	calls []string
+}
+
+func (p *phaseClient) call(typ string) error {
+	p.mu.Lock()
+	p.calls = append(p.calls, typ)
+	p.mu.Unlock()
+	time.Sleep(p.delay)
+	if p.fail == typ {
+		return minio.ErrorResponse{StatusCode: http.StatusInternalServerError, Code: "InternalError", Message: typ + " failed"}
+	}
+	return nil
+}
+
+func (p *phaseClient) PutObject(ctx context.Context, bucket, object string, reader io.Reader, size int64, opts minio.PutObjectOptions) (minio.UploadInfo, error) {
+	if err := p.call(http.MethodPut); err != nil {
+		return minio.UploadInfo{}, err
+	}
+	return p.ObjectClient.PutObject(ctx, bucket, object, reader, size, opts)
+}
+
+func (p *phaseClient) GetObject(ctx context.Context, bucket, object string, opts minio.GetObjectOptions) (io.ReadCloser, minio.ObjectInfo, error) {
+	if err := p.call(http.MethodGet); err != nil {
+		return nil, minio.ObjectInfo{}, err
+	}
+	rc, info, err := p.ObjectClient.GetObject(ctx, bucket, object, opts)
+	if err != nil || !p.corrupt {
+		return rc, info, err
+	}
+	b, _ := io.ReadAll(rc)
+	rc.Close()
+	b[len(b)/2] ^= 1
+	return io.NopCloser(bytes.NewReader(b)), info, nil
+}
+
+func (p *phaseClient) RemoveObject(ctx context.Context, bucket, object string, opts minio.RemoveObjectOptions) error {
+	if err := p.call(http.MethodDelete); err != nil {
+		return err
+	}
+	return p.ObjectClient.RemoveObject(ctx, bucket, object, opts)
+}
+
+func TestObjectLifecycle(t *testing.T) {
+	const delay = 2 * time.Millisecond
+	content := []byte(strings.Repeat("lifecycle", 100))
+	tests := []struct {
+		name     string
+		fail     string
+		corrupt  bool
+		phases   []string
+		errPhase string
+		integ    bool
+		left     int
+	}{
+		{name: "ok", phases: []string{"PUT", "GET", "DELETE"}},
+		{name: "put-fails", fail: "PUT", phases: []string{"PUT"}, errPhase: "PUT"},
+		{name: "get-fails", fail: "GET", phases: []string{"PUT", "GET", "DELETE"}, errPhase: "GET"},
+		{name: "get-corrupt", corrupt: true, phases: []string{"PUT", "GET", "DELETE"}, errPhase: "GET", integ: true},
+		{name: "delete-fails", fail: "DELETE", phases: []string{"PUT", "GET", "DELETE"}, errPhase: "DELETE", left: 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mem := NewMemClient("bucket")
+			client := &phaseClient{ObjectClient: mem, delay: delay, fail: test.fail, corrupt: test.corrupt}
+			obj := &generator.Object{Name: "obj", Size: int64(len(content)), Reader: bytes.NewReader(content)}
+			ops := objectLifecycle(context.Background(), client, "bucket", obj, minio.PutObjectOptions{}, Operation{Thread: 3, File: "obj", ObjPerOp: 1})
+
+			if strings.Join(client.calls, ",") != strings.Join(test.phases, ",") {
+				t.Fatalf("calls %v, want %v", client.calls, test.phases)
+			}
+			if len(ops) != len(test.phases)+1 {
+				t.Fatalf("got %d ops, want %d", len(ops), len(test.phases)+1)
+			}
+			total := ops[len(ops)-1]
+			if total.OpType != lifecycleOpType || total.Thread != 3 || total.Size != obj.Size {
+				t.Errorf("unexpected lifecycle op: %+v", total)
+			}
+			var sum time.Duration
+			for i, op := range ops[:len(ops)-1] {
+				if op.OpType != test.phases[i] || op.Thread != 3 || op.File != "obj" {
+					t.Errorf("phase %d: unexpected op %+v", i, op)
+				}
+				if op.Duration() < delay {
+					t.Errorf("%s: latency %v, want >= %v", op.OpType, op.Duration(), delay)
+				}
+				if i > 0 && op.Start.Before(ops[i-1].End) {
+					t.Errorf("%s started before %s ended", op.OpType, ops[i-1].OpType)
+				}
+				if op.Start.Before(total.Start) || op.End.After(total.End) {
+					t.Errorf("%s outside of lifecycle", op.OpType)
+				}
+				if (op.Err != "") != (op.OpType == test.errPhase) {
+					t.Errorf("%s: error %q, want error in %q", op.OpType, op.Err, test.errPhase)
+				}
+				sum += op.Duration()
+			}
+			if total.Duration() < sum {
+				t.Errorf("lifecycle latency %v, less than phases %v", total.Duration(), sum)
+			}
+			switch {
+			case test.errPhase == "" && total.Err != "":
+				t.Errorf("unexpected error %q", total.Err)
+			case test.errPhase != "" && !strings.HasPrefix(total.Err, test.errPhase+": "):
+				t.Errorf("lifecycle error %q not attributed to %s", total.Err, test.errPhase)
+			}
+			if test.integ && !strings.Contains(total.Err, integrityErrPrefix) {
+				t.Errorf("corruption not reported as integrity error: %q", total.Err)
+			}
+			if n := mem.Len("bucket"); n != test.left {
+				t.Errorf("%d objects left, want %d", n, test.left)
+			}
+		})
+	}
+}
@@ -40,6 +40,12 @@ type List struct {
 	NoPrefix      bool
 	Metadata      bool
 	MaxKeys       int
+
+	// Paged records every page as a separate operation.
+	Paged bool
+
+	// MaxPages stops each paged listing after this many pages, if > 0.
+	MaxPages int
 }
 
 // Prepare will create an empty bucket or delete any content already there
@@ -183,6 +189,7 @@ func (d *List) Start(ctx context.Context, wait chan struct{}) error {
 
 	for i := 0; i < d.Concurrency; i++ {
 		go func(i int) {
+			getClient := d.workerClient(i)
 			rcv := c.Receiver()
 			defer wg.Done()
 			done := ctx.Done()
@@ -193,6 +200,9 @@ func (d *List) Start(ctx context.Context, wait chan struct{}) error {
 			}
 
 			<-wait
+			if d.RampUp.Wait(ctx, i) != nil {
+				return
+			}
 			for {
 				select {
 				case <-done:
@@ -200,12 +210,50 @@ func (d *List) Start(ctx context.Context, wait chan struct{}) error {
 				default:
 				}
 
+				if d.ThinkTime.Wait(ctx) != nil {
+					return
+				}
 				if d.rpsLimit(ctx) != nil {
 					return
 				}
 
 				prefix := objs[0].Prefix
-				client, cldone := d.Client()
+				client, cldone := getClient()
+				if d.Paged {
+					endpoint := client.EndpointURL().String()
+					_, err := ListPages(nonTerm, minio.Core{Client: client}, d.Bucket, ListPagesOptions{
+						Prefix:   prefix + "/",
+						MaxKeys:  d.MaxKeys,
+						MaxPages: d.MaxPages,
+						OnPage: func(p ListPage) {
+							rcv <- Operation{
+								File:     prefix,
+								OpType:   "LIST",
+								Thread:   uint32(i),
+								Endpoint: endpoint,
+								ObjPerOp: p.Keys,
+								Start:    p.Start,
+								End:      p.End,
+							}
+						},
+					})
+					cldone()
+					if err != nil {
+						d.Error(err)
+						now := time.Now()
+						rcv <- Operation{
+							File:     prefix,
+							OpType:   "LIST",
+							Thread:   uint32(i),
+							Endpoint: endpoint,
+							Err:      err.Error(),
+							ErrCat:   ErrorClassifier{}.Classify(err),
+							Start:    now,
+							End:      now,
+						}
+					}
+					continue
+				}
 				op := Operation{
 					File:     prefix,
 					OpType:   "LIST",
@@ -233,7 +281,7 @@ func (d *List) Start(ctx context.Context, wait chan struct{}) error {
 					}
 					if err.Err != nil {
 						d.Error(err.Err)
-						op.Err = err.Err.Error()
+						op.SetErr(err.Err)
 					}
 					op.ObjPerOp++
 					if op.FirstByte == nil {
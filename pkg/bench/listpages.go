@@ -0,0 +1,105 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// PageLister lists a single page of objects.
+// It is implemented by minio.Core.
+type PageLister interface {
+	ListObjectsV2(bucketName, objectPrefix, startAfter, continuationToken, delimiter string, maxkeys int) (minio.ListBucketV2Result, error)
+}
+
+// ListPagesOptions configures ListPages.
+type ListPagesOptions struct {
+	// Prefix to list, for example a KeyGenerator shard followed by '/'.
+	Prefix string
+
+	// MaxKeys is the number of keys requested per page.
+	MaxKeys int
+
+	// MaxPages stops listing after this many pages, if > 0.
+	MaxPages int
+
+	// OnPage, if set, is called after each page.
+	OnPage func(p ListPage)
+}
+
+// ListPage describes a single listed page.
+type ListPage struct {
+	Start, End time.Time
+	Keys       int
+}
+
+// ListPagesResult is the result of ListPages.
+type ListPagesResult struct {
+	Keys  int
+	Pages int
+
+	// PageDurations contains the duration of each page request.
+	PageDurations []time.Duration
+
+	// Truncated is set if listing was stopped by MaxPages.
+	Truncated bool
+}
+
+// errNoContinuation is returned if a truncated listing has no continuation token.
+var errNoContinuation = errors.New("list: truncated result without continuation token")
+
+// ListPages lists all objects in bucket with the specified prefix,
+// following continuation tokens until the listing is complete or MaxPages is reached.
+// The context is checked between pages.
+func ListPages(ctx context.Context, client PageLister, bucket string, o ListPagesOptions) (ListPagesResult, error) {
+	var res ListPagesResult
+	token := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return res, err
+		}
+		if o.MaxPages > 0 && res.Pages >= o.MaxPages {
+			res.Truncated = true
+			return res, nil
+		}
+		page := ListPage{Start: time.Now()}
+		lr, err := client.ListObjectsV2(bucket, o.Prefix, "", token, "", o.MaxKeys)
+		page.End = time.Now()
+		if err != nil {
+			return res, err
+		}
+		page.Keys = len(lr.Contents)
+		res.Pages++
+		res.Keys += page.Keys
+		res.PageDurations = append(res.PageDurations, page.End.Sub(page.Start))
+		if o.OnPage != nil {
+			o.OnPage(page)
+		}
+		if !lr.IsTruncated {
+			return res, nil
+		}
+		if lr.NextContinuationToken == "" || lr.NextContinuationToken == token {
+			return res, errNoContinuation
+		}
+		token = lr.NextContinuationToken
+	}
+}
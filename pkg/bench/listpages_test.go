@@ -0,0 +1,134 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/warp/pkg/generator"
+)
+
+// mockPageLister paginates over a sorted list of keys.
+// The continuation token is the index of the next key.
+type mockPageLister struct {
+	keys     []string
+	requests int
+	// noToken returns truncated pages without a continuation token.
+	noToken bool
+}
+
+func (m *mockPageLister) ListObjectsV2(bucketName, objectPrefix, startAfter, continuationToken, delimiter string, maxkeys int) (minio.ListBucketV2Result, error) {
+	m.requests++
+	var keys []string
+	for _, k := range m.keys {
+		if strings.HasPrefix(k, objectPrefix) {
+			keys = append(keys, k)
+		}
+	}
+	start := 0
+	if continuationToken != "" {
+		var err error
+		start, err = strconv.Atoi(continuationToken)
+		if err != nil {
+			return minio.ListBucketV2Result{}, err
+		}
+	}
+	end := min(start+maxkeys, len(keys))
+	var res minio.ListBucketV2Result
+	for _, k := range keys[start:end] {
+		res.Contents = append(res.Contents, minio.ObjectInfo{Key: k})
+	}
+	if end < len(keys) {
+		res.IsTruncated = true
+		if !m.noToken {
+			res.NextContinuationToken = strconv.Itoa(end)
+		}
+	}
+	return res, nil
+}
+
+func TestListPages(t *testing.T) {
+	kg, err := generator.NewKeyGenerator(generator.KeyOptions{Prefix: "bench", Shards: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &mockPageLister{}
+	for range 1000 {
+		client.keys = append(client.keys, kg.Next())
+	}
+	slices.Sort(client.keys)
+
+	tests := []struct {
+		name      string
+		opts      ListPagesOptions
+		wantKeys  int
+		wantPages int
+		truncated bool
+	}{
+		{name: "all", opts: ListPagesOptions{MaxKeys: 100}, wantKeys: 1000, wantPages: 10},
+		{name: "partial-page", opts: ListPagesOptions{MaxKeys: 300}, wantKeys: 1000, wantPages: 4},
+		{name: "shard", opts: ListPagesOptions{Prefix: kg.Shard(1) + "/", MaxKeys: 100}, wantKeys: 250, wantPages: 3},
+		{name: "max-pages", opts: ListPagesOptions{MaxKeys: 100, MaxPages: 3}, wantKeys: 300, wantPages: 3, truncated: true},
+		{name: "empty", opts: ListPagesOptions{Prefix: "missing/", MaxKeys: 100}, wantKeys: 0, wantPages: 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client.requests = 0
+			var onPage int
+			test.opts.OnPage = func(p ListPage) {
+				onPage += p.Keys
+			}
+			res, err := ListPages(context.Background(), client, "bucket", test.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Keys != test.wantKeys || onPage != test.wantKeys {
+				t.Errorf("got %d keys (%d from pages), want %d", res.Keys, onPage, test.wantKeys)
+			}
+			if res.Pages != test.wantPages || client.requests != test.wantPages || len(res.PageDurations) != test.wantPages {
+				t.Errorf("got %d pages, %d requests, want %d", res.Pages, client.requests, test.wantPages)
+			}
+			if res.Truncated != test.truncated {
+				t.Errorf("got truncated %v, want %v", res.Truncated, test.truncated)
+			}
+		})
+	}
+
+	t.Run("no-token", func(t *testing.T) {
+		bad := &mockPageLister{keys: client.keys, noToken: true}
+		_, err := ListPages(context.Background(), bad, "bucket", ListPagesOptions{MaxKeys: 100})
+		if !errors.Is(err, errNoContinuation) {
+			t.Errorf("got error %v, want %v", err, errNoContinuation)
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		client.requests = 0
+		res, err := ListPages(ctx, client, "bucket", ListPagesOptions{MaxKeys: 100, OnPage: func(ListPage) { cancel() }})
+		if !errors.Is(err, context.Canceled) || res.Pages != 1 {
+			t.Errorf("got error %v after %d pages", err, res.Pages)
+		}
+	})
+}
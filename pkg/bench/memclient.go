@@ -0,0 +1,460 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/tags"
+)
+
+// MemClient is an in-memory ObjectClient for tests.
+// Object content is kept in memory, so it should only be used with small objects.
+// It also implements ObjectRemover, so it can be used with BatchDelete,
+// ObjectCopier, ObjectTagger, ObjectSelector and ContentMD5Putter.
+// It is safe for concurrent use.
+type MemClient struct {
+	mu      sync.Mutex
+	buckets map[string]map[string]memObject
+}
+
+type memObject struct {
+	data []byte
+	info minio.ObjectInfo
+}
+
+// NewMemClient returns an in-memory client with the specified buckets.
+func NewMemClient(buckets ...string) *MemClient {
+	m := &MemClient{buckets: make(map[string]map[string]memObject, len(buckets))}
+	for _, b := range buckets {
+		m.MakeBucket(b)
+	}
+	return m
+}
+
+// MakeBucket creates bucket if it does not exist.
+func (m *MemClient) MakeBucket(bucket string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.buckets[bucket] == nil {
+		m.buckets[bucket] = make(map[string]memObject)
+	}
+}
+
+// BucketExists returns whether bucket exists.
+func (m *MemClient) BucketExists(ctx context.Context, bucket string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.buckets[bucket]
+	return ok, nil
+}
+
+// Len returns the number of objects in bucket.
+func (m *MemClient) Len(bucket string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.buckets[bucket])
+}
+
+func memError(status int, code, bucket, object string) error {
+	return minio.ErrorResponse{
+		StatusCode: status,
+		Code:       code,
+		Message:    http.StatusText(status),
+		BucketName: bucket,
+		Key:        object,
+	}
+}
+
+// bucket returns the objects of bucket.
+// m.mu must be held.
+func (m *MemClient) bucket(bucket string) (map[string]memObject, error) {
+	b, ok := m.buckets[bucket]
+	if !ok {
+		return nil, memError(http.StatusNotFound, "NoSuchBucket", bucket, "")
+	}
+	return b, nil
+}
+
+// lookup returns the object.
+// m.mu must be held.
+func (m *MemClient) lookup(bucket, object string) (memObject, error) {
+	b, err := m.bucket(bucket)
+	if err != nil {
+		return memObject{}, err
+	}
+	obj, ok := b[object]
+	if !ok {
+		return memObject{}, memError(http.StatusNotFound, "NoSuchKey", bucket, object)
+	}
+	return obj, nil
+}
+
+// PutObject stores the content of reader.
+// If size is >= 0 the content must have exactly that size.
+// The ETag is the MD5 of the content.
+func (m *MemClient) PutObject(ctx context.Context, bucket, object string, reader io.Reader, size int64, opts minio.PutObjectOptions) (minio.UploadInfo, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return minio.UploadInfo{}, err
+	}
+	if err := ctx.Err(); err != nil {
+		return minio.UploadInfo{}, err
+	}
+	if size >= 0 && int64(len(data)) != size {
+		return minio.UploadInfo{}, memError(http.StatusBadRequest, "IncompleteBody", bucket, object)
+	}
+	sum := md5.Sum(data)
+	info := minio.ObjectInfo{
+		Key:          object,
+		Size:         int64(len(data)),
+		ETag:         hex.EncodeToString(sum[:]),
+		LastModified: time.Now().UTC(),
+		ContentType:  opts.ContentType,
+		UserMetadata: maps.Clone(opts.UserMetadata),
+		UserTags:     maps.Clone(opts.UserTags),
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b, err := m.bucket(bucket)
+	if err != nil {
+		return minio.UploadInfo{}, err
+	}
+	b[object] = memObject{data: data, info: info}
+	return minio.UploadInfo{
+		Bucket:       bucket,
+		Key:          object,
+		ETag:         info.ETag,
+		Size:         info.Size,
+		LastModified: info.LastModified,
+	}, nil
+}
+
+// PutObjectContentMD5 uploads an object like PutObject.
+// A BadDigest error is returned if md5Base64 does not match the content.
+func (m *MemClient) PutObjectContentMD5(ctx context.Context, bucket, object string, reader io.Reader, size int64, md5Base64 string, opts minio.PutObjectOptions) (minio.UploadInfo, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return minio.UploadInfo{}, err
+	}
+	if sum := md5.Sum(data); base64.StdEncoding.EncodeToString(sum[:]) != md5Base64 {
+		return minio.UploadInfo{}, memError(http.StatusBadRequest, "BadDigest", bucket, object)
+	}
+	return m.PutObject(ctx, bucket, object, bytes.NewReader(data), size, opts)
+}
+
+// CopyObject copies the source object to the destination.
+// Metadata and tags are copied with the content.
+func (m *MemClient) CopyObject(ctx context.Context, dst minio.CopyDestOptions, src minio.CopySrcOptions) (minio.UploadInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return minio.UploadInfo{}, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	obj, err := m.lookup(src.Bucket, src.Object)
+	if err != nil {
+		return minio.UploadInfo{}, err
+	}
+	b, err := m.bucket(dst.Bucket)
+	if err != nil {
+		return minio.UploadInfo{}, err
+	}
+	info := obj.info
+	info.Key = dst.Object
+	info.LastModified = time.Now().UTC()
+	b[dst.Object] = memObject{data: obj.data, info: info}
+	return minio.UploadInfo{
+		Bucket:       dst.Bucket,
+		Key:          dst.Object,
+		ETag:         info.ETag,
+		Size:         info.Size,
+		LastModified: info.LastModified,
+	}, nil
+}
+
+// PutObjectTagging replaces the tags of an object.
+func (m *MemClient) PutObjectTagging(ctx context.Context, bucket, object string, otags *tags.Tags, opts minio.PutObjectTaggingOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	obj, err := m.lookup(bucket, object)
+	if err != nil {
+		return err
+	}
+	obj.info.UserTags = otags.ToMap()
+	m.buckets[bucket][object] = obj
+	return nil
+}
+
+// GetObjectTagging returns the tags of an object.
+func (m *MemClient) GetObjectTagging(ctx context.Context, bucket, object string, opts minio.GetObjectTaggingOptions) (*tags.Tags, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	obj, err := m.lookup(bucket, object)
+	m.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return tags.NewTags(obj.info.UserTags, true)
+}
+
+// GetObject returns the content of an object.
+// A single range set with opts.SetRange is supported.
+// If-None-Match set with opts.SetMatchETagExcept returns a 304 error if the ETag matches.
+func (m *MemClient) GetObject(ctx context.Context, bucket, object string, opts minio.GetObjectOptions) (io.ReadCloser, minio.ObjectInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, minio.ObjectInfo{}, err
+	}
+	m.mu.Lock()
+	obj, err := m.lookup(bucket, object)
+	m.mu.Unlock()
+	if err != nil {
+		return nil, minio.ObjectInfo{}, err
+	}
+	if etag := opts.Header().Get("If-None-Match"); etag != "" && strings.Trim(etag, `"`) == obj.info.ETag {
+		return nil, minio.ObjectInfo{}, memError(http.StatusNotModified, "NotModified", bucket, object)
+	}
+	data := obj.data
+	if rng := opts.Header().Get("Range"); rng != "" {
+		start, end, ok := parseMemRange(rng, int64(len(data)))
+		if !ok {
+			return nil, minio.ObjectInfo{}, memError(http.StatusRequestedRangeNotSatisfiable, "InvalidRange", bucket, object)
+		}
+		data = data[start:end]
+	}
+	return io.NopCloser(bytes.NewReader(data)), obj.info, nil
+}
+
+// memSelectQuery matches the queries supported by MemClient.SelectObjectContent.
+var memSelectQuery = regexp.MustCompile(`(?i)^\s*SELECT\s+\*\s+FROM\s+S3Object(?:\s+\w+)?(?:\s+WHERE\s+(?:\w+\.)?(\w+)\s*=\s*(?:'([^']*)'|(-?\d+)))?\s*;?\s*$`)
+
+// SelectObjectContent runs an S3 Select query on a CSV or JSON lines object.
+// Only "SELECT * FROM S3Object s" with an optional "WHERE s.column = value" condition is supported.
+// CSV input must have a header line and FileHeaderInfo set to USE.
+// Records are returned as CSV or JSON lines as set in the output serialization.
+func (m *MemClient) SelectObjectContent(ctx context.Context, bucket, object string, opts minio.SelectObjectOptions) (io.ReadCloser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	obj, err := m.lookup(bucket, object)
+	m.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	q := memSelectQuery.FindStringSubmatch(opts.Expression)
+	if q == nil {
+		return nil, memError(http.StatusBadRequest, "UnsupportedSyntax", bucket, object)
+	}
+	column, value := q[1], q[2]+q[3]
+
+	var columns []string
+	var records [][]string
+	in := opts.InputSerialization
+	switch {
+	case in.CSV != nil && in.CSV.FileHeaderInfo == minio.CSVFileHeaderInfoUse:
+		all, err := csv.NewReader(bytes.NewReader(obj.data)).ReadAll()
+		if err != nil || len(all) == 0 {
+			return nil, memError(http.StatusBadRequest, "InvalidTextEncoding", bucket, object)
+		}
+		columns, records = all[0], all[1:]
+	case in.JSON != nil && in.JSON.Type == minio.JSONLinesType:
+		dec := json.NewDecoder(bytes.NewReader(obj.data))
+		dec.UseNumber()
+		for {
+			var rec map[string]any
+			if err := dec.Decode(&rec); err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, memError(http.StatusBadRequest, "InvalidJsonType", bucket, object)
+			}
+			if columns == nil {
+				columns = slices.Sorted(maps.Keys(rec))
+			}
+			values := make([]string, len(columns))
+			for i, c := range columns {
+				values[i] = fmt.Sprint(rec[c])
+			}
+			records = append(records, values)
+		}
+	default:
+		return nil, memError(http.StatusBadRequest, "UnsupportedSyntax", bucket, object)
+	}
+	where := -1
+	if column != "" {
+		if where = slices.Index(columns, column); where < 0 {
+			return nil, memError(http.StatusBadRequest, "ColumnNotFound", bucket, object)
+		}
+	}
+
+	var out bytes.Buffer
+	cw := csv.NewWriter(&out)
+	for _, rec := range records {
+		if where >= 0 && rec[where] != value {
+			continue
+		}
+		if opts.OutputSerialization.JSON != nil {
+			fields := make(map[string]string, len(columns))
+			for i, c := range columns {
+				fields[c] = rec[i]
+			}
+			b, _ := json.Marshal(fields)
+			out.Write(append(b, '\n'))
+			continue
+		}
+		cw.Write(rec)
+		cw.Flush()
+	}
+	return io.NopCloser(&out), nil
+}
+
+// parseMemRange parses a single "bytes=" range, as set by minio.GetObjectOptions.SetRange.
+// It returns the start and end offset of the range, end exclusive.
+func parseMemRange(rng string, size int64) (start, end int64, ok bool) {
+	spec, found := strings.CutPrefix(rng, "bytes=")
+	if !found {
+		return 0, 0, false
+	}
+	var err error
+	switch {
+	case strings.HasPrefix(spec, "-"):
+		var n int64
+		_, err = fmt.Sscanf(spec, "-%d", &n)
+		start, end = max(size-n, 0), size
+	case strings.HasSuffix(spec, "-"):
+		_, err = fmt.Sscanf(spec, "%d-", &start)
+		end = size
+	default:
+		_, err = fmt.Sscanf(spec, "%d-%d", &start, &end)
+		end = min(end+1, size)
+	}
+	if err != nil || start < 0 || start >= size || end <= start {
+		return 0, 0, false
+	}
+	return start, end, true
+}
+
+// StatObject returns the info of an object.
+func (m *MemClient) StatObject(ctx context.Context, bucket, object string, opts minio.StatObjectOptions) (minio.ObjectInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return minio.ObjectInfo{}, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	obj, err := m.lookup(bucket, object)
+	return obj.info, err
+}
+
+// RemoveObject deletes an object.
+// Like S3, deleting an object that does not exist is not an error.
+func (m *MemClient) RemoveObject(ctx context.Context, bucket, object string, opts minio.RemoveObjectOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b, err := m.bucket(bucket)
+	if err != nil {
+		return err
+	}
+	delete(b, object)
+	return nil
+}
+
+// RemoveObjects deletes all objects sent on objectsCh.
+func (m *MemClient) RemoveObjects(ctx context.Context, bucket string, objectsCh <-chan minio.ObjectInfo, opts minio.RemoveObjectsOptions) <-chan minio.RemoveObjectError {
+	errCh := make(chan minio.RemoveObjectError, cap(objectsCh))
+	go func() {
+		defer close(errCh)
+		for obj := range objectsCh {
+			if err := m.RemoveObject(ctx, bucket, obj.Key, minio.RemoveObjectOptions{}); err != nil {
+				errCh <- minio.RemoveObjectError{ObjectName: obj.Key, Err: err}
+			}
+		}
+	}()
+	return errCh
+}
+
+// ListObjects lists objects in lexical order.
+// Prefix, StartAfter, MaxKeys and Recursive are supported.
+// If Recursive is false, keys with a '/' after the prefix are returned
+// as a single common prefix entry.
+func (m *MemClient) ListObjects(ctx context.Context, bucket string, opts minio.ListObjectsOptions) <-chan minio.ObjectInfo {
+	ch := make(chan minio.ObjectInfo, 1)
+	m.mu.Lock()
+	b, err := m.bucket(bucket)
+	var entries []minio.ObjectInfo
+	if err == nil {
+		prefixes := make(map[string]struct{})
+		for _, key := range slices.Sorted(maps.Keys(b)) {
+			if !strings.HasPrefix(key, opts.Prefix) || key <= opts.StartAfter {
+				continue
+			}
+			if !opts.Recursive {
+				if i := strings.IndexByte(key[len(opts.Prefix):], '/'); i >= 0 {
+					p := key[:len(opts.Prefix)+i+1]
+					if _, ok := prefixes[p]; !ok {
+						prefixes[p] = struct{}{}
+						entries = append(entries, minio.ObjectInfo{Key: p})
+					}
+					continue
+				}
+			}
+			entries = append(entries, b[key].info)
+		}
+	}
+	m.mu.Unlock()
+	if opts.MaxKeys > 0 && len(entries) > opts.MaxKeys {
+		entries = entries[:opts.MaxKeys]
+	}
+	go func() {
+		defer close(ch)
+		if err != nil {
+			ch <- minio.ObjectInfo{Err: err}
+			return
+		}
+		for _, e := range entries {
+			select {
+			case ch <- e:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
@@ -0,0 +1,190 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/warp/pkg/generator"
+)
+
+// Both implementations must satisfy the interfaces used by benchmarks.
+var (
+	_ ObjectClient     = (*MemClient)(nil)
+	_ ObjectRemover    = (*MemClient)(nil)
+	_ ObjectCopier     = (*MemClient)(nil)
+	_ ObjectCopier     = (*minio.Client)(nil)
+	_ ObjectTagger     = (*MemClient)(nil)
+	_ ObjectTagger     = (*minio.Client)(nil)
+	_ ObjectSelector   = (*MemClient)(nil)
+	_ ContentMD5Putter = (*MemClient)(nil)
+	_ ContentMD5Putter = minioObjectClient{}
+	_ BucketMaker      = (*minio.Client)(nil)
+	_ ObjectClient     = NewObjectClient(nil)
+)
+
+func TestMemClient(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemClient("bucket")
+	body := []byte("hello, warp benchmark")
+	opts := minio.PutObjectOptions{ContentType: "text/plain", UserMetadata: map[string]string{"k": "v"}}
+	info, err := c.PutObject(ctx, "bucket", "dir/obj", bytes.NewReader(body), int64(len(body)), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size != int64(len(body)) || CheckETag(info.ETag, md5Sum(body), nil) != ETagMatch {
+		t.Errorf("unexpected upload info %+v", info)
+	}
+
+	// PUT then GET returns the same bytes.
+	r, oi, err := c.GetObject(ctx, "bucket", "dir/obj", minio.GetObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := io.ReadAll(r)
+	r.Close()
+	if !bytes.Equal(got, body) {
+		t.Errorf("got body %q, want %q", got, body)
+	}
+	if oi.ContentType != "text/plain" || oi.UserMetadata["k"] != "v" || oi.ETag != info.ETag {
+		t.Errorf("unexpected object info %+v", oi)
+	}
+
+	// Ranges.
+	for rng, want := range map[[2]int64]string{{0, 4}: "hello", {7, 10}: "warp", {0, -5}: "hmark"} {
+		var opts minio.GetObjectOptions
+		if err := opts.SetRange(rng[0], rng[1]); err != nil {
+			t.Fatal(err)
+		}
+		r, _, err := c.GetObject(ctx, "bucket", "dir/obj", opts)
+		if err != nil {
+			t.Fatalf("range %v: %v", rng, err)
+		}
+		got, _ := io.ReadAll(r)
+		if string(got) != want {
+			t.Errorf("range %v: got %q, want %q", rng, got, want)
+		}
+	}
+	var badRange minio.GetObjectOptions
+	badRange.SetRange(100, 200)
+	if _, _, err := c.GetObject(ctx, "bucket", "dir/obj", badRange); minio.ToErrorResponse(err).Code != "InvalidRange" {
+		t.Errorf("out of range: got %v", err)
+	}
+
+	// Stat.
+	st, err := c.StatObject(ctx, "bucket", "dir/obj", minio.StatObjectOptions{})
+	if err != nil || st.Size != int64(len(body)) {
+		t.Errorf("stat: %+v, %v", st, err)
+	}
+
+	// Errors are S3 responses.
+	var cl ErrorClassifier
+	_, err = c.StatObject(ctx, "bucket", "missing", minio.StatObjectOptions{})
+	if resp := minio.ToErrorResponse(err); resp.Code != "NoSuchKey" || resp.StatusCode != http.StatusNotFound || cl.Classify(err) != ErrCatClient {
+		t.Errorf("missing object: %v", err)
+	}
+	if _, err := c.PutObject(ctx, "nobucket", "obj", bytes.NewReader(body), -1, minio.PutObjectOptions{}); minio.ToErrorResponse(err).Code != "NoSuchBucket" {
+		t.Errorf("missing bucket: %v", err)
+	}
+	if _, err := c.PutObject(ctx, "bucket", "short", bytes.NewReader(body), 100, minio.PutObjectOptions{}); err == nil {
+		t.Error("expected error for short body")
+	}
+
+	// List.
+	for _, key := range []string{"a", "dir/obj2", "dir/sub/obj", "z"} {
+		if _, err := c.PutObject(ctx, "bucket", key, bytes.NewReader(nil), 0, minio.PutObjectOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	list := func(opts minio.ListObjectsOptions) []string {
+		var keys []string
+		for obj := range c.ListObjects(ctx, "bucket", opts) {
+			if obj.Err != nil {
+				t.Fatal(obj.Err)
+			}
+			keys = append(keys, obj.Key)
+		}
+		return keys
+	}
+	tests := []struct {
+		opts minio.ListObjectsOptions
+		want []string
+	}{
+		{opts: minio.ListObjectsOptions{Recursive: true}, want: []string{"a", "dir/obj", "dir/obj2", "dir/sub/obj", "z"}},
+		{opts: minio.ListObjectsOptions{}, want: []string{"a", "dir/", "z"}},
+		{opts: minio.ListObjectsOptions{Prefix: "dir/"}, want: []string{"dir/obj", "dir/obj2", "dir/sub/"}},
+		{opts: minio.ListObjectsOptions{Recursive: true, StartAfter: "dir/obj2", MaxKeys: 1}, want: []string{"dir/sub/obj"}},
+	}
+	for _, test := range tests {
+		if got := list(test.opts); !slices.Equal(got, test.want) {
+			t.Errorf("list %+v: got %v, want %v", test.opts, got, test.want)
+		}
+	}
+	for obj := range c.ListObjects(ctx, "nobucket", minio.ListObjectsOptions{}) {
+		if minio.ToErrorResponse(obj.Err).Code != "NoSuchBucket" {
+			t.Errorf("list missing bucket: %v", obj.Err)
+		}
+	}
+
+	// Delete.
+	if err := c.RemoveObject(ctx, "bucket", "dir/obj", minio.RemoveObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := c.GetObject(ctx, "bucket", "dir/obj", minio.GetObjectOptions{}); minio.ToErrorResponse(err).Code != "NoSuchKey" {
+		t.Errorf("get deleted object: %v", err)
+	}
+	if err := c.RemoveObject(ctx, "bucket", "dir/obj", minio.RemoveObjectOptions{}); err != nil {
+		t.Errorf("deleting missing object: %v", err)
+	}
+}
+
+func TestMemClientPopulate(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemClient("bucket")
+	keys, err := generator.NewKeyGenerator(generator.KeyOptions{Prefix: "mem", Shards: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := Populate(ctx, c, PopulateOptions{Bucket: "bucket", Count: 50, Concurrency: 4, Size: 1000, Keys: keys})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Len("bucket") != 50 || res.Objects.Len() != 50 {
+		t.Fatalf("got %d objects stored, %d tracked, want 50", c.Len("bucket"), res.Objects.Len())
+	}
+	for _, obj := range res.Objects.Objects() {
+		r, info, err := c.GetObject(ctx, "bucket", obj.Name, minio.GetObjectOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		n, _ := io.Copy(io.Discard, r)
+		if n != obj.Size || info.Size != obj.Size {
+			t.Errorf("%s: got %d bytes, want %d", obj.Name, n, obj.Size)
+		}
+	}
+	del := BatchDelete(ctx, c, "bucket", res.Objects, 20)
+	if del.Deleted != 20 || len(del.Failed) != 0 || c.Len("bucket") != 30 {
+		t.Errorf("batch delete: deleted %d, failed %d, %d left", del.Deleted, len(del.Failed), c.Len("bucket"))
+	}
+}
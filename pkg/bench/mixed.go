@@ -22,8 +22,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"math/rand"
 	"net/http"
+	"slices"
 	"sync"
 	"time"
 
@@ -39,6 +41,43 @@ type Mixed struct {
 	GetOpts       minio.GetObjectOptions
 	StatOpts      minio.StatObjectOptions
 	CreateObjects int
+
+	// OpConcurrency limits the number of concurrent operations of each type.
+	// Types not in the map, or with a limit <= 0, are only limited by Concurrency.
+	OpConcurrency map[string]int
+
+	// Tags generates the tags of uploaded objects and of TAG-PUT operations.
+	// It must be set if the distribution contains tagging operations.
+	Tags *generator.TagGenerator
+}
+
+// opLimits holds a semaphore for each operation type with a concurrency limit.
+type opLimits map[string]chan struct{}
+
+// newOpLimits returns semaphores for the limits below concurrency.
+func newOpLimits(limits map[string]int, concurrency int) opLimits {
+	l := make(opLimits)
+	for op, n := range limits {
+		if n > 0 && n < concurrency {
+			l[op] = make(chan struct{}, n)
+		}
+	}
+	return l
+}
+
+// acquire waits until an operation of type op may start.
+// The returned function must be called when the operation is done.
+func (l opLimits) acquire(ctx context.Context, op string) (release func(), err error) {
+	sem, ok := l[op]
+	if !ok {
+		return func() {}, nil
+	}
+	select {
+	case sem <- struct{}{}:
+		return func() { <-sem }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
 }
 
 // MixedDistribution keeps track of operation distribution
@@ -46,8 +85,11 @@ type Mixed struct {
 type MixedDistribution struct {
 	// Operation -> distribution.
 	Distribution map[string]float64
-	objects      map[string]generator.Object
-	rng          *rand.Rand
+	// Seed for the operation order.
+	// If 0 a fixed default seed is used.
+	Seed    int64
+	objects map[string]generator.Object
+	rng     *rand.Rand
 
 	ops []string
 
@@ -68,13 +110,18 @@ func (m *MixedDistribution) Generate(allocObjs int) error {
 
 	const genOps = 1000
 	m.ops = make([]string, 0, genOps)
-	for op, dist := range m.Distribution {
-		add := int(0.5 + dist*genOps)
+	// Iterate in sorted order, so the seed gives the same order every time.
+	for _, op := range slices.Sorted(maps.Keys(m.Distribution)) {
+		add := int(0.5 + m.Distribution[op]*genOps)
 		for range add {
 			m.ops = append(m.ops, op)
 		}
 	}
-	m.rng = rand.New(rand.NewSource(0xabad1dea))
+	seed := m.Seed
+	if seed == 0 {
+		seed = 0xabad1dea
+	}
+	m.rng = rand.New(rand.NewSource(seed))
 	m.rng.Shuffle(len(m.ops), func(i, j int) {
 		m.ops[i], m.ops[j] = m.ops[j], m.ops[i]
 	})
@@ -154,6 +201,9 @@ func (g *Mixed) Prepare(ctx context.Context) error {
 	if g.CreateObjects <= g.Concurrency {
 		return errors.New("initial number of objects should be at least matching concurrency")
 	}
+	if g.Tags == nil && (g.Dist.Distribution[OpTagPut] > 0 || g.Dist.Distribution[OpTagGet] > 0) {
+		return errors.New("tagging operations require tags to be configured")
+	}
 	if err := g.createEmptyBucket(ctx); err != nil {
 		return err
 	}
@@ -187,6 +237,9 @@ func (g *Mixed) Prepare(ctx context.Context) error {
 				obj := src.Object()
 				client, clDone := g.Client()
 				opts.ContentType = obj.ContentType
+				if g.Tags != nil {
+					opts.UserTags = g.Tags.Next()
+				}
 				res, err := client.PutObject(ctx, g.Bucket, obj.Name, obj.Reader, obj.Size, opts)
 				if err != nil {
 					err := fmt.Errorf("upload error: %w", err)
@@ -231,9 +284,11 @@ func (g *Mixed) Start(ctx context.Context, wait chan struct{}) error {
 	}
 	// Non-terminating context.
 	nonTerm := context.Background()
+	limits := newOpLimits(g.OpConcurrency, g.Concurrency)
 
 	for i := 0; i < g.Concurrency; i++ {
 		go func(i int) {
+			getClient := g.workerClient(i)
 			rcv := c.Receiver()
 			defer wg.Done()
 			done := ctx.Done()
@@ -243,6 +298,9 @@ func (g *Mixed) Start(ctx context.Context, wait chan struct{}) error {
 			getOpts := g.GetOpts
 
 			<-wait
+			if g.RampUp.Wait(ctx, i) != nil {
+				return
+			}
 			for {
 				select {
 				case <-done:
@@ -250,16 +308,23 @@ func (g *Mixed) Start(ctx context.Context, wait chan struct{}) error {
 				default:
 				}
 
+				if g.ThinkTime.Wait(ctx) != nil {
+					return
+				}
 				if g.rpsLimit(ctx) != nil {
 					return
 				}
 
 				operation := g.Dist.getOp()
+				release, err := limits.acquire(ctx, operation)
+				if err != nil {
+					return
+				}
 				switch operation {
 				case http.MethodGet:
 					fbr := firstByteRecorder{}
 					obj, objDone := g.Dist.randomObj()
-					client, clDone := g.Client()
+					client, clDone := getClient()
 					op := Operation{
 						OpType:   operation,
 						Thread:   uint32(i),
@@ -276,17 +341,18 @@ func (g *Mixed) Start(ctx context.Context, wait chan struct{}) error {
 					fbr.r = o
 					if err != nil {
 						g.Error("download error:", err)
-						op.Err = err.Error()
+						op.SetErr(err)
 						op.End = time.Now()
 						rcv <- op
 						clDone()
 						objDone()
+						release()
 						continue
 					}
 					n, err := io.Copy(io.Discard, &fbr)
 					if err != nil {
 						g.Error("download error:", err)
-						op.Err = err.Error()
+						op.SetErr(err)
 					}
 					op.FirstByte = fbr.t
 					op.End = time.Now()
@@ -302,7 +368,10 @@ func (g *Mixed) Start(ctx context.Context, wait chan struct{}) error {
 				case http.MethodPut:
 					obj := src.Object()
 					putOpts.ContentType = obj.ContentType
-					client, clDone := g.Client()
+					if g.Tags != nil {
+						putOpts.UserTags = g.Tags.Next()
+					}
+					client, clDone := getClient()
 					op := Operation{
 						OpType:   operation,
 						Thread:   uint32(i),
@@ -316,7 +385,7 @@ func (g *Mixed) Start(ctx context.Context, wait chan struct{}) error {
 					op.End = time.Now()
 					if err != nil {
 						g.Error("upload error:", err)
-						op.Err = err.Error()
+						op.SetErr(err)
 					}
 					obj.VersionID = res.VersionID
 
@@ -333,7 +402,7 @@ func (g *Mixed) Start(ctx context.Context, wait chan struct{}) error {
 					}
 					rcv <- op
 				case http.MethodDelete:
-					client, clDone := g.Client()
+					client, clDone := getClient()
 					obj := g.Dist.deleteRandomObj()
 					op := Operation{
 						OpType:   operation,
@@ -350,12 +419,12 @@ func (g *Mixed) Start(ctx context.Context, wait chan struct{}) error {
 					clDone()
 					if err != nil {
 						g.Error("delete error: ", err)
-						op.Err = err.Error()
+						op.SetErr(err)
 					}
 					rcv <- op
 				case "STAT":
 					obj, objDone := g.Dist.randomObj()
-					client, clDone := g.Client()
+					client, clDone := getClient()
 					op := Operation{
 						OpType:   operation,
 						Thread:   uint32(i),
@@ -369,7 +438,7 @@ func (g *Mixed) Start(ctx context.Context, wait chan struct{}) error {
 					objI, err := client.StatObject(nonTerm, g.Bucket, obj.Name, statOpts)
 					if err != nil {
 						g.Error("stat error: ", err)
-						op.Err = err.Error()
+						op.SetErr(err)
 					}
 					op.End = time.Now()
 					if objI.Size != obj.Size && op.Err == "" {
@@ -379,9 +448,32 @@ func (g *Mixed) Start(ctx context.Context, wait chan struct{}) error {
 					rcv <- op
 					objDone()
 					clDone()
+				case OpTagPut, OpTagGet:
+					obj, objDone := g.Dist.randomObj()
+					client, clDone := getClient()
+					op := Operation{
+						OpType:   operation,
+						Thread:   uint32(i),
+						Size:     0,
+						File:     obj.Name,
+						ObjPerOp: 1,
+						Endpoint: client.EndpointURL().String(),
+					}
+					if operation == OpTagPut {
+						putObjectTags(nonTerm, client, g.Bucket, obj.Name, obj.VersionID, g.Tags.Next(), &op)
+					} else {
+						getObjectTags(nonTerm, client, g.Bucket, obj.Name, obj.VersionID, g.Tags.Len(), &op)
+					}
+					if op.Err != "" {
+						g.Error("tagging error: ", op.Err)
+					}
+					rcv <- op
+					objDone()
+					clDone()
 				default:
 					g.Error("unknown operation: ", operation)
 				}
+				release()
 			}
 		}(i)
 	}
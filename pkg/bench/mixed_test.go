@@ -0,0 +1,197 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/warp/pkg/generator"
+)
+
+func TestMixedDistribution(t *testing.T) {
+	newDist := func(seed int64) *MixedDistribution {
+		m := &MixedDistribution{
+			Distribution: map[string]float64{
+				http.MethodGet:    80,
+				http.MethodPut:    15,
+				http.MethodDelete: 5,
+			},
+			Seed: seed,
+		}
+		if err := m.Generate(10); err != nil {
+			t.Fatal(err)
+		}
+		return m
+	}
+	m := newDist(1)
+	const n = 100_000
+	counts := make(map[string]int)
+	seq := make([]string, 0, 100)
+	for i := 0; i < n; i++ {
+		op := m.getOp()
+		counts[op]++
+		if len(seq) < cap(seq) {
+			seq = append(seq, op)
+		}
+	}
+	want := map[string]float64{http.MethodGet: 0.8, http.MethodPut: 0.15, http.MethodDelete: 0.05}
+	for op, share := range want {
+		got := float64(counts[op]) / n
+		if math.Abs(got-share) > 0.01 {
+			t.Errorf("%s: share %.3f, want %.3f", op, got, share)
+		}
+	}
+
+	// Same seed gives the same sequence.
+	m2 := newDist(1)
+	for i, op := range seq {
+		if got := m2.getOp(); got != op {
+			t.Fatalf("op %d: got %s, want %s", i, got, op)
+		}
+	}
+	m3 := newDist(2)
+	seq3 := make([]string, len(seq))
+	for i := range seq3 {
+		seq3[i] = m3.getOp()
+	}
+	if slices.Equal(seq, seq3) {
+		t.Error("different seeds gave the same sequence")
+	}
+
+	bad := &MixedDistribution{Distribution: map[string]float64{http.MethodPut: 1, http.MethodDelete: 2}}
+	if err := bad.Generate(10); err == nil {
+		t.Error("expected error when DELETE > PUT")
+	}
+	zero := &MixedDistribution{Distribution: map[string]float64{http.MethodGet: 0}}
+	if err := zero.Generate(10); err == nil {
+		t.Error("expected error for zero total distribution")
+	}
+}
+
+func TestMixedOpConcurrency(t *testing.T) {
+	const size = 1 << 10
+	var mu sync.Mutex
+	inFlight := make(map[string]int)
+	maxInFlight := make(map[string]int)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		mu.Lock()
+		inFlight[r.Method]++
+		maxInFlight[r.Method] = max(maxInFlight[r.Method], inFlight[r.Method])
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inFlight[r.Method]--
+			mu.Unlock()
+		}()
+		time.Sleep(2 * time.Millisecond)
+		w.Header().Set("ETag", `"d41d8cd98f00b204e9800998ecf8427e"`)
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+			w.Header().Set("Content-Length", fmt.Sprint(size))
+			w.WriteHeader(http.StatusOK)
+			if r.Method == http.MethodGet {
+				w.Write(make([]byte, size))
+			}
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer srv.Close()
+	cl, err := minio.New(strings.TrimPrefix(srv.URL, "http://"), &minio.Options{
+		Creds:        credentials.NewStaticV4("access", "secret", ""),
+		Region:       "us-east-1",
+		BucketLookup: minio.BucketLookupPath,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := generator.NewFn(generator.WithRandomData().Apply(), generator.WithSize(size))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dist := &MixedDistribution{
+		Distribution: map[string]float64{
+			http.MethodGet:    40,
+			"STAT":            20,
+			http.MethodPut:    20,
+			http.MethodDelete: 20,
+		},
+	}
+	if err := dist.Generate(1000); err != nil {
+		t.Fatal(err)
+	}
+	for range 500 {
+		dist.addObj(*src().Object())
+	}
+	limits := map[string]int{http.MethodDelete: 2, http.MethodGet: 4, "STAT": 0}
+	b := &Mixed{
+		Common: Common{
+			Source:      src,
+			Bucket:      "bucket",
+			Concurrency: 16,
+			Client:      func() (*minio.Client, func()) { return cl, func() {} },
+			Error:       func(data ...any) { t.Log(data...) },
+		},
+		Dist:          dist,
+		OpConcurrency: limits,
+	}
+	ops, err := RunFor(context.Background(), b, 300*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := make(map[string]int)
+	for _, op := range ops {
+		if op.Err != "" {
+			t.Fatalf("%s: %s", op.OpType, op.Err)
+		}
+		counts[op.OpType]++
+	}
+	for _, op := range []string{http.MethodGet, "STAT", http.MethodPut, http.MethodDelete} {
+		if counts[op] == 0 {
+			t.Errorf("no %s operations", op)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if got := maxInFlight[http.MethodDelete]; got > limits[http.MethodDelete] {
+		t.Errorf("%d concurrent DELETE, limit %d", got, limits[http.MethodDelete])
+	}
+	if got := maxInFlight[http.MethodGet]; got > limits[http.MethodGet] {
+		t.Errorf("%d concurrent GET, limit %d", got, limits[http.MethodGet])
+	}
+	// Unlimited types are bounded by the global concurrency only.
+	if got := maxInFlight[http.MethodHead] + maxInFlight[http.MethodPut]; got <= limits[http.MethodGet] {
+		t.Errorf("only %d concurrent unlimited operations", got)
+	}
+}
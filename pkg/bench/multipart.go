@@ -200,6 +200,7 @@ func (g *Multipart) Start(ctx context.Context, wait chan struct{}) error {
 
 	for i := 0; i < g.Concurrency; i++ {
 		go func(i int) {
+			getClient := g.workerClient(i)
 			rng := rand.New(rand.NewSource(int64(i)))
 			rcv := c.Receiver()
 			defer wg.Done()
@@ -207,6 +208,9 @@ func (g *Multipart) Start(ctx context.Context, wait chan struct{}) error {
 			done := ctx.Done()
 
 			<-wait
+			if g.RampUp.Wait(ctx, i) != nil {
+				return
+			}
 			for {
 				select {
 				case <-done:
@@ -214,6 +218,9 @@ func (g *Multipart) Start(ctx context.Context, wait chan struct{}) error {
 				default:
 				}
 
+				if g.ThinkTime.Wait(ctx) != nil {
+					return
+				}
 				if g.rpsLimit(ctx) != nil {
 					return
 				}
@@ -222,7 +229,7 @@ func (g *Multipart) Start(ctx context.Context, wait chan struct{}) error {
 				part := rng.Intn(len(g.objects))
 				obj := g.objects[part]
 				part += g.PartStart
-				client, cldone := g.Client()
+				client, cldone := getClient()
 				op := Operation{
 					OpType:   http.MethodGet,
 					Thread:   uint32(i),
@@ -237,7 +244,7 @@ func (g *Multipart) Start(ctx context.Context, wait chan struct{}) error {
 				o, err := client.GetObject(nonTerm, g.Bucket, obj.Name, opts)
 				if err != nil {
 					g.Error("download error:", err)
-					op.Err = err.Error()
+					op.SetErr(err)
 					op.End = time.Now()
 					rcv <- op
 					cldone()
@@ -247,7 +254,7 @@ func (g *Multipart) Start(ctx context.Context, wait chan struct{}) error {
 				n, err := io.Copy(io.Discard, &fbr)
 				if err != nil {
 					g.Error("download error:", err)
-					op.Err = err.Error()
+					op.SetErr(err)
 				}
 				op.FirstByte = fbr.t
 				op.End = time.Now()
@@ -2,7 +2,6 @@ package bench
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -35,14 +34,18 @@ func (g *MultipartPut) Start(ctx context.Context, wait chan struct{}) error {
 
 	for i := 0; i < g.Concurrency; i++ {
 		thread := uint32(i)
+		getClient := g.workerClient(i)
 		eg.Go(func() error {
 			<-wait
+			if g.RampUp.Wait(ctx, i) != nil {
+				return nil
+			}
 
 			for ctx.Err() == nil {
 				objectName := g.Source().Object().Name
 
-				uploadID, err := g.createMultupartUpload(ctx, objectName)
-				if errors.Is(err, context.Canceled) {
+				uploadID, err := g.createMultupartUpload(ctx, getClient, objectName)
+				if err != nil && ctx.Err() != nil {
 					return nil
 				}
 				if err != nil {
@@ -50,8 +53,8 @@ func (g *MultipartPut) Start(ctx context.Context, wait chan struct{}) error {
 					continue
 				}
 
-				parts, err := g.uploadParts(ctx, thread, objectName, uploadID)
-				if errors.Is(err, context.Canceled) {
+				parts, err := g.uploadParts(ctx, getClient, thread, objectName, uploadID)
+				if err != nil && ctx.Err() != nil {
 					return nil
 				}
 				if err != nil {
@@ -59,7 +62,7 @@ func (g *MultipartPut) Start(ctx context.Context, wait chan struct{}) error {
 					continue
 				}
 
-				err = g.completeMultipartUpload(ctx, objectName, uploadID, parts)
+				err = g.completeMultipartUpload(ctx, getClient, objectName, uploadID, parts)
 				if err != nil {
 					g.Error("complete multipart upload")
 				}
@@ -75,7 +78,7 @@ func (g *MultipartPut) Cleanup(ctx context.Context) {
 	g.deleteAllInBucket(ctx, "")
 }
 
-func (g *MultipartPut) createMultupartUpload(ctx context.Context, objectName string) (string, error) {
+func (g *MultipartPut) createMultupartUpload(ctx context.Context, getClient func() (*minio.Client, func()), objectName string) (string, error) {
 	if err := g.rpsLimit(ctx); err != nil {
 		return "", err
 	}
@@ -83,13 +86,13 @@ func (g *MultipartPut) createMultupartUpload(ctx context.Context, objectName str
 	// Non-terminating context.
 	nonTerm := context.Background()
 
-	client, done := g.Client()
+	client, done := getClient()
 	defer done()
 	c := minio.Core{Client: client}
 	return c.NewMultipartUpload(nonTerm, g.Bucket, objectName, g.PutOpts)
 }
 
-func (g *MultipartPut) uploadParts(ctx context.Context, thread uint32, objectName, uploadID string) ([]minio.CompletePart, error) {
+func (g *MultipartPut) uploadParts(ctx context.Context, getClient func() (*minio.Client, func()), thread uint32, objectName, uploadID string) ([]minio.CompletePart, error) {
 	partIdxCh := make(chan int, g.PartsNumber)
 	for i := 0; i < g.PartsNumber; i++ {
 		partIdxCh <- i + 1
@@ -118,12 +121,15 @@ func (g *MultipartPut) uploadParts(ctx context.Context, thread uint32, objectNam
 					continue
 				}
 
+				if err := g.ThinkTime.Wait(ctx); err != nil {
+					return err
+				}
 				if err := g.rpsLimit(ctx); err != nil {
 					return err
 				}
 
 				obj := g.Source().Object()
-				client, done := g.Client()
+				client, done := getClient()
 				defer done()
 				core := minio.Core{Client: client}
 				op := Operation{
@@ -173,7 +179,7 @@ func (g *MultipartPut) uploadParts(ctx context.Context, thread uint32, objectNam
 	return parts, err
 }
 
-func (g *MultipartPut) completeMultipartUpload(ctx context.Context, objectName, uploadID string, parts []minio.CompletePart) error {
+func (g *MultipartPut) completeMultipartUpload(ctx context.Context, getClient func() (*minio.Client, func()), objectName, uploadID string, parts []minio.CompletePart) error {
 	select {
 	case <-ctx.Done():
 		return nil
@@ -183,7 +189,7 @@ func (g *MultipartPut) completeMultipartUpload(ctx context.Context, objectName,
 	// Non-terminating context.
 	nonTerm := context.Background()
 
-	cl, done := g.Client()
+	cl, done := getClient()
 	c := minio.Core{Client: cl}
 	defer done()
 	_, err := c.CompleteMultipartUpload(nonTerm, g.Bucket, objectName, uploadID, parts, g.PutOpts)
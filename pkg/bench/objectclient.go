@@ -0,0 +1,94 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"io"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// ObjectClient is the set of object operations used by benchmarks.
+// Use NewObjectClient to adapt a *minio.Client, or MemClient for tests.
+// Errors from the server are returned as minio.ErrorResponse,
+// so they can be inspected with minio.ToErrorResponse and ErrorClassifier.
+type ObjectClient interface {
+	ObjectPutter
+
+	// GetObject returns the content and info of an object.
+	// The caller must close the returned reader.
+	GetObject(ctx context.Context, bucket, object string, opts minio.GetObjectOptions) (io.ReadCloser, minio.ObjectInfo, error)
+
+	// StatObject returns the info of an object.
+	StatObject(ctx context.Context, bucket, object string, opts minio.StatObjectOptions) (minio.ObjectInfo, error)
+
+	// RemoveObject deletes an object.
+	RemoveObject(ctx context.Context, bucket, object string, opts minio.RemoveObjectOptions) error
+
+	// ListObjects lists objects in bucket.
+	// Errors are returned as an ObjectInfo with Err set, after which the channel is closed.
+	ListObjects(ctx context.Context, bucket string, opts minio.ListObjectsOptions) <-chan minio.ObjectInfo
+}
+
+// ContentMD5Putter is implemented by clients that can upload content
+// with a precomputed Content-MD5 header.
+type ContentMD5Putter interface {
+	// PutObjectContentMD5 uploads the content in a single request with Content-MD5 set to md5Base64.
+	PutObjectContentMD5(ctx context.Context, bucket, object string, reader io.Reader, size int64, md5Base64 string, opts minio.PutObjectOptions) (minio.UploadInfo, error)
+}
+
+// contentMD5Putter is an ObjectPutter that sends a precomputed Content-MD5.
+type contentMD5Putter struct {
+	c         ContentMD5Putter
+	md5Base64 string
+}
+
+// PutObject uploads the content with the Content-MD5 header set.
+func (p contentMD5Putter) PutObject(ctx context.Context, bucket, object string, reader io.Reader, size int64, opts minio.PutObjectOptions) (minio.UploadInfo, error) {
+	return p.c.PutObjectContentMD5(ctx, bucket, object, reader, size, p.md5Base64, opts)
+}
+
+// NewObjectClient returns an ObjectClient that sends requests using c.
+func NewObjectClient(c *minio.Client) ObjectClient {
+	return minioObjectClient{Client: c}
+}
+
+// minioObjectClient adapts *minio.Client to ObjectClient.
+type minioObjectClient struct {
+	*minio.Client
+}
+
+// GetObject requests the object and waits for the response headers.
+func (m minioObjectClient) GetObject(ctx context.Context, bucket, object string, opts minio.GetObjectOptions) (io.ReadCloser, minio.ObjectInfo, error) {
+	obj, err := m.Client.GetObject(ctx, bucket, object, opts)
+	if err != nil {
+		return nil, minio.ObjectInfo{}, err
+	}
+	info, err := obj.Stat()
+	if err != nil {
+		obj.Close()
+		return nil, minio.ObjectInfo{}, err
+	}
+	return obj, info, nil
+}
+
+// PutObjectContentMD5 uploads the content in a single PUT with the Content-MD5 header set.
+func (m minioObjectClient) PutObjectContentMD5(ctx context.Context, bucket, object string, reader io.Reader, size int64, md5Base64 string, opts minio.PutObjectOptions) (minio.UploadInfo, error) {
+	return minio.Core{Client: m.Client}.PutObject(ctx, bucket, object, reader, size, md5Base64, "", opts)
+}
@@ -0,0 +1,148 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"math/rand"
+	"os"
+	"sync"
+
+	"github.com/minio/warp/pkg/generator"
+)
+
+// ObjectSet is a set of objects known to exist, keyed by name.
+// It is safe for concurrent use.
+// The set can be persisted with Save or Journal and restored with LoadObjectSet.
+type ObjectSet struct {
+	mu      sync.Mutex
+	objects generator.Objects
+	idx     map[string]int
+
+	journal    *os.File
+	journalErr error
+}
+
+// NewObjectSet returns an empty object set.
+func NewObjectSet() *ObjectSet {
+	return &ObjectSet{idx: make(map[string]int)}
+}
+
+// Add adds obj to the set, replacing any object with the same name.
+func (s *ObjectSet) Add(obj generator.Object) {
+	// Readers are not retained.
+	obj.Reader = nil
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.writeJournal(newObjectRecord(obj))
+	if i, ok := s.idx[obj.Name]; ok {
+		s.objects[i] = obj
+		return
+	}
+	s.idx[obj.Name] = len(s.objects)
+	s.objects = append(s.objects, obj)
+}
+
+// Remove removes the object with the specified name.
+// Returns whether the object was in the set.
+func (s *ObjectSet) Remove(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, ok := s.idx[name]
+	if !ok {
+		return false
+	}
+	s.writeJournal(objectRecord{Del: true, Name: name})
+	last := len(s.objects) - 1
+	if i != last {
+		s.objects[i] = s.objects[last]
+		s.idx[s.objects[i].Name] = i
+	}
+	s.objects = s.objects[:last]
+	delete(s.idx, name)
+	return true
+}
+
+// Take removes up to n objects from the set and returns them.
+func (s *ObjectSet) Take(n int) generator.Objects {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n = min(max(n, 0), len(s.objects))
+	taken := append(generator.Objects(nil), s.objects[len(s.objects)-n:]...)
+	s.objects = s.objects[:len(s.objects)-n]
+	for _, obj := range taken {
+		delete(s.idx, obj.Name)
+		s.writeJournal(objectRecord{Del: true, Name: obj.Name})
+	}
+	return taken
+}
+
+// Get returns the object with the specified name.
+func (s *ObjectSet) Get(name string) (generator.Object, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, ok := s.idx[name]
+	if !ok {
+		return generator.Object{}, false
+	}
+	return s.objects[i], true
+}
+
+// Contains returns whether an object with the specified name is in the set.
+func (s *ObjectSet) Contains(name string) bool {
+	_, ok := s.Get(name)
+	return ok
+}
+
+// Random returns a random object from the set.
+// Returns false if the set is empty.
+func (s *ObjectSet) Random(rng *rand.Rand) (generator.Object, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.objects) == 0 {
+		return generator.Object{}, false
+	}
+	return s.objects[rng.Intn(len(s.objects))], true
+}
+
+// Len returns the number of objects in the set.
+func (s *ObjectSet) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.objects)
+}
+
+// Objects returns a copy of the objects in the set.
+// The order is not specified.
+func (s *ObjectSet) Objects() generator.Objects {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append(generator.Objects(nil), s.objects...)
+}
+
+// Locked returns a copy of the objects in the set that have Object Lock retention or a legal hold.
+func (s *ObjectSet) Locked() generator.Objects {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var locked generator.Objects
+	for _, obj := range s.objects {
+		if obj.Locked {
+			locked = append(locked, obj)
+		}
+	}
+	return locked
+}
@@ -0,0 +1,71 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/minio/warp/pkg/generator"
+)
+
+func TestObjectSet(t *testing.T) {
+	s := NewObjectSet()
+	for i := range 10 {
+		s.Add(generator.Object{Name: strconv.Itoa(i), Size: int64(i)})
+	}
+	s.Add(generator.Object{Name: "3", Size: 33})
+	if s.Len() != 10 {
+		t.Fatalf("got %d objects, want 10", s.Len())
+	}
+	if obj, _ := s.Get("3"); obj.Size != 33 {
+		t.Errorf("replaced object size %d, want 33", obj.Size)
+	}
+	if !s.Remove("0") || s.Remove("0") {
+		t.Error("unexpected Remove result")
+	}
+	if s.Contains("0") || !s.Contains("9") {
+		t.Error("unexpected Contains result")
+	}
+	if obj, ok := s.Get("9"); !ok || obj.Size != 9 {
+		t.Errorf("moved object: got %+v, %v", obj, ok)
+	}
+	rng := rand.New(rand.NewSource(1))
+	for range 100 {
+		obj, ok := s.Random(rng)
+		if !ok || obj.Name == "0" {
+			t.Fatalf("unexpected random object %+v", obj)
+		}
+	}
+	taken := s.Take(3)
+	if len(taken) != 3 || s.Len() != 6 {
+		t.Fatalf("took %d objects, %d remaining", len(taken), s.Len())
+	}
+	for _, obj := range taken {
+		if s.Contains(obj.Name) {
+			t.Errorf("taken object %s still in set", obj.Name)
+		}
+	}
+	for _, obj := range s.Objects() {
+		s.Remove(obj.Name)
+	}
+	if _, ok := s.Random(rng); ok || s.Len() != 0 {
+		t.Error("set should be empty")
+	}
+}
@@ -0,0 +1,168 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/minio/warp/pkg/generator"
+)
+
+// objectRecord is a single line of an object set file.
+type objectRecord struct {
+	Del         bool   `json:"del,omitempty"`
+	Name        string `json:"name"`
+	Prefix      string `json:"prefix,omitempty"`
+	Size        int64  `json:"size,omitempty"`
+	VersionID   string `json:"version,omitempty"`
+	ContentType string `json:"ct,omitempty"`
+	Locked      bool   `json:"locked,omitempty"`
+}
+
+func (r objectRecord) object() generator.Object {
+	return generator.Object{Name: r.Name, Prefix: r.Prefix, Size: r.Size, VersionID: r.VersionID, ContentType: r.ContentType, Locked: r.Locked}
+}
+
+func newObjectRecord(obj generator.Object) objectRecord {
+	return objectRecord{Name: obj.Name, Prefix: obj.Prefix, Size: obj.Size, VersionID: obj.VersionID, ContentType: obj.ContentType, Locked: obj.Locked}
+}
+
+// Save writes a snapshot of the set to path, replacing any existing file.
+// The file is written to a temporary file first, so an existing file is
+// left intact if writing fails.
+func (s *ObjectSet) Save(path string) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	bw := bufio.NewWriter(f)
+	enc := json.NewEncoder(bw)
+	for _, obj := range s.Objects() {
+		if err := enc.Encode(newObjectRecord(obj)); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := errors.Join(bw.Flush(), f.Sync(), f.Close()); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// LoadObjectSet reads an object set written by Save or Journal.
+// If the file ends with a partial or corrupted record, for example
+// because the process writing it was killed, the file is truncated
+// after the last valid record and the records before it are returned.
+func LoadObjectSet(path string) (*ObjectSet, error) {
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	s := NewObjectSet()
+	valid, err := s.readRecords(f)
+	if err != nil {
+		return nil, err
+	}
+	if st, err := f.Stat(); err == nil && st.Size() > valid {
+		if err := f.Truncate(valid); err != nil {
+			return nil, fmt.Errorf("truncating %s: %w", path, err)
+		}
+	}
+	return s, nil
+}
+
+// readRecords applies all records in r to s.
+// It returns the number of bytes up to and including the last valid record.
+func (s *ObjectSet) readRecords(r io.Reader) (valid int64, err error) {
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			// A line without a newline was not completely written.
+			return valid, nil
+		}
+		if err != nil {
+			return valid, err
+		}
+		var rec objectRecord
+		if err := json.Unmarshal(bytes.TrimSpace(line), &rec); err != nil || rec.Name == "" {
+			return valid, nil
+		}
+		if rec.Del {
+			s.Remove(rec.Name)
+		} else {
+			s.Add(rec.object())
+		}
+		valid += int64(len(line))
+	}
+}
+
+// Journal appends all following additions and removals to the file at path.
+// Records are written as they happen, so the set can be restored with
+// LoadObjectSet if the process is stopped.
+// Call Close to stop journaling and close the file.
+func (s *ObjectSet) Journal(path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.journal != nil {
+		f.Close()
+		return errors.New("object set is already journaled")
+	}
+	s.journal = f
+	s.journalErr = nil
+	return nil
+}
+
+// Close stops journaling and returns the first error writing the journal, if any.
+func (s *ObjectSet) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.journal == nil {
+		return nil
+	}
+	err := errors.Join(s.journalErr, s.journal.Close())
+	s.journal = nil
+	s.journalErr = nil
+	return err
+}
+
+// writeJournal appends rec to the journal, if any.
+// s.mu must be held.
+func (s *ObjectSet) writeJournal(rec objectRecord) {
+	if s.journal == nil || s.journalErr != nil {
+		return
+	}
+	b, err := json.Marshal(rec)
+	if err == nil {
+		_, err = s.journal.Write(append(b, '\n'))
+	}
+	s.journalErr = err
+}
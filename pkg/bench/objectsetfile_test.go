@@ -0,0 +1,151 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/minio/warp/pkg/generator"
+)
+
+func sortedNames(s *ObjectSet) []string {
+	var names []string
+	for _, obj := range s.Objects() {
+		names = append(names, obj.Name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func TestObjectSetSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "objects.json")
+	s := NewObjectSet()
+	for i := range 100 {
+		s.Add(generator.Object{Name: fmt.Sprintf("obj-%03d", i), Prefix: "p", Size: int64(i), VersionID: "v1", Locked: i%2 == 0})
+	}
+	if err := s.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadObjectSet(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(sortedNames(got), sortedNames(s)) {
+		t.Fatal("loaded set differs")
+	}
+	if obj, ok := got.Get("obj-042"); !ok || obj.Size != 42 || obj.Prefix != "p" || obj.VersionID != "v1" || !obj.Locked {
+		t.Errorf("unexpected object %+v", obj)
+	}
+	if _, err := LoadObjectSet(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("expected error loading missing file")
+	}
+}
+
+func TestObjectSetJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "objects.json")
+	s := NewObjectSet()
+	if err := s.Journal(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Journal(path); err == nil {
+		t.Error("expected error journaling twice")
+	}
+	var wg sync.WaitGroup
+	for w := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 50 {
+				s.Add(generator.Object{Name: fmt.Sprintf("w%d-%02d", w, i), Size: 10})
+			}
+		}()
+	}
+	wg.Wait()
+	s.Remove("w0-00")
+	s.Take(5)
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// Not recorded after close.
+	s.Add(generator.Object{Name: "after-close"})
+	s.Remove("after-close")
+
+	got, err := LoadObjectSet(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Len() != 400-6 || !slices.Equal(sortedNames(got), sortedNames(s)) {
+		t.Fatalf("loaded %d objects, want %d", got.Len(), s.Len())
+	}
+}
+
+func TestObjectSetLoadTruncated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "objects.json")
+	s := NewObjectSet()
+	for i := range 10 {
+		s.Add(generator.Object{Name: fmt.Sprintf("obj-%d", i), Size: 100})
+	}
+	if err := s.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range map[string][]byte{
+		"partial": b[:len(b)-5],
+		"garbage": append(slices.Clone(b), []byte("{\"name\":\x00\n{\"name\":\"obj-x\"}\n")...),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := os.WriteFile(path, data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := LoadObjectSet(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := 10
+			if name == "partial" {
+				want = 9
+			}
+			if got.Len() != want {
+				t.Errorf("loaded %d objects, want %d", got.Len(), want)
+			}
+			// The file must be truncated, so records can be appended.
+			if err := got.Journal(path); err != nil {
+				t.Fatal(err)
+			}
+			got.Add(generator.Object{Name: "new"})
+			if err := got.Close(); err != nil {
+				t.Fatal(err)
+			}
+			again, err := LoadObjectSet(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if again.Len() != want+1 || !again.Contains("new") {
+				t.Errorf("reloaded %d objects, want %d", again.Len(), want+1)
+			}
+		})
+	}
+}
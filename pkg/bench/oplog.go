@@ -0,0 +1,187 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Status values of OperationLogEntry.
+const (
+	OpLogStatusOK    = "ok"
+	OpLogStatusError = "error"
+)
+
+// OperationLogEntry is a completed operation as written by OperationLogger.
+// It is a flat record intended for offline analysis with other tools.
+type OperationLogEntry struct {
+	Type  string    `json:"type"`
+	Key   string    `json:"key,omitempty"`
+	Size  int64     `json:"size"`
+	Start time.Time `json:"start"`
+	// DurationNS is the duration of the operation in nanoseconds.
+	DurationNS int64 `json:"duration_ns"`
+	// FirstByteNS is the time to first byte in nanoseconds, if recorded.
+	FirstByteNS int64  `json:"first_byte_ns,omitempty"`
+	Status      string `json:"status"`
+	Err         string `json:"error,omitempty"`
+	// Bytes is the number of bytes transferred. It is 0 for failed operations.
+	Bytes    int64  `json:"bytes"`
+	Thread   uint32 `json:"thread"`
+	Endpoint string `json:"endpoint,omitempty"`
+	ClientID string `json:"client_id,omitempty"`
+}
+
+// NewOperationLogEntry returns the log entry of op.
+func NewOperationLogEntry(op Operation) OperationLogEntry {
+	e := OperationLogEntry{
+		Type:       op.OpType,
+		Key:        op.File,
+		Size:       op.Size,
+		Start:      op.Start,
+		DurationNS: int64(op.Duration()),
+		Status:     OpLogStatusOK,
+		Err:        op.Err,
+		Bytes:      op.Size,
+		Thread:     op.Thread,
+		Endpoint:   op.Endpoint,
+		ClientID:   op.ClientID,
+	}
+	if op.FirstByte != nil {
+		e.FirstByteNS = int64(op.FirstByte.Sub(op.Start))
+	}
+	if op.Err != "" {
+		e.Status = OpLogStatusError
+		e.Bytes = 0
+	}
+	return e
+}
+
+// errOpLogClosed is returned when logging to a closed OperationLogger.
+var errOpLogClosed = errors.New("operation log is closed")
+
+// OperationLogger writes completed operations as JSON lines.
+// Operations are queued on a bounded channel and written by a single goroutine,
+// so Log only blocks when the queue is full and memory use is bounded.
+// Operations are written in the order Log was called.
+// Close must be called to flush the output.
+// It is safe for concurrent use.
+type OperationLogger struct {
+	mu     sync.RWMutex
+	closed bool
+	ops    chan Operation
+	done   chan struct{}
+
+	w     io.Writer
+	close io.Closer
+	n     atomic.Int64
+	err   error
+}
+
+// NewOperationLogger returns a logger writing to w, with room for queue operations waiting to be written.
+// w is not closed by Close.
+func NewOperationLogger(w io.Writer, queue int) *OperationLogger {
+	l := &OperationLogger{
+		ops:  make(chan Operation, max(queue, 0)),
+		done: make(chan struct{}),
+		w:    w,
+	}
+	go l.run()
+	return l
+}
+
+// CreateOperationLog creates the file at path and returns a logger writing to it.
+// The file is closed by Close.
+func CreateOperationLog(path string) (*OperationLogger, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	l := NewOperationLogger(f, 1000)
+	l.close = f
+	return l, nil
+}
+
+// run writes queued operations until the queue is closed.
+// After an error operations are still drained, so Log does not block.
+func (l *OperationLogger) run() {
+	defer close(l.done)
+	bw := bufio.NewWriter(l.w)
+	enc := json.NewEncoder(bw)
+	for op := range l.ops {
+		if l.err != nil {
+			continue
+		}
+		l.err = enc.Encode(NewOperationLogEntry(op))
+		if l.err == nil {
+			l.n.Add(1)
+		}
+	}
+	if l.err == nil {
+		l.err = bw.Flush()
+	}
+}
+
+// Log queues op to be written.
+// An error is only returned if the logger is closed.
+// Write errors are returned by Close.
+func (l *OperationLogger) Log(op Operation) error {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.closed {
+		return errOpLogClosed
+	}
+	l.ops <- op
+	return nil
+}
+
+// LogFrom logs all operations received on ops until it is closed.
+func (l *OperationLogger) LogFrom(ops <-chan Operation) {
+	for op := range ops {
+		l.Log(op)
+	}
+}
+
+// Close writes all queued operations, flushes the output and closes the file, if any.
+// The first error encountered while writing is returned.
+// Calling Close more than once returns the same error.
+func (l *OperationLogger) Close() error {
+	l.mu.Lock()
+	if !l.closed {
+		l.closed = true
+		close(l.ops)
+		<-l.done
+		if l.close != nil {
+			l.err = errors.Join(l.err, l.close.Close())
+		}
+	}
+	l.mu.Unlock()
+	return l.err
+}
+
+// Count returns the number of operations written so far.
+func (l *OperationLogger) Count() int64 {
+	return l.n.Load()
+}
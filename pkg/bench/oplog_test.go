@@ -0,0 +1,170 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// readOperationLog decodes all entries of an operation log.
+func readOperationLog(t *testing.T, b []byte) []OperationLogEntry {
+	t.Helper()
+	var res []OperationLogEntry
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	for sc.Scan() {
+		var e OperationLogEntry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			t.Fatalf("line %d: %v", len(res)+1, err)
+		}
+		res = append(res, e)
+	}
+	return res
+}
+
+func TestOperationLogger(t *testing.T) {
+	start := time.Date(2025, 1, 2, 3, 4, 5, 6, time.UTC)
+	firstByte := start.Add(2 * time.Millisecond)
+	ops := []Operation{
+		{OpType: http.MethodPut, File: "a/1", Size: 100, Start: start, End: start.Add(5 * time.Millisecond), Thread: 1, Endpoint: "http://host:9000"},
+		{OpType: http.MethodGet, File: "a/1", Size: 100, Start: start, End: start.Add(7 * time.Millisecond), FirstByte: &firstByte, Thread: 2},
+		{OpType: http.MethodGet, File: "a/2", Size: 200, Start: start, End: start.Add(time.Second), Err: "timeout", Thread: 3},
+		{OpType: http.MethodDelete, File: "a/1", Start: start, End: start.Add(time.Millisecond)},
+	}
+	var buf bytes.Buffer
+	l := NewOperationLogger(&buf, 1)
+	for _, op := range ops {
+		if err := l.Log(op); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if l.Count() != int64(len(ops)) {
+		t.Errorf("got count %d, want %d", l.Count(), len(ops))
+	}
+	got := readOperationLog(t, buf.Bytes())
+	want := []OperationLogEntry{
+		{Type: "PUT", Key: "a/1", Size: 100, Start: start, DurationNS: 5e6, Status: OpLogStatusOK, Bytes: 100, Thread: 1, Endpoint: "http://host:9000"},
+		{Type: "GET", Key: "a/1", Size: 100, Start: start, DurationNS: 7e6, FirstByteNS: 2e6, Status: OpLogStatusOK, Bytes: 100, Thread: 2},
+		{Type: "GET", Key: "a/2", Size: 200, Start: start, DurationNS: 1e9, Status: OpLogStatusError, Err: "timeout", Thread: 3},
+		{Type: "DELETE", Key: "a/1", Start: start, DurationNS: 1e6, Status: OpLogStatusOK},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !got[i].Start.Equal(want[i].Start) {
+			t.Errorf("entry %d: got start %v, want %v", i, got[i].Start, want[i].Start)
+		}
+		got[i].Start = want[i].Start
+		if got[i] != want[i] {
+			t.Errorf("entry %d:\ngot  %+v\nwant %+v", i, got[i], want[i])
+		}
+	}
+
+	if err := l.Log(ops[0]); err == nil {
+		t.Error("want error logging after close")
+	}
+	if err := l.Close(); err != nil {
+		t.Errorf("second close: %v", err)
+	}
+}
+
+func TestOperationLoggerConcurrent(t *testing.T) {
+	const threads, perThread = 8, 500
+	var buf bytes.Buffer
+	l := NewOperationLogger(&buf, 10)
+	var wg sync.WaitGroup
+	for th := range threads {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ch := make(chan Operation)
+			go func() {
+				for i := range perThread {
+					ch <- Operation{OpType: http.MethodPut, Thread: uint32(th), File: fmt.Sprint(i), Size: int64(i)}
+				}
+				close(ch)
+			}()
+			l.LogFrom(ch)
+		}()
+	}
+	wg.Wait()
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	got := readOperationLog(t, buf.Bytes())
+	if len(got) != threads*perThread {
+		t.Fatalf("got %d entries, want %d", len(got), threads*perThread)
+	}
+	// Operations from each sender are written in the order they were logged.
+	next := make([]int64, threads)
+	for _, e := range got {
+		if e.Size != next[e.Thread] {
+			t.Fatalf("thread %d: got operation %d, want %d", e.Thread, e.Size, next[e.Thread])
+		}
+		next[e.Thread]++
+	}
+}
+
+// failingWriter fails all writes.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestOperationLoggerErrors(t *testing.T) {
+	l := NewOperationLogger(failingWriter{}, 0)
+	for range 10000 {
+		if err := l.Log(Operation{OpType: http.MethodGet, File: "obj"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := l.Close(); err == nil {
+		t.Error("want write error from close")
+	}
+
+	path := filepath.Join(t.TempDir(), "ops.jsonl")
+	l, err := CreateOperationLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Log(Operation{OpType: http.MethodPut, File: "obj", Size: 10})
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := readOperationLog(t, b); len(got) != 1 || got[0].Key != "obj" || got[0].Bytes != 10 {
+		t.Errorf("unexpected log %+v", got)
+	}
+}
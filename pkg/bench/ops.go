@@ -35,18 +35,41 @@ import (
 type Operations []Operation
 
 type Operation struct {
-	Start      time.Time  `json:"start"`
-	End        time.Time  `json:"end"`
-	FirstByte  *time.Time `json:"first_byte"`
-	OpType     string     `json:"type"`
-	Err        string     `json:"err"`
-	File       string     `json:"file,omitempty"`
-	ClientID   string     `json:"client_id"`
-	Endpoint   string     `json:"endpoint"`
-	ObjPerOp   int        `json:"ops"`
-	Size       int64      `json:"size"`
-	Thread     uint32     `json:"thread"`
-	Categories Categories `json:"cat"`
+	Start     time.Time  `json:"start"`
+	End       time.Time  `json:"end"`
+	FirstByte *time.Time `json:"first_byte"`
+	OpType    string     `json:"type"`
+	Err       string     `json:"err"`
+	// ErrCat is the category of Err, set when the error is recorded.
+	ErrCat     ErrorCategory `json:"err_cat,omitempty"`
+	File       string        `json:"file,omitempty"`
+	ClientID   string        `json:"client_id"`
+	Endpoint   string        `json:"endpoint"`
+	ObjPerOp   int           `json:"ops"`
+	Size       int64         `json:"size"`
+	Thread     uint32        `json:"thread"`
+	Categories Categories    `json:"cat"`
+	// Retries is the number of times the operation was retried.
+	Retries int `json:"retries,omitempty"`
+}
+
+// SetErr sets the error of the operation and stores its category.
+func (o *Operation) SetErr(err error) {
+	o.Err = err.Error()
+	o.ErrCat = ErrorClassifier{}.Classify(err)
+}
+
+// ErrCategory returns the category of the operation error.
+// Operations without a stored category, for example errors only available
+// as text or loaded from older files, are classified by their message.
+func (o Operation) ErrCategory() ErrorCategory {
+	if o.Err == "" {
+		return ErrCatNone
+	}
+	if o.ErrCat != ErrCatNone {
+		return o.ErrCat
+	}
+	return ErrorClassifier{}.ClassifyMessage(o.Err)
 }
 
 // Duration returns the duration o.End-o.Start
@@ -1043,11 +1066,14 @@ func (o Operations) FilterErrors() Operations {
 	return errs
 }
 
+// csvHeader is the first line of operations written as CSV.
+const csvHeader = "idx\tthread\top\tclient_id\tn_objects\tbytes\tendpoint\tfile\terror\tstart\tfirst_byte\tend\tduration_ns\tcat\terr_cat\tretries\n"
+
 // CSV will write the operations to w as CSV.
 // The comment, if any, is written at the end of the file, each line prefixed with '# '.
 func (o Operations) CSV(w io.Writer, comment string) error {
 	bw := bufio.NewWriter(w)
-	_, err := bw.WriteString("idx\tthread\top\tclient_id\tn_objects\tbytes\tendpoint\tfile\terror\tstart\tfirst_byte\tend\tduration_ns\tcat\n")
+	_, err := bw.WriteString(csvHeader)
 	if err != nil {
 		return err
 	}
@@ -1075,7 +1101,7 @@ func (o Operation) WriteCSV(w io.Writer, i int) error {
 	if o.FirstByte != nil {
 		ttfb = o.FirstByte.Format(time.RFC3339Nano)
 	}
-	_, err := fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%d\t%d\t%s\t%s\t%s\t%s\t%s\t%s\t%d\t%d\n", i, o.Thread, o.OpType, o.ClientID, o.ObjPerOp, o.Size, csvEscapeString(o.Endpoint), o.File, csvEscapeString(o.Err), o.Start.Format(time.RFC3339Nano), ttfb, o.End.Format(time.RFC3339Nano), o.End.Sub(o.Start)/time.Nanosecond, o.Categories)
+	_, err := fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%d\t%d\t%s\t%s\t%s\t%s\t%s\t%s\t%d\t%d\t%d\t%d\n", i, o.Thread, o.OpType, o.ClientID, o.ObjPerOp, o.Size, csvEscapeString(o.Endpoint), o.File, csvEscapeString(o.Err), o.Start.Format(time.RFC3339Nano), ttfb, o.End.Format(time.RFC3339Nano), o.End.Sub(o.Start)/time.Nanosecond, o.Categories, o.ErrCat, o.Retries)
 	return err
 }
 
@@ -1194,6 +1220,21 @@ func StreamOperationsFromCSV(r io.Reader, analyzeOnly bool, offset, limit int, l
 			}
 			cat = Categories(c)
 		}
+		var errCat ErrorCategory
+		if idx, ok := fieldIdx["err_cat"]; ok {
+			c, err := strconv.ParseUint(values[idx], 10, 8)
+			if err != nil {
+				return err
+			}
+			errCat = ErrorCategory(c)
+		}
+		var retries int
+		if idx, ok := fieldIdx["retries"]; ok {
+			retries, err = strconv.Atoi(values[idx])
+			if err != nil {
+				return err
+			}
+		}
 		var endpoint, clientID string
 		if idx, ok := fieldIdx["endpoint"]; ok {
 			endpoint = values[idx]
@@ -1210,12 +1251,14 @@ func StreamOperationsFromCSV(r io.Reader, analyzeOnly bool, offset, limit int, l
 			FirstByte:  ttfb,
 			End:        end,
 			Err:        values[fieldIdx["error"]],
+			ErrCat:     errCat,
 			Size:       size,
 			File:       file,
 			Thread:     uint32(thread),
 			Endpoint:   endpoint,
 			ClientID:   getClient(clientID),
 			Categories: cat,
+			Retries:    retries,
 		}
 		n++
 		if log != nil && n%100000 == 0 {
@@ -0,0 +1,75 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// opTimeouter is implemented by benchmarks that use Common.OpTimeout.
+type opTimeouter interface {
+	timesOutOps()
+}
+
+// opTimeoutError is returned for an attempt that did not complete within OpTimeout.
+type opTimeoutError struct {
+	timeout time.Duration
+	err     error
+}
+
+func (e opTimeoutError) Error() string {
+	return fmt.Sprintf("operation timeout after %v: %v", e.timeout, e.err)
+}
+
+func (e opTimeoutError) Unwrap() error {
+	return e.err
+}
+
+// Is reports the error as context.DeadlineExceeded,
+// so it is classified as a timeout whatever the client returned.
+func (e opTimeoutError) Is(target error) bool {
+	return target == context.DeadlineExceeded
+}
+
+// withOpTimeout calls fn with ctx limited to OpTimeout.
+// Each call has its own deadline, so every retry of an operation gets the full timeout.
+// If the deadline expired the error of fn is returned as an opTimeoutError.
+func (c *Common) withOpTimeout(ctx context.Context, fn func(ctx context.Context) error) error {
+	if c.OpTimeout <= 0 {
+		return fn(ctx)
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.OpTimeout)
+	defer cancel()
+	err := fn(ctx)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return opTimeoutError{timeout: c.OpTimeout, err: err}
+	}
+	return err
+}
+
+// opTimedOut sets the error of op to err if err is from an attempt that timed out.
+// It is used where op.Err has already been set from the error returned by the client.
+func opTimedOut(op *Operation, err error) {
+	var timeout opTimeoutError
+	if errors.As(err, &timeout) {
+		op.SetErr(err)
+	}
+}
@@ -0,0 +1,109 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/warp/pkg/generator"
+)
+
+// hangTransport never answers and returns when the request is canceled.
+type hangTransport struct{}
+
+func (hangTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	<-req.Context().Done()
+	return nil, req.Context().Err()
+}
+
+func TestOpTimeout(t *testing.T) {
+	const timeout = 50 * time.Millisecond
+	cl, err := minio.New("localhost:9000", &minio.Options{
+		Creds:        credentials.NewStaticV4("access", "secret", ""),
+		Region:       "us-east-1",
+		BucketLookup: minio.BucketLookupPath,
+		Transport:    hangTransport{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	objs := generator.Objects{{Name: "a", Size: 10, ETag: "x"}, {Name: "b", Size: 10, ETag: "y"}}
+	gen, err := generator.NewFn(generator.WithRandomData().Apply(), generator.WithSize(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range []Benchmark{
+		&Get{objects: objs},
+		&Get{objects: objs, Conditional: &ConditionalGet{}},
+		&Put{},
+		&Stat{objects: objs},
+	} {
+		var errs atomic.Int64
+		c := b.GetCommon()
+		*c = Common{
+			Client:      func() (*minio.Client, func()) { return cl, func() {} },
+			Bucket:      "bucket",
+			Concurrency: 2,
+			Source:      gen,
+			PutOpts:     minio.PutObjectOptions{DisableContentSha256: true},
+			OpTimeout:   timeout,
+			// Every attempt has its own timeout.
+			Retry: NewRetrier(1, time.Millisecond, time.Millisecond, 0),
+			Error: func(data ...any) { errs.Add(1) },
+		}
+		ops, err := RunFor(context.Background(), b, 300*time.Millisecond)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ops) == 0 || errs.Load() != int64(len(ops)) {
+			t.Fatalf("%T: %d ops, %d errors", b, len(ops), errs.Load())
+		}
+		retried := 0
+		for _, op := range ops {
+			if op.Retries > 0 {
+				retried++
+			}
+			if cat := op.ErrCategory(); cat != ErrCatTimeout {
+				t.Errorf("%T: error %q categorized as %v", b, op.Err, cat)
+			}
+			// Hanging requests are abandoned at the timeout, not when the run ends.
+			attempts := time.Duration(op.Retries + 1)
+			if d := op.End.Sub(op.Start); d < attempts*timeout || d > 5*time.Second {
+				t.Errorf("%T: operation took %v with %d retries, timeout %v", b, d, op.Retries, timeout)
+			}
+		}
+		if g, ok := b.(*Get); (!ok || g.Conditional == nil) && retried == 0 {
+			t.Errorf("%T: timeouts were not retried", b)
+		}
+	}
+}
+
+func TestOpTimeoutUnsupported(t *testing.T) {
+	if err := Validate(&Delete{Common: Common{OpTimeout: time.Second}}); err == nil {
+		t.Error("DELETE accepted an operation timeout")
+	}
+	if err := Validate(&Get{Common: Common{OpTimeout: time.Second}}); err != nil {
+		t.Error(err)
+	}
+}
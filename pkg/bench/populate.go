@@ -0,0 +1,157 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/warp/pkg/generator"
+)
+
+// ObjectPutter uploads objects.
+// It is implemented by *minio.Client.
+type ObjectPutter interface {
+	PutObject(ctx context.Context, bucket, object string, reader io.Reader, size int64, opts minio.PutObjectOptions) (minio.UploadInfo, error)
+}
+
+// PopulateOptions configures Populate.
+type PopulateOptions struct {
+	Bucket string
+
+	// Count is the number of objects to upload.
+	Count int
+
+	// Concurrency is the number of concurrent uploads.
+	// Values <= 0 will use 1.
+	Concurrency int
+
+	// Size of each object, used if Sizes is nil.
+	Size int64
+
+	// Sizes, if set, provides the size of each object.
+	Sizes *generator.SizeSampler
+
+	// Keys generates object names.
+	Keys *generator.KeyGenerator
+
+	// Content returns a content generator for a single goroutine.
+	// If nil, static content is used.
+	Content func() generator.Generator
+
+	PutOpts minio.PutObjectOptions
+
+	// Progress, if set, is called with the number of finished and total uploads.
+	// It may be called concurrently.
+	Progress func(done, total int)
+}
+
+// PopulateFailure is an object that could not be uploaded.
+type PopulateFailure struct {
+	Key string
+	Err error
+}
+
+// PopulateResult is the result of Populate.
+type PopulateResult struct {
+	// Objects contains all successfully uploaded objects.
+	Objects *ObjectSet
+
+	// Failed contains objects that failed to upload.
+	Failed []PopulateFailure
+}
+
+// Populate uploads objects so they can be used by GET and DELETE benchmarks.
+// Upload failures are returned in the result and do not stop the upload of other objects.
+// If ctx is canceled, the objects uploaded so far are returned with the context error.
+func Populate(ctx context.Context, client ObjectPutter, o PopulateOptions) (*PopulateResult, error) {
+	if o.Keys == nil {
+		return nil, errors.New("populate: no key generator")
+	}
+	if o.Count < 0 {
+		return nil, fmt.Errorf("populate: invalid count: %d", o.Count)
+	}
+	if o.Content == nil {
+		o.Content = func() generator.Generator {
+			g, _ := generator.NewGenerator(generator.GeneratorStatic)
+			return g
+		}
+	}
+	concurrency := max(o.Concurrency, 1)
+	type job struct {
+		key  string
+		size int64
+	}
+	jobs := make(chan job, concurrency)
+	go func() {
+		defer close(jobs)
+		for range o.Count {
+			j := job{key: o.Keys.Next(), size: o.Size}
+			if o.Sizes != nil {
+				j.size = o.Sizes.Next()
+			}
+			select {
+			case jobs <- j:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	res := PopulateResult{Objects: NewObjectSet()}
+	var mu sync.Mutex
+	var done atomic.Int64
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for range concurrency {
+		go func() {
+			defer wg.Done()
+			content := o.Content()
+			opts := o.PutOpts
+			for j := range jobs {
+				if ctx.Err() != nil {
+					return
+				}
+				info, err := client.PutObject(ctx, o.Bucket, j.key, content.Reader(j.size), j.size, opts)
+				if err == nil && info.Size != j.size {
+					err = fmt.Errorf("short upload. want: %d, got: %d", j.size, info.Size)
+				}
+				if err != nil {
+					if ctx.Err() != nil {
+						return
+					}
+					mu.Lock()
+					res.Failed = append(res.Failed, PopulateFailure{Key: j.key, Err: err})
+					mu.Unlock()
+				} else {
+					res.Objects.Add(generator.Object{Name: j.key, Size: j.size, VersionID: info.VersionID})
+				}
+				if o.Progress != nil {
+					o.Progress(int(done.Add(1)), o.Count)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return &res, ctx.Err()
+}
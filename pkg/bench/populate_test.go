@@ -0,0 +1,151 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/warp/pkg/generator"
+)
+
+// mockPutter records uploaded objects.
+type mockPutter struct {
+	mu      sync.Mutex
+	objects map[string]int64
+	opts    map[string]minio.PutObjectOptions
+	puts    int
+	// fail returns an error for keys that should fail.
+	fail func(key string) error
+}
+
+func (m *mockPutter) PutObject(ctx context.Context, bucket, object string, reader io.Reader, size int64, opts minio.PutObjectOptions) (minio.UploadInfo, error) {
+	n, err := io.Copy(io.Discard, reader)
+	if err != nil {
+		return minio.UploadInfo{}, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.puts++
+	if m.fail != nil {
+		if err := m.fail(object); err != nil {
+			return minio.UploadInfo{}, err
+		}
+	}
+	if m.objects == nil {
+		m.objects = make(map[string]int64)
+		m.opts = make(map[string]minio.PutObjectOptions)
+	}
+	m.objects[object] = n
+	m.opts[object] = opts
+	return minio.UploadInfo{Bucket: bucket, Key: object, Size: n}, nil
+}
+
+func TestPopulate(t *testing.T) {
+	keys, err := generator.NewKeyGenerator(generator.KeyOptions{Prefix: "pop", Shards: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sizes, err := generator.NewUniformSizeSampler(1, 10<<10, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &mockPutter{
+		fail: func(key string) error {
+			if strings.HasSuffix(key, "7") {
+				return errors.New("injected failure")
+			}
+			return nil
+		},
+	}
+	var lastProgress, progressCalls int
+	var progressMu sync.Mutex
+	res, err := Populate(context.Background(), client, PopulateOptions{
+		Bucket:      "bucket",
+		Count:       100,
+		Concurrency: 8,
+		Sizes:       sizes,
+		Keys:        keys,
+		Progress: func(done, total int) {
+			progressMu.Lock()
+			defer progressMu.Unlock()
+			progressCalls++
+			lastProgress = max(lastProgress, done)
+			if total != 100 {
+				t.Errorf("progress total %d, want 100", total)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.puts != 100 {
+		t.Errorf("got %d PUTs, want 100", client.puts)
+	}
+	// Keys ending in 7: 7, 17, ..., 97.
+	if len(res.Failed) != 10 {
+		t.Errorf("got %d failures, want 10", len(res.Failed))
+	}
+	if got := res.Objects.Len(); got != 90 {
+		t.Errorf("got %d tracked objects, want 90", got)
+	}
+	for _, obj := range res.Objects.Objects() {
+		size, ok := client.objects[obj.Name]
+		if !ok {
+			t.Errorf("tracked object %s was not uploaded", obj.Name)
+			continue
+		}
+		if size != obj.Size {
+			t.Errorf("object %s: tracked size %d, uploaded %d", obj.Name, obj.Size, size)
+		}
+	}
+	for _, f := range res.Failed {
+		if res.Objects.Contains(f.Key) {
+			t.Errorf("failed object %s is tracked", f.Key)
+		}
+	}
+	if progressCalls != 100 || lastProgress != 100 {
+		t.Errorf("progress called %d times, last %d", progressCalls, lastProgress)
+	}
+}
+
+func TestPopulateCanceled(t *testing.T) {
+	keys, _ := generator.NewKeyGenerator(generator.KeyOptions{})
+	ctx, cancel := context.WithCancel(context.Background())
+	var n int
+	client := &mockPutter{
+		fail: func(string) error {
+			if n++; n == 10 {
+				cancel()
+			}
+			return nil
+		},
+	}
+	res, err := Populate(ctx, client, PopulateOptions{Count: 1000, Concurrency: 1, Size: 10, Keys: keys})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v, want context.Canceled", err)
+	}
+	if client.puts >= 1000 || res.Objects.Len() > client.puts {
+		t.Errorf("upload not stopped: %d PUTs, %d objects", client.puts, res.Objects.Len())
+	}
+}
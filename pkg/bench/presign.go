@@ -0,0 +1,361 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/warp/pkg/generator"
+)
+
+// Presigned benchmarks uploads or downloads through presigned URLs.
+// Every operation creates a URL and sends the request with a plain HTTP client.
+// The time to create the URL is recorded as a separate PRESIGN operation.
+type Presigned struct {
+	Common
+
+	// Get will download objects uploaded by Prepare instead of uploading new objects.
+	Get bool
+
+	// CreateObjects is the number of objects to upload for downloads.
+	CreateObjects int
+
+	// Expires is the validity of the URLs. If 0, 15 minutes is used.
+	Expires time.Duration
+
+	// Presigner, if set, creates the URLs instead of the benchmark client.
+	Presigner Presigner
+
+	objects  generator.Objects
+	prefixes map[string]struct{}
+	mu       sync.Mutex
+}
+
+// Presigner creates presigned URLs.
+// It is implemented by *minio.Client.
+type Presigner interface {
+	PresignedPutObject(ctx context.Context, bucket, object string, expires time.Duration) (*url.URL, error)
+	PresignedGetObject(ctx context.Context, bucket, object string, expires time.Duration, reqParams url.Values) (*url.URL, error)
+}
+
+// presignOpType is the operation type for creating presigned URLs.
+const presignOpType = "PRESIGN"
+
+// presignedPut uploads obj to a URL from p using hc.
+// Signing is recorded in sign and the upload in put.
+func presignedPut(ctx context.Context, p Presigner, hc *http.Client, bucket string, obj *generator.Object, expires time.Duration, sign, put *Operation) {
+	sign.Start = time.Now()
+	u, err := p.PresignedPutObject(ctx, bucket, obj.Name, expires)
+	sign.End = time.Now()
+	if err != nil {
+		sign.SetErr(err)
+		put.Start, put.End = sign.End, sign.End
+		put.Err, put.ErrCat = sign.Err, sign.ErrCat
+		return
+	}
+	put.Size = obj.Size
+	put.Start = time.Now()
+	defer func() { put.End = time.Now() }()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), obj.Reader)
+	if err != nil {
+		put.SetErr(err)
+		return
+	}
+	req.ContentLength = obj.Size
+	if obj.ContentType != "" {
+		req.Header.Set("Content-Type", obj.ContentType)
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		put.SetErr(err)
+		return
+	}
+	defer resp.Body.Close()
+	if err := presignedResponseErr(resp, bucket, obj.Name); err != nil {
+		put.SetErr(err)
+		return
+	}
+	obj.VersionID = resp.Header.Get("x-amz-version-id")
+	io.Copy(io.Discard, resp.Body)
+}
+
+// presignedGet downloads obj from a URL from p using hc.
+// Signing is recorded in sign and the download in get.
+func presignedGet(ctx context.Context, p Presigner, hc *http.Client, bucket string, obj generator.Object, expires time.Duration, sign, get *Operation) {
+	var params url.Values
+	if obj.VersionID != "" {
+		params = url.Values{"versionId": []string{obj.VersionID}}
+	}
+	sign.Start = time.Now()
+	u, err := p.PresignedGetObject(ctx, bucket, obj.Name, expires, params)
+	sign.End = time.Now()
+	if err != nil {
+		sign.SetErr(err)
+		get.Start, get.End = sign.End, sign.End
+		get.Err, get.ErrCat = sign.Err, sign.ErrCat
+		return
+	}
+	get.Size = obj.Size
+	get.Start = time.Now()
+	defer func() { get.End = time.Now() }()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		get.SetErr(err)
+		return
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		get.SetErr(err)
+		return
+	}
+	defer resp.Body.Close()
+	if err := presignedResponseErr(resp, bucket, obj.Name); err != nil {
+		get.SetErr(err)
+		return
+	}
+	fbr := firstByteRecorder{r: resp.Body}
+	n, err := io.Copy(io.Discard, &fbr)
+	get.FirstByte = fbr.t
+	switch {
+	case err != nil:
+		get.SetErr(err)
+	case n != obj.Size:
+		get.Err = fmt.Sprint("unexpected download size. want:", obj.Size, ", got:", n)
+	}
+}
+
+// presignedResponseErr returns the error in resp, if the request failed.
+// S3 errors are returned as minio.ErrorResponse.
+func presignedResponseErr(resp *http.Response, bucket, object string) error {
+	if resp.StatusCode/100 == 2 {
+		return nil
+	}
+	errResp := minio.ErrorResponse{
+		StatusCode: resp.StatusCode,
+		BucketName: bucket,
+		Key:        object,
+	}
+	if err := xml.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&errResp); err != nil || errResp.Code == "" {
+		errResp.Code = resp.Status
+		errResp.Message = http.StatusText(resp.StatusCode)
+	}
+	return errResp
+}
+
+// expires returns the validity of created URLs.
+func (g *Presigned) expires() time.Duration {
+	if g.Expires > 0 {
+		return g.Expires
+	}
+	return 15 * time.Minute
+}
+
+// presigner returns the presigner and the HTTP client used for requests.
+func (g *Presigned) presigner(cl *minio.Client) (Presigner, *http.Client) {
+	hc := &http.Client{Transport: g.Transport}
+	if g.Presigner != nil {
+		return g.Presigner, hc
+	}
+	return cl, hc
+}
+
+// Prepare will create an empty bucket or delete any content already there.
+// When downloading, objects are uploaded through presigned URLs.
+func (g *Presigned) Prepare(ctx context.Context) error {
+	if err := g.createEmptyBucket(ctx); err != nil {
+		return err
+	}
+	if !g.Get {
+		return nil
+	}
+	src := g.Source()
+	g.UpdateStatus(fmt.Sprint("Uploading ", g.CreateObjects, " objects of ", src.String()))
+
+	var wg sync.WaitGroup
+	wg.Add(g.Concurrency)
+	objs := splitObjs(g.CreateObjects, g.Concurrency)
+	rcv := g.Collector.Receiver()
+	var groupErr error
+
+	for i, obj := range objs {
+		go func(i int, obj []struct{}) {
+			defer wg.Done()
+			src := g.Source()
+			g.addPrefix(src.Prefix())
+			for range obj {
+				select {
+				case <-ctx.Done():
+					return
+				default:
+				}
+				if g.rpsLimit(ctx) != nil {
+					return
+				}
+
+				obj := src.Object()
+				client, cldone := g.Client()
+				p, hc := g.presigner(client)
+				op := Operation{
+					OpType:   http.MethodPut,
+					Thread:   uint32(i),
+					File:     obj.Name,
+					ObjPerOp: 1,
+					Endpoint: client.EndpointURL().String(),
+				}
+				var sign Operation
+				presignedPut(ctx, p, hc, g.Bucket, obj, g.expires(), &sign, &op)
+				cldone()
+				if op.Err != "" {
+					err := fmt.Errorf("upload error: %s", op.Err)
+					g.Error(err)
+					g.mu.Lock()
+					if groupErr == nil {
+						groupErr = err
+					}
+					g.mu.Unlock()
+					return
+				}
+				obj.Reader = nil
+				g.mu.Lock()
+				g.objects = append(g.objects, *obj)
+				g.prepareProgress(float64(len(g.objects)) / float64(g.CreateObjects))
+				g.mu.Unlock()
+				rcv <- op
+			}
+		}(i, obj)
+	}
+	wg.Wait()
+	return groupErr
+}
+
+// Start will execute the main benchmark.
+// Operations should begin executing when the start channel is closed.
+func (g *Presigned) Start(ctx context.Context, wait chan struct{}) error {
+	if g.Get && len(g.objects) == 0 {
+		return fmt.Errorf("no objects to download")
+	}
+	var wg sync.WaitGroup
+	wg.Add(g.Concurrency)
+	c := g.Collector
+	opType := http.MethodPut
+	if g.Get {
+		opType = http.MethodGet
+	}
+	if g.AutoTermDur > 0 {
+		ctx = c.AutoTerm(ctx, opType, g.AutoTermScale, autoTermCheck, autoTermSamples, g.AutoTermDur)
+	}
+	// Non-terminating context.
+	nonTerm := context.Background()
+
+	for i := 0; i < g.Concurrency; i++ {
+		go func(i int) {
+			getClient := g.workerClient(i)
+			rng := rand.New(rand.NewSource(int64(i)))
+			rcv := c.Receiver()
+			src := g.Source()
+			if !g.Get {
+				g.addPrefix(src.Prefix())
+			}
+			defer wg.Done()
+			done := ctx.Done()
+
+			<-wait
+			if g.RampUp.Wait(ctx, i) != nil {
+				return
+			}
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				if g.ThinkTime.Wait(ctx) != nil {
+					return
+				}
+				if g.rpsLimit(ctx) != nil {
+					return
+				}
+
+				client, cldone := getClient()
+				p, hc := g.presigner(client)
+				op := Operation{
+					OpType:   opType,
+					Thread:   uint32(i),
+					ObjPerOp: 1,
+					Endpoint: client.EndpointURL().String(),
+				}
+				sign := op
+				sign.OpType = presignOpType
+				if g.Get {
+					obj := g.objects[rng.Intn(len(g.objects))]
+					op.File = obj.Name
+					presignedGet(nonTerm, p, hc, g.Bucket, obj, g.expires(), &sign, &op)
+				} else {
+					obj := src.Object()
+					op.File = obj.Name
+					presignedPut(nonTerm, p, hc, g.Bucket, obj, g.expires(), &sign, &op)
+				}
+				cldone()
+				if g.DiscardOutput {
+					op.File = ""
+				}
+				sign.File = op.File
+				if op.Err != "" {
+					g.Error(opType, " error: ", op.Err)
+				}
+				rcv <- sign
+				if sign.Err == "" {
+					rcv <- op
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	return nil
+}
+
+// addPrefix records a prefix objects are uploaded to.
+func (g *Presigned) addPrefix(prefix string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.prefixes == nil {
+		g.prefixes = make(map[string]struct{})
+	}
+	g.prefixes[prefix] = struct{}{}
+}
+
+// Cleanup deletes everything uploaded to the bucket.
+func (g *Presigned) Cleanup(ctx context.Context) {
+	g.mu.Lock()
+	pf := make([]string, 0, len(g.prefixes))
+	for p := range g.prefixes {
+		pf = append(pf, p)
+	}
+	g.mu.Unlock()
+	g.deleteAllInBucket(ctx, pf...)
+}
@@ -0,0 +1,228 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/warp/pkg/generator"
+)
+
+// fakePresigner returns URLs to a server with a token that must be presented.
+type fakePresigner struct {
+	base  string
+	delay time.Duration
+	err   error
+}
+
+func (f fakePresigner) presign(method, bucket, object string, expires time.Duration) (*url.URL, error) {
+	time.Sleep(f.delay)
+	if f.err != nil {
+		return nil, f.err
+	}
+	u, err := url.Parse(f.base + "/" + bucket + "/" + object)
+	if err != nil {
+		return nil, err
+	}
+	u.RawQuery = url.Values{"token": []string{method + ":" + expires.String()}}.Encode()
+	return u, nil
+}
+
+func (f fakePresigner) PresignedPutObject(_ context.Context, bucket, object string, expires time.Duration) (*url.URL, error) {
+	return f.presign(http.MethodPut, bucket, object, expires)
+}
+
+func (f fakePresigner) PresignedGetObject(_ context.Context, bucket, object string, expires time.Duration, _ url.Values) (*url.URL, error) {
+	return f.presign(http.MethodGet, bucket, object, expires)
+}
+
+// newPresignServer returns a server storing objects.
+// Requests must carry the token of the fake presigner.
+func newPresignServer(t *testing.T) *httptest.Server {
+	var mu sync.Mutex
+	objects := make(map[string][]byte)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.Query().Get("token"), r.Method+":"+time.Minute.String(); got != want {
+			w.WriteHeader(http.StatusForbidden)
+			io.WriteString(w, `<Error><Code>AccessDenied</Code><Message>bad token</Message></Error>`)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			b, err := io.ReadAll(r.Body)
+			if err != nil || int64(len(b)) != r.ContentLength {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			objects[r.URL.Path] = b
+		case http.MethodGet:
+			b, ok := objects[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				io.WriteString(w, `<Error><Code>NoSuchKey</Code><Message>not found</Message></Error>`)
+				return
+			}
+			w.Write(b)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestPresignedPutGet(t *testing.T) {
+	ctx := context.Background()
+	srv := newPresignServer(t)
+	const delay = 5 * time.Millisecond
+	p := fakePresigner{base: srv.URL, delay: delay}
+	hc := srv.Client()
+
+	content := []byte("presigned content")
+	obj := &generator.Object{Name: "dir/obj", Size: int64(len(content)), Reader: bytes.NewReader(content)}
+	var sign, put Operation
+	presignedPut(ctx, p, hc, "bucket", obj, time.Minute, &sign, &put)
+	if sign.Err != "" || put.Err != "" {
+		t.Fatalf("put failed: %q, %q", sign.Err, put.Err)
+	}
+	if sign.Duration() < delay {
+		t.Errorf("signing took %v, want >= %v", sign.Duration(), delay)
+	}
+	if put.Start.Before(sign.End) {
+		t.Error("transfer started before signing ended")
+	}
+	if put.Size != obj.Size {
+		t.Errorf("put size %d, want %d", put.Size, obj.Size)
+	}
+
+	var get Operation
+	sign = Operation{}
+	presignedGet(ctx, p, hc, "bucket", *obj, time.Minute, &sign, &get)
+	if sign.Err != "" || get.Err != "" {
+		t.Fatalf("get failed: %q, %q", sign.Err, get.Err)
+	}
+	if get.FirstByte == nil || get.Size != obj.Size || get.Start.Before(sign.End) {
+		t.Errorf("unexpected get op: %+v", get)
+	}
+
+	// Content must round-trip.
+	u, _ := p.PresignedGetObject(ctx, "bucket", obj.Name, time.Minute, nil)
+	resp, err := hc.Get(u.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !bytes.Equal(got, content) {
+		t.Errorf("got %q, want %q", got, content)
+	}
+
+	// Server errors are returned as S3 errors.
+	get, sign = Operation{}, Operation{}
+	presignedGet(ctx, p, hc, "bucket", generator.Object{Name: "missing", Size: 1}, time.Minute, &sign, &get)
+	if !strings.Contains(get.Err, "not found") {
+		t.Errorf("missing object: got error %q", get.Err)
+	}
+	put, sign = Operation{}, Operation{}
+	obj.Reader = bytes.NewReader(content)
+	presignedPut(ctx, p, hc, "bucket", obj, time.Hour, &sign, &put)
+	if !strings.Contains(put.Err, "bad token") {
+		t.Errorf("wrong URL: got error %q", put.Err)
+	}
+	// Size mismatch.
+	get, sign = Operation{}, Operation{}
+	presignedGet(ctx, p, hc, "bucket", generator.Object{Name: "dir/obj", Size: 100}, time.Minute, &sign, &get)
+	if !strings.Contains(get.Err, "unexpected download size") {
+		t.Errorf("size mismatch: got error %q", get.Err)
+	}
+
+	// Signing errors fail both operations.
+	put, sign = Operation{}, Operation{}
+	presignedPut(ctx, fakePresigner{err: errors.New("no credentials")}, hc, "bucket", obj, time.Minute, &sign, &put)
+	if sign.Err == "" || put.Err == "" {
+		t.Errorf("signing error not reported: %q, %q", sign.Err, put.Err)
+	}
+}
+
+func TestPresignedErrorResponse(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusServiceUnavailable,
+		Status:     "503 Service Unavailable",
+		Body:       io.NopCloser(strings.NewReader(`<Error><Code>SlowDown</Code><Message>Please reduce your request rate.</Message></Error>`)),
+	}
+	err := presignedResponseErr(resp, "bucket", "obj")
+	if got := minio.ToErrorResponse(err); got.Code != "SlowDown" || got.StatusCode != 503 || got.Key != "obj" {
+		t.Errorf("got %+v", got)
+	}
+	if got := (ErrorClassifier{}).Classify(err); got != ErrCatThrottled {
+		t.Errorf("classified as %v, want %v", got, ErrCatThrottled)
+	}
+	resp = &http.Response{StatusCode: http.StatusBadGateway, Status: "502 Bad Gateway", Body: io.NopCloser(strings.NewReader("<html>"))}
+	if got := minio.ToErrorResponse(presignedResponseErr(resp, "bucket", "obj")); got.StatusCode != 502 || got.Code == "" {
+		t.Errorf("non-XML error: got %+v", got)
+	}
+}
+
+func TestPresignedBenchmark(t *testing.T) {
+	srv := newPresignServer(t)
+	u, _ := url.Parse(srv.URL)
+	cl, err := minio.New(u.Host, &minio.Options{Region: "us-east-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := generator.NewFn(generator.WithRandomData().Apply(), generator.WithSize(1<<10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &Presigned{
+		Common: Common{
+			Source:      src,
+			Bucket:      "bucket",
+			Concurrency: 2,
+			Client:      func() (*minio.Client, func()) { return cl, func() {} },
+			Error:       func(data ...any) { t.Log(data...) },
+		},
+		Expires:   time.Minute,
+		Presigner: fakePresigner{base: srv.URL},
+	}
+	ops, err := RunFor(context.Background(), b, 100*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := make(map[string]int)
+	for _, op := range ops {
+		if op.Err != "" {
+			t.Fatalf("%s error: %s", op.OpType, op.Err)
+		}
+		counts[op.OpType]++
+	}
+	if counts[http.MethodPut] == 0 || counts[presignOpType] != counts[http.MethodPut] || len(counts) != 2 {
+		t.Errorf("got ops %v, want one PRESIGN per PUT", counts)
+	}
+}
@@ -26,18 +26,75 @@ import (
 	"mime/multipart"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
 	"github.com/minio/warp/pkg/generator"
 )
 
 // Put benchmarks upload speed.
+// Common.Buckets is supported.
 type Put struct {
 	Common
 	PostObject bool
-	prefixes   map[string]struct{}
+
+	// BandwidthLimit limits each upload to this many bytes per second, if > 0.
+	BandwidthLimit int64
+
+	// Attrs, if set, provides content type and metadata for each object.
+	Attrs *AttrsSource
+
+	// VerifyETag compares the MD5 of each single part upload to the returned ETag.
+	// Mismatches are reported as integrity errors.
+	VerifyETag bool
+
+	// ReadAfterWrite, if set, reads each object back after it has been uploaded.
+	// The read is not part of the measured operation.
+	ReadAfterWrite *ReadAfterWrite
+
+	// ContentMD5 hashes each object before it is uploaded and sends the
+	// MD5 in the Content-MD5 header. Uploads are always a single PUT.
+	// The hashing is not part of the measured operation, see PrehashStats.
+	ContentMD5 bool
+
+	// Stream uploads each object from a generator.FixedLengthStreamReader
+	// of the object size instead of the generated content.
+	// The client cannot seek the content, but sends the size as Content-Length.
+	Stream bool
+
+	// ReaderPool, if set, provides the content of every upload instead of the object source.
+	// A reader is acquired before each upload and released after it,
+	// so the number of content buffers is bounded by the pool capacity.
+	// Only use it with static data, since the pool repeats the static pattern.
+	ReaderPool *generator.ReaderPool
+
+	prefixes map[string]struct{}
+	// locked are the uploads with Object Lock by bucket, released before cleanup.
+	locked     map[string]*ObjectSet
 	cl         *http.Client
+	prehashed  atomic.Int64
+	prehashDur atomic.Int64
+}
+
+// PrehashStats is the time spent hashing objects for Content-MD5.
+type PrehashStats struct {
+	Objects int64
+	Total   time.Duration
+}
+
+// String returns the number of objects hashed and the average time per object.
+func (s PrehashStats) String() string {
+	if s.Objects == 0 {
+		return "no objects hashed"
+	}
+	return fmt.Sprintf("%d objects hashed in %v, %v/object", s.Objects, s.Total.Round(time.Millisecond), s.Total/time.Duration(s.Objects))
+}
+
+// PrehashStats returns the time spent computing Content-MD5 so far.
+func (u *Put) PrehashStats() PrehashStats {
+	return PrehashStats{Objects: u.prehashed.Load(), Total: time.Duration(u.prehashDur.Load())}
 }
 
 // Prepare will create an empty bucket or delete any content already there.
@@ -60,6 +117,10 @@ func (u *Put) Start(ctx context.Context, wait chan struct{}) error {
 		ctx = c.AutoTerm(ctx, http.MethodPut, u.AutoTermScale, autoTermCheck, autoTermSamples, u.AutoTermDur)
 	}
 	u.prefixes = make(map[string]struct{}, u.Concurrency)
+	u.locked = make(map[string]*ObjectSet)
+	for _, bucket := range u.BucketNames() {
+		u.locked[bucket] = NewObjectSet()
+	}
 
 	// Non-terminating context.
 	nonTerm := context.Background()
@@ -68,6 +129,7 @@ func (u *Put) Start(ctx context.Context, wait chan struct{}) error {
 		src := u.Source()
 		u.prefixes[src.Prefix()] = struct{}{}
 		go func(i int) {
+			getClient := u.workerClient(i)
 			rcv := c.Receiver()
 			defer wg.Done()
 
@@ -81,6 +143,9 @@ func (u *Put) Start(ctx context.Context, wait chan struct{}) error {
 			done := ctx.Done()
 
 			<-wait
+			if u.RampUp.Wait(ctx, i) != nil {
+				return
+			}
 			for {
 				select {
 				case <-done:
@@ -88,13 +153,39 @@ func (u *Put) Start(ctx context.Context, wait chan struct{}) error {
 				default:
 				}
 
+				if u.ThinkTime.Wait(ctx) != nil {
+					return
+				}
 				if u.rpsLimit(ctx) != nil {
 					return
 				}
 
 				obj := src.Object()
-				opts.ContentType = obj.ContentType
-				client, cldone := u.Client()
+				var pooled *generator.PooledReader
+				if u.ReaderPool != nil {
+					var err error
+					if pooled, err = u.ReaderPool.Acquire(ctx, obj.Size); err != nil {
+						return
+					}
+					obj.Reader = pooled
+				}
+				var contentMD5 string
+				if u.ContentMD5 {
+					start := time.Now()
+					sum, err := generator.PrecomputeChecksum(obj.Reader, generator.ChecksumMD5)
+					if err != nil {
+						u.Error("content md5: ", err)
+						u.ReaderPool.Release(pooled)
+						continue
+					}
+					u.prehashDur.Add(int64(time.Since(start)))
+					u.prehashed.Add(1)
+					contentMD5 = sum
+				}
+				obj.Reader = generator.NewThrottledReader(ctx, obj.Reader, u.BandwidthLimit)
+				attrs := u.Attrs.Next()
+				bucket := u.nextBucket()
+				client, cldone := getClient()
 				op := Operation{
 					OpType:   http.MethodPut,
 					Thread:   uint32(i),
@@ -103,16 +194,43 @@ func (u *Put) Start(ctx context.Context, wait chan struct{}) error {
 					File:     obj.Name,
 					Endpoint: client.EndpointURL().String(),
 				}
+				if attrs.Locked() {
+					op.Categories = NewCategories(CatLocked)
+				}
 
 				op.Start = time.Now()
 				var err error
 				var res minio.UploadInfo
 				if !u.PostObject {
-					res, err = client.PutObject(nonTerm, u.Bucket, obj.Name, obj.Reader, obj.Size, opts)
+					err = u.Retry.DoOp(ctx, &op, func() error {
+						if _, err := obj.Reader.Seek(0, io.SeekStart); err != nil {
+							return err
+						}
+						return u.withOpTimeout(nonTerm, func(ctx context.Context) (err error) {
+							var putter ObjectPutter = client
+							if u.Stream {
+								res, err = putStream(ctx, putter, bucket, obj, attrs, opts)
+								return err
+							}
+							if u.ContentMD5 {
+								putter = contentMD5Putter{c: minioObjectClient{Client: client}, md5Base64: contentMD5}
+							}
+							res, err = putObjectVerified(ctx, putter, bucket, obj, attrs, opts, u.VerifyETag)
+							return err
+						})
+					})
 				} else {
 					op.OpType = http.MethodPost
 					var verID string
-					verID, err = u.postPolicy(ctx, client, u.Bucket, obj)
+					err = u.Retry.DoOp(ctx, &op, func() error {
+						if _, err := obj.Reader.Seek(0, io.SeekStart); err != nil {
+							return err
+						}
+						return u.withOpTimeout(ctx, func(ctx context.Context) (err error) {
+							verID, err = u.postPolicy(ctx, client, bucket, obj, attrs.SSE)
+							return err
+						})
+					})
 					if err == nil {
 						res.Size = obj.Size
 						res.VersionID = verID
@@ -120,10 +238,20 @@ func (u *Put) Start(ctx context.Context, wait chan struct{}) error {
 				}
 				op.End = time.Now()
 				if err != nil {
-					u.Error("upload error: ", err)
-					op.Err = err.Error()
+					op.SetErr(err)
+					u.Error("upload error: ", op.Err)
 				}
+				u.ReaderPool.Release(pooled)
 				obj.VersionID = res.VersionID
+				if err == nil && attrs.Locked() {
+					obj.Locked = true
+					u.locked[bucket].Add(*obj)
+				}
+				if err == nil {
+					if err := u.ReadAfterWrite.Check(nonTerm, NewObjectClient(client), bucket, obj.Name, attrs.GetOptions(minio.GetObjectOptions{ServerSideEncryption: opts.ServerSideEncryption}), op.End); err != nil {
+						u.Error("read after write: ", err)
+					}
+				}
 
 				if res.Size != obj.Size && op.Err == "" {
 					err := fmt.Sprint("short upload. want:", obj.Size, ", got:", res.Size)
@@ -142,8 +270,26 @@ func (u *Put) Start(ctx context.Context, wait chan struct{}) error {
 	return nil
 }
 
+// spreadsBuckets implements bucketSpreader.
+func (u *Put) spreadsBuckets() {}
+
+// timesOutOps implements opTimeouter.
+func (u *Put) timesOutOps() {}
+
+// retriesOps implements opRetrier.
+func (u *Put) retriesOps() {}
+
 // Cleanup deletes everything uploaded to the bucket.
+// Legal holds are removed first. Objects with COMPLIANCE retention
+// cannot be deleted and are reported.
 func (u *Put) Cleanup(ctx context.Context) {
+	cl, done := u.Client()
+	for bucket, set := range u.locked {
+		if compliance := releaseLocks(ctx, cl, bucket, set.Locked()); len(compliance) > 0 {
+			u.Error(fmt.Sprintf("%d objects in %s have COMPLIANCE retention and cannot be deleted, for example %s", len(compliance), bucket, compliance[0].Name))
+		}
+	}
+	done()
 	pf := make([]string, 0, len(u.prefixes))
 	for p := range u.prefixes {
 		pf = append(pf, p)
@@ -152,9 +298,12 @@ func (u *Put) Cleanup(ctx context.Context) {
 }
 
 // postPolicy will upload using https://docs.aws.amazon.com/AmazonS3/latest/API/RESTObjectPOST.html API.
-func (u *Put) postPolicy(ctx context.Context, c *minio.Client, bucket string, obj *generator.Object) (versionID string, err error) {
+func (u *Put) postPolicy(ctx context.Context, c *minio.Client, bucket string, obj *generator.Object, sse encrypt.ServerSide) (versionID string, err error) {
 	pp := minio.NewPostPolicy()
-	pp.SetEncryption(u.PutOpts.ServerSideEncryption)
+	if sse == nil {
+		sse = u.PutOpts.ServerSideEncryption
+	}
+	pp.SetEncryption(sse)
 	err = errors.Join(
 		pp.SetContentType(obj.ContentType),
 		pp.SetBucket(bucket),
@@ -192,7 +341,7 @@ func (u *Put) postPolicy(ctx context.Context, c *minio.Client, bucket string, ob
 		pw.CloseWithError(writer.Close())
 	}()
 
-	req, err := http.NewRequest(http.MethodPost, url.String(), pr)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url.String(), pr)
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,470 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/warp/pkg/generator"
+)
+
+func TestPutBuckets(t *testing.T) {
+	var mu sync.Mutex
+	perBucket := make(map[string]int64)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		if r.Method == http.MethodPut {
+			bucket, _, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+			mu.Lock()
+			perBucket[bucket]++
+			mu.Unlock()
+		}
+		w.Header().Set("ETag", `"d41d8cd98f00b204e9800998ecf8427e"`)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	cl, err := minio.New(strings.TrimPrefix(srv.URL, "http://"), &minio.Options{
+		Creds:        credentials.NewStaticV4("access", "secret", ""),
+		Region:       "us-east-1",
+		BucketLookup: minio.BucketLookupPath,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := generator.NewFn(generator.WithRandomData().Apply(), generator.WithSize(1<<10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sel, err := generator.NewBucketSelector([]generator.BucketWeight{{Name: "hot", Weight: 3}, {Name: "cold", Weight: 1}}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &Put{Common: Common{
+		Source:      src,
+		Bucket:      "unused",
+		Buckets:     sel,
+		Concurrency: 4,
+		Client:      func() (*minio.Client, func()) { return cl, func() {} },
+		Error:       func(data ...any) { t.Log(data...) },
+	}}
+	ops, err := RunFor(context.Background(), b, 200*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ops) < 100 {
+		t.Fatalf("only %d operations", len(ops))
+	}
+
+	counts := sel.Counts()
+	mu.Lock()
+	defer mu.Unlock()
+	if perBucket["unused"] != 0 || counts["hot"]+counts["cold"] != int64(len(ops)) {
+		t.Errorf("got %d operations, selector counts %v, server counts %v", len(ops), counts, perBucket)
+	}
+	for _, bucket := range []string{"hot", "cold"} {
+		if counts[bucket] != perBucket[bucket] {
+			t.Errorf("bucket %s: selected %d times, server received %d uploads", bucket, counts[bucket], perBucket[bucket])
+		}
+	}
+	if frac := float64(counts["hot"]) / float64(len(ops)); frac < 0.65 || frac > 0.85 {
+		t.Errorf("hot bucket picked %.2f of the time, want about 0.75", frac)
+	}
+
+	// Other benchmarks do not silently ignore the buckets.
+	get := &Get{Common: b.Common, objects: generator.Objects{{Name: "a"}}}
+	if _, err := RunFor(context.Background(), get, 10*time.Millisecond); err == nil {
+		t.Error("GET with multiple buckets: expected error")
+	}
+}
+
+func TestPutGetZeroSize(t *testing.T) {
+	var mu sync.Mutex
+	stored := make(map[string][]byte)
+	var badLength int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			// Bodies are sent with a streaming signature, check the decoded length.
+			io.Copy(io.Discard, r.Body)
+			if r.Header.Get("X-Amz-Decoded-Content-Length") != "0" {
+				badLength++
+			}
+			stored[r.URL.Path] = nil
+			w.Header().Set("ETag", fmt.Sprintf(`"%x"`, md5Sum(nil)))
+		case http.MethodGet:
+			body, ok := stored[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+			w.Header().Set("ETag", fmt.Sprintf(`"%x"`, md5Sum(body)))
+			w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+			w.WriteHeader(http.StatusOK)
+			w.Write(body)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	cl, err := minio.New(strings.TrimPrefix(srv.URL, "http://"), &minio.Options{
+		Creds:        credentials.NewStaticV4("access", "secret", ""),
+		Region:       "us-east-1",
+		BucketLookup: minio.BucketLookupPath,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := generator.NewFn(generator.WithRandomData().Apply(), generator.WithSize(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	common := Common{
+		Source:      src,
+		Bucket:      "bucket",
+		Concurrency: 2,
+		Client:      func() (*minio.Client, func()) { return cl, func() {} },
+		Error:       func(data ...any) { t.Error(data...) },
+	}
+	puts, err := RunFor(context.Background(), &Put{Common: common, VerifyETag: true}, 200*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(puts) == 0 {
+		t.Fatal("no uploads")
+	}
+	var objs generator.Objects
+	for _, op := range puts {
+		if op.Err != "" || op.Size != 0 {
+			t.Fatalf("upload: size %d, error %q", op.Size, op.Err)
+		}
+		objs = append(objs, generator.Object{Name: op.File, Size: 0})
+	}
+	mu.Lock()
+	if badLength > 0 || len(stored) != len(puts) {
+		t.Errorf("server stored %d objects for %d uploads, %d with non-zero Content-Length", len(stored), len(puts), badLength)
+	}
+	mu.Unlock()
+
+	gets, err := RunFor(context.Background(), &Get{Common: common, objects: objs}, 200*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gets) == 0 {
+		t.Fatal("no downloads")
+	}
+	for _, op := range gets {
+		if op.Err != "" || op.Size != 0 {
+			t.Fatalf("download: size %d, error %q", op.Size, op.Err)
+		}
+		if op.FirstByte == nil || op.FirstByte.Before(op.Start) || op.FirstByte.After(op.End) {
+			t.Fatalf("download: first byte %v not within %v -> %v", op.FirstByte, op.Start, op.End)
+		}
+	}
+}
+
+func TestPutContentMD5(t *testing.T) {
+	var mu sync.Mutex
+	var match, mismatch int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		sum := md5Sum(body)
+		mu.Lock()
+		if r.Header.Get("Content-Md5") == base64.StdEncoding.EncodeToString(sum) && len(body) == 1<<10 {
+			match++
+		} else {
+			mismatch++
+		}
+		mu.Unlock()
+		w.Header().Set("ETag", fmt.Sprintf(`"%x"`, sum))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	cl, err := minio.New(strings.TrimPrefix(srv.URL, "http://"), &minio.Options{
+		Creds:        credentials.NewStaticV4("access", "secret", ""),
+		Region:       "us-east-1",
+		BucketLookup: minio.BucketLookupPath,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := generator.NewFn(generator.WithRandomData().Apply(), generator.WithSize(1<<10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &Put{
+		Common: Common{
+			Source:      src,
+			Bucket:      "bucket",
+			Concurrency: 2,
+			Client:      func() (*minio.Client, func()) { return cl, func() {} },
+			Error:       func(data ...any) { t.Error(data...) },
+			// Send the body as is, so the server can hash it.
+			PutOpts: minio.PutObjectOptions{DisableContentSha256: true},
+		},
+		ContentMD5: true,
+		VerifyETag: true,
+	}
+	ops, err := RunFor(context.Background(), b, 200*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ops) == 0 {
+		t.Fatal("no uploads")
+	}
+	mu.Lock()
+	if mismatch > 0 || match != len(ops) {
+		t.Errorf("%d uploads: %d with matching Content-MD5, %d without", len(ops), match, mismatch)
+	}
+	mu.Unlock()
+	if s := b.PrehashStats(); s.Objects < int64(len(ops)) || s.Total <= 0 {
+		t.Errorf("prehash stats %v for %d uploads", s, len(ops))
+	}
+
+	// The in-memory client rejects a wrong digest.
+	mem := NewMemClient("bucket")
+	body := []byte("content")
+	sum := base64.StdEncoding.EncodeToString(md5Sum(body))
+	if _, err := mem.PutObjectContentMD5(context.Background(), "bucket", "ok", bytes.NewReader(body), int64(len(body)), sum, minio.PutObjectOptions{}); err != nil {
+		t.Error(err)
+	}
+	if _, err := mem.PutObjectContentMD5(context.Background(), "bucket", "bad", bytes.NewReader(body[1:]), int64(len(body)-1), sum, minio.PutObjectOptions{}); minio.ToErrorResponse(err).Code != "BadDigest" {
+		t.Errorf("wrong digest: got %v", err)
+	}
+}
+
+func TestPutStream(t *testing.T) {
+	// Objects above the 16MiB part size are also sent in a single PUT.
+	for _, size := range []int64{100<<10 + 13, 17 << 20} {
+		t.Run(strconv.FormatInt(size, 10), func(t *testing.T) {
+			testPutStream(t, size)
+		})
+	}
+}
+
+func testPutStream(t *testing.T, size int64) {
+	var mu sync.Mutex
+	var ok, bad int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, _ := io.Copy(io.Discard, r.Body)
+		mu.Lock()
+		if r.ContentLength == size && len(r.TransferEncoding) == 0 && n == size {
+			ok++
+		} else {
+			bad++
+			t.Errorf("Content-Length %d, transfer encoding %v, got %d bytes", r.ContentLength, r.TransferEncoding, n)
+		}
+		mu.Unlock()
+		w.Header().Set("ETag", `"d41d8cd98f00b204e9800998ecf8427e"`)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	cl, err := minio.New(strings.TrimPrefix(srv.URL, "http://"), &minio.Options{
+		Creds:        credentials.NewStaticV4("access", "secret", ""),
+		Region:       "us-east-1",
+		BucketLookup: minio.BucketLookupPath,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := generator.NewFn(generator.WithRandomData().Apply(), generator.WithSize(size))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &Put{
+		Common: Common{
+			Source:      src,
+			Bucket:      "bucket",
+			Concurrency: 2,
+			Client:      func() (*minio.Client, func()) { return cl, func() {} },
+			Error:       func(data ...any) { t.Error(data...) },
+			// Send the body as is, so the server sees the content length.
+			PutOpts: minio.PutObjectOptions{DisableContentSha256: true},
+		},
+		Stream: true,
+	}
+	ops, err := RunFor(context.Background(), b, 200*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ops) == 0 {
+		t.Fatal("no uploads")
+	}
+	for _, op := range ops {
+		if op.Err != "" || op.Size != size {
+			t.Fatalf("upload: size %d, error %q", op.Size, op.Err)
+		}
+	}
+	mu.Lock()
+	if bad > 0 || ok != len(ops) {
+		t.Errorf("%d uploads: %d with Content-Length, %d without", len(ops), ok, bad)
+	}
+	mu.Unlock()
+}
+
+func TestPutLockedCleanup(t *testing.T) {
+	var mu sync.Mutex
+	var locked, released int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		q := r.URL.Query()
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == http.MethodPut && q.Has("legal-hold"):
+			if bytes.Contains(body, []byte("OFF")) {
+				released++
+			}
+		case r.Method == http.MethodPut:
+			if r.Header.Get("X-Amz-Object-Lock-Mode") == "COMPLIANCE" {
+				locked++
+			}
+			w.Header().Set("ETag", fmt.Sprintf(`"%x"`, md5Sum(body)))
+		case q.Has("retention"):
+			until := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+			fmt.Fprintf(w, "<Retention><Mode>COMPLIANCE</Mode><RetainUntilDate>%s</RetainUntilDate></Retention>", until)
+		case q.Has("list-type"):
+			fmt.Fprint(w, "<ListBucketResult></ListBucketResult>")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	cl, err := minio.New(strings.TrimPrefix(srv.URL, "http://"), &minio.Options{
+		Creds:        credentials.NewStaticV4("access", "secret", ""),
+		Region:       "us-east-1",
+		BucketLookup: minio.BucketLookupPath,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := generator.NewFn(generator.WithRandomData().Apply(), generator.WithSize(1<<10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var errs []string
+	b := &Put{
+		Common: Common{
+			Source:      src,
+			Bucket:      "bucket",
+			Concurrency: 2,
+			Client:      func() (*minio.Client, func()) { return cl, func() {} },
+			Error: func(data ...any) {
+				mu.Lock()
+				errs = append(errs, fmt.Sprint(data...))
+				mu.Unlock()
+			},
+			UpdateStatus: func(string) {},
+			PutOpts:      minio.PutObjectOptions{DisableContentSha256: true},
+		},
+		Attrs: &AttrsSource{LockMode: minio.Compliance, RetainFor: time.Hour, LegalHold: true},
+	}
+	ops, err := RunFor(context.Background(), b, 100*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var uploaded int
+	for _, op := range ops {
+		if op.Err == "" {
+			uploaded++
+		}
+	}
+	// Locked uploads are flagged in the object set of the bucket.
+	if uploaded == 0 || len(b.locked["bucket"].Locked()) != uploaded {
+		t.Fatalf("%d uploads, %d locked objects", uploaded, len(b.locked["bucket"].Locked()))
+	}
+	mu.Lock()
+	errs = nil
+	mu.Unlock()
+
+	b.Cleanup(context.Background())
+	mu.Lock()
+	defer mu.Unlock()
+	if locked < uploaded || released != uploaded {
+		t.Errorf("%d uploads, %d with retention, %d legal holds released", uploaded, locked, released)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0], "COMPLIANCE") {
+		t.Errorf("got errors %q, want a COMPLIANCE warning", errs)
+	}
+}
+
+func TestPutReaderPool(t *testing.T) {
+	const size = 200 << 10
+	var bad atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !bytes.Equal(body, generator.PatternBytesAt(0, 0, size)) {
+			bad.Add(1)
+		}
+		w.Header().Set("ETag", fmt.Sprintf(`"%x"`, md5Sum(body)))
+	}))
+	defer srv.Close()
+	cl, err := minio.New(strings.TrimPrefix(srv.URL, "http://"), &minio.Options{
+		Creds:        credentials.NewStaticV4("access", "secret", ""),
+		Region:       "us-east-1",
+		BucketLookup: minio.BucketLookupPath,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := generator.NewFn(generator.WithRandomData().Apply(), generator.WithSize(size), generator.WithStaticData(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool, err := generator.NewReaderPool(4, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &Put{
+		Common: Common{
+			Source:      src,
+			Bucket:      "bucket",
+			Concurrency: 4,
+			Client:      func() (*minio.Client, func()) { return cl, func() {} },
+			Error:       func(data ...any) {},
+			PutOpts:     minio.PutObjectOptions{DisableContentSha256: true},
+		},
+		ReaderPool: pool,
+	}
+	ops, err := RunFor(context.Background(), b, 200*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ops) < 4 || bad.Load() != 0 {
+		t.Fatalf("%d uploads, %d with unexpected content", len(ops), bad.Load())
+	}
+	if pool.Created() > pool.Cap() || pool.InUse() != 0 {
+		t.Errorf("%d readers created, %d in use", pool.Created(), pool.InUse())
+	}
+}
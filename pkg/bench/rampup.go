@@ -0,0 +1,84 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+// RampUp staggers the start of benchmark workers over a window,
+// so connections are not all set up at the same moment.
+// The window is split into one slot per worker, and each worker starts
+// at a random point within a slot. Slots are assigned to workers in random order.
+// A nil *RampUp starts all workers immediately.
+// It is safe for concurrent use.
+type RampUp struct {
+	window time.Duration
+	delays []time.Duration
+}
+
+// NewRampUp returns a ramp-up that releases workers over window.
+func NewRampUp(window time.Duration, workers int, seed int64) (*RampUp, error) {
+	if window < 0 {
+		return nil, errors.New("NewRampUp: window must be >= 0")
+	}
+	if workers <= 0 {
+		return nil, errors.New("NewRampUp: workers must be > 0")
+	}
+	rng := rand.New(rand.NewSource(seed))
+	slot := window / time.Duration(workers)
+	delays := make([]time.Duration, workers)
+	for i := range delays {
+		delays[i] = time.Duration(i) * slot
+		if slot > 0 {
+			delays[i] += time.Duration(rng.Int63n(int64(slot)))
+		}
+	}
+	rng.Shuffle(len(delays), func(i, j int) { delays[i], delays[j] = delays[j], delays[i] })
+	return &RampUp{window: window, delays: delays}, nil
+}
+
+// Window returns the duration over which workers are started.
+func (r *RampUp) Window() time.Duration {
+	if r == nil {
+		return 0
+	}
+	return r.window
+}
+
+// Delay returns how long worker should wait after the benchmark starts.
+// Workers beyond the number given to NewRampUp reuse the delays of the first workers.
+func (r *RampUp) Delay(worker int) time.Duration {
+	if r == nil || worker < 0 {
+		return 0
+	}
+	return r.delays[worker%len(r.delays)]
+}
+
+// Wait pauses for the delay of worker.
+// It returns early with the context error if ctx is canceled.
+func (r *RampUp) Wait(ctx context.Context, worker int) error {
+	d := r.Delay(worker)
+	if d <= 0 || sleepCtx(ctx, d) {
+		return nil
+	}
+	return ctx.Err()
+}
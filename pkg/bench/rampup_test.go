@@ -0,0 +1,129 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRampUpDelays(t *testing.T) {
+	const workers = 100
+	const window = 10 * time.Second
+	r, err := NewRampUp(window, workers, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	delays := make([]time.Duration, workers)
+	for i := range delays {
+		delays[i] = r.Delay(i)
+		if delays[i] < 0 || delays[i] >= window {
+			t.Fatalf("worker %d: delay %v outside [0, %v)", i, delays[i], window)
+		}
+	}
+	// Each tenth of the window must hold a tenth of the workers.
+	buckets := make([]int, 10)
+	for _, d := range delays {
+		buckets[d*10/window]++
+	}
+	for i, n := range buckets {
+		if n != workers/10 {
+			t.Errorf("window part %d: got %d workers, want %d", i, n, workers/10)
+		}
+	}
+	// Workers must not start in index order.
+	if slices.IsSorted(delays) {
+		t.Error("delays are sorted by worker index")
+	}
+	r2, _ := NewRampUp(window, workers, 1)
+	for i := range delays {
+		if r2.Delay(i) != delays[i] {
+			t.Fatalf("worker %d: same seed gave %v and %v", i, delays[i], r2.Delay(i))
+		}
+	}
+
+	var nilRamp *RampUp
+	if nilRamp.Delay(5) != 0 || nilRamp.Wait(context.Background(), 5) != nil {
+		t.Error("nil ramp-up should not delay")
+	}
+	if _, err := NewRampUp(time.Second, 0, 1); err == nil {
+		t.Error("expected error for 0 workers")
+	}
+	if _, err := NewRampUp(-time.Second, 1, 1); err == nil {
+		t.Error("expected error for negative window")
+	}
+}
+
+func TestRampUpWait(t *testing.T) {
+	const workers = 20
+	const window = 200 * time.Millisecond
+	r, err := NewRampUp(window, workers, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var started []time.Duration
+	start := time.Now()
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := r.Wait(context.Background(), i); err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			started = append(started, time.Since(start))
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	if len(started) != workers {
+		t.Fatalf("%d of %d workers started", len(started), workers)
+	}
+	slices.Sort(started)
+	// The first worker starts early in the window, the last one late.
+	if started[0] > window/4 {
+		t.Errorf("first worker started after %v", started[0])
+	}
+	if last := started[workers-1]; last < window*3/4 {
+		t.Errorf("last worker started after %v, want close to %v", last, window)
+	}
+	// Half of the workers start in each half of the window.
+	early := 0
+	for _, d := range started {
+		if d < window/2 {
+			early++
+		}
+	}
+	if early < workers/4 || early > workers*3/4 {
+		t.Errorf("%d of %d workers started in the first half of the window", early, workers)
+	}
+
+	// Canceling returns without waiting.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	long, _ := NewRampUp(time.Hour, 1, 1)
+	if err := long.Wait(ctx, 0); err == nil {
+		t.Error("expected context error")
+	}
+}
@@ -0,0 +1,65 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimiter is a token bucket limiting the number of operations per second.
+// A nil RateLimiter does not limit.
+type RateLimiter struct {
+	l *rate.Limiter
+}
+
+// NewRateLimiter returns a limiter allowing opsPerSec operations per second
+// with bursts of up to burst operations.
+// If opsPerSec <= 0 nil is returned, meaning unlimited.
+func NewRateLimiter(opsPerSec float64, burst int) *RateLimiter {
+	if opsPerSec <= 0 {
+		return nil
+	}
+	return &RateLimiter{l: rate.NewLimiter(rate.Limit(opsPerSec), max(burst, 1))}
+}
+
+// Wait blocks until an operation is allowed or ctx is canceled.
+func (r *RateLimiter) Wait(ctx context.Context) error {
+	if r == nil {
+		return nil
+	}
+	return r.l.Wait(ctx)
+}
+
+// Limit returns the allowed operations per second, or 0 if unlimited.
+func (r *RateLimiter) Limit() float64 {
+	if r == nil {
+		return 0
+	}
+	return float64(r.l.Limit())
+}
+
+// SetLimit changes the allowed operations per second.
+// It has no effect on a nil RateLimiter.
+func (r *RateLimiter) SetLimit(opsPerSec float64) {
+	if r == nil {
+		return
+	}
+	r.l.SetLimit(rate.Limit(opsPerSec))
+}
@@ -0,0 +1,66 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	if NewRateLimiter(0, 1) != nil || NewRateLimiter(-1, 1) != nil {
+		t.Fatal("expected nil limiter for rate <= 0")
+	}
+	var unlimited *RateLimiter
+	if err := unlimited.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	const rps = 200
+	l := NewRateLimiter(rps, 1)
+	ctx := context.Background()
+	start := time.Now()
+	const n = 100
+	for i := 0; i < n; i++ {
+		if err := l.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// First token is available immediately.
+	got := float64(n-1) / time.Since(start).Seconds()
+	if got > rps*1.1 || got < rps*0.7 {
+		t.Errorf("achieved %.1f ops/s, want ~%d", got, rps)
+	}
+
+	// Cancellation must interrupt a wait.
+	slow := NewRateLimiter(0.01, 1)
+	slow.Wait(ctx)
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	start = time.Now()
+	if err := slow.Wait(ctx); err == nil {
+		t.Fatal("expected error on canceled wait")
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("Wait returned after %v", d)
+	}
+}
@@ -0,0 +1,157 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// ReadAfterWrite reads objects back right after they are written
+// to measure how often they are not yet visible.
+// A nil *ReadAfterWrite does not check.
+// It is safe for concurrent use.
+type ReadAfterWrite struct {
+	// UseGet reads the object with GET instead of HEAD.
+	UseGet bool
+
+	// Retries is the number of reads after the first that did not find the object.
+	Retries int
+
+	// Backoff is the delay before the first retry.
+	// The delay is doubled for each following retry.
+	Backoff time.Duration
+
+	checks, misses, recovered, notVisible atomic.Int64
+	wait, maxWait                         atomic.Int64
+}
+
+// ReadAfterWriteStats contains the results of read after write checks.
+type ReadAfterWriteStats struct {
+	// Checks is the number of objects checked.
+	Checks int64
+	// Misses is the number of objects that were not visible on the first read.
+	Misses int64
+	// Recovered is the number of misses that became visible on a retry.
+	Recovered int64
+	// NotVisible is the number of misses that did not become visible.
+	NotVisible int64
+
+	// MeanVisibility and MaxVisibility is the time from the write
+	// until recovered objects were visible.
+	MeanVisibility, MaxVisibility time.Duration
+}
+
+// String returns a human readable summary.
+func (s ReadAfterWriteStats) String() string {
+	if s.Checks == 0 {
+		return "no objects checked"
+	}
+	return fmt.Sprintf("%d objects checked, %d not visible on first read (%.2f%%), %d visible after retry, %d never visible. Time to visibility, mean: %v, max: %v",
+		s.Checks, s.Misses, 100*float64(s.Misses)/float64(s.Checks), s.Recovered, s.NotVisible, s.MeanVisibility, s.MaxVisibility)
+}
+
+// Check reads object until it is visible, retrying as configured.
+// written is the time the write completed.
+// An error is returned if the object is not visible after all retries
+// or a read fails for another reason than the object not being found,
+// including the bucket not being found.
+func (r *ReadAfterWrite) Check(ctx context.Context, client ObjectClient, bucket, object string, opts minio.GetObjectOptions, written time.Time) error {
+	if r == nil {
+		return nil
+	}
+	r.checks.Add(1)
+	delay := r.Backoff
+	for attempt := 0; ; attempt++ {
+		found, err := r.read(ctx, client, bucket, object, opts)
+		if err != nil {
+			return err
+		}
+		if found {
+			if attempt > 0 {
+				r.recovered.Add(1)
+				d := int64(time.Since(written))
+				r.wait.Add(d)
+				for {
+					m := r.maxWait.Load()
+					if d <= m || r.maxWait.CompareAndSwap(m, d) {
+						break
+					}
+				}
+			}
+			return nil
+		}
+		if attempt == 0 {
+			r.misses.Add(1)
+		}
+		if attempt >= r.Retries {
+			r.notVisible.Add(1)
+			return fmt.Errorf("object %s/%s not visible after %d reads", bucket, object, attempt+1)
+		}
+		if !sleepCtx(ctx, delay) {
+			return ctx.Err()
+		}
+		delay *= 2
+	}
+}
+
+// read returns whether the object was found.
+func (r *ReadAfterWrite) read(ctx context.Context, client ObjectClient, bucket, object string, opts minio.GetObjectOptions) (bool, error) {
+	var err error
+	if r.UseGet {
+		var rc io.ReadCloser
+		rc, _, err = client.GetObject(ctx, bucket, object, opts)
+		if err == nil {
+			_, err = io.Copy(io.Discard, rc)
+			rc.Close()
+		}
+	} else {
+		_, err = client.StatObject(ctx, bucket, object, opts)
+	}
+	if err != nil {
+		if resp := minio.ToErrorResponse(err); resp.StatusCode == http.StatusNotFound && resp.Code != "NoSuchBucket" {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Stats returns the results of all checks so far.
+func (r *ReadAfterWrite) Stats() ReadAfterWriteStats {
+	if r == nil {
+		return ReadAfterWriteStats{}
+	}
+	s := ReadAfterWriteStats{
+		Checks:        r.checks.Load(),
+		Misses:        r.misses.Load(),
+		Recovered:     r.recovered.Load(),
+		NotVisible:    r.notVisible.Load(),
+		MaxVisibility: time.Duration(r.maxWait.Load()),
+	}
+	if s.Recovered > 0 {
+		s.MeanVisibility = time.Duration(r.wait.Load() / s.Recovered)
+	}
+	return s
+}
@@ -0,0 +1,128 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// lazyClient hides each object for the first hidden reads after it is written.
+type lazyClient struct {
+	*MemClient
+	hidden int
+
+	mu    sync.Mutex
+	reads map[string]int
+}
+
+func (l *lazyClient) visible(object string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.reads[object]++
+	return l.reads[object] > l.hidden
+}
+
+func (l *lazyClient) StatObject(ctx context.Context, bucket, object string, opts minio.StatObjectOptions) (minio.ObjectInfo, error) {
+	if !l.visible(object) {
+		return minio.ObjectInfo{}, memError(http.StatusNotFound, "NoSuchKey", bucket, object)
+	}
+	return l.MemClient.StatObject(ctx, bucket, object, opts)
+}
+
+func (l *lazyClient) GetObject(ctx context.Context, bucket, object string, opts minio.GetObjectOptions) (io.ReadCloser, minio.ObjectInfo, error) {
+	if !l.visible(object) {
+		return nil, minio.ObjectInfo{}, memError(http.StatusNotFound, "NoSuchKey", bucket, object)
+	}
+	return l.MemClient.GetObject(ctx, bucket, object, opts)
+}
+
+func TestReadAfterWrite(t *testing.T) {
+	ctx := context.Background()
+	body := []byte("visible")
+	check := func(useGet bool, hidden, retries int, objects ...string) (*ReadAfterWrite, []error) {
+		c := &lazyClient{MemClient: NewMemClient("bucket"), hidden: hidden, reads: make(map[string]int)}
+		r := &ReadAfterWrite{UseGet: useGet, Retries: retries, Backoff: time.Millisecond}
+		var errs []error
+		for _, obj := range objects {
+			if _, err := c.PutObject(ctx, "bucket", obj, bytes.NewReader(body), int64(len(body)), minio.PutObjectOptions{}); err != nil {
+				t.Fatal(err)
+			}
+			errs = append(errs, r.Check(ctx, c, "bucket", obj, minio.GetObjectOptions{}, time.Now()))
+		}
+		return r, errs
+	}
+
+	// Immediately visible.
+	r, errs := check(false, 0, 3, "a", "b")
+	if errs[0] != nil || errs[1] != nil {
+		t.Fatal(errs)
+	}
+	if s := r.Stats(); s.Checks != 2 || s.Misses != 0 || s.Recovered != 0 || s.MaxVisibility != 0 {
+		t.Errorf("unexpected stats: %+v", s)
+	}
+
+	// Visible on the third read, with HEAD and GET.
+	for _, useGet := range []bool{false, true} {
+		r, errs = check(useGet, 2, 3, "a", "b", "c")
+		for _, err := range errs {
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		s := r.Stats()
+		if s.Checks != 3 || s.Misses != 3 || s.Recovered != 3 || s.NotVisible != 0 {
+			t.Errorf("get %v: unexpected stats: %+v", useGet, s)
+		}
+		// Retries wait 1ms and 2ms.
+		if s.MeanVisibility < 3*time.Millisecond || s.MaxVisibility < s.MeanVisibility {
+			t.Errorf("get %v: unexpected visibility times: %+v", useGet, s)
+		}
+	}
+
+	// Never visible within the retries.
+	r, errs = check(false, 5, 2, "a")
+	if errs[0] == nil {
+		t.Fatal("expected error")
+	}
+	if s := r.Stats(); s.Checks != 1 || s.Misses != 1 || s.Recovered != 0 || s.NotVisible != 1 {
+		t.Errorf("unexpected stats: %+v", s)
+	}
+
+	// Other errors are returned without retrying.
+	r = &ReadAfterWrite{Retries: 3}
+	if err := r.Check(ctx, NewMemClient(), "missing-bucket", "a", minio.GetObjectOptions{}, time.Now()); minio.ToErrorResponse(err).Code != "NoSuchBucket" {
+		t.Errorf("got %v, want NoSuchBucket", err)
+	}
+	if s := r.Stats(); s.Misses != 0 {
+		t.Errorf("unexpected stats: %+v", s)
+	}
+
+	// nil does not check.
+	var none *ReadAfterWrite
+	if err := none.Check(ctx, nil, "bucket", "a", minio.GetObjectOptions{}, time.Now()); err != nil || none.Stats().Checks != 0 {
+		t.Error("nil ReadAfterWrite checked")
+	}
+}
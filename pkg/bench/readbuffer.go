@@ -0,0 +1,68 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"errors"
+	"io"
+	"sync"
+)
+
+// ReadBuffer reads response bodies in large chunks.
+// io.Copy to io.Discard reads 8KB at a time, which can limit the throughput of
+// large GETs by the number of reads. Reading into a larger buffer lets the
+// connection return more data per read.
+// Buffers are reused between operations. It is safe for concurrent use.
+// A nil *ReadBuffer copies with io.Copy.
+type ReadBuffer struct {
+	size int
+	pool sync.Pool
+}
+
+// NewReadBuffer returns a ReadBuffer that reads size bytes at a time.
+func NewReadBuffer(size int) (*ReadBuffer, error) {
+	if size <= 0 {
+		return nil, errors.New("NewReadBuffer: size must be > 0")
+	}
+	b := &ReadBuffer{size: size}
+	b.pool.New = func() any {
+		buf := make([]byte, size)
+		return &buf
+	}
+	return b, nil
+}
+
+// Size returns the buffer size.
+func (b *ReadBuffer) Size() int {
+	if b == nil {
+		return 0
+	}
+	return b.size
+}
+
+// Copy copies r to w until EOF, reading at most Size bytes at a time.
+// The number of bytes copied is returned.
+func (b *ReadBuffer) Copy(w io.Writer, r io.Reader) (int64, error) {
+	if b == nil {
+		return io.Copy(w, r)
+	}
+	buf := b.pool.Get().(*[]byte)
+	defer b.pool.Put(buf)
+	// Hide io.ReaderFrom and io.WriterTo, so the buffer is used.
+	return io.CopyBuffer(struct{ io.Writer }{w}, struct{ io.Reader }{r}, *buf)
+}
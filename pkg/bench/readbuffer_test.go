@@ -0,0 +1,179 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/warp/pkg/generator"
+)
+
+// readSizeTransport records the largest read of a response body.
+type readSizeTransport struct {
+	maxRead atomic.Int64
+}
+
+func (t *readSizeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err == nil {
+		resp.Body = &readSizeBody{ReadCloser: resp.Body, t: t}
+	}
+	return resp, err
+}
+
+type readSizeBody struct {
+	io.ReadCloser
+	t *readSizeTransport
+}
+
+func (b *readSizeBody) Read(p []byte) (int, error) {
+	for {
+		old := b.t.maxRead.Load()
+		if int64(len(p)) <= old || b.t.maxRead.CompareAndSwap(old, int64(len(p))) {
+			break
+		}
+	}
+	return b.ReadCloser.Read(p)
+}
+
+// newLargeObjectServer serves body for every GET, like an S3 server.
+func newLargeObjectServer(tb testing.TB, body []byte) (*minio.Client, *readSizeTransport) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.Header().Set("ETag", fmt.Sprintf(`"%x"`, md5Sum(body)))
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.WriteHeader(http.StatusOK)
+		w.Write(body)
+	}))
+	tb.Cleanup(srv.Close)
+	tr := &readSizeTransport{}
+	cl, err := minio.New(strings.TrimPrefix(srv.URL, "http://"), &minio.Options{
+		Creds:        credentials.NewStaticV4("access", "secret", ""),
+		Region:       "us-east-1",
+		BucketLookup: minio.BucketLookupPath,
+		Transport:    tr,
+	})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return cl, tr
+}
+
+func TestReadBuffer(t *testing.T) {
+	body := generator.PatternBytesAt(1000, 0, 8<<20+123)
+	cl, tr := newLargeObjectServer(t, body)
+	const size = 256 << 10
+	b, err := NewReadBuffer(size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	for _, buf := range []*ReadBuffer{b, nil} {
+		tr.maxRead.Store(0)
+		o, err := cl.GetObject(ctx, "bucket", "large", minio.GetObjectOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		h := md5.New()
+		fbr := firstByteRecorder{r: o}
+		n, err := buf.Copy(h, &fbr)
+		o.Close()
+		if err != nil || n != int64(len(body)) {
+			t.Fatalf("buffer %d: copied %d of %d bytes, err %v", buf.Size(), n, len(body), err)
+		}
+		if !bytes.Equal(h.Sum(nil), md5Sum(body)) {
+			t.Errorf("buffer %d: content mismatch", buf.Size())
+		}
+		if fbr.t == nil {
+			t.Errorf("buffer %d: first byte not recorded", buf.Size())
+		}
+		switch got := tr.maxRead.Load(); {
+		case buf != nil && got != size:
+			t.Errorf("largest read %d, want buffer size %d", got, size)
+		case buf == nil && got >= size:
+			t.Errorf("largest read %d without buffer", got)
+		}
+	}
+
+	// The GET benchmark reads whole objects with the buffer.
+	tr.maxRead.Store(0)
+	common := Common{
+		Bucket:      "bucket",
+		Concurrency: 2,
+		Client:      func() (*minio.Client, func()) { return cl, func() {} },
+		Error:       func(data ...any) { t.Error(data...) },
+	}
+	objs := generator.Objects{{Name: "large", Size: int64(len(body))}}
+	ops, err := RunFor(ctx, &Get{Common: common, objects: objs, ReadBuffer: b}, 200*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ops) == 0 {
+		t.Fatal("no downloads")
+	}
+	for _, op := range ops {
+		if op.Err != "" || op.Size != int64(len(body)) {
+			t.Fatalf("download: size %d, error %q", op.Size, op.Err)
+		}
+	}
+	if got := tr.maxRead.Load(); got != size {
+		t.Errorf("benchmark largest read %d, want %d", got, size)
+	}
+
+	if _, err := NewReadBuffer(0); err == nil {
+		t.Error("expected error for empty buffer")
+	}
+}
+
+// BenchmarkReadBuffer compares GET throughput with and without a read buffer.
+func BenchmarkReadBuffer(b *testing.B) {
+	body := generator.PatternBytesAt(1000, 0, 32<<20)
+	cl, _ := newLargeObjectServer(b, body)
+	for _, size := range []int{0, 64 << 10, 1 << 20} {
+		var buf *ReadBuffer
+		if size > 0 {
+			buf, _ = NewReadBuffer(size)
+		}
+		b.Run("size="+strconv.Itoa(size), func(b *testing.B) {
+			b.SetBytes(int64(len(body)))
+			for b.Loop() {
+				o, err := cl.GetObject(context.Background(), "bucket", "large", minio.GetObjectOptions{})
+				if err != nil {
+					b.Fatal(err)
+				}
+				if _, err := buf.Copy(io.Discard, o); err != nil {
+					b.Fatal(err)
+				}
+				o.Close()
+			}
+		})
+	}
+}
@@ -0,0 +1,259 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression is the compression applied to a report.
+type Compression uint8
+
+const (
+	// CompressNone writes uncompressed output.
+	CompressNone Compression = iota
+
+	// CompressGzip writes gzip compressed output.
+	CompressGzip
+
+	// CompressZstd writes zstandard compressed output.
+	CompressZstd
+)
+
+// String returns the name of the compression.
+func (c Compression) String() string {
+	switch c {
+	case CompressNone:
+		return "none"
+	case CompressGzip:
+		return "gzip"
+	case CompressZstd:
+		return "zstd"
+	}
+	return fmt.Sprintf("Compression(%d)", int(c))
+}
+
+// ParseCompression parses a compression name.
+// "none", "gzip" and "zstd" are accepted. An empty string means none.
+func ParseCompression(s string) (Compression, error) {
+	switch strings.ToLower(s) {
+	case "", "none":
+		return CompressNone, nil
+	case "gzip", "gz":
+		return CompressGzip, nil
+	case "zstd", "zst":
+		return CompressZstd, nil
+	}
+	return CompressNone, fmt.Errorf("unknown compression: %q", s)
+}
+
+// ReportFormat is the format of operations written to a report.
+type ReportFormat uint8
+
+const (
+	// ReportCSV writes tab separated values in the format read by OperationsFromCSV.
+	ReportCSV ReportFormat = iota
+
+	// ReportJSON writes one JSON object per operation and line.
+	ReportJSON
+)
+
+// String returns the name of the format.
+func (f ReportFormat) String() string {
+	switch f {
+	case ReportCSV:
+		return "csv"
+	case ReportJSON:
+		return "json"
+	}
+	return fmt.Sprintf("ReportFormat(%d)", int(f))
+}
+
+// ReportFormatFromPath returns the compression and format selected by the extension of path.
+// For example "ops.csv.zst" is zstd compressed CSV, and "ops.json.gz" is gzip compressed JSON.
+// Unknown extensions are uncompressed CSV.
+func ReportFormatFromPath(path string) (ReportFormat, Compression) {
+	comp := CompressNone
+	switch {
+	case strings.HasSuffix(path, ".gz"):
+		comp = CompressGzip
+		path = strings.TrimSuffix(path, ".gz")
+	case strings.HasSuffix(path, ".zst"), strings.HasSuffix(path, ".zstd"):
+		comp = CompressZstd
+		path = strings.TrimSuffix(strings.TrimSuffix(path, ".zst"), ".zstd")
+	}
+	if strings.HasSuffix(path, ".json") || strings.HasSuffix(path, ".jsonl") {
+		return ReportJSON, comp
+	}
+	return ReportCSV, comp
+}
+
+// errReportClosed is returned when writing to a closed ReportWriter.
+var errReportClosed = errors.New("report writer is closed")
+
+// ReportWriter writes operations to a report as they are added.
+// Output is buffered and compressed in a streaming fashion, so memory use
+// does not grow with the number of operations.
+// Close must be called to flush and finalize the output.
+// It is safe for concurrent use.
+type ReportWriter struct {
+	mu     sync.Mutex
+	format ReportFormat
+	bw     *bufio.Writer
+	enc    *json.Encoder
+	comp   io.WriteCloser
+	file   io.Closer
+	n      int
+	err    error
+}
+
+// NewReportWriter returns a writer that writes operations to w.
+// w is not closed by Close.
+func NewReportWriter(w io.Writer, format ReportFormat, comp Compression) (*ReportWriter, error) {
+	r := ReportWriter{format: format}
+	switch comp {
+	case CompressNone:
+	case CompressGzip:
+		r.comp = gzip.NewWriter(w)
+	case CompressZstd:
+		enc, err := zstd.NewWriter(w, zstd.WithEncoderLevel(zstd.SpeedBetterCompression))
+		if err != nil {
+			return nil, err
+		}
+		r.comp = enc
+	default:
+		return nil, fmt.Errorf("unknown compression: %v", comp)
+	}
+	if r.comp != nil {
+		w = r.comp
+	}
+	r.bw = bufio.NewWriter(w)
+	switch format {
+	case ReportCSV:
+		_, r.err = r.bw.WriteString(csvHeader)
+	case ReportJSON:
+		r.enc = json.NewEncoder(r.bw)
+	default:
+		return nil, fmt.Errorf("unknown report format: %v", format)
+	}
+	return &r, r.err
+}
+
+// CreateReport creates the file at path and returns a writer for it.
+// The format and compression are selected by the extension, see ReportFormatFromPath.
+// The file is closed by Close.
+func CreateReport(path string) (*ReportWriter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	format, comp := ReportFormatFromPath(path)
+	r, err := NewReportWriter(f, format, comp)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	r.file = f
+	return r, nil
+}
+
+// Write adds an operation to the report.
+// After an error all further writes return the same error.
+func (r *ReportWriter) Write(op Operation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	switch r.format {
+	case ReportCSV:
+		r.err = op.WriteCSV(r.bw, r.n)
+	case ReportJSON:
+		r.err = r.enc.Encode(op)
+	}
+	r.n++
+	return r.err
+}
+
+// WriteFrom writes all operations received on ops until it is closed.
+// Operations are still drained after an error, so senders are not blocked.
+func (r *ReportWriter) WriteFrom(ops <-chan Operation) error {
+	var err error
+	for op := range ops {
+		if err == nil {
+			err = r.Write(op)
+		}
+	}
+	return err
+}
+
+// Count returns the number of operations written.
+func (r *ReportWriter) Count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.n
+}
+
+// WriteComment writes comment to CSV output, each line prefixed with '# '.
+// Comments are ignored by OperationsFromCSV.
+// JSON output does not contain comments, so nothing is written.
+func (r *ReportWriter) WriteComment(comment string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil || r.format != ReportCSV || comment == "" {
+		return r.err
+	}
+	for txt := range strings.SplitSeq(comment, "\n") {
+		if _, r.err = r.bw.WriteString("# " + txt + "\n"); r.err != nil {
+			break
+		}
+	}
+	return r.err
+}
+
+// Close flushes all buffered data and finalizes the compressed stream.
+// The first error encountered while writing is returned.
+func (r *ReportWriter) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err == errReportClosed {
+		return r.err
+	}
+	err := r.err
+	if err == nil {
+		err = r.bw.Flush()
+	}
+	if r.comp != nil {
+		err = errors.Join(err, r.comp.Close())
+	}
+	if r.file != nil {
+		err = errors.Join(err, r.file.Close())
+	}
+	r.err = errReportClosed
+	return err
+}
@@ -0,0 +1,177 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestReportWriter(t *testing.T) {
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	ops := make(Operations, 500)
+	for i := range ops {
+		ops[i] = Operation{
+			OpType:   http.MethodPut,
+			Thread:   uint32(i % 8),
+			Size:     int64(i * 100),
+			ObjPerOp: 1,
+			File:     "obj/" + strings.Repeat("x", i%10),
+			Endpoint: "http://127.0.0.1:9000",
+			Start:    start.Add(time.Duration(i) * time.Millisecond),
+			End:      start.Add(time.Duration(i+5) * time.Millisecond),
+		}
+		if i%50 == 0 {
+			ops[i].Err = "injected\terror"
+		}
+	}
+	decompress := func(t *testing.T, comp Compression, b []byte) io.Reader {
+		switch comp {
+		case CompressGzip:
+			r, err := gzip.NewReader(bytes.NewReader(b))
+			if err != nil {
+				t.Fatal(err)
+			}
+			return r
+		case CompressZstd:
+			r, err := zstd.NewReader(bytes.NewReader(b))
+			if err != nil {
+				t.Fatal(err)
+			}
+			return r
+		}
+		return bytes.NewReader(b)
+	}
+	for _, format := range []ReportFormat{ReportCSV, ReportJSON} {
+		for _, comp := range []Compression{CompressNone, CompressGzip, CompressZstd} {
+			t.Run(format.String()+"-"+comp.String(), func(t *testing.T) {
+				var buf bytes.Buffer
+				w, err := NewReportWriter(&buf, format, comp)
+				if err != nil {
+					t.Fatal(err)
+				}
+				ch := make(chan Operation)
+				done := make(chan error)
+				go func() { done <- w.WriteFrom(ch) }()
+				for _, op := range ops {
+					ch <- op
+				}
+				close(ch)
+				if err := <-done; err != nil {
+					t.Fatal(err)
+				}
+				if err := w.WriteComment("warp put\n--duration=1m"); err != nil {
+					t.Fatal(err)
+				}
+				if err := w.Close(); err != nil {
+					t.Fatal(err)
+				}
+				if err := w.Write(ops[0]); !errors.Is(err, errReportClosed) {
+					t.Errorf("write after close: %v", err)
+				}
+				if w.Count() != len(ops) {
+					t.Errorf("count %d, want %d", w.Count(), len(ops))
+				}
+
+				var got Operations
+				r := decompress(t, comp, buf.Bytes())
+				if format == ReportCSV {
+					got, err = OperationsFromCSV(r, false, 0, 0, t.Logf)
+					if err != nil {
+						t.Fatal(err)
+					}
+				} else {
+					dec := json.NewDecoder(r)
+					for {
+						var op Operation
+						if err := dec.Decode(&op); err == io.EOF {
+							break
+						} else if err != nil {
+							t.Fatal(err)
+						}
+						got = append(got, op)
+					}
+				}
+				if len(got) != len(ops) {
+					t.Fatalf("read %d operations, want %d", len(got), len(ops))
+				}
+				for i, op := range got {
+					want := ops[i]
+					if op.OpType != want.OpType || op.Size != want.Size || op.File != want.File || op.Err != want.Err ||
+						op.Thread != want.Thread || !op.Start.Equal(want.Start) || !op.End.Equal(want.End) {
+						t.Fatalf("operation %d: got %+v, want %+v", i, op, want)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestCreateReport(t *testing.T) {
+	tests := map[string]struct {
+		format ReportFormat
+		comp   Compression
+	}{
+		"ops.csv":      {ReportCSV, CompressNone},
+		"ops.csv.zst":  {ReportCSV, CompressZstd},
+		"ops.json.gz":  {ReportJSON, CompressGzip},
+		"ops.jsonl":    {ReportJSON, CompressNone},
+		"ops.json.zst": {ReportJSON, CompressZstd},
+		"ops.gz":       {ReportCSV, CompressGzip},
+	}
+	dir := t.TempDir()
+	for name, want := range tests {
+		format, comp := ReportFormatFromPath(name)
+		if format != want.format || comp != want.comp {
+			t.Errorf("%s: got %v/%v, want %v/%v", name, format, comp, want.format, want.comp)
+		}
+		path := filepath.Join(dir, name)
+		w, err := CreateReport(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Write(Operation{OpType: http.MethodGet, Start: time.Now(), End: time.Now()}); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if st, err := os.Stat(path); err != nil || st.Size() == 0 {
+			t.Errorf("%s: no output written: %v", name, err)
+		}
+	}
+	for _, s := range []string{"", "none", "gzip", "zstd"} {
+		if _, err := ParseCompression(s); err != nil {
+			t.Errorf("ParseCompression(%q): %v", s, err)
+		}
+	}
+	if _, err := ParseCompression("lz4"); err == nil {
+		t.Error("expected error for unknown compression")
+	}
+}
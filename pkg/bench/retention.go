@@ -153,6 +153,7 @@ func (g *Retention) Start(ctx context.Context, wait chan struct{}) error {
 
 	for i := 0; i < g.Concurrency; i++ {
 		go func(i int) {
+			getClient := g.workerClient(i)
 			rng := rand.New(rand.NewSource(int64(i)))
 			rcv := c.Receiver()
 			defer wg.Done()
@@ -160,6 +161,9 @@ func (g *Retention) Start(ctx context.Context, wait chan struct{}) error {
 			var opts minio.PutObjectRetentionOptions
 
 			<-wait
+			if g.RampUp.Wait(ctx, i) != nil {
+				return
+			}
 			mode := minio.Governance
 			for {
 				select {
@@ -168,12 +172,15 @@ func (g *Retention) Start(ctx context.Context, wait chan struct{}) error {
 				default:
 				}
 
+				if g.ThinkTime.Wait(ctx) != nil {
+					return
+				}
 				if g.rpsLimit(ctx) != nil {
 					return
 				}
 
 				obj := g.objects[rng.Intn(len(g.objects))]
-				client, cldone := g.Client()
+				client, cldone := getClient()
 				op := Operation{
 					OpType:   "RETENTION",
 					Thread:   uint32(i),
@@ -192,7 +199,7 @@ func (g *Retention) Start(ctx context.Context, wait chan struct{}) error {
 				err := client.PutObjectRetention(nonTerm, g.Bucket, obj.Name, opts)
 				if err != nil {
 					g.Error("put retention error:", err)
-					op.Err = err.Error()
+					op.SetErr(err)
 					op.End = time.Now()
 					rcv <- op
 					cldone()
@@ -0,0 +1,136 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+)
+
+// opRetrier is implemented by benchmarks that use Common.Retry.
+type opRetrier interface {
+	retriesOps()
+}
+
+// Retrier retries operations that fail with transient errors.
+// A nil *Retrier will run operations once.
+type Retrier struct {
+	// MaxAttempts is the maximum number of attempts, including the first.
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry.
+	// The delay is doubled for each following retry.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the delay between attempts.
+	MaxDelay time.Duration
+
+	// Jitter is the fraction of the delay that is randomized, 0 to 1.
+	Jitter float64
+
+	// Classifier is used to categorize errors.
+	Classifier ErrorClassifier
+
+	retries atomic.Int64
+}
+
+// NewRetrier returns a Retrier that retries a failed operation up to retries times.
+// If retries is 0 or less, nil is returned.
+func NewRetrier(retries int, baseDelay, maxDelay time.Duration, jitter float64) *Retrier {
+	if retries <= 0 {
+		return nil
+	}
+	return &Retrier{
+		MaxAttempts: retries + 1,
+		BaseDelay:   baseDelay,
+		MaxDelay:    maxDelay,
+		Jitter:      min(max(jitter, 0), 1),
+	}
+}
+
+// Transient returns whether err should be retried.
+// Throttling, server errors and timeouts are considered transient.
+func (r *Retrier) Transient(err error) bool {
+	switch r.Classifier.Classify(err) {
+	case ErrCatThrottled, ErrCatServer, ErrCatTimeout:
+		return true
+	}
+	return false
+}
+
+// Do calls fn until it succeeds, returns a non-transient error,
+// MaxAttempts is reached or ctx is canceled.
+// The last error returned by fn is returned.
+func (r *Retrier) Do(ctx context.Context, fn func() error) error {
+	_, err := r.do(ctx, fn)
+	return err
+}
+
+// DoOp is like Do, but adds the number of retries to op.Retries.
+func (r *Retrier) DoOp(ctx context.Context, op *Operation, fn func() error) error {
+	n, err := r.do(ctx, fn)
+	op.Retries += n
+	return err
+}
+
+func (r *Retrier) do(ctx context.Context, fn func() error) (retries int, err error) {
+	err = fn()
+	if r == nil {
+		return 0, err
+	}
+	for attempt := 1; err != nil && attempt < r.MaxAttempts && r.Transient(err); attempt++ {
+		t := time.NewTimer(r.delay(attempt))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return retries, err
+		case <-t.C:
+		}
+		r.retries.Add(1)
+		retries++
+		err = fn()
+	}
+	return retries, err
+}
+
+// Retries returns the number of retries performed.
+func (r *Retrier) Retries() int64 {
+	if r == nil {
+		return 0
+	}
+	return r.retries.Load()
+}
+
+// delay returns the delay before the specified retry, starting at 1.
+func (r *Retrier) delay(retry int) time.Duration {
+	d := r.BaseDelay
+	for i := 1; i < retry && (r.MaxDelay <= 0 || d < r.MaxDelay); i++ {
+		d *= 2
+	}
+	if r.MaxDelay > 0 && d > r.MaxDelay {
+		d = r.MaxDelay
+	}
+	if r.Jitter > 0 && d > 0 {
+		// Randomize the last Jitter fraction of the delay.
+		j := time.Duration(float64(d) * r.Jitter)
+		d = d - j + rand.N(j+1)
+	}
+	return d
+}
@@ -0,0 +1,198 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/warp/pkg/generator"
+)
+
+func TestRetrier(t *testing.T) {
+	slowDown := minio.ErrorResponse{StatusCode: 503, Code: "SlowDown"}
+	notFound := minio.ErrorResponse{StatusCode: 404, Code: "NoSuchKey"}
+
+	// failN returns an operation that fails n times with err then succeeds.
+	failN := func(n int, err error) (fn func() error, calls *int) {
+		calls = new(int)
+		return func() error {
+			*calls++
+			if *calls <= n {
+				return err
+			}
+			return nil
+		}, calls
+	}
+
+	tests := []struct {
+		name        string
+		maxAttempts int
+		failures    int
+		err         error
+		wantCalls   int
+		wantErr     bool
+	}{
+		{name: "success", maxAttempts: 3, failures: 0, err: slowDown, wantCalls: 1},
+		{name: "recovers", maxAttempts: 5, failures: 3, err: slowDown, wantCalls: 4},
+		{name: "exhausted", maxAttempts: 3, failures: 5, err: slowDown, wantCalls: 3, wantErr: true},
+		{name: "non-transient", maxAttempts: 5, failures: 3, err: notFound, wantCalls: 1, wantErr: true},
+		{name: "timeout", maxAttempts: 5, failures: 1, err: context.DeadlineExceeded, wantCalls: 2},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewRetrier(test.maxAttempts-1, time.Millisecond, 4*time.Millisecond, 0.5)
+			fn, calls := failN(test.failures, test.err)
+			var op Operation
+			err := r.DoOp(context.Background(), &op, fn)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error: %v", err, test.wantErr)
+			}
+			if *calls != test.wantCalls {
+				t.Errorf("got %d calls, want %d", *calls, test.wantCalls)
+			}
+			if got := r.Retries(); got != int64(test.wantCalls-1) || op.Retries != test.wantCalls-1 {
+				t.Errorf("got %d retries, %d on operation, want %d", got, op.Retries, test.wantCalls-1)
+			}
+		})
+	}
+
+	t.Run("retries", func(t *testing.T) {
+		if r := NewRetrier(0, time.Millisecond, time.Millisecond, 0); r != nil {
+			t.Errorf("0 retries: got %+v, want nil", r)
+		}
+		// 1 is a single retry.
+		r := NewRetrier(1, time.Millisecond, time.Millisecond, 0)
+		fn, calls := failN(5, slowDown)
+		if err := r.Do(context.Background(), fn); err == nil || *calls != 2 {
+			t.Errorf("1 retry: %d calls, error %v", *calls, err)
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		r := NewRetrier(10, time.Hour, time.Hour, 0)
+		ctx, cancel := context.WithCancel(context.Background())
+		fn, calls := failN(5, slowDown)
+		time.AfterFunc(10*time.Millisecond, cancel)
+		err := r.Do(ctx, fn)
+		if !errors.As(err, &minio.ErrorResponse{}) {
+			t.Errorf("got error %v, want last operation error", err)
+		}
+		if *calls != 1 {
+			t.Errorf("got %d calls, want 1", *calls)
+		}
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		var r *Retrier
+		fn, calls := failN(5, slowDown)
+		if err := r.Do(context.Background(), fn); err == nil {
+			t.Error("want error")
+		}
+		if *calls != 1 || r.Retries() != 0 {
+			t.Errorf("nil retrier: %d calls, %d retries", *calls, r.Retries())
+		}
+	})
+
+	t.Run("delay", func(t *testing.T) {
+		r := NewRetrier(10, 10*time.Millisecond, 50*time.Millisecond, 0)
+		want := []time.Duration{10, 20, 40, 50, 50}
+		for i, w := range want {
+			if got := r.delay(i + 1); got != w*time.Millisecond {
+				t.Errorf("retry %d: got delay %v, want %v", i+1, got, w*time.Millisecond)
+			}
+		}
+	})
+}
+
+func TestRetryBenchmarks(t *testing.T) {
+	// Every other request is throttled.
+	var requests atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1)%2 == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Length", "10")
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodGet {
+			w.Write(make([]byte, 10))
+		}
+	}))
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+	// Retries by the client would hide the failures.
+	cl, err := minio.New(u.Host, &minio.Options{Creds: credentials.NewStaticV4("access", "secret", ""), Region: "us-east-1", MaxRetries: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	objs := generator.Objects{{Name: "a", Size: 10}}
+	for _, b := range []Benchmark{&Get{objects: objs}, &Stat{objects: objs}} {
+		retry := NewRetrier(3, time.Millisecond, time.Millisecond, 0)
+		var errs atomic.Int64
+		*b.GetCommon() = Common{
+			Client:      func() (*minio.Client, func()) { return cl, func() {} },
+			Bucket:      "bucket",
+			Concurrency: 1,
+			Retry:       retry,
+			Error:       func(data ...any) { errs.Add(1) },
+		}
+		// Failed requests take about 200ms, since the client waits before giving up.
+		ops, err := RunFor(context.Background(), b, time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var retries int
+		for _, op := range ops {
+			retries += op.Retries
+		}
+		if len(ops) == 0 || retries == 0 || int64(retries) != retry.Retries() {
+			t.Errorf("%T: %d operations, %d retries, retrier counted %d", b, len(ops), retries, retry.Retries())
+		}
+		// Only the operation running when the benchmark ends is not retried.
+		if errs.Load() > 1 {
+			t.Errorf("%T: %d errors", b, errs.Load())
+		}
+	}
+}
+
+func TestRetryUnsupported(t *testing.T) {
+	retry := NewRetrier(2, time.Millisecond, time.Millisecond, 0)
+	for _, b := range []Benchmark{&Mixed{}, &Delete{}, &List{}} {
+		b.GetCommon().Retry = retry
+		if err := Validate(b); err == nil {
+			t.Errorf("%T accepted retries", b)
+		}
+	}
+	for _, b := range []Benchmark{&Get{}, &Put{}, &Stat{}} {
+		b.GetCommon().Retry = retry
+		if err := Validate(b); err != nil {
+			t.Error(err)
+		}
+	}
+}
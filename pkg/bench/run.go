@@ -0,0 +1,48 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// RunFor starts b and runs it for duration d, or until ctx is canceled.
+// Prepare must be called before RunFor.
+// Workers stop issuing new operations when the duration has elapsed,
+// while operations in flight are allowed to finish.
+// The operations collected until then are returned.
+// Any collector set on the benchmark is replaced.
+func RunFor(ctx context.Context, b Benchmark, d time.Duration) (Operations, error) {
+	if d <= 0 {
+		return nil, errors.New("RunFor: duration must be > 0")
+	}
+	if err := Validate(b); err != nil {
+		return nil, err
+	}
+	c := b.GetCommon()
+	collector, ops := NewOpsCollector(c.ExtraOut...)
+	c.Collector = collector
+	ctx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+	start := make(chan struct{})
+	close(start)
+	err := b.Start(ctx, start)
+	return ops(), err
+}
@@ -0,0 +1,92 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// sleepBench issues operations that each take opDur.
+type sleepBench struct {
+	Common
+	opDur time.Duration
+}
+
+func (s *sleepBench) Prepare(context.Context) error { return nil }
+
+func (s *sleepBench) Cleanup(context.Context) {}
+
+func (s *sleepBench) Start(ctx context.Context, wait chan struct{}) error {
+	var wg sync.WaitGroup
+	for i := 0; i < s.Concurrency; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			rcv := s.Collector.Receiver()
+			<-wait
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				default:
+				}
+				op := Operation{OpType: "SLEEP", Thread: uint32(i), ObjPerOp: 1, Start: time.Now()}
+				time.Sleep(s.opDur)
+				op.End = time.Now()
+				rcv <- op
+			}
+		}(i)
+	}
+	wg.Wait()
+	return nil
+}
+
+func TestRunFor(t *testing.T) {
+	b := &sleepBench{Common: Common{Concurrency: 4}, opDur: 10 * time.Millisecond}
+	const dur = 200 * time.Millisecond
+	start := time.Now()
+	ops, err := RunFor(context.Background(), b, dur)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < dur || elapsed > dur+100*time.Millisecond {
+		t.Errorf("run took %v, want ~%v", elapsed, dur)
+	}
+	// ~20 ops per thread.
+	if len(ops) < 40 || len(ops) > 100 {
+		t.Errorf("got %d operations, want ~80", len(ops))
+	}
+
+	// Cancel before the duration expires.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	ops, err = RunFor(ctx, b, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+		t.Errorf("canceled run took %v", elapsed)
+	}
+	if len(ops) == 0 {
+		t.Error("expected partial results")
+	}
+}
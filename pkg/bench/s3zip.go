@@ -135,6 +135,7 @@ func (g *S3Zip) Start(ctx context.Context, wait chan struct{}) error {
 
 	for i := 0; i < g.Concurrency; i++ {
 		go func(i int) {
+			getClient := g.workerClient(i)
 			rng := rand.New(rand.NewSource(int64(i)))
 			rcv := c.Receiver()
 			defer wg.Done()
@@ -142,6 +143,9 @@ func (g *S3Zip) Start(ctx context.Context, wait chan struct{}) error {
 			var opts minio.GetObjectOptions
 
 			<-wait
+			if g.RampUp.Wait(ctx, i) != nil {
+				return
+			}
 			for {
 				select {
 				case <-done:
@@ -149,13 +153,16 @@ func (g *S3Zip) Start(ctx context.Context, wait chan struct{}) error {
 				default:
 				}
 
+				if g.ThinkTime.Wait(ctx) != nil {
+					return
+				}
 				if g.rpsLimit(ctx) != nil {
 					return
 				}
 
 				fbr := firstByteRecorder{}
 				obj := g.objects[rng.Intn(len(g.objects))]
-				client, cldone := g.Client()
+				client, cldone := getClient()
 				op := Operation{
 					OpType:   "GET",
 					Thread:   uint32(i),
@@ -171,7 +178,7 @@ func (g *S3Zip) Start(ctx context.Context, wait chan struct{}) error {
 				o, err := client.GetObject(nonTerm, g.Bucket, op.File, opts)
 				if err != nil {
 					g.Error("download error:", err)
-					op.Err = err.Error()
+					op.SetErr(err)
 					op.End = time.Now()
 					rcv <- op
 					cldone()
@@ -181,7 +188,7 @@ func (g *S3Zip) Start(ctx context.Context, wait chan struct{}) error {
 				n, err := io.Copy(io.Discard, &fbr)
 				if err != nil {
 					g.Error("download error:", err)
-					op.Err = err.Error()
+					op.SetErr(err)
 				}
 				op.FirstByte = fbr.t
 				op.End = time.Now()
@@ -0,0 +1,305 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/warp/pkg/generator"
+)
+
+// OpSelect is the operation type of S3 Select requests.
+const OpSelect = "SELECT"
+
+// DefaultSelectQuery returns a quarter of the generated records.
+const DefaultSelectQuery = "SELECT * FROM S3Object s WHERE s.category = 'alpha'"
+
+// ObjectSelector runs S3 Select queries.
+// Use NewObjectSelector to adapt a *minio.Client.
+type ObjectSelector interface {
+	// SelectObjectContent returns the records matching the query.
+	// The caller must close the returned reader.
+	SelectObjectContent(ctx context.Context, bucket, object string, opts minio.SelectObjectOptions) (io.ReadCloser, error)
+}
+
+// NewObjectSelector returns an ObjectSelector that sends requests using c.
+func NewObjectSelector(c *minio.Client) ObjectSelector {
+	return minioObjectClient{Client: c}
+}
+
+// SelectObjectContent sends the query and returns the records as they are received.
+func (m minioObjectClient) SelectObjectContent(ctx context.Context, bucket, object string, opts minio.SelectObjectOptions) (io.ReadCloser, error) {
+	return m.Client.SelectObjectContent(ctx, bucket, object, opts)
+}
+
+// SelectOptions returns the options to query objects generated in format with query.
+// Records are returned in the same format, one per line.
+func SelectOptions(format, query string) minio.SelectObjectOptions {
+	opts := minio.SelectObjectOptions{
+		Expression:     query,
+		ExpressionType: minio.QueryExpressionTypeSQL,
+	}
+	opts.InputSerialization.CompressionType = minio.SelectCompressionNONE
+	if format == generator.RecordFormatJSON {
+		opts.InputSerialization.JSON = &minio.JSONInputOptions{Type: minio.JSONLinesType}
+		opts.OutputSerialization.JSON = &minio.JSONOutputOptions{RecordDelimiter: "\n"}
+		return opts
+	}
+	opts.InputSerialization.CSV = &minio.CSVInputOptions{
+		FileHeaderInfo:  minio.CSVFileHeaderInfoUse,
+		RecordDelimiter: "\n",
+		FieldDelimiter:  ",",
+	}
+	opts.OutputSerialization.CSV = &minio.CSVOutputOptions{
+		RecordDelimiter: "\n",
+		FieldDelimiter:  ",",
+	}
+	return opts
+}
+
+// selectObject runs a query on object and records the result in op.
+// op.Size is set to the number of bytes returned.
+// Returns the number of records returned, counted as lines.
+func selectObject(ctx context.Context, client ObjectSelector, bucket, object string, opts minio.SelectObjectOptions, op *Operation) (rows int64) {
+	op.Start = time.Now()
+	r, err := client.SelectObjectContent(ctx, bucket, object, opts)
+	if err != nil {
+		op.End = time.Now()
+		op.SetErr(err)
+		return 0
+	}
+	defer r.Close()
+	fbr := firstByteRecorder{r: r}
+	buf := make([]byte, 32<<10)
+	for {
+		n, err := fbr.Read(buf)
+		op.Size += int64(n)
+		rows += int64(bytes.Count(buf[:n], []byte{'\n'}))
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			op.SetErr(err)
+			break
+		}
+	}
+	op.FirstByte = fbr.t
+	op.End = time.Now()
+	return rows
+}
+
+// Select benchmarks S3 Select queries on objects with structured records.
+type Select struct {
+	Common
+
+	// Format of the records, generator.RecordFormatCSV or generator.RecordFormatJSON.
+	Format string
+
+	// Query is the SQL expression sent.
+	// If empty, DefaultSelectQuery is used.
+	Query string
+
+	CreateObjects int
+
+	objects  generator.Objects
+	selected atomic.Int64
+	queries  atomic.Int64
+}
+
+// SelectStats are the records returned by a Select benchmark.
+type SelectStats struct {
+	Queries, Records int64
+}
+
+// String returns the queries and the average records returned per query.
+func (s SelectStats) String() string {
+	if s.Queries == 0 {
+		return "no queries"
+	}
+	return fmt.Sprintf("%d queries, %d records returned, %.1f records/query",
+		s.Queries, s.Records, float64(s.Records)/float64(s.Queries))
+}
+
+// Stats returns the successful queries and records returned so far.
+func (g *Select) Stats() SelectStats {
+	return SelectStats{Queries: g.queries.Load(), Records: g.selected.Load()}
+}
+
+func (g *Select) query() string {
+	if g.Query == "" {
+		return DefaultSelectQuery
+	}
+	return g.Query
+}
+
+// Prepare will create an empty bucket or delete any content already there
+// and upload objects of records. Object names and sizes are taken from the source,
+// and sizes are rounded down to a whole number of records.
+func (g *Select) Prepare(ctx context.Context) error {
+	records, err := generator.NewRecordGenerator(g.Format)
+	if err != nil {
+		return err
+	}
+	if err := g.createEmptyBucket(ctx); err != nil {
+		return err
+	}
+	g.UpdateStatus(fmt.Sprint("Uploading ", g.CreateObjects, " ", g.Format, " objects"))
+
+	var wg sync.WaitGroup
+	wg.Add(g.Concurrency)
+	objs := splitObjs(g.CreateObjects, g.Concurrency)
+	rcv := g.Collector.Receiver()
+	var groupErr error
+	var mu sync.Mutex
+
+	for i, obj := range objs {
+		go func(i int, obj []struct{}) {
+			defer wg.Done()
+			src := g.Source()
+			opts := g.PutOpts
+			opts.ContentType = records.ContentType()
+
+			for range obj {
+				select {
+				case <-ctx.Done():
+					return
+				default:
+				}
+				if g.rpsLimit(ctx) != nil {
+					return
+				}
+
+				obj := src.Object()
+				rows := records.Rows(obj.Size)
+				obj.Size = records.Size(rows)
+				client, cldone := g.Client()
+				op := Operation{
+					OpType:   http.MethodPut,
+					Thread:   uint32(i),
+					Size:     obj.Size,
+					File:     obj.Name,
+					ObjPerOp: 1,
+					Endpoint: client.EndpointURL().String(),
+				}
+				op.Start = time.Now()
+				res, err := client.PutObject(ctx, g.Bucket, obj.Name, records.Reader(rows), obj.Size, opts)
+				op.End = time.Now()
+				cldone()
+				if err == nil && res.Size != obj.Size {
+					err = fmt.Errorf("short upload. want: %d, got %d", obj.Size, res.Size)
+				}
+				if err != nil {
+					err := fmt.Errorf("upload error: %w", err)
+					g.Error(err)
+					mu.Lock()
+					if groupErr == nil {
+						groupErr = err
+					}
+					mu.Unlock()
+					return
+				}
+				mu.Lock()
+				obj.Reader = nil
+				g.objects = append(g.objects, *obj)
+				g.prepareProgress(float64(len(g.objects)) / float64(g.CreateObjects))
+				mu.Unlock()
+				rcv <- op
+			}
+		}(i, obj)
+	}
+	wg.Wait()
+	return groupErr
+}
+
+// Start will execute the main benchmark.
+// Operations should begin executing when the start channel is closed.
+func (g *Select) Start(ctx context.Context, wait chan struct{}) error {
+	var wg sync.WaitGroup
+	wg.Add(g.Concurrency)
+	c := g.Collector
+	if g.AutoTermDur > 0 {
+		ctx = c.AutoTerm(ctx, OpSelect, g.AutoTermScale, autoTermCheck, autoTermSamples, g.AutoTermDur)
+	}
+	// Non-terminating context.
+	nonTerm := context.Background()
+	opts := SelectOptions(g.Format, g.query())
+
+	for i := 0; i < g.Concurrency; i++ {
+		go func(i int) {
+			getClient := g.workerClient(i)
+			rng := rand.New(rand.NewSource(int64(i)))
+			rcv := c.Receiver()
+			defer wg.Done()
+			done := ctx.Done()
+
+			<-wait
+			if g.RampUp.Wait(ctx, i) != nil {
+				return
+			}
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				if g.ThinkTime.Wait(ctx) != nil {
+					return
+				}
+				if g.rpsLimit(ctx) != nil {
+					return
+				}
+
+				obj := g.objects[rng.Intn(len(g.objects))]
+				client, cldone := getClient()
+				op := Operation{
+					OpType:   OpSelect,
+					Thread:   uint32(i),
+					File:     obj.Name,
+					ObjPerOp: 1,
+					Endpoint: client.EndpointURL().String(),
+				}
+				rows := selectObject(nonTerm, NewObjectSelector(client), g.Bucket, obj.Name, opts, &op)
+				cldone()
+				if op.Err != "" {
+					g.Error("select error:", op.Err)
+				} else {
+					g.queries.Add(1)
+					g.selected.Add(rows)
+				}
+				rcv <- op
+			}
+		}(i)
+	}
+	wg.Wait()
+	return nil
+}
+
+// Cleanup deletes everything uploaded to the bucket.
+func (g *Select) Cleanup(ctx context.Context) {
+	g.deleteAllInBucket(ctx, g.objects.Prefixes()...)
+}
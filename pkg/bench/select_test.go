@@ -0,0 +1,95 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/warp/pkg/generator"
+)
+
+func TestSelectObject(t *testing.T) {
+	ctx := context.Background()
+	const rows = 1001
+	for _, format := range []string{generator.RecordFormatCSV, generator.RecordFormatJSON} {
+		t.Run(format, func(t *testing.T) {
+			g, err := generator.NewRecordGenerator(format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			mc := NewMemClient("bucket")
+			if _, err := mc.PutObject(ctx, "bucket", "obj", g.Reader(rows), g.Size(rows), minio.PutObjectOptions{}); err != nil {
+				t.Fatal(err)
+			}
+			for _, tc := range []struct {
+				query string
+				want  int64
+			}{
+				{DefaultSelectQuery, generator.CountCategory(rows, "alpha")},
+				{"SELECT * FROM S3Object s WHERE s.category = 'gamma'", generator.CountCategory(rows, "gamma")},
+				{"select * from s3object", rows},
+				{"SELECT * FROM S3Object s WHERE s.id = 1000000007", 1},
+				{"SELECT * FROM S3Object s WHERE s.category = 'none'", 0},
+			} {
+				op := Operation{OpType: OpSelect}
+				got := selectObject(ctx, mc, "bucket", "obj", SelectOptions(format, tc.query), &op)
+				if op.Err != "" {
+					t.Fatalf("%s: %v", tc.query, op.Err)
+				}
+				if got != tc.want {
+					t.Errorf("%s: got %d records, want %d", tc.query, got, tc.want)
+				}
+				if tc.want > 0 && (op.Size == 0 || op.FirstByte == nil || op.End.Before(op.Start)) {
+					t.Errorf("%s: unexpected operation %+v", tc.query, op)
+				}
+			}
+
+			// Returned records are in the requested format.
+			r, err := mc.SelectObjectContent(ctx, "bucket", "obj", SelectOptions(format, "SELECT * FROM S3Object s WHERE s.id = 1000000007"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, _ := io.ReadAll(r)
+			r.Close()
+			want := generator.RecordAt(7)
+			if format == generator.RecordFormatCSV {
+				rec, err := csv.NewReader(bytes.NewReader(b)).Read()
+				if err != nil || rec[2] != want.Category || rec[1] != want.Name {
+					t.Errorf("got record %q (%v), want %+v", b, err, want)
+				}
+			} else {
+				var rec map[string]string
+				if err := json.Unmarshal(b, &rec); err != nil || rec["category"] != want.Category || rec["name"] != want.Name {
+					t.Errorf("got record %q (%v), want %+v", b, err, want)
+				}
+			}
+
+			op := Operation{OpType: OpSelect}
+			selectObject(ctx, mc, "bucket", "obj", SelectOptions(format, "SELECT name FROM S3Object"), &op)
+			if op.Err == "" {
+				t.Error("expected error for unsupported query")
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/warp/pkg/generator"
+)
+
+// DefaultSelfTestSizes are the object sizes uploaded by SelfTest if none are given.
+// They cover empty and single byte objects, sizes around the static pattern length
+// and an object large enough to be sent in multiple parts by some clients.
+var DefaultSelfTestSizes = []int64{0, 1, 4 << 10, 128<<10 + 1, 1<<20 + 7, 5 << 20}
+
+// SelfTestOptions configures SelfTest.
+type SelfTestOptions struct {
+	// Bucket to upload to. It must exist.
+	Bucket string
+
+	// Prefix is prepended to the object names.
+	Prefix string
+
+	// Sizes of the objects to upload.
+	// If empty, DefaultSelfTestSizes is used.
+	Sizes []int64
+}
+
+// SelfTestResult contains the outcome of a self-test for each object size.
+type SelfTestResult struct {
+	Objects []SelfTestObject
+}
+
+// SelfTestObject is the outcome for a single object size.
+type SelfTestObject struct {
+	Size   int64
+	Object string
+
+	// Step is the operation that failed, such as "PUT" or "GET".
+	// It is empty if the object passed.
+	Step string
+	Err  error
+
+	// Durations of the successful operations.
+	Put, Get, Delete time.Duration
+}
+
+// Passed returns whether the object was uploaded, verified and deleted.
+func (o SelfTestObject) Passed() bool {
+	return o.Err == nil
+}
+
+// Passed returns whether all objects passed.
+func (r SelfTestResult) Passed() bool {
+	for _, o := range r.Objects {
+		if !o.Passed() {
+			return false
+		}
+	}
+	return len(r.Objects) > 0
+}
+
+// Err returns an error describing the first failed object, or nil if all passed.
+func (r SelfTestResult) Err() error {
+	for _, o := range r.Objects {
+		if !o.Passed() {
+			return fmt.Errorf("self-test %s of %d byte object %q failed: %w", o.Step, o.Size, o.Object, o.Err)
+		}
+	}
+	if len(r.Objects) == 0 {
+		return errors.New("self-test: no objects tested")
+	}
+	return nil
+}
+
+// String returns one line per object size.
+func (r SelfTestResult) String() string {
+	var sb strings.Builder
+	for _, o := range r.Objects {
+		if o.Passed() {
+			fmt.Fprintf(&sb, "%10d bytes: PASS (PUT %v, GET %v, DELETE %v)\n", o.Size,
+				o.Put.Round(time.Millisecond), o.Get.Round(time.Millisecond), o.Delete.Round(time.Millisecond))
+			continue
+		}
+		fmt.Fprintf(&sb, "%10d bytes: FAIL %s: %v\n", o.Size, o.Step, o.Err)
+	}
+	return sb.String()
+}
+
+// SelfTest uploads an object of static content for each size, downloads it,
+// verifies every byte against the pattern and deletes it again.
+// Objects are deleted even if the download or verification fails.
+// It is intended as a quick check of the bucket, credentials and
+// anything between the client and the server before a benchmark is run.
+func SelfTest(ctx context.Context, client ObjectClient, o SelfTestOptions) SelfTestResult {
+	sizes := o.Sizes
+	if len(sizes) == 0 {
+		sizes = DefaultSelfTestSizes
+	}
+	g, _ := generator.NewGenerator(generator.GeneratorStatic)
+	pattern := generator.StaticPattern(0)
+	res := SelfTestResult{Objects: make([]SelfTestObject, 0, len(sizes))}
+	for i, size := range sizes {
+		obj := SelfTestObject{
+			Size:   size,
+			Object: fmt.Sprintf("%swarp-selftest-%d-%d", o.Prefix, i, size),
+		}
+		selfTestObject(ctx, client, o.Bucket, g, pattern, &obj)
+		res.Objects = append(res.Objects, obj)
+	}
+	return res
+}
+
+// selfTestObject runs the self-test for a single object and records the outcome in obj.
+func selfTestObject(ctx context.Context, client ObjectClient, bucket string, g generator.Generator, pattern []byte, obj *SelfTestObject) {
+	fail := func(step string, err error) {
+		if obj.Err == nil {
+			obj.Step, obj.Err = step, err
+		}
+	}
+	start := time.Now()
+	info, err := client.PutObject(ctx, bucket, obj.Object, g.Reader(obj.Size), obj.Size, minio.PutObjectOptions{})
+	if err != nil {
+		fail(http.MethodPut, err)
+		return
+	}
+	obj.Put = time.Since(start)
+	if info.Size != obj.Size {
+		fail(http.MethodPut, fmt.Errorf("server stored %d bytes, want %d", info.Size, obj.Size))
+	}
+
+	if obj.Err == nil {
+		start = time.Now()
+		r, _, err := client.GetObject(ctx, bucket, obj.Object, minio.GetObjectOptions{})
+		if err != nil {
+			fail(http.MethodGet, err)
+		} else {
+			err = generator.Verify(r, pattern, obj.Size)
+			r.Close()
+			if err != nil {
+				fail(http.MethodGet, err)
+			} else {
+				obj.Get = time.Since(start)
+			}
+		}
+	}
+
+	start = time.Now()
+	if err := client.RemoveObject(ctx, bucket, obj.Object, minio.RemoveObjectOptions{}); err != nil {
+		fail(http.MethodDelete, err)
+		return
+	}
+	obj.Delete = time.Since(start)
+}
@@ -0,0 +1,97 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/warp/pkg/generator"
+)
+
+// corruptingClient flips a byte in the middle of every object returned by GetObject.
+type corruptingClient struct {
+	*MemClient
+}
+
+func (c corruptingClient) GetObject(ctx context.Context, bucket, object string, opts minio.GetObjectOptions) (io.ReadCloser, minio.ObjectInfo, error) {
+	r, info, err := c.MemClient.GetObject(ctx, bucket, object, opts)
+	if err != nil {
+		return nil, info, err
+	}
+	b, err := io.ReadAll(r)
+	r.Close()
+	if err != nil {
+		return nil, info, err
+	}
+	if len(b) > 0 {
+		b[len(b)/2] ^= 0xff
+	}
+	return io.NopCloser(bytes.NewReader(b)), info, nil
+}
+
+func TestSelfTest(t *testing.T) {
+	ctx := context.Background()
+	mc := NewMemClient("bucket")
+	res := SelfTest(ctx, mc, SelfTestOptions{Bucket: "bucket", Prefix: "st/"})
+	if !res.Passed() || res.Err() != nil {
+		t.Fatalf("self-test failed: %v\n%s", res.Err(), res)
+	}
+	if len(res.Objects) != len(DefaultSelfTestSizes) {
+		t.Errorf("got %d results, want %d", len(res.Objects), len(DefaultSelfTestSizes))
+	}
+	if n := mc.Len("bucket"); n != 0 {
+		t.Errorf("%d objects left after self-test", n)
+	}
+	if s := res.String(); strings.Count(s, "PASS") != len(DefaultSelfTestSizes) {
+		t.Errorf("unexpected report:\n%s", s)
+	}
+
+	// A corrupting client fails every non-empty object and still deletes it.
+	mc = NewMemClient("bucket")
+	res = SelfTest(ctx, corruptingClient{mc}, SelfTestOptions{Bucket: "bucket", Sizes: []int64{0, 1000}})
+	if res.Passed() {
+		t.Fatal("self-test passed with corrupted data")
+	}
+	if !res.Objects[0].Passed() {
+		t.Errorf("empty object failed: %v", res.Objects[0].Err)
+	}
+	o := res.Objects[1]
+	var mismatch *generator.MismatchError
+	if o.Step != http.MethodGet || !errors.As(o.Err, &mismatch) || mismatch.Offset != 500 {
+		t.Errorf("got step %q, error %v, want GET mismatch at offset 500", o.Step, o.Err)
+	}
+	if err := res.Err(); err == nil || !strings.Contains(err.Error(), "GET of 1000 byte object") || !strings.Contains(err.Error(), "data mismatch at offset 500") {
+		t.Errorf("unclear error: %v", err)
+	}
+	if n := mc.Len("bucket"); n != 0 {
+		t.Errorf("%d objects left after failed self-test", n)
+	}
+
+	// A missing bucket fails on upload.
+	res = SelfTest(ctx, NewMemClient(), SelfTestOptions{Bucket: "missing", Sizes: []int64{10}})
+	if res.Passed() || res.Objects[0].Step != http.MethodPut {
+		t.Errorf("got %+v, want PUT failure", res.Objects[0])
+	}
+}
@@ -0,0 +1,196 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+// redactedHeaders are request headers and query parameters with credentials
+// or SSE-C encryption keys.
+// Their values are not written to a SlowRequestLog.
+var redactedHeaders = []string{
+	"Authorization", "X-Amz-Security-Token", "X-Amz-Signature", "X-Amz-Credential",
+	"X-Amz-Server-Side-Encryption-Customer-Key", "X-Amz-Server-Side-Encryption-Customer-Key-Md5",
+	"X-Amz-Copy-Source-Server-Side-Encryption-Customer-Key", "X-Amz-Copy-Source-Server-Side-Encryption-Customer-Key-Md5",
+}
+
+// SlowRequest is a request that took longer than the threshold of a SlowRequestLog.
+type SlowRequest struct {
+	Start  time.Time `json:"start"`
+	Method string    `json:"method"`
+	URL    string    `json:"url"`
+	// LatencyNS is the time until the response headers were received in nanoseconds.
+	LatencyNS int64  `json:"latency_ns"`
+	Status    int    `json:"status,omitempty"`
+	Err       string `json:"error,omitempty"`
+	// RequestID and HostID are the x-amz-request-id and x-amz-id-2 response headers.
+	RequestID      string      `json:"request_id,omitempty"`
+	HostID         string      `json:"host_id,omitempty"`
+	RequestHeader  http.Header `json:"request_header"`
+	ResponseHeader http.Header `json:"response_header,omitempty"`
+}
+
+// SlowRequestLog writes requests slower than a threshold as JSON lines.
+// Requests are only captured once they have exceeded the threshold,
+// so requests below it only pay for reading the clock.
+// Credentials in headers and presigned URLs are redacted.
+// A nil SlowRequestLog captures nothing.
+// It is safe for concurrent use.
+type SlowRequestLog struct {
+	threshold time.Duration
+
+	mu    sync.Mutex
+	enc   *json.Encoder
+	close io.Closer
+	n     int64
+	err   error
+}
+
+// NewSlowRequestLog returns a log writing requests slower than threshold to w.
+// w is not closed by Close.
+func NewSlowRequestLog(w io.Writer, threshold time.Duration) *SlowRequestLog {
+	return &SlowRequestLog{threshold: threshold, enc: json.NewEncoder(w)}
+}
+
+// CreateSlowRequestLog creates the file at path and returns a log writing to it.
+// The file is closed by Close.
+func CreateSlowRequestLog(path string, threshold time.Duration) (*SlowRequestLog, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	l := NewSlowRequestLog(f, threshold)
+	l.close = f
+	return l, nil
+}
+
+// Transport returns rt with requests slower than the threshold logged.
+// If l is nil, rt is returned.
+func (l *SlowRequestLog) Transport(rt http.RoundTripper) http.RoundTripper {
+	if l == nil {
+		return rt
+	}
+	return slowRequestTransport{RoundTripper: rt, log: l}
+}
+
+// add writes the request and response, if any.
+func (l *SlowRequestLog) add(req *http.Request, resp *http.Response, err error, start time.Time, latency time.Duration) {
+	e := SlowRequest{
+		Start:         start,
+		Method:        req.Method,
+		URL:           redactURL(req.URL),
+		LatencyNS:     int64(latency),
+		RequestHeader: redactHeader(req.Header),
+	}
+	if err != nil {
+		e.Err = err.Error()
+	}
+	if resp != nil {
+		e.Status = resp.StatusCode
+		e.RequestID = resp.Header.Get("X-Amz-Request-Id")
+		e.HostID = resp.Header.Get("X-Amz-Id-2")
+		e.ResponseHeader = resp.Header.Clone()
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err != nil {
+		return
+	}
+	l.err = l.enc.Encode(e)
+	if l.err == nil {
+		l.n++
+	}
+}
+
+// Count returns the number of requests written so far.
+func (l *SlowRequestLog) Count() int64 {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.n
+}
+
+// Close closes the file, if any.
+// The first error encountered while writing is returned.
+func (l *SlowRequestLog) Close() error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.close != nil {
+		l.err = errors.Join(l.err, l.close.Close())
+		l.close = nil
+	}
+	return l.err
+}
+
+// slowRequestTransport logs requests slower than the threshold of log.
+type slowRequestTransport struct {
+	http.RoundTripper
+	log *SlowRequestLog
+}
+
+// RoundTrip sends req and logs it if the response took longer than the threshold.
+func (t slowRequestTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.RoundTripper.RoundTrip(req)
+	if latency := time.Since(start); latency > t.log.threshold {
+		t.log.add(req, resp, err, start, latency)
+	}
+	return resp, err
+}
+
+// redactHeader returns a copy of h with credentials removed.
+func redactHeader(h http.Header) http.Header {
+	h = h.Clone()
+	for _, k := range redactedHeaders {
+		if h.Get(k) != "" {
+			h.Set(k, "REDACTED")
+		}
+	}
+	return h
+}
+
+// redactURL returns u with credentials of presigned URLs removed.
+func redactURL(u *url.URL) string {
+	q := u.Query()
+	changed := false
+	for _, k := range redactedHeaders {
+		if q.Has(k) {
+			q.Set(k, "REDACTED")
+			changed = true
+		}
+	}
+	if !changed {
+		return u.Redacted()
+	}
+	c := *u
+	c.RawQuery = q.Encode()
+	return c.Redacted()
+}
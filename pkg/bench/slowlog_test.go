@@ -0,0 +1,147 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// delayTransport answers every request after a delay if the path contains "slow".
+// The response has the object name as request ID.
+type delayTransport struct {
+	delay time.Duration
+}
+
+func (t delayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		io.Copy(io.Discard, req.Body)
+		req.Body.Close()
+	}
+	if strings.Contains(req.URL.Path, "slow") {
+		time.Sleep(t.delay)
+	}
+	h := make(http.Header)
+	h.Set("X-Amz-Request-Id", req.URL.Path[strings.LastIndexByte(req.URL.Path, '/')+1:])
+	h.Set("X-Amz-Id-2", "host-id")
+	h.Set("ETag", `"d41d8cd98f00b204e9800998ecf8427e"`)
+	h.Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+	h.Set("Content-Length", "0")
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     h,
+		Body:       http.NoBody,
+		Request:    req,
+	}, nil
+}
+
+func TestSlowRequestLog(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewSlowRequestLog(&buf, 20*time.Millisecond)
+	cl, err := minio.New("localhost:9000", &minio.Options{
+		Creds:        credentials.NewStaticV4("access", "secret", "token"),
+		Region:       "us-east-1",
+		BucketLookup: minio.BucketLookupPath,
+		Transport:    log.Transport(delayTransport{delay: 40 * time.Millisecond}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	for _, name := range []string{"fast-1", "slow-1", "fast-2", "slow-2", "fast-3"} {
+		if _, err := cl.StatObject(ctx, "bucket", name, minio.StatObjectOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := log.Count(); n != 2 {
+		t.Fatalf("logged %d requests, want 2", n)
+	}
+	dec := json.NewDecoder(&buf)
+	for _, want := range []string{"slow-1", "slow-2"} {
+		var e SlowRequest
+		if err := dec.Decode(&e); err != nil {
+			t.Fatal(err)
+		}
+		if e.RequestID != want || e.HostID != "host-id" || e.Method != http.MethodHead || e.Status != http.StatusOK {
+			t.Errorf("unexpected entry %+v", e)
+		}
+		if !strings.HasSuffix(e.URL, "/bucket/"+want) || e.LatencyNS < int64(20*time.Millisecond) {
+			t.Errorf("%s: url %s, latency %v", want, e.URL, time.Duration(e.LatencyNS))
+		}
+		if e.ResponseHeader.Get("Etag") == "" || e.RequestHeader.Get("X-Amz-Date") == "" {
+			t.Errorf("%s: headers not captured: %v, %v", want, e.RequestHeader, e.ResponseHeader)
+		}
+		for _, k := range []string{"Authorization", "X-Amz-Security-Token"} {
+			if v := e.RequestHeader.Get(k); v != "REDACTED" {
+				t.Errorf("%s: header %s = %q, want redacted", want, k, v)
+			}
+		}
+	}
+	if dec.More() {
+		t.Error("unexpected extra entries")
+	}
+	if err := log.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Presigned credentials are redacted.
+	u, _ := url.Parse("http://localhost:9000/bucket/obj?X-Amz-Signature=secret&X-Amz-Expires=60")
+	if got := redactURL(u); strings.Contains(got, "secret") || !strings.Contains(got, "X-Amz-Expires=60") {
+		t.Errorf("presigned URL not redacted: %s", got)
+	}
+
+	// SSE-C keys are redacted, the algorithm is kept.
+	h := make(http.Header)
+	for _, prefix := range []string{"X-Amz-", "X-Amz-Copy-Source-"} {
+		h.Set(prefix+"Server-Side-Encryption-Customer-Algorithm", "AES256")
+		h.Set(prefix+"Server-Side-Encryption-Customer-Key", "c2VjcmV0")
+		h.Set(prefix+"Server-Side-Encryption-Customer-Key-MD5", "bWQ1")
+	}
+	for k, v := range redactHeader(h) {
+		want := "REDACTED"
+		if strings.HasSuffix(k, "-Algorithm") {
+			want = "AES256"
+		}
+		if v[0] != want {
+			t.Errorf("header %s = %q, want %q", k, v[0], want)
+		}
+	}
+	if h.Get("X-Amz-Server-Side-Encryption-Customer-Key") != "c2VjcmV0" {
+		t.Error("original header modified")
+	}
+
+	// A nil log leaves the transport unchanged.
+	var none *SlowRequestLog
+	tr := delayTransport{}
+	if none.Transport(tr) != http.RoundTripper(tr) || none.Count() != 0 || none.Close() != nil {
+		t.Error("nil log should do nothing")
+	}
+}
@@ -79,6 +79,7 @@ func (s *Snowball) Start(ctx context.Context, wait chan struct{}) error {
 		src := s.Source()
 		s.prefixes[src.Prefix()] = struct{}{}
 		go func(i int) {
+			getClient := s.workerClient(i)
 			var buf bytes.Buffer
 			rcv := c.Receiver()
 			defer wg.Done()
@@ -87,6 +88,9 @@ func (s *Snowball) Start(ctx context.Context, wait chan struct{}) error {
 			done := ctx.Done()
 
 			<-wait
+			if s.RampUp.Wait(ctx, i) != nil {
+				return
+			}
 			for {
 				select {
 				case <-done:
@@ -94,6 +98,9 @@ func (s *Snowball) Start(ctx context.Context, wait chan struct{}) error {
 				default:
 				}
 
+				if s.ThinkTime.Wait(ctx) != nil {
+					return
+				}
 				if s.rpsLimit(ctx) != nil {
 					return
 				}
@@ -159,7 +166,7 @@ func (s *Snowball) Start(ctx context.Context, wait chan struct{}) error {
 				opts.ContentType = obj.ContentType
 				opts.DisableMultipart = true
 
-				client, cldone := s.Client()
+				client, cldone := getClient()
 				op.Endpoint = client.EndpointURL().String()
 				op.Start = time.Now()
 				tarLength := int64(buf.Len())
@@ -168,7 +175,7 @@ func (s *Snowball) Start(ctx context.Context, wait chan struct{}) error {
 				op.End = time.Now()
 				if err != nil {
 					s.Error("upload error: ", err)
-					op.Err = err.Error()
+					op.SetErr(err)
 				}
 				obj.VersionID = res.VersionID
 
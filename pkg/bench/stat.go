@@ -23,6 +23,7 @@ import (
 	"math/rand"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/minio/minio-go/v7"
@@ -43,6 +44,62 @@ type Stat struct {
 
 	ListExisting bool
 	ListFlat     bool
+
+	// MissRatio is the fraction of requests, 0 to 1, for objects that do not exist.
+	// These are expected to return 404 and are counted as misses, not errors.
+	MissRatio float64
+
+	found, notFound atomic.Int64
+}
+
+// ObjectStater returns object info.
+// It is implemented by *minio.Client.
+type ObjectStater interface {
+	StatObject(ctx context.Context, bucket, object string, opts minio.StatObjectOptions) (minio.ObjectInfo, error)
+}
+
+// absentSuffix is added to object names to get names of objects that do not exist.
+const absentSuffix = ".absent"
+
+// statObject sends a HEAD request for obj and records the result in op.
+// exists is whether the object is expected to exist.
+// An object that is not found is only an error if it was expected to exist,
+// and an object that is found is an error if it was not.
+// The CatFound or CatNotFound category is set on op when the server answered.
+// Returns whether the object was found and the error of the request, if any.
+func statObject(ctx context.Context, client ObjectStater, bucket string, obj generator.Object, opts minio.StatObjectOptions, exists bool, op *Operation) (found bool, err error) {
+	op.Start = time.Now()
+	info, err := client.StatObject(ctx, bucket, obj.Name, opts)
+	op.End = time.Now()
+	if err != nil {
+		if resp := minio.ToErrorResponse(err); resp.StatusCode != http.StatusNotFound {
+			op.SetErr(err)
+			return false, err
+		}
+		op.Categories = NewCategories(CatNotFound)
+		if exists {
+			op.SetErr(err)
+		}
+		return false, err
+	}
+	op.Categories = NewCategories(CatFound)
+	switch {
+	case !exists:
+		op.Err = fmt.Sprint("object unexpectedly exists: ", obj.Name)
+	case info.Size != obj.Size:
+		op.Err = fmt.Sprint("unexpected file size. want:", obj.Size, ", got:", info.Size)
+	}
+	return true, nil
+}
+
+// Found returns the number of STAT requests that found the object.
+func (g *Stat) Found() int64 {
+	return g.found.Load()
+}
+
+// NotFound returns the number of STAT requests that did not find the object.
+func (g *Stat) NotFound() int64 {
+	return g.notFound.Load()
 }
 
 // Prepare will create an empty bucket or delete any content already there
@@ -227,6 +284,7 @@ func (g *Stat) Start(ctx context.Context, wait chan struct{}) error {
 
 	for i := 0; i < g.Concurrency; i++ {
 		go func(i int) {
+			getClient := g.workerClient(i)
 			rng := rand.New(rand.NewSource(int64(i)))
 			rcv := c.Receiver()
 			defer wg.Done()
@@ -234,6 +292,9 @@ func (g *Stat) Start(ctx context.Context, wait chan struct{}) error {
 			done := ctx.Done()
 
 			<-wait
+			if g.RampUp.Wait(ctx, i) != nil {
+				return
+			}
 			for {
 				select {
 				case <-done:
@@ -241,12 +302,19 @@ func (g *Stat) Start(ctx context.Context, wait chan struct{}) error {
 				default:
 				}
 
+				if g.ThinkTime.Wait(ctx) != nil {
+					return
+				}
 				if g.rpsLimit(ctx) != nil {
 					return
 				}
 
 				obj := g.objects[rng.Intn(len(g.objects))]
-				client, cldone := g.Client()
+				exists := g.MissRatio <= 0 || rng.Float64() >= g.MissRatio
+				if !exists {
+					obj.Name += absentSuffix
+				}
+				client, cldone := getClient()
 				op := Operation{
 					OpType:   "STAT",
 					Thread:   uint32(i),
@@ -256,24 +324,28 @@ func (g *Stat) Start(ctx context.Context, wait chan struct{}) error {
 					Endpoint: client.EndpointURL().String(),
 				}
 
-				op.Start = time.Now()
-				var err error
 				if g.Versions > 1 {
 					opts.VersionID = obj.VersionID
 				}
-				objI, err := client.StatObject(nonTerm, g.Bucket, obj.Name, opts)
-				if err != nil {
-					g.Error("StatObject error: ", err)
-					op.Err = err.Error()
-					op.End = time.Now()
-					rcv <- op
-					cldone()
-					continue
+				var found bool
+				start := time.Now()
+				err := g.Retry.DoOp(ctx, &op, func() error {
+					op.Err, op.ErrCat, op.Categories = "", ErrCatNone, 0
+					return g.withOpTimeout(nonTerm, func(ctx context.Context) (err error) {
+						found, err = statObject(ctx, client, g.Bucket, obj, opts, exists, &op)
+						return err
+					})
+				})
+				opTimedOut(&op, err)
+				// Retries are part of the operation.
+				op.Start = start
+				if found {
+					g.found.Add(1)
+				} else if op.Categories != 0 {
+					g.notFound.Add(1)
 				}
-				op.End = time.Now()
-				if objI.Size != obj.Size && op.Err == "" {
-					op.Err = fmt.Sprint("unexpected file size. want:", obj.Size, ", got:", objI.Size)
-					g.Error(op.Err)
+				if op.Err != "" {
+					g.Error("StatObject error: ", op.Err)
 				}
 				rcv <- op
 				cldone()
@@ -284,6 +356,12 @@ func (g *Stat) Start(ctx context.Context, wait chan struct{}) error {
 	return nil
 }
 
+// timesOutOps implements opTimeouter.
+func (g *Stat) timesOutOps() {}
+
+// retriesOps implements opRetrier.
+func (g *Stat) retriesOps() {}
+
 // Cleanup deletes everything uploaded to the bucket.
 func (g *Stat) Cleanup(ctx context.Context) {
 	g.deleteAllInBucket(ctx, g.objects.Prefixes()...)
@@ -0,0 +1,147 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/warp/pkg/generator"
+)
+
+// slowStater delays every request.
+type slowStater struct {
+	ObjectStater
+	delay time.Duration
+	err   error
+}
+
+func (s slowStater) StatObject(ctx context.Context, bucket, object string, opts minio.StatObjectOptions) (minio.ObjectInfo, error) {
+	time.Sleep(s.delay)
+	if s.err != nil {
+		return minio.ObjectInfo{}, s.err
+	}
+	return s.ObjectStater.StatObject(ctx, bucket, object, opts)
+}
+
+func TestStatObject(t *testing.T) {
+	ctx := context.Background()
+	mem := NewMemClient("bucket")
+	present := generator.Object{Name: "present", Size: 10}
+	if _, err := mem.PutObject(ctx, "bucket", present.Name, bytes.NewReader(make([]byte, 10)), 10, minio.PutObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	absent := generator.Object{Name: "present" + absentSuffix, Size: 10}
+	const delay = 5 * time.Millisecond
+	client := slowStater{ObjectStater: mem, delay: delay}
+
+	tests := []struct {
+		name    string
+		client  ObjectStater
+		obj     generator.Object
+		exists  bool
+		found   bool
+		cat     Categories
+		wantErr bool
+	}{
+		{name: "hit", client: client, obj: present, exists: true, found: true, cat: NewCategories(CatFound)},
+		{name: "expected-miss", client: client, obj: absent, exists: false, cat: NewCategories(CatNotFound)},
+		{name: "unexpected-miss", client: client, obj: absent, exists: true, cat: NewCategories(CatNotFound), wantErr: true},
+		{name: "unexpected-hit", client: client, obj: present, exists: false, found: true, cat: NewCategories(CatFound), wantErr: true},
+		{name: "size-mismatch", client: client, obj: generator.Object{Name: "present", Size: 11}, exists: true, found: true, cat: NewCategories(CatFound), wantErr: true},
+		{name: "server-error", client: slowStater{delay: delay, err: minio.ErrorResponse{StatusCode: 500, Code: "InternalError"}}, obj: present, exists: true, wantErr: true},
+		{name: "network-error", client: slowStater{delay: delay, err: errors.New("connection reset")}, obj: absent, exists: false, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var op Operation
+			found, _ := statObject(ctx, test.client, "bucket", test.obj, minio.StatObjectOptions{}, test.exists, &op)
+			if found != test.found {
+				t.Errorf("found %v, want %v", found, test.found)
+			}
+			if op.Categories != test.cat {
+				t.Errorf("categories %v, want %v", op.Categories, test.cat)
+			}
+			if (op.Err != "") != test.wantErr {
+				t.Errorf("error %q, want error %v", op.Err, test.wantErr)
+			}
+			if d := op.Duration(); d < delay {
+				t.Errorf("recorded latency %v, want >= %v", d, delay)
+			}
+		})
+	}
+}
+
+func TestStatMissRatio(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, absentSuffix) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", "10")
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+	cl, err := minio.New(u.Host, &minio.Options{Creds: credentials.NewStaticV4("access", "secret", ""), Region: "us-east-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Stat{
+		Common: Common{
+			Client:      func() (*minio.Client, func()) { return cl, func() {} },
+			Bucket:      "bucket",
+			Concurrency: 2,
+			Error:       func(data ...any) { t.Error(data...) },
+		},
+		objects:   generator.Objects{{Name: "a", Size: 10}, {Name: "b", Size: 10}},
+		MissRatio: 0.25,
+	}
+	ops, err := RunFor(context.Background(), s, 300*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found, notFound int64
+	for _, op := range ops {
+		switch op.Categories {
+		case NewCategories(CatFound):
+			found++
+		case NewCategories(CatNotFound):
+			notFound++
+		default:
+			t.Fatalf("unexpected categories %v", op.Categories)
+		}
+	}
+	if found != s.Found() || notFound != s.NotFound() {
+		t.Errorf("counted %d/%d, benchmark reported %d/%d", found, notFound, s.Found(), s.NotFound())
+	}
+	if ratio := float64(notFound) / float64(len(ops)); len(ops) < 100 || ratio < 0.15 || ratio > 0.35 {
+		t.Errorf("miss ratio %.2f of %d ops, want 0.25", ratio, len(ops))
+	}
+}
@@ -0,0 +1,217 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+// SweepOptions configures ConcurrencySweep.
+type SweepOptions struct {
+	// Start is the initial concurrency. Values <= 0 will use 1.
+	Start int
+
+	// Step is the number of workers added for each level. Values <= 0 will use 1.
+	Step int
+
+	// Max is the maximum concurrency.
+	Max int
+
+	// Stabilize is the time to wait after changing concurrency before measuring.
+	Stabilize time.Duration
+
+	// Measure is the time throughput and latency is measured at each level.
+	Measure time.Duration
+
+	// MaxP99 stops the sweep when the p99 latency exceeds this, if > 0.
+	MaxP99 time.Duration
+
+	// Plateau stops the sweep when throughput improves less than this fraction
+	// compared to the best level so far. For example 0.05 for 5%.
+	Plateau float64
+}
+
+// SweepLevel contains the measurements for a single concurrency level.
+type SweepLevel struct {
+	Concurrency int
+	Ops         int
+	Errors      int
+	OpsPerSec   float64
+	BytesPerSec float64
+	P99         time.Duration
+}
+
+// SweepResult is the result of ConcurrencySweep.
+type SweepResult struct {
+	// Levels contains all measured levels in order.
+	Levels []SweepLevel
+
+	// Best is the lowest concurrency level with throughput within Plateau of the highest,
+	// and p99 latency within MaxP99.
+	Best SweepLevel
+}
+
+// SweepOp is a single operation executed by a sweep worker.
+// It returns the number of bytes transferred.
+type SweepOp func(ctx context.Context) (int64, error)
+
+type sweepSample struct {
+	end  time.Time
+	dur  time.Duration
+	size int64
+	err  bool
+}
+
+// ConcurrencySweep runs op with increasing concurrency to find the level that gives the highest throughput.
+// Workers are added between levels and are all stopped when the sweep returns.
+// The sweep stops when Max is reached, throughput plateaus or p99 latency exceeds MaxP99.
+func ConcurrencySweep(ctx context.Context, op SweepOp, o SweepOptions) (SweepResult, error) {
+	o.Start = max(o.Start, 1)
+	o.Step = max(o.Step, 1)
+	o.Plateau = max(o.Plateau, 0)
+	if o.Max < o.Start {
+		return SweepResult{}, errors.New("sweep: max concurrency must be >= start")
+	}
+	if o.Measure <= 0 {
+		return SweepResult{}, errors.New("sweep: measure duration must be > 0")
+	}
+
+	var mu sync.Mutex
+	var samples []sweepSample
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	workers := 0
+	addWorkers := func(n int) {
+		for range n {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for ctx.Err() == nil {
+					start := time.Now()
+					size, err := op(ctx)
+					end := time.Now()
+					if ctx.Err() != nil {
+						return
+					}
+					mu.Lock()
+					samples = append(samples, sweepSample{end: end, dur: end.Sub(start), size: size, err: err != nil})
+					mu.Unlock()
+				}
+			}()
+		}
+		workers += n
+	}
+
+	var res SweepResult
+	var best float64
+	for c := o.Start; c <= o.Max; c += o.Step {
+		addWorkers(c - workers)
+		if !sleepCtx(ctx, o.Stabilize) {
+			return res, ctx.Err()
+		}
+		mu.Lock()
+		samples = samples[:0]
+		mu.Unlock()
+		start := time.Now()
+		if !sleepCtx(ctx, o.Measure) {
+			return res, ctx.Err()
+		}
+		mu.Lock()
+		level := sweepMeasure(c, samples, start, time.Since(start))
+		mu.Unlock()
+		res.Levels = append(res.Levels, level)
+
+		if o.MaxP99 > 0 && level.P99 > o.MaxP99 {
+			break
+		}
+		if best > 0 && level.OpsPerSec < best*(1+o.Plateau) {
+			break
+		}
+		best = max(best, level.OpsPerSec)
+	}
+	res.Best = sweepBest(res.Levels, o)
+	return res, nil
+}
+
+// sweepMeasure returns the level measurements from samples ending in the measurement window.
+func sweepMeasure(concurrency int, samples []sweepSample, start time.Time, dur time.Duration) SweepLevel {
+	level := SweepLevel{Concurrency: concurrency}
+	var bytes int64
+	durs := make([]time.Duration, 0, len(samples))
+	for _, s := range samples {
+		if s.end.Before(start) {
+			continue
+		}
+		if s.err {
+			level.Errors++
+			continue
+		}
+		level.Ops++
+		bytes += s.size
+		durs = append(durs, s.dur)
+	}
+	slices.Sort(durs)
+	if len(durs) > 0 {
+		level.P99 = durs[max(int(math.Ceil(0.99*float64(len(durs))))-1, 0)]
+	}
+	level.OpsPerSec = float64(level.Ops) / dur.Seconds()
+	level.BytesPerSec = float64(bytes) / dur.Seconds()
+	return level
+}
+
+// sweepBest returns the lowest level within o.Plateau of the highest throughput.
+func sweepBest(levels []SweepLevel, o SweepOptions) SweepLevel {
+	var top float64
+	for _, l := range levels {
+		if o.MaxP99 > 0 && l.P99 > o.MaxP99 {
+			continue
+		}
+		top = max(top, l.OpsPerSec)
+	}
+	for _, l := range levels {
+		if o.MaxP99 > 0 && l.P99 > o.MaxP99 {
+			continue
+		}
+		if l.OpsPerSec >= top/(1+o.Plateau) {
+			return l
+		}
+	}
+	if len(levels) > 0 {
+		return levels[0]
+	}
+	return SweepLevel{}
+}
+
+// sleepCtx sleeps for d and returns false if ctx was canceled.
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
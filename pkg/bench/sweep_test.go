@@ -0,0 +1,113 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"math"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// kneeOp returns an operation that can serve knee concurrent operations at base latency.
+// Above that latency grows linearly with concurrency, so throughput is flat.
+func kneeOp(knee int, base time.Duration) SweepOp {
+	var active atomic.Int64
+	return func(ctx context.Context) (int64, error) {
+		n := active.Add(1)
+		defer active.Add(-1)
+		d := base
+		if n > int64(knee) {
+			d = base * time.Duration(n) / time.Duration(knee)
+		}
+		t := time.NewTimer(d)
+		defer t.Stop()
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-t.C:
+		}
+		return 1000, nil
+	}
+}
+
+func TestConcurrencySweep(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping in short mode")
+	}
+	res, err := ConcurrencySweep(context.Background(), kneeOp(8, 10*time.Millisecond), SweepOptions{
+		Start:     2,
+		Step:      2,
+		Max:       32,
+		Stabilize: 20 * time.Millisecond,
+		Measure:   250 * time.Millisecond,
+		Plateau:   0.15,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Best.Concurrency != 8 {
+		t.Errorf("best concurrency %d, want 8", res.Best.Concurrency)
+	}
+	// Should stop shortly after the knee.
+	if last := res.Levels[len(res.Levels)-1].Concurrency; last > 12 {
+		t.Errorf("sweep continued to %d", last)
+	}
+	if math.Abs(res.Best.BytesPerSec-res.Best.OpsPerSec*1000) > 1 {
+		t.Errorf("unexpected throughput %v bytes/s at %v ops/s", res.Best.BytesPerSec, res.Best.OpsPerSec)
+	}
+}
+
+func TestConcurrencySweepLatency(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping in short mode")
+	}
+	// Latency is 20ms up to 8 workers and 80ms above.
+	var active atomic.Int64
+	op := func(ctx context.Context) (int64, error) {
+		d := 20 * time.Millisecond
+		if active.Add(1) > 8 {
+			d *= 4
+		}
+		defer active.Add(-1)
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(d):
+		}
+		return 1000, nil
+	}
+	res, err := ConcurrencySweep(context.Background(), op, SweepOptions{
+		Start:     4,
+		Step:      4,
+		Max:       32,
+		Stabilize: 40 * time.Millisecond,
+		Measure:   300 * time.Millisecond,
+		MaxP99:    50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last := res.Levels[len(res.Levels)-1]; last.Concurrency != 12 || last.P99 <= 50*time.Millisecond {
+		t.Errorf("want sweep to stop at 12 when p99 exceeded, got %d, p99 %v", last.Concurrency, last.P99)
+	}
+	if res.Best.Concurrency != 8 {
+		t.Errorf("best concurrency %d, want 8", res.Best.Concurrency)
+	}
+}
@@ -0,0 +1,71 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/tags"
+)
+
+// Operation types of object tagging operations.
+const (
+	OpTagPut = "TAG-PUT"
+	OpTagGet = "TAG-GET"
+)
+
+// ObjectTagger sets and reads object tags.
+// It is implemented by *minio.Client.
+type ObjectTagger interface {
+	PutObjectTagging(ctx context.Context, bucket, object string, otags *tags.Tags, opts minio.PutObjectTaggingOptions) error
+	GetObjectTagging(ctx context.Context, bucket, object string, opts minio.GetObjectTaggingOptions) (*tags.Tags, error)
+}
+
+// putObjectTags replaces the tags of object with objTags and records the result in op.
+func putObjectTags(ctx context.Context, client ObjectTagger, bucket, object, versionID string, objTags map[string]string, op *Operation) {
+	op.Start = time.Now()
+	t, err := tags.NewTags(objTags, true)
+	if err != nil {
+		op.End = op.Start
+		op.SetErr(err)
+		return
+	}
+	err = client.PutObjectTagging(ctx, bucket, object, t, minio.PutObjectTaggingOptions{VersionID: versionID})
+	op.End = time.Now()
+	if err != nil {
+		op.SetErr(err)
+	}
+}
+
+// getObjectTags reads the tags of object and records the result in op.
+// It is an error if the object does not have want tags.
+func getObjectTags(ctx context.Context, client ObjectTagger, bucket, object, versionID string, want int, op *Operation) {
+	op.Start = time.Now()
+	t, err := client.GetObjectTagging(ctx, bucket, object, minio.GetObjectTaggingOptions{VersionID: versionID})
+	op.End = time.Now()
+	if err != nil {
+		op.SetErr(err)
+		return
+	}
+	if got := t.Count(); got != want {
+		op.Err = fmt.Sprint("unexpected number of tags. want:", want, ", got:", got)
+	}
+}
@@ -0,0 +1,92 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"bytes"
+	"context"
+	"maps"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/tags"
+	"github.com/minio/warp/pkg/generator"
+)
+
+// recordingTagger records the tags of every PutObjectTagging request.
+type recordingTagger struct {
+	ObjectTagger
+	sent []map[string]string
+}
+
+func (r *recordingTagger) PutObjectTagging(ctx context.Context, bucket, object string, otags *tags.Tags, opts minio.PutObjectTaggingOptions) error {
+	r.sent = append(r.sent, otags.ToMap())
+	return r.ObjectTagger.PutObjectTagging(ctx, bucket, object, otags, opts)
+}
+
+func TestObjectTags(t *testing.T) {
+	ctx := context.Background()
+	mem := NewMemClient("bucket")
+	if _, err := mem.PutObject(ctx, "bucket", "obj", bytes.NewReader([]byte("x")), 1, minio.PutObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	gen, err := generator.NewTagGenerator(3, generator.NewConstantSizeSampler(20), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &recordingTagger{ObjectTagger: mem}
+
+	// Getting before tags are set finds no tags.
+	var op Operation
+	getObjectTags(ctx, client, "bucket", "obj", "", gen.Len(), &op)
+	if op.Err == "" {
+		t.Error("want error for object without tags")
+	}
+
+	want := gen.Next()
+	op = Operation{}
+	putObjectTags(ctx, client, "bucket", "obj", "", want, &op)
+	if op.Err != "" {
+		t.Fatal(op.Err)
+	}
+	if op.End.Before(op.Start) || op.Start.IsZero() {
+		t.Errorf("invalid operation times %v - %v", op.Start, op.End)
+	}
+	if len(client.sent) != 1 || len(client.sent[0]) != 3 {
+		t.Fatalf("got requests %v, want one with 3 tags", client.sent)
+	}
+
+	op = Operation{}
+	getObjectTags(ctx, client, "bucket", "obj", "", gen.Len(), &op)
+	if op.Err != "" {
+		t.Fatal(op.Err)
+	}
+	got, err := mem.GetObjectTagging(ctx, "bucket", "obj", minio.GetObjectTaggingOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !maps.Equal(got.ToMap(), want) {
+		t.Errorf("got tags %v, want %v", got.ToMap(), want)
+	}
+
+	op = Operation{}
+	putObjectTags(ctx, client, "bucket", "missing", "", want, &op)
+	if op.Err == "" {
+		t.Error("want error for missing object")
+	}
+}
@@ -0,0 +1,134 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+type thinkDistribution int
+
+const (
+	thinkConstant thinkDistribution = iota
+	thinkUniform
+	thinkExponential
+)
+
+// ThinkTime is a pause each worker makes between operations,
+// modeling clients that do not send requests back to back.
+// Unlike the rate limit it applies to each worker separately.
+// A nil *ThinkTime does not pause.
+// It is safe for concurrent use.
+type ThinkTime struct {
+	dist     thinkDistribution
+	min, max time.Duration
+	mean     time.Duration
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// NewConstantThinkTime returns a think time that is always d.
+func NewConstantThinkTime(d time.Duration) (*ThinkTime, error) {
+	if d < 0 {
+		return nil, errors.New("NewConstantThinkTime: duration must be >= 0")
+	}
+	return &ThinkTime{dist: thinkConstant, min: d}, nil
+}
+
+// NewUniformThinkTime returns think times uniformly distributed in [minDur, maxDur].
+func NewUniformThinkTime(minDur, maxDur time.Duration, seed int64) (*ThinkTime, error) {
+	if minDur < 0 || minDur > maxDur {
+		return nil, errors.New("NewUniformThinkTime: want 0 <= min <= max")
+	}
+	return &ThinkTime{dist: thinkUniform, min: minDur, max: maxDur, rng: rand.New(rand.NewSource(seed))}, nil
+}
+
+// NewExponentialThinkTime returns exponentially distributed think times with the specified mean.
+// This models independent clients with a random time between requests.
+// No think time is longer than 10 times the mean.
+func NewExponentialThinkTime(mean time.Duration, seed int64) (*ThinkTime, error) {
+	if mean <= 0 {
+		return nil, errors.New("NewExponentialThinkTime: mean must be > 0")
+	}
+	return &ThinkTime{dist: thinkExponential, mean: mean, max: 10 * mean, rng: rand.New(rand.NewSource(seed))}, nil
+}
+
+// ParseThinkTime parses a think time.
+// A single duration, for example "100ms", is constant.
+// "50ms-150ms" is uniformly distributed between the two durations,
+// and "exp:100ms" is exponentially distributed with a mean of 100ms.
+func ParseThinkTime(s string, seed int64) (*ThinkTime, error) {
+	if mean, ok := strings.CutPrefix(s, "exp:"); ok {
+		d, err := time.ParseDuration(mean)
+		if err != nil {
+			return nil, fmt.Errorf("think time %q: %w", s, err)
+		}
+		return NewExponentialThinkTime(d, seed)
+	}
+	if lo, hi, ok := strings.Cut(s, "-"); ok {
+		minDur, err := time.ParseDuration(lo)
+		if err != nil {
+			return nil, fmt.Errorf("think time %q: %w", s, err)
+		}
+		maxDur, err := time.ParseDuration(hi)
+		if err != nil {
+			return nil, fmt.Errorf("think time %q: %w", s, err)
+		}
+		return NewUniformThinkTime(minDur, maxDur, seed)
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return nil, fmt.Errorf("think time %q: %w", s, err)
+	}
+	return NewConstantThinkTime(d)
+}
+
+// Next returns the next think time.
+func (t *ThinkTime) Next() time.Duration {
+	if t == nil {
+		return 0
+	}
+	switch t.dist {
+	case thinkUniform:
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		return t.min + time.Duration(t.rng.Int63n(int64(t.max-t.min)+1))
+	case thinkExponential:
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		return min(time.Duration(t.rng.ExpFloat64()*float64(t.mean)), t.max)
+	}
+	return t.min
+}
+
+// Wait pauses for the next think time.
+// It returns early with the context error if ctx is canceled.
+func (t *ThinkTime) Wait(ctx context.Context) error {
+	d := t.Next()
+	if d <= 0 || sleepCtx(ctx, d) {
+		return nil
+	}
+	return ctx.Err()
+}
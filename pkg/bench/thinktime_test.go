@@ -0,0 +1,162 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/warp/pkg/generator"
+)
+
+func TestThinkTimeDistribution(t *testing.T) {
+	const n = 20000
+	stats := func(tt *ThinkTime) (mean, lo, hi time.Duration) {
+		var sum time.Duration
+		lo = math.MaxInt64
+		for range n {
+			d := tt.Next()
+			sum += d
+			lo, hi = min(lo, d), max(hi, d)
+		}
+		return sum / n, lo, hi
+	}
+	c, err := NewConstantThinkTime(50 * time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mean, lo, hi := stats(c); mean != 50*time.Millisecond || lo != hi {
+		t.Errorf("constant: mean %v, range %v-%v", mean, lo, hi)
+	}
+	u, err := NewUniformThinkTime(10*time.Millisecond, 30*time.Millisecond, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mean, lo, hi := stats(u); lo < 10*time.Millisecond || hi > 30*time.Millisecond || math.Abs(float64(mean-20*time.Millisecond)) > float64(time.Millisecond)/2 {
+		t.Errorf("uniform: mean %v, range %v-%v", mean, lo, hi)
+	}
+	e, err := NewExponentialThinkTime(10*time.Millisecond, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mean, _, hi := stats(e); hi > 100*time.Millisecond || math.Abs(float64(mean-10*time.Millisecond)) > float64(time.Millisecond)/2 {
+		t.Errorf("exponential: mean %v, max %v", mean, hi)
+	}
+	var none *ThinkTime
+	if none.Next() != 0 || none.Wait(context.Background()) != nil {
+		t.Error("nil think time should not pause")
+	}
+
+	for s, want := range map[string]bool{"100ms": true, "10ms-20ms": true, "exp:5ms": true, "20ms-10ms": false, "exp:0s": false, "fast": false, "-1s": false} {
+		if _, err := ParseThinkTime(s, 1); (err == nil) != want {
+			t.Errorf("ParseThinkTime(%q): error %v", s, err)
+		}
+	}
+}
+
+func TestThinkTimeWait(t *testing.T) {
+	tt, _ := NewConstantThinkTime(10 * time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	start := time.Now()
+	if err := tt.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("canceled wait took %v", d)
+	}
+}
+
+func TestThinkTimeWorker(t *testing.T) {
+	u := newPutServer(t)
+	cl, err := minio.New(u.Host, &minio.Options{Creds: credentials.NewStaticV4("access", "secret", ""), Region: "us-east-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := generator.NewFn(generator.WithRandomData().Apply(), generator.WithSize(1<<10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const think = 20 * time.Millisecond
+	tt, _ := NewConstantThinkTime(think)
+	put := &Put{Common: Common{
+		Client:      func() (*minio.Client, func()) { return cl, func() {} },
+		Source:      src,
+		Bucket:      "bucket",
+		Concurrency: 2,
+		ThinkTime:   tt,
+		Error:       func(data ...any) { t.Error(data...) },
+	}}
+	ops, err := RunFor(context.Background(), put, 500*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := make(map[uint32]time.Time)
+	var gaps []time.Duration
+	ops.SortByStartTime()
+	for _, op := range ops {
+		if end, ok := last[op.Thread]; ok {
+			gaps = append(gaps, op.Start.Sub(end))
+		}
+		last[op.Thread] = op.End
+	}
+	if len(gaps) < 10 {
+		t.Fatalf("only %d gaps measured", len(gaps))
+	}
+	var sum time.Duration
+	for _, g := range gaps {
+		if g < think {
+			t.Errorf("gap %v shorter than think time %v", g, think)
+		}
+		sum += g
+	}
+	if mean := sum / time.Duration(len(gaps)); mean > think+10*time.Millisecond {
+		t.Errorf("mean gap %v, want about %v", mean, think)
+	}
+
+	// Multipart uploads pause before every part.
+	mp := &MultipartPut{
+		Common:           put.Common,
+		PartsNumber:      3,
+		PartsConcurrency: 1,
+	}
+	ops, err = RunFor(context.Background(), mp, 300*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clear(last)
+	ops.SortByStartTime()
+	n := 0
+	for _, op := range ops {
+		if end, ok := last[op.Thread]; ok {
+			if g := op.Start.Sub(end); g < think {
+				t.Errorf("multipart: gap %v shorter than think time %v", g, think)
+			}
+			n++
+		}
+		last[op.Thread] = op.End
+	}
+	if n < 5 {
+		t.Fatalf("multipart: only %d gaps measured", n)
+	}
+}
@@ -0,0 +1,213 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TraceOp is an operation read from a workload trace.
+type TraceOp struct {
+	PlannedOp
+
+	// Time is the timestamp of the operation in the trace.
+	Time time.Time
+
+	// Delay is the time since the previous operation, multiplied by TraceOptions.TimeScale.
+	// It is 0 for the first operation and if timestamps go backwards.
+	Delay time.Duration
+}
+
+// TraceOptions configures a TraceReader.
+type TraceOptions struct {
+	// TimeScale multiplies the time between operations.
+	// For example 0.5 replays the trace at double speed.
+	// 0 replays at the recorded speed.
+	TimeScale float64
+
+	// Warn is called with the line number and reason when a malformed line is skipped.
+	Warn func(line int, err error)
+}
+
+// TraceReader reads operations from a workload trace.
+//
+// Each line is either CSV with the fields timestamp, op, key, size,
+// or a JSON object with the keys "timestamp", "op", "key" and "size".
+// Both forms can be mixed, and a CSV header line is ignored.
+// Timestamps are RFC 3339 or Unix seconds with an optional fraction.
+// Operations are GET, PUT, DELETE and STAT, with HEAD accepted as STAT.
+// The size is required for PUT and optional for other operations.
+// Empty lines and lines starting with '#' are ignored.
+type TraceReader struct {
+	sc      *bufio.Scanner
+	opts    TraceOptions
+	line    int
+	skipped int
+	started bool
+	last    time.Time
+}
+
+// NewTraceReader returns a reader of the trace in r.
+func NewTraceReader(r io.Reader, opts TraceOptions) *TraceReader {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64<<10), 1<<20)
+	if opts.TimeScale <= 0 {
+		opts.TimeScale = 1
+	}
+	return &TraceReader{sc: sc, opts: opts}
+}
+
+// Skipped returns the number of malformed lines skipped so far.
+func (t *TraceReader) Skipped() int {
+	return t.skipped
+}
+
+// Next returns the next operation in the trace.
+// io.EOF is returned when the trace has been read.
+// Malformed lines are skipped; other errors are returned.
+func (t *TraceReader) Next() (TraceOp, error) {
+	for t.sc.Scan() {
+		t.line++
+		line := bytes.TrimSpace(t.sc.Bytes())
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		op, err := parseTraceLine(line)
+		if errors.Is(err, errTraceHeader) && !t.started {
+			t.started = true
+			continue
+		}
+		t.started = true
+		if err != nil {
+			t.skipped++
+			if t.opts.Warn != nil {
+				t.opts.Warn(t.line, err)
+			}
+			continue
+		}
+		if !t.last.IsZero() && op.Time.After(t.last) {
+			// Timestamps that go backwards get no delay.
+			op.Delay = time.Duration(float64(op.Time.Sub(t.last)) * t.opts.TimeScale)
+		}
+		if op.Time.After(t.last) {
+			t.last = op.Time
+		}
+		return op, nil
+	}
+	if err := t.sc.Err(); err != nil {
+		return TraceOp{}, fmt.Errorf("trace line %d: %w", t.line+1, err)
+	}
+	return TraceOp{}, io.EOF
+}
+
+var errTraceHeader = errors.New("header line")
+
+type traceJSON struct {
+	Timestamp json.RawMessage `json:"timestamp"`
+	Op        string          `json:"op"`
+	Key       string          `json:"key"`
+	Size      *int64          `json:"size"`
+}
+
+// parseTraceLine parses a single CSV or JSON trace line.
+func parseTraceLine(line []byte) (TraceOp, error) {
+	var ts, op, key, size string
+	if line[0] == '{' {
+		var j traceJSON
+		if err := json.Unmarshal(line, &j); err != nil {
+			return TraceOp{}, err
+		}
+		ts = strings.Trim(string(j.Timestamp), `"`)
+		op, key = j.Op, j.Key
+		if j.Size != nil {
+			size = strconv.FormatInt(*j.Size, 10)
+		}
+	} else {
+		r := csv.NewReader(bytes.NewReader(line))
+		r.FieldsPerRecord = -1
+		fields, err := r.Read()
+		if err != nil {
+			return TraceOp{}, err
+		}
+		if len(fields) < 3 || len(fields) > 4 {
+			return TraceOp{}, fmt.Errorf("got %d fields, want timestamp, op, key and size", len(fields))
+		}
+		if strings.EqualFold(fields[0], "timestamp") {
+			return TraceOp{}, errTraceHeader
+		}
+		ts, op, key = fields[0], fields[1], fields[2]
+		if len(fields) == 4 {
+			size = fields[3]
+		}
+	}
+
+	var res TraceOp
+	var err error
+	if res.Time, err = parseTraceTime(strings.TrimSpace(ts)); err != nil {
+		return TraceOp{}, err
+	}
+	switch op = strings.ToUpper(strings.TrimSpace(op)); op {
+	case http.MethodGet, http.MethodPut, http.MethodDelete, "STAT":
+	case http.MethodHead:
+		op = "STAT"
+	default:
+		return TraceOp{}, fmt.Errorf("unknown op %q", op)
+	}
+	res.OpType = op
+	if res.Key = key; key == "" {
+		return TraceOp{}, errors.New("empty key")
+	}
+	if size = strings.TrimSpace(size); size != "" {
+		if res.Size, err = strconv.ParseInt(size, 10, 64); err != nil || res.Size < 0 {
+			return TraceOp{}, fmt.Errorf("invalid size %q", size)
+		}
+	} else if op == http.MethodPut {
+		return TraceOp{}, errors.New("PUT without size")
+	}
+	return res, nil
+}
+
+// parseTraceTime parses an RFC 3339 timestamp or Unix seconds with an optional fraction.
+func parseTraceTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+	secs, frac, _ := strings.Cut(s, ".")
+	sec, err := strconv.ParseInt(secs, 10, 64)
+	if err != nil || len(frac) > 9 {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q", s)
+	}
+	var nsec int64
+	if frac != "" {
+		nsec, err = strconv.ParseInt(frac+strings.Repeat("0", 9-len(frac)), 10, 64)
+		if err != nil || nsec < 0 {
+			return time.Time{}, fmt.Errorf("invalid timestamp %q", s)
+		}
+	}
+	return time.Unix(sec, nsec).UTC(), nil
+}
@@ -0,0 +1,98 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTraceReader(t *testing.T) {
+	const trace = `timestamp,op,key,size
+# comment
+2025-01-02T03:04:05Z,PUT,a/1,1024
+2025-01-02T03:04:05.5Z,get,a/1,1024
+{"timestamp": 1735787046, "op": "HEAD", "key": "a/1"}
+2025-01-02T03:04:06Z,DELETE,a/1,not-a-size
+1735787047.25,PUT,"b,2",0
+
+{"timestamp": "2025-01-02T03:04:08Z", "op": "PUT", "key": "c/3", "size": 10485760}
+{"timestamp": "2025-01-02T03:04:09Z", "op": "PUT", "key": "c/4"
+2025-01-02T03:04:07Z,GET,c/3
+2025-01-02T03:04:10Z,COPY,c/3,1
+2025-01-02T03:04:10Z,DELETE,c/3
+`
+	var warned []int
+	r := NewTraceReader(strings.NewReader(trace), TraceOptions{
+		TimeScale: 0.5,
+		Warn:      func(line int, err error) { warned = append(warned, line) },
+	})
+	var got []TraceOp
+	for {
+		op, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, op)
+	}
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	op := func(typ, key string, size int64, at, delay time.Duration) TraceOp {
+		return TraceOp{PlannedOp: PlannedOp{OpType: typ, Key: key, Size: size}, Time: start.Add(at), Delay: delay}
+	}
+	want := []TraceOp{
+		op("PUT", "a/1", 1024, 0, 0),
+		op("GET", "a/1", 1024, 500*time.Millisecond, 250*time.Millisecond),
+		op("STAT", "a/1", 0, time.Second, 250*time.Millisecond),
+		op("PUT", "b,2", 0, 2250*time.Millisecond, 625*time.Millisecond),
+		op("PUT", "c/3", 10<<20, 3*time.Second, 375*time.Millisecond),
+		// Goes back in time.
+		op("GET", "c/3", 0, 2*time.Second, 0),
+		op("DELETE", "c/3", 0, 5*time.Second, time.Second),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got\n%+v\nwant\n%+v", got, want)
+	}
+	if r.Skipped() != 3 || !reflect.DeepEqual(warned, []int{6, 10, 12}) {
+		t.Errorf("skipped %d, warned for lines %v, want lines 6, 10 and 12", r.Skipped(), warned)
+	}
+}
+
+func TestParseTraceLine(t *testing.T) {
+	for _, line := range []string{
+		"2025-01-02T03:04:05Z,PUT,a",
+		"2025-01-02T03:04:05Z,GET,,1",
+		"2025-01-02T03:04:05Z,GET,a,-1",
+		"yesterday,GET,a,1",
+		"1.1234567890,GET,a,1",
+		"1,GET",
+		"1,GET,a,1,extra",
+		`{"timestamp": 1, "op": "GET"}`,
+		`{"timestamp": 1, "op": "GET", "key": "a", "size": -1}`,
+	} {
+		if _, err := parseTraceLine([]byte(line)); err == nil {
+			t.Errorf("%q: expected error", line)
+		}
+	}
+}
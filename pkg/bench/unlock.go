@@ -0,0 +1,59 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/warp/pkg/generator"
+)
+
+// objectUnlocker releases Object Lock on objects so they can be deleted.
+// It is implemented by *minio.Client.
+type objectUnlocker interface {
+	PutObjectLegalHold(ctx context.Context, bucketName, objectName string, opts minio.PutObjectLegalHoldOptions) error
+	GetObjectRetention(ctx context.Context, bucketName, objectName, versionID string) (mode *minio.RetentionMode, retainUntilDate *time.Time, err error)
+}
+
+// releaseLocks removes the legal holds of the locked objects in objs
+// and returns the objects with COMPLIANCE retention,
+// which cannot be deleted until the retention expires.
+// Objects with GOVERNANCE retention can be deleted with a governance bypass.
+// Nothing is done if client cannot release locks.
+// Failures are not returned, since deleting the objects will report them.
+func releaseLocks(ctx context.Context, client any, bucket string, objs generator.Objects) (compliance generator.Objects) {
+	u, ok := client.(objectUnlocker)
+	if !ok {
+		return nil
+	}
+	off := minio.LegalHoldDisabled
+	now := time.Now()
+	for _, obj := range objs {
+		if !obj.Locked {
+			continue
+		}
+		_ = u.PutObjectLegalHold(ctx, bucket, obj.Name, minio.PutObjectLegalHoldOptions{VersionID: obj.VersionID, Status: &off})
+		mode, until, err := u.GetObjectRetention(ctx, bucket, obj.Name, obj.VersionID)
+		if err == nil && mode != nil && *mode == minio.Compliance && until != nil && until.After(now) {
+			compliance = append(compliance, obj)
+		}
+	}
+	return compliance
+}
@@ -0,0 +1,278 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/minio/warp/pkg/generator"
+)
+
+// objectVersion identifies a version of an object.
+type objectVersion struct {
+	name, versionID string
+}
+
+// UploadTracker records the objects created by the requests sent through its transport,
+// so they can be deleted after a benchmark.
+// Uploads, copies, appends, completed multipart uploads and delete markers
+// are added and successful deletes are removed, per bucket and version.
+// Only requests to the buckets given to NewUploadTracker are tracked.
+// A nil UploadTracker tracks nothing.
+// It is safe for concurrent use.
+type UploadTracker struct {
+	buckets []string
+
+	mu      sync.Mutex
+	objects map[string]map[objectVersion]generator.Object
+	// locked are the keys of multipart uploads started with Object Lock.
+	locked map[string]bool
+}
+
+// NewUploadTracker returns a tracker for objects in buckets.
+func NewUploadTracker(buckets []string) *UploadTracker {
+	return &UploadTracker{
+		buckets: buckets,
+		objects: make(map[string]map[objectVersion]generator.Object),
+		locked:  make(map[string]bool),
+	}
+}
+
+// Transport returns a transport that sends requests to rt and tracks the objects they create.
+// If t is nil, rt is returned.
+func (t *UploadTracker) Transport(rt http.RoundTripper) http.RoundTripper {
+	if t == nil {
+		return rt
+	}
+	return uploadTrackerTransport{RoundTripper: rt, t: t}
+}
+
+// Len returns the number of tracked object versions in all buckets.
+func (t *UploadTracker) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	n := 0
+	for _, objs := range t.objects {
+		n += len(objs)
+	}
+	return n
+}
+
+// Buckets returns the sorted names of the buckets with tracked objects.
+func (t *UploadTracker) Buckets() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var buckets []string
+	for bucket, objs := range t.objects {
+		if len(objs) > 0 {
+			buckets = append(buckets, bucket)
+		}
+	}
+	slices.Sort(buckets)
+	return buckets
+}
+
+// Objects returns the tracked objects in bucket.
+// The order is not specified.
+func (t *UploadTracker) Objects(bucket string) generator.Objects {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	objs := make(generator.Objects, 0, len(t.objects[bucket]))
+	for _, obj := range t.objects[bucket] {
+		objs = append(objs, obj)
+	}
+	return objs
+}
+
+// DeleteAll deletes the tracked objects in every bucket.
+// See DeleteAll for the parameters and the handling of locked objects.
+// Objects that are not deleted remain tracked.
+func (t *UploadTracker) DeleteAll(ctx context.Context, client ObjectRemover, concurrency, batchSize int) DeleteAllResult {
+	var res DeleteAllResult
+	for _, bucket := range t.Buckets() {
+		objs := t.Objects(bucket)
+		for _, obj := range objs {
+			t.remove(bucket, obj.Name, obj.VersionID)
+		}
+		r, remaining := deleteObjects(ctx, client, bucket, objs, concurrency, batchSize)
+		for _, obj := range remaining {
+			t.add(bucket, obj)
+		}
+		res.Deleted += r.Deleted
+		res.Failed = append(res.Failed, r.Failed...)
+		res.Compliance = append(res.Compliance, r.Compliance...)
+	}
+	return res
+}
+
+func (t *UploadTracker) add(bucket string, obj generator.Object) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	objs := t.objects[bucket]
+	if objs == nil {
+		objs = make(map[objectVersion]generator.Object)
+		t.objects[bucket] = objs
+	}
+	objs[objectVersion{name: obj.Name, versionID: obj.VersionID}] = obj
+}
+
+func (t *UploadTracker) remove(bucket, name, versionID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.objects[bucket], objectVersion{name: name, versionID: versionID})
+}
+
+// setLocked records whether the multipart upload of the object is locked
+// and returns the previous value.
+func (t *UploadTracker) setLocked(bucket, name string, locked bool) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	key := bucket + "/" + name
+	was := t.locked[key]
+	if locked {
+		t.locked[key] = true
+	} else {
+		delete(t.locked, key)
+	}
+	return was
+}
+
+// object returns the bucket and object name of req.
+// Both path and virtual host style requests are supported.
+// ok is false if the request is not for an object in a tracked bucket.
+func (t *UploadTracker) object(req *http.Request) (bucket, name string, ok bool) {
+	path := strings.TrimPrefix(req.URL.Path, "/")
+	host := req.URL.Hostname()
+	for _, b := range t.buckets {
+		if strings.HasPrefix(host, b+".") {
+			return b, path, path != ""
+		}
+	}
+	bucket, name, _ = strings.Cut(path, "/")
+	return bucket, name, name != "" && slices.Contains(t.buckets, bucket)
+}
+
+// bucket returns the bucket of a request for a tracked bucket, such as DeleteObjects.
+func (t *UploadTracker) bucket(req *http.Request) (string, bool) {
+	path := strings.Trim(req.URL.Path, "/")
+	host := req.URL.Hostname()
+	for _, b := range t.buckets {
+		if path == b || path == "" && strings.HasPrefix(host, b+".") {
+			return b, true
+		}
+	}
+	return "", false
+}
+
+// requestLocked returns whether req sets Object Lock retention or a legal hold.
+func requestLocked(req *http.Request) bool {
+	return req.Header.Get("X-Amz-Object-Lock-Mode") != "" ||
+		strings.EqualFold(req.Header.Get("X-Amz-Object-Lock-Legal-Hold"), "ON")
+}
+
+// uploadTrackerTransport tracks the objects created by successful requests.
+type uploadTrackerTransport struct {
+	http.RoundTripper
+	t *UploadTracker
+}
+
+// RoundTrip sends req and records the objects created or deleted by it.
+func (u uploadTrackerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := u.RoundTripper.RoundTrip(req)
+	if err != nil || resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp, err
+	}
+	if req.Method == http.MethodPost && req.URL.Query().Has("delete") {
+		if bucket, ok := u.t.bucket(req); ok {
+			return u.deleted(bucket, resp)
+		}
+		return resp, nil
+	}
+	bucket, name, ok := u.t.object(req)
+	if !ok {
+		return resp, nil
+	}
+	q := req.URL.Query()
+	versionID := resp.Header.Get("X-Amz-Version-Id")
+	switch req.Method {
+	case http.MethodPut:
+		// Parts, tags, retention and other subresources do not create objects.
+		if len(q) == 0 {
+			u.t.add(bucket, generator.Object{Name: name, VersionID: versionID, Size: max(req.ContentLength, 0), Locked: requestLocked(req)})
+		}
+	case http.MethodPost:
+		switch {
+		case q.Has("uploads"):
+			u.t.setLocked(bucket, name, requestLocked(req))
+		case q.Has("uploadId"):
+			u.t.add(bucket, generator.Object{Name: name, VersionID: versionID, Locked: u.t.setLocked(bucket, name, false)})
+		}
+	case http.MethodDelete:
+		switch {
+		case q.Has("versionId"):
+			u.t.remove(bucket, name, q.Get("versionId"))
+		case q.Has("uploadId"):
+			u.t.setLocked(bucket, name, false)
+		case resp.Header.Get("X-Amz-Delete-Marker") == "true" && versionID != "":
+			u.t.add(bucket, generator.Object{Name: name, VersionID: versionID})
+		default:
+			u.t.remove(bucket, name, "")
+		}
+	}
+	return resp, nil
+}
+
+// deleteResult is the response to a DeleteObjects request.
+type deleteResult struct {
+	Deleted []struct {
+		Key                   string
+		VersionID             string `xml:"VersionId"`
+		DeleteMarker          bool
+		DeleteMarkerVersionID string `xml:"DeleteMarkerVersionId"`
+	}
+}
+
+// deleted records the objects deleted by a DeleteObjects request.
+// The response body is read and replaced.
+func (u uploadTrackerTransport) deleted(bucket string, resp *http.Response) (*http.Response, error) {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return resp, err
+	}
+	var res deleteResult
+	if xml.Unmarshal(body, &res) != nil {
+		return resp, nil
+	}
+	for _, d := range res.Deleted {
+		if d.DeleteMarker && d.VersionID == "" && d.DeleteMarkerVersionID != "" {
+			u.t.add(bucket, generator.Object{Name: d.Key, VersionID: d.DeleteMarkerVersionID})
+			continue
+		}
+		u.t.remove(bucket, d.Key, d.VersionID)
+	}
+	return resp, nil
+}
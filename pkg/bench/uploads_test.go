@@ -0,0 +1,141 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/minio/warp/pkg/generator"
+)
+
+// fixedResponses answers every request with the status and headers of the first matching rule.
+type fixedResponses struct {
+	rules []fixedResponse
+}
+
+type fixedResponse struct {
+	method, url string
+	status      int
+	header      map[string]string
+	body        string
+}
+
+func (f fixedResponses) RoundTrip(req *http.Request) (*http.Response, error) {
+	for _, r := range f.rules {
+		if r.method != req.Method || r.url != req.URL.String() {
+			continue
+		}
+		resp := &http.Response{StatusCode: r.status, Header: make(http.Header), Body: io.NopCloser(strings.NewReader(r.body))}
+		for k, v := range r.header {
+			resp.Header.Set(k, v)
+		}
+		return resp, nil
+	}
+	return &http.Response{StatusCode: http.StatusNotFound, Header: make(http.Header), Body: http.NoBody}, nil
+}
+
+func TestUploadTracker(t *testing.T) {
+	const deleted = `<DeleteResult><Deleted><Key>del/a</Key></Deleted>` +
+		`<Deleted><Key>del/b</Key><DeleteMarker>true</DeleteMarker><DeleteMarkerVersionId>m2</DeleteMarkerVersionId></Deleted></DeleteResult>`
+	tr := fixedResponses{rules: []fixedResponse{
+		{method: http.MethodPut, url: "http://s3/bucket/put", status: 200},
+		{method: http.MethodPut, url: "http://s3/bucket/put?tagging=", status: 200},
+		{method: http.MethodPut, url: "http://s3/bucket/versioned", status: 200, header: map[string]string{"X-Amz-Version-Id": "v1"}},
+		{method: http.MethodPut, url: "http://s3/bucket/locked", status: 200},
+		{method: http.MethodPut, url: "http://s3/bucket/denied", status: 403},
+		{method: http.MethodPut, url: "http://s3/other/put", status: 200},
+		{method: http.MethodPut, url: "http://bucket2.s3/virtual", status: 200},
+		{method: http.MethodPut, url: "http://s3/bucket/multi?partNumber=1&uploadId=u", status: 200},
+		{method: http.MethodPost, url: "http://s3/bucket/multi?uploads=", status: 200},
+		{method: http.MethodPost, url: "http://s3/bucket/multi?uploadId=u", status: 200},
+		{method: http.MethodPut, url: "http://s3/bucket/del/a", status: 200},
+		{method: http.MethodPut, url: "http://s3/bucket/del/b", status: 200, header: map[string]string{"X-Amz-Version-Id": "v2"}},
+		{method: http.MethodPost, url: "http://s3/bucket?delete=", status: 200, body: deleted},
+		{method: http.MethodPut, url: "http://s3/bucket/removed", status: 204},
+		{method: http.MethodDelete, url: "http://s3/bucket/removed", status: 204},
+		{method: http.MethodDelete, url: "http://s3/bucket/versioned", status: 204, header: map[string]string{"X-Amz-Delete-Marker": "true", "X-Amz-Version-Id": "m1"}},
+	}}
+	var nilTracker *UploadTracker
+	if nilTracker.Transport(tr) == nil {
+		t.Fatal("nil tracker must return the transport")
+	}
+	u := NewUploadTracker([]string{"bucket", "bucket2"})
+	client := &http.Client{Transport: u.Transport(tr)}
+	for _, r := range tr.rules {
+		req, err := http.NewRequest(r.method, r.url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.HasSuffix(r.url, "/locked") || strings.HasSuffix(r.url, "?uploads=") {
+			req.Header.Set("X-Amz-Object-Lock-Legal-Hold", "ON")
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil || string(body) != r.body {
+			t.Fatalf("%s %s: body %q, %v", r.method, r.url, body, err)
+		}
+	}
+
+	got := func(bucket string) []string {
+		var res []string
+		for _, obj := range u.Objects(bucket) {
+			s := obj.Name
+			if obj.VersionID != "" {
+				s += "@" + obj.VersionID
+			}
+			if obj.Locked {
+				s += " locked"
+			}
+			res = append(res, s)
+		}
+		slices.Sort(res)
+		return res
+	}
+	if b := u.Buckets(); !slices.Equal(b, []string{"bucket", "bucket2"}) {
+		t.Fatalf("got buckets %v", b)
+	}
+	want := []string{"del/b@m2", "del/b@v2", "locked locked", "multi locked", "put", "versioned@m1", "versioned@v1"}
+	if g := got("bucket"); !slices.Equal(g, want) {
+		t.Errorf("got %v, want %v", g, want)
+	}
+	if g := got("bucket2"); !slices.Equal(g, []string{"virtual"}) {
+		t.Errorf("got %v, want [virtual]", g)
+	}
+	if u.Len() != len(want)+1 {
+		t.Errorf("got %d objects, want %d", u.Len(), len(want)+1)
+	}
+
+	// Objects that fail to delete remain tracked.
+	u.add("bucket", generator.Object{Name: "fail"})
+	res := u.DeleteAll(context.Background(), &mockRemover{}, 2, 1000)
+	if res.Deleted != len(want)+1 || len(res.Failed) != 1 {
+		t.Errorf("deleted %d, failed %d", res.Deleted, len(res.Failed))
+	}
+	if g := got("bucket"); u.Len() != 1 || !slices.Equal(g, []string{"fail"}) {
+		t.Errorf("got %v remaining", g)
+	}
+}
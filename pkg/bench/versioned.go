@@ -135,6 +135,7 @@ func (g *Versioned) Start(ctx context.Context, wait chan struct{}) error {
 	nonTerm := context.Background()
 	for i := 0; i < g.Concurrency; i++ {
 		go func(i int) {
+			getClient := g.workerClient(i)
 			rcv := c.Receiver()
 			defer wg.Done()
 			done := ctx.Done()
@@ -144,6 +145,9 @@ func (g *Versioned) Start(ctx context.Context, wait chan struct{}) error {
 			getOpts := g.GetOpts
 
 			<-wait
+			if g.RampUp.Wait(ctx, i) != nil {
+				return
+			}
 			for {
 				select {
 				case <-done:
@@ -151,6 +155,9 @@ func (g *Versioned) Start(ctx context.Context, wait chan struct{}) error {
 				default:
 				}
 
+				if g.ThinkTime.Wait(ctx) != nil {
+					return
+				}
 				if g.rpsLimit(ctx) != nil {
 					return
 				}
@@ -160,7 +167,7 @@ func (g *Versioned) Start(ctx context.Context, wait chan struct{}) error {
 				case http.MethodGet:
 					fbr := firstByteRecorder{}
 					obj, objDone := g.Dist.randomObjRead()
-					client, clDone := g.Client()
+					client, clDone := getClient()
 					op := Operation{
 						OpType:   operation,
 						Thread:   uint32(i),
@@ -176,7 +183,7 @@ func (g *Versioned) Start(ctx context.Context, wait chan struct{}) error {
 					fbr.r, err = client.GetObject(nonTerm, g.Bucket, obj.Name, getOpts)
 					if err != nil {
 						g.Error("download error: ", err)
-						op.Err = err.Error()
+						op.SetErr(err)
 						op.End = time.Now()
 						rcv <- op
 						clDone()
@@ -186,7 +193,7 @@ func (g *Versioned) Start(ctx context.Context, wait chan struct{}) error {
 					n, err := io.Copy(io.Discard, &fbr)
 					if err != nil {
 						g.Error("download error: ", err)
-						op.Err = err.Error()
+						op.SetErr(err)
 					}
 					op.FirstByte = fbr.t
 					op.End = time.Now()
@@ -200,7 +207,7 @@ func (g *Versioned) Start(ctx context.Context, wait chan struct{}) error {
 				case http.MethodPut:
 					obj, objDone := g.Dist.newVersion(src.Object())
 					putOpts.ContentType = obj.ContentType
-					client, clDone := g.Client()
+					client, clDone := getClient()
 					op := Operation{
 						OpType:   operation,
 						Thread:   uint32(i),
@@ -215,7 +222,7 @@ func (g *Versioned) Start(ctx context.Context, wait chan struct{}) error {
 					op.End = time.Now()
 					if err != nil {
 						g.Error("upload error: ", err)
-						op.Err = err.Error()
+						op.SetErr(err)
 					}
 
 					obj.VersionID = res.VersionID
@@ -235,7 +242,7 @@ func (g *Versioned) Start(ctx context.Context, wait chan struct{}) error {
 					objDone(res.VersionID)
 					rcv <- op
 				case http.MethodDelete:
-					client, clDone := g.Client()
+					client, clDone := getClient()
 					obj := g.Dist.deleteRandomObj()
 					op := Operation{
 						OpType:   operation,
@@ -251,12 +258,12 @@ func (g *Versioned) Start(ctx context.Context, wait chan struct{}) error {
 					clDone()
 					if err != nil {
 						g.Error("delete error:", err)
-						op.Err = err.Error()
+						op.SetErr(err)
 					}
 					rcv <- op
 				case "STAT":
 					obj, objDone := g.Dist.randomObjRead()
-					client, clDone := g.Client()
+					client, clDone := getClient()
 					op := Operation{
 						OpType:   operation,
 						Thread:   uint32(i),
@@ -271,7 +278,7 @@ func (g *Versioned) Start(ctx context.Context, wait chan struct{}) error {
 					objI, err := client.StatObject(nonTerm, g.Bucket, obj.Name, statOpts)
 					if err != nil {
 						g.Error("stat error:", err)
-						op.Err = err.Error()
+						op.SetErr(err)
 					}
 					op.End = time.Now()
 					if objI.Size != obj.Size && op.Err == "" {
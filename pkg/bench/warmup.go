@@ -0,0 +1,85 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type warmupCollector struct {
+	c     Collector
+	rcv   chan Operation
+	until func() time.Time
+	once  sync.Once
+	wg    sync.WaitGroup
+}
+
+// NewWarmupCollector returns a collector that forwards operations to c,
+// except operations that started before until.
+// Closing the returned collector will also close c.
+func NewWarmupCollector(c Collector, until time.Time) Collector {
+	return NewWarmupCollectorFunc(c, func() time.Time { return until })
+}
+
+// NewWarmupCollectorFunc is like NewWarmupCollector,
+// but the end of the warm-up is returned by until when the first operation is received.
+// It is used when the benchmark start time is set after the collector is created.
+func NewWarmupCollectorFunc(c Collector, until func() time.Time) Collector {
+	w := &warmupCollector{
+		c:     c,
+		rcv:   make(chan Operation, 1000),
+		until: until,
+	}
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		dst := c.Receiver()
+		var end time.Time
+		for op := range w.rcv {
+			if end.IsZero() {
+				end = w.until()
+			}
+			if op.Start.Before(end) {
+				continue
+			}
+			dst <- op
+		}
+	}()
+	return w
+}
+
+// AutoTerm forwards to the wrapped collector.
+func (w *warmupCollector) AutoTerm(ctx context.Context, op string, threshold float64, wantSamples, splitInto int, minDur time.Duration) context.Context {
+	return w.c.AutoTerm(ctx, op, threshold, wantSamples, splitInto, minDur)
+}
+
+// Receiver returns the receiver of input.
+func (w *warmupCollector) Receiver() chan<- Operation {
+	return w.rcv
+}
+
+// Close the collector and the wrapped collector.
+func (w *warmupCollector) Close() {
+	w.once.Do(func() {
+		close(w.rcv)
+		w.wg.Wait()
+		w.c.Close()
+	})
+}
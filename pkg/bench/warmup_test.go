@@ -0,0 +1,78 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWarmupCollector(t *testing.T) {
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	warmup := 10 * time.Second
+	inner, retrieve := NewOpsCollector()
+	c := NewWarmupCollector(inner, start.Add(warmup))
+
+	rcv := c.Receiver()
+	// One operation every 500ms, for 20 seconds.
+	// Operations started during warmup but ending after it are also dropped.
+	for i := range 40 {
+		opStart := start.Add(time.Duration(i) * 500 * time.Millisecond)
+		rcv <- Operation{
+			OpType:   "GET",
+			Start:    opStart,
+			End:      opStart.Add(time.Second),
+			ObjPerOp: 1,
+			Size:     100,
+		}
+	}
+	c.Close()
+	// Close must be safe to call again.
+	c.Close()
+
+	ops := retrieve()
+	if len(ops) != 20 {
+		t.Fatalf("got %d operations, want 20", len(ops))
+	}
+	for _, op := range ops {
+		if op.Start.Before(start.Add(warmup)) {
+			t.Errorf("operation started at %v is inside warmup", op.Start.Sub(start))
+		}
+	}
+	if first, _ := ops.TimeRange(); !first.Equal(start.Add(warmup)) {
+		t.Errorf("first operation at %v, want %v", first.Sub(start), warmup)
+	}
+}
+
+func TestWarmupCollectorFunc(t *testing.T) {
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	inner, retrieve := NewOpsCollector()
+	// The start time is only known once operations are running.
+	var benchStart time.Time
+	c := NewWarmupCollectorFunc(inner, func() time.Time { return benchStart.Add(time.Second) })
+	benchStart = start
+	rcv := c.Receiver()
+	for i := range 4 {
+		opStart := start.Add(time.Duration(i) * 500 * time.Millisecond)
+		rcv <- Operation{OpType: "GET", Start: opStart, End: opStart.Add(time.Millisecond)}
+	}
+	c.Close()
+	if ops := retrieve(); len(ops) != 2 {
+		t.Fatalf("got %d operations, want 2", len(ops))
+	}
+}
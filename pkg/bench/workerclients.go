@@ -0,0 +1,60 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"sync"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// WorkerClients creates a separate client function for every benchmark worker,
+// so workers do not share HTTP transports and connection pools.
+// Use Client as Common.WorkerClient.
+type WorkerClients struct {
+	newClient func(worker int) func() (*minio.Client, func())
+
+	mu      sync.Mutex
+	clients map[int]func() (*minio.Client, func())
+}
+
+// NewWorkerClients returns clients created by newClient.
+// newClient is called once per worker, the first time the worker requests a client,
+// and should create new clients with their own transport.
+func NewWorkerClients(newClient func(worker int) func() (*minio.Client, func())) *WorkerClients {
+	return &WorkerClients{newClient: newClient, clients: make(map[int]func() (*minio.Client, func()))}
+}
+
+// Client returns the client function of worker.
+func (w *WorkerClients) Client(worker int) func() (*minio.Client, func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	cl, ok := w.clients[worker]
+	if !ok {
+		cl = w.newClient(worker)
+		w.clients[worker] = cl
+	}
+	return cl
+}
+
+// Len returns the number of workers that have clients.
+func (w *WorkerClients) Len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.clients)
+}
@@ -0,0 +1,165 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/warp/pkg/generator"
+)
+
+// countingTransport counts requests sent through it.
+type countingTransport struct {
+	rt       http.RoundTripper
+	requests atomic.Int64
+}
+
+func (c *countingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	c.requests.Add(1)
+	return c.rt.RoundTrip(r)
+}
+
+// newPutServer returns a server that accepts all PUT requests and multipart uploads.
+func newPutServer(t *testing.T) *url.URL {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Header().Set("ETag", `"d41d8cd98f00b204e9800998ecf8427e"`)
+		w.WriteHeader(http.StatusOK)
+		if r.Method != http.MethodPost {
+			return
+		}
+		if r.URL.Query().Has("uploads") {
+			io.WriteString(w, "<InitiateMultipartUploadResult><Bucket>bucket</Bucket><Key>key</Key><UploadId>upload</UploadId></InitiateMultipartUploadResult>")
+			return
+		}
+		io.WriteString(w, `<CompleteMultipartUploadResult><Bucket>bucket</Bucket><Key>key</Key><ETag>"etag"</ETag></CompleteMultipartUploadResult>`)
+	}))
+	t.Cleanup(srv.Close)
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+func TestWorkerClients(t *testing.T) {
+	u := newPutServer(t)
+	var mu sync.Mutex
+	transports := make(map[int]*countingTransport)
+	newClient := func(worker int) func() (*minio.Client, func()) {
+		tr := &countingTransport{rt: http.DefaultTransport.(*http.Transport).Clone()}
+		cl, err := minio.New(u.Host, &minio.Options{
+			Creds:     credentials.NewStaticV4("access", "secret", ""),
+			Region:    "us-east-1",
+			Transport: tr,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		mu.Lock()
+		if _, ok := transports[worker]; ok {
+			t.Errorf("worker %d: client created twice", worker)
+		}
+		transports[worker] = tr
+		mu.Unlock()
+		return func() (*minio.Client, func()) { return cl, func() {} }
+	}
+
+	src, err := generator.NewFn(generator.WithRandomData().Apply(), generator.WithSize(1<<10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	run := func(c Common) Operations {
+		c.Source = src
+		c.Bucket = "bucket"
+		c.Concurrency = 4
+		c.Error = func(data ...any) { t.Log(data...) }
+		ops, err := RunFor(context.Background(), &Put{Common: c}, 200*time.Millisecond)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ops
+	}
+
+	// Isolated: each worker has a transport that only it uses.
+	wc := NewWorkerClients(newClient)
+	ops := run(Common{WorkerClient: wc.Client})
+	if wc.Len() != 4 || len(transports) != 4 {
+		t.Fatalf("got %d workers with %d transports, want 4", wc.Len(), len(transports))
+	}
+	perThread := make(map[int]int64)
+	for _, op := range ops {
+		if op.Err != "" {
+			t.Fatalf("operation error: %s", op.Err)
+		}
+		perThread[int(op.Thread)]++
+	}
+	for worker, tr := range transports {
+		if n := tr.requests.Load(); n == 0 || n != perThread[worker] {
+			t.Errorf("worker %d: %d requests on its transport, %d operations", worker, n, perThread[worker])
+		}
+	}
+
+	// Shared: all workers use the same transport.
+	clear(transports)
+	shared := newClient(0)
+	ops = run(Common{Client: shared})
+	if len(transports) != 1 {
+		t.Fatalf("got %d transports, want 1", len(transports))
+	}
+	if n := transports[0].requests.Load(); n != int64(len(ops)) {
+		t.Errorf("shared transport: %d requests, %d operations", n, len(ops))
+	}
+
+	// Multipart uploads use the client of the worker for every request.
+	clear(transports)
+	wc = NewWorkerClients(newClient)
+	mp := &MultipartPut{
+		Common: Common{
+			WorkerClient: wc.Client,
+			Source:       src,
+			Bucket:       "bucket",
+			Concurrency:  4,
+			Error:        func(data ...any) { t.Error(data...) },
+		},
+		PartsNumber:      3,
+		PartsConcurrency: 2,
+	}
+	ops, err = RunFor(context.Background(), mp, 200*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wc.Len() != 4 || len(ops) == 0 {
+		t.Fatalf("got %d workers for %d parts, want 4", wc.Len(), len(ops))
+	}
+	for worker, tr := range transports {
+		if tr.requests.Load() == 0 {
+			t.Errorf("worker %d: no requests on its transport", worker)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// ZipfSelector picks positions in a set of objects with Zipf distributed popularity.
+// Position k of n is picked with a probability proportional to 1/(k+1)^skew,
+// so the first objects are the hot keys.
+// Any skew > 0 is supported, higher values concentrate picks on fewer objects.
+// It is safe for concurrent use.
+type ZipfSelector struct {
+	skew float64
+
+	mu  sync.Mutex
+	rng *rand.Rand
+	// cdf is the cumulative weight of each position for the last n.
+	cdf []float64
+}
+
+// NewZipfSelector returns a selector with the given skew.
+// The same seed gives the same sequence of picks.
+func NewZipfSelector(skew float64, seed int64) (*ZipfSelector, error) {
+	if !(skew > 0) || math.IsInf(skew, 1) {
+		return nil, errors.New("NewZipfSelector: skew must be > 0")
+	}
+	return &ZipfSelector{skew: skew, rng: rand.New(rand.NewSource(seed))}, nil
+}
+
+// Skew returns the configured skew.
+func (z *ZipfSelector) Skew() float64 {
+	return z.skew
+}
+
+// Index returns the position of the next object in a set of n objects.
+// n must be > 0. n may change between calls, but changing it
+// recomputes the distribution, which takes time proportional to n.
+func (z *ZipfSelector) Index(n int) int {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+	if len(z.cdf) != n {
+		z.reset(n)
+	}
+	u := z.rng.Float64() * z.cdf[n-1]
+	return min(sort.SearchFloat64s(z.cdf, u), n-1)
+}
+
+// reset computes the cumulative weights for n objects.
+func (z *ZipfSelector) reset(n int) {
+	if cap(z.cdf) >= n {
+		z.cdf = z.cdf[:n]
+	} else {
+		z.cdf = make([]float64, n)
+	}
+	var sum float64
+	for k := range z.cdf {
+		sum += math.Pow(float64(k+1), -z.skew)
+		z.cdf[k] = sum
+	}
+}
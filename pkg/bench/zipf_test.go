@@ -0,0 +1,102 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/minio/warp/pkg/generator"
+)
+
+func TestZipfSelector(t *testing.T) {
+	const n, draws = 1000, 200_000
+	for _, skew := range []float64{0.8, 1, 1.5} {
+		z, err := NewZipfSelector(skew, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts := make([]int, n)
+		for range draws {
+			idx := z.Index(n)
+			if idx < 0 || idx >= n {
+				t.Fatalf("skew %v: index %d out of range", skew, idx)
+			}
+			counts[idx]++
+		}
+		var total float64
+		for k := range n {
+			total += math.Pow(float64(k+1), -skew)
+		}
+		// The most popular keys follow the configured shape.
+		for k := range 5 {
+			want := draws * math.Pow(float64(k+1), -skew) / total
+			if got := float64(counts[k]); math.Abs(got-want) > want*0.05 {
+				t.Errorf("skew %v: key %d picked %v times, want %.0f", skew, k, got, want)
+			}
+		}
+		// The top 1% of keys dominate compared to a uniform pick.
+		var top int
+		for _, c := range counts[:n/100] {
+			top += c
+		}
+		if frac := float64(top) / draws; frac < 0.2 {
+			t.Errorf("skew %v: top 1%% of keys got %.2f of picks", skew, frac)
+		}
+	}
+
+	// Same seed, same sequence. n may change.
+	a, _ := NewZipfSelector(1.2, 42)
+	b, _ := NewZipfSelector(1.2, 42)
+	for i := range 1000 {
+		n := 1 + i%7
+		x, y := a.Index(n), b.Index(n)
+		if x != y {
+			t.Fatalf("pick %d: %d != %d for same seed", i, x, y)
+		}
+		if x >= n {
+			t.Fatalf("pick %d: index %d of %d", i, x, n)
+		}
+	}
+
+	// Picks from an object set.
+	set := NewObjectSet()
+	for i := range 10 {
+		set.Add(generator.Object{Name: strconv.Itoa(i)})
+	}
+	hot := 0
+	for range 1000 {
+		obj, ok := set.Pick(a)
+		if !ok {
+			t.Fatal("no object picked")
+		}
+		if obj.Name == "0" {
+			hot++
+		}
+	}
+	if hot < 300 {
+		t.Errorf("first object picked %d of 1000 times", hot)
+	}
+
+	for _, skew := range []float64{0, -1, math.NaN(), math.Inf(1)} {
+		if _, err := NewZipfSelector(skew, 1); err == nil {
+			t.Errorf("skew %v: expected error", skew)
+		}
+	}
+}
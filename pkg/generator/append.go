@@ -0,0 +1,62 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"fmt"
+	"io"
+)
+
+// AppendPattern generates the content of objects that are written in parts,
+// for example with appends or partial overwrites.
+// Every part continues the static pattern at the offset it is written to,
+// so the combined object can be verified with Verify and Pattern.
+// It is safe for concurrent use.
+type AppendPattern struct {
+	r *staticReader
+}
+
+// NewAppendPattern returns an AppendPattern repeating the static pattern of the given size.
+// Pattern sizes <= 0 use the default 128KB pattern.
+func NewAppendPattern(patternSize int) *AppendPattern {
+	return &AppendPattern{r: newStaticReader(patternSize)}
+}
+
+// Pattern returns the pattern the combined object must contain.
+// The pattern must not be modified.
+func (a *AppendPattern) Pattern() []byte {
+	return a.r.pattern
+}
+
+// Original returns a reader with the first size bytes of the object.
+func (a *AppendPattern) Original(size int64) (io.ReadSeeker, error) {
+	return a.Append(0, size)
+}
+
+// Append returns a reader with appendSize bytes, that continues an object of originalSize bytes.
+// The pattern offset starts where the original object ends.
+// Appending the returned bytes to the object contains the same as Original(originalSize+appendSize).
+// An error is returned if either size is negative.
+func (a *AppendPattern) Append(originalSize, appendSize int64) (io.ReadSeeker, error) {
+	if originalSize < 0 || appendSize < 0 {
+		return nil, fmt.Errorf("append pattern: negative size: %d+%d", originalSize, appendSize)
+	}
+	r := a.r.Clone()
+	r.ResetSize(originalSize + appendSize)
+	return io.NewSectionReader(r, originalSize, appendSize), nil
+}
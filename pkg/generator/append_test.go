@@ -0,0 +1,91 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestAppendPattern(t *testing.T) {
+	const patternSize = 1000
+	a := NewAppendPattern(patternSize)
+	// Sizes crossing pattern boundaries, and empty parts.
+	parts := []int64{1500, 0, 1, 999, 2500, 7}
+	var object []byte
+	var size int64
+	for i, n := range parts {
+		var r io.Reader
+		var err error
+		if i == 0 {
+			r, err = a.Original(n)
+		} else {
+			r, err = a.Append(size, n)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The appended bytes start at the original end of the object.
+		if want := PatternBytesAt(patternSize, size, n); !bytes.Equal(b, want) {
+			t.Fatalf("part %d: %d bytes at offset %d do not continue the pattern", i, len(b), size)
+		}
+		if int64(len(b)) != n {
+			t.Fatalf("part %d: got %d bytes, want %d", i, len(b), n)
+		}
+		object = append(object, b...)
+		size += n
+	}
+	if err := Verify(bytes.NewReader(object), a.Pattern(), size); err != nil {
+		t.Fatalf("combined object: %v", err)
+	}
+	orig, _ := a.Original(size)
+	all, _ := io.ReadAll(orig)
+	if !bytes.Equal(all, object) {
+		t.Error("combined object differs from a single upload")
+	}
+
+	// Parts can be read again after seeking.
+	r, _ := a.Append(1234, 100)
+	io.CopyN(io.Discard, r, 40)
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := io.ReadAll(r); !bytes.Equal(b, PatternBytesAt(patternSize, 1234, 100)) {
+		t.Error("seeked part does not match")
+	}
+
+	// Appending restarted data breaks verification.
+	bad := append(object, PatternBytesAt(patternSize, 0, 10)...)
+	var mm *MismatchError
+	if err := Verify(bytes.NewReader(bad), a.Pattern(), int64(len(bad))); !errors.As(err, &mm) || mm.Offset != size {
+		t.Errorf("restarted append: got %v", err)
+	}
+
+	if _, err := a.Append(-1, 10); err == nil {
+		t.Error("negative original size accepted")
+	}
+	if _, err := a.Original(-10); err == nil {
+		t.Error("negative size accepted")
+	}
+}
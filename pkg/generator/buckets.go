@@ -0,0 +1,138 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// BucketWeight is a bucket and its relative weight.
+type BucketWeight struct {
+	Name   string
+	Weight float64
+}
+
+// ParseBucketWeights parses comma separated buckets with optional weights,
+// for example "logs:3,media:1,backup". Buckets without a weight have weight 1.
+func ParseBucketWeights(s string) ([]BucketWeight, error) {
+	var res []BucketWeight
+	for part := range strings.SplitSeq(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, weight, ok := strings.Cut(part, ":")
+		bw := BucketWeight{Name: name, Weight: 1}
+		if ok {
+			w, err := strconv.ParseFloat(weight, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid weight for bucket %q: %w", name, err)
+			}
+			bw.Weight = w
+		}
+		res = append(res, bw)
+	}
+	if len(res) == 0 {
+		return nil, errors.New("no buckets specified")
+	}
+	return res, nil
+}
+
+// BucketSelector picks buckets at random according to their weights.
+// The sequence of picks is determined by the seed.
+// It is safe for concurrent use.
+type BucketSelector struct {
+	names []string
+	// cumulative weights, normalized so the last is 1.
+	cum    []float64
+	seed   uint64
+	n      atomic.Uint64
+	counts []atomic.Int64
+}
+
+// NewBucketSelector returns a selector for the specified buckets.
+// Weights must be >= 0 with at least one above 0, and bucket names must be unique.
+func NewBucketSelector(buckets []BucketWeight, seed int64) (*BucketSelector, error) {
+	if len(buckets) == 0 {
+		return nil, errors.New("NewBucketSelector: no buckets")
+	}
+	s := BucketSelector{
+		names:  make([]string, 0, len(buckets)),
+		cum:    make([]float64, 0, len(buckets)),
+		seed:   uint64(seed),
+		counts: make([]atomic.Int64, len(buckets)),
+	}
+	var total float64
+	for _, b := range buckets {
+		if b.Name == "" {
+			return nil, errors.New("NewBucketSelector: empty bucket name")
+		}
+		if slices.Contains(s.names, b.Name) {
+			return nil, fmt.Errorf("NewBucketSelector: duplicate bucket %q", b.Name)
+		}
+		if b.Weight < 0 {
+			return nil, fmt.Errorf("NewBucketSelector: negative weight for bucket %q", b.Name)
+		}
+		total += b.Weight
+		s.names = append(s.names, b.Name)
+		s.cum = append(s.cum, total)
+	}
+	if total <= 0 {
+		return nil, errors.New("NewBucketSelector: all weights are 0")
+	}
+	for i := range s.cum {
+		s.cum[i] /= total
+	}
+	return &s, nil
+}
+
+// Next returns the bucket for the next operation.
+func (s *BucketSelector) Next() string {
+	n := s.n.Add(1) - 1
+	// 53 random bits as a float in [0, 1).
+	f := float64(splitMix64(s.seed^splitMix64(n))>>11) / (1 << 53)
+	i, _ := slices.BinarySearchFunc(s.cum, f, func(c, f float64) int {
+		if c <= f {
+			return -1
+		}
+		return 1
+	})
+	// Guard against rounding in the normalized weights.
+	i = min(i, len(s.cum)-1)
+	s.counts[i].Add(1)
+	return s.names[i]
+}
+
+// Buckets returns all bucket names in the order they were specified.
+func (s *BucketSelector) Buckets() []string {
+	return slices.Clone(s.names)
+}
+
+// Counts returns the number of times each bucket has been picked.
+func (s *BucketSelector) Counts() map[string]int64 {
+	res := make(map[string]int64, len(s.names))
+	for i, name := range s.names {
+		res[name] = s.counts[i].Load()
+	}
+	return res
+}
@@ -0,0 +1,111 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"math"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestBucketSelector(t *testing.T) {
+	weights := []BucketWeight{{"a", 6}, {"b", 3}, {"unused", 0}, {"c", 1}}
+	s, err := NewBucketSelector(weights, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const n = 100000
+	picks := make([]string, n)
+	var wg sync.WaitGroup
+	for w := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := w; i < n; i += 4 {
+				picks[i] = s.Next()
+			}
+		}()
+	}
+	wg.Wait()
+
+	got := make(map[string]int64)
+	for _, p := range picks {
+		got[p]++
+	}
+	counts := s.Counts()
+	if !reflect.DeepEqual(counts, map[string]int64{"a": got["a"], "b": got["b"], "unused": 0, "c": got["c"]}) {
+		t.Errorf("counts %v do not match picks %v", counts, got)
+	}
+	for _, w := range weights {
+		frac := float64(counts[w.Name]) / n
+		if want := w.Weight / 10; math.Abs(frac-want) > 0.01 {
+			t.Errorf("bucket %s: picked %.3f of the time, want %.3f", w.Name, frac, want)
+		}
+	}
+	if got, want := s.Buckets(), []string{"a", "b", "unused", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got buckets %v, want %v", got, want)
+	}
+
+	// The same seed gives the same sequence, another seed does not.
+	seq := func(seed int64) []string {
+		s, err := NewBucketSelector(weights, seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res := make([]string, 100)
+		for i := range res {
+			res[i] = s.Next()
+		}
+		return res
+	}
+	if !reflect.DeepEqual(seq(2), seq(2)) {
+		t.Error("same seed gave different sequences")
+	}
+	if reflect.DeepEqual(seq(2), seq(3)) {
+		t.Error("different seeds gave the same sequence")
+	}
+
+	for name, w := range map[string][]BucketWeight{
+		"none":      nil,
+		"empty":     {{"", 1}},
+		"duplicate": {{"a", 1}, {"a", 2}},
+		"negative":  {{"a", 1}, {"b", -1}},
+		"all zero":  {{"a", 0}},
+	} {
+		if _, err := NewBucketSelector(w, 1); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestParseBucketWeights(t *testing.T) {
+	got, err := ParseBucketWeights("logs:3, media:0.5,backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []BucketWeight{{"logs", 3}, {"media", 0.5}, {"backup", 1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	for _, s := range []string{"", " , ", "logs:heavy"} {
+		if _, err := ParseBucketWeights(s); err == nil {
+			t.Errorf("%q: expected error", s)
+		}
+	}
+}
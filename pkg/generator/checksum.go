@@ -0,0 +1,178 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+)
+
+// ChecksumAlgorithm selects the hash computed by a checksum reader.
+type ChecksumAlgorithm int
+
+const (
+	ChecksumCRC32C ChecksumAlgorithm = iota
+	ChecksumMD5
+	ChecksumSHA256
+)
+
+// String returns the name of the algorithm.
+func (c ChecksumAlgorithm) String() string {
+	switch c {
+	case ChecksumCRC32C:
+		return "CRC32C"
+	case ChecksumMD5:
+		return "MD5"
+	case ChecksumSHA256:
+		return "SHA256"
+	}
+	return fmt.Sprintf("ChecksumAlgorithm(%d)", int(c))
+}
+
+// Header returns the name of the S3 header or trailer carrying the checksum.
+func (c ChecksumAlgorithm) Header() string {
+	switch c {
+	case ChecksumCRC32C:
+		return "x-amz-checksum-crc32c"
+	case ChecksumMD5:
+		return "Content-Md5"
+	case ChecksumSHA256:
+		return "x-amz-checksum-sha256"
+	}
+	return ""
+}
+
+func (c ChecksumAlgorithm) newHash() (hash.Hash, error) {
+	switch c {
+	case ChecksumCRC32C:
+		return crc32.New(crc32.MakeTable(crc32.Castagnoli)), nil
+	case ChecksumMD5:
+		return md5.New(), nil
+	case ChecksumSHA256:
+		return sha256.New(), nil
+	}
+	return nil, fmt.Errorf("unknown checksum algorithm: %v", c)
+}
+
+// errChecksumSeek is returned when seeking would skip or re-read hashed data.
+var errChecksumSeek = errors.New("checksum reader: only seeking to start is supported")
+
+// ChecksumReader wraps an io.ReadSeeker and hashes all data read through it.
+// Checksumming needs a single forward pass over the data.
+// Seeking to the start resets the hash, other seeks that move the position return an error.
+type ChecksumReader struct {
+	r    io.ReadSeeker
+	h    hash.Hash
+	algo ChecksumAlgorithm
+	pos  int64
+	done bool
+}
+
+// NewChecksumReader returns a reader that hashes everything read from r using algo.
+func NewChecksumReader(r io.ReadSeeker, algo ChecksumAlgorithm) (*ChecksumReader, error) {
+	h, err := algo.newHash()
+	if err != nil {
+		return nil, err
+	}
+	return &ChecksumReader{r: r, h: h, algo: algo}, nil
+}
+
+// Read reads from the underlying reader and updates the hash.
+func (c *ChecksumReader) Read(p []byte) (n int, err error) {
+	n, err = c.r.Read(p)
+	c.h.Write(p[:n])
+	c.pos += int64(n)
+	if err == io.EOF {
+		c.done = true
+	}
+	return n, err
+}
+
+// Seek supports seeking to the start, which resets the hash,
+// and seeks that do not change the position.
+func (c *ChecksumReader) Seek(offset int64, whence int) (int64, error) {
+	if offset == 0 && whence == io.SeekCurrent {
+		return c.pos, nil
+	}
+	if offset != 0 || whence != io.SeekStart {
+		return c.pos, errChecksumSeek
+	}
+	n, err := c.r.Seek(0, io.SeekStart)
+	if err != nil {
+		return n, err
+	}
+	c.h.Reset()
+	c.pos = 0
+	c.done = false
+	return n, nil
+}
+
+// Sum returns the checksum of all data read.
+// nil is returned until the underlying reader has returned io.EOF.
+func (c *ChecksumReader) Sum() []byte {
+	if !c.done {
+		return nil
+	}
+	return c.h.Sum(nil)
+}
+
+// Base64 returns the checksum base64 encoded, as S3 expects it in
+// checksum headers and trailers, for example x-amz-checksum-crc32c.
+// An empty string is returned until the underlying reader has returned io.EOF.
+func (c *ChecksumReader) Base64() string {
+	sum := c.Sum()
+	if sum == nil {
+		return ""
+	}
+	return base64.StdEncoding.EncodeToString(sum)
+}
+
+// Header returns the header or trailer name and value of the checksum.
+// ok is false until the underlying reader has returned io.EOF.
+func (c *ChecksumReader) Header() (key, value string, ok bool) {
+	value = c.Base64()
+	return c.algo.Header(), value, value != ""
+}
+
+// PrecomputeChecksum hashes all of r using algo and seeks back to the start,
+// so the content can be uploaded with the checksum sent ahead of the body,
+// for example in a Content-MD5 header.
+// The checksum is returned base64 encoded.
+func PrecomputeChecksum(r io.ReadSeeker, algo ChecksumAlgorithm) (string, error) {
+	cr, err := NewChecksumReader(r, algo)
+	if err != nil {
+		return "", err
+	}
+	if _, err := cr.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(io.Discard, cr); err != nil {
+		return "", err
+	}
+	sum := cr.Base64()
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	return sum, nil
+}
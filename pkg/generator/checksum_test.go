@@ -0,0 +1,145 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"testing"
+)
+
+func TestChecksumReader(t *testing.T) {
+	crc32c := func(b []byte) []byte {
+		return binary.BigEndian.AppendUint32(nil, crc32.Checksum(b, crc32.MakeTable(crc32.Castagnoli)))
+	}
+	md5Sum := func(b []byte) []byte {
+		h := md5.Sum(b)
+		return h[:]
+	}
+	sha256Sum := func(b []byte) []byte {
+		h := sha256.Sum256(b)
+		return h[:]
+	}
+	algos := map[ChecksumAlgorithm]func([]byte) []byte{
+		ChecksumCRC32C: crc32c,
+		ChecksumMD5:    md5Sum,
+		ChecksumSHA256: sha256Sum,
+	}
+	for algo, oneShot := range algos {
+		for _, size := range []int64{0, 1, 1000, 128<<10 + 1, 1 << 20} {
+			src := newStaticReader(1000)
+			src.ResetSize(size)
+			body, err := io.ReadAll(src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			src.ResetSize(size)
+			cr, err := NewChecksumReader(src, algo)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Read partially, rewind and read everything.
+			io.CopyN(io.Discard, cr, size/2)
+			if cr.Sum() != nil {
+				t.Errorf("%v: Sum() should be nil before EOF", algo)
+			}
+			if _, err := cr.Seek(0, io.SeekStart); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := io.Copy(io.Discard, cr); err != nil {
+				t.Fatal(err)
+			}
+			if got, want := cr.Sum(), oneShot(body); !bytes.Equal(got, want) {
+				t.Errorf("%v, size %d: Sum() = %x, want %x", algo, size, got, want)
+			}
+		}
+	}
+
+	cr, _ := NewChecksumReader(newStaticReader(10), ChecksumMD5)
+	if _, err := cr.Seek(5, io.SeekStart); err != errChecksumSeek {
+		t.Errorf("Seek(5) error = %v, want %v", err, errChecksumSeek)
+	}
+	if _, err := NewChecksumReader(newStaticReader(10), ChecksumAlgorithm(100)); err == nil {
+		t.Error("expected error on unknown algorithm")
+	}
+}
+
+func TestChecksumReaderHeader(t *testing.T) {
+	// "123456789" is the standard CRC32C check input.
+	tests := []struct {
+		algo  ChecksumAlgorithm
+		body  string
+		key   string
+		value string
+	}{
+		{algo: ChecksumCRC32C, body: "123456789", key: "x-amz-checksum-crc32c", value: "4waSgw=="},
+		{algo: ChecksumCRC32C, body: "", key: "x-amz-checksum-crc32c", value: "AAAAAA=="},
+		{algo: ChecksumMD5, body: "", key: "Content-Md5", value: "1B2M2Y8AsgTpgAmY7PhCfg=="},
+		{algo: ChecksumSHA256, body: "abc", key: "x-amz-checksum-sha256", value: "ungWv48Bz+pBQUDeXa4iI7ADYaOWF3qctBD/YfIAFa0="},
+	}
+	for _, test := range tests {
+		cr, err := NewChecksumReader(bytes.NewReader([]byte(test.body)), test.algo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, _, ok := cr.Header(); ok {
+			t.Errorf("%v: header returned before EOF", test.algo)
+		}
+		if _, err := io.Copy(io.Discard, cr); err != nil {
+			t.Fatal(err)
+		}
+		key, value, ok := cr.Header()
+		if !ok || key != test.key || value != test.value {
+			t.Errorf("%v(%q): got %s: %s (%v), want %s: %s", test.algo, test.body, key, value, ok, test.key, test.value)
+		}
+		if len(value)%4 != 0 {
+			t.Errorf("%v: value %q is not padded base64", test.algo, value)
+		}
+		if test.algo == ChecksumCRC32C && len(value) != 8 {
+			t.Errorf("CRC32C value %q should be 8 characters", value)
+		}
+	}
+}
+
+func TestPrecomputeChecksum(t *testing.T) {
+	body := PatternBytesAt(1000, 0, 10_000)
+	for _, size := range []int64{0, 1, 10_000} {
+		r := newStaticReader(1000)
+		r.ResetSize(size)
+		// Start from a partly read reader.
+		io.CopyN(io.Discard, r, size/2)
+		got, err := PrecomputeChecksum(r, ChecksumMD5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sum := md5.Sum(body[:size])
+		if want := base64.StdEncoding.EncodeToString(sum[:]); got != want {
+			t.Errorf("size %d: got %s, want %s", size, got, want)
+		}
+		// The full body can be read again.
+		b, err := io.ReadAll(r)
+		if err != nil || !bytes.Equal(b, body[:size]) {
+			t.Errorf("size %d: read %d bytes after hashing, err %v", size, len(b), err)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"io"
+	"math/rand"
+)
+
+// chunkedReader returns data from r in chunks of varying size.
+type chunkedReader struct {
+	r        io.ReadSeeker
+	rng      *rand.Rand
+	minChunk int
+	maxChunk int
+}
+
+// NewChunkedReader returns a reader that returns at most a random number of bytes,
+// between minChunk and maxChunk, on every Read, regardless of the buffer size.
+// This exercises handling of short reads by consumers of the reader.
+// The content and EOF returned are the same as those of r.
+// Seeking is forwarded to r.
+// minChunk is raised to 1 and maxChunk to minChunk if they are lower.
+func NewChunkedReader(r io.ReadSeeker, minChunk, maxChunk int, seed int64) io.ReadSeeker {
+	minChunk = max(minChunk, 1)
+	return &chunkedReader{
+		r:        r,
+		rng:      rand.New(rand.NewSource(seed)),
+		minChunk: minChunk,
+		maxChunk: max(maxChunk, minChunk),
+	}
+}
+
+// Read reads up to one chunk from the underlying reader.
+func (c *chunkedReader) Read(p []byte) (int, error) {
+	n := c.minChunk
+	if c.maxChunk > c.minChunk {
+		n += c.rng.Intn(c.maxChunk - c.minChunk + 1)
+	}
+	if len(p) > n {
+		p = p[:n]
+	}
+	return c.r.Read(p)
+}
+
+// Seek forwards to the underlying reader.
+func (c *chunkedReader) Seek(offset int64, whence int) (int64, error) {
+	return c.r.Seek(offset, whence)
+}
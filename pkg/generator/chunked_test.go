@@ -0,0 +1,92 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestChunkedReader(t *testing.T) {
+	const size = 1<<20 + 7
+	src := newRandomReader(1)
+	src.ResetSize(size)
+	want, err := io.ReadAll(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, bounds := range [][2]int{{1, 1}, {1, 17}, {100, 4096}, {3000, 3000}, {0, -1}} {
+		src.ResetSize(size)
+		r := NewChunkedReader(src, bounds[0], bounds[1], 42)
+		minChunk, maxChunk := max(bounds[0], 1), max(bounds[1], bounds[0], 1)
+		var got []byte
+		buf := make([]byte, 1<<16)
+		sizes := make(map[int]bool)
+		for {
+			n, err := r.Read(buf)
+			if n > 0 {
+				if n > maxChunk {
+					t.Fatalf("%v: read %d bytes, max %d", bounds, n, maxChunk)
+				}
+				// Only the last read may be shorter than the minimum.
+				if n < minChunk && len(got)+n != size {
+					t.Fatalf("%v: read %d bytes at offset %d, min %d", bounds, n, len(got), minChunk)
+				}
+				sizes[n] = true
+				got = append(got, buf[:n]...)
+			}
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		if !bytes.Equal(got, want) {
+			t.Fatalf("%v: content mismatch", bounds)
+		}
+		if maxChunk-minChunk > 10 && len(sizes) < 5 {
+			t.Errorf("%v: only %d distinct chunk sizes", bounds, len(sizes))
+		}
+		if n, err := r.Read(buf); n != 0 || err != io.EOF {
+			t.Errorf("%v: read after EOF returned %d, %v", bounds, n, err)
+		}
+	}
+
+	// Small buffers are filled as far as possible.
+	src.ResetSize(size)
+	r := NewChunkedReader(src, 100, 200, 1)
+	small := make([]byte, 10)
+	if n, err := r.Read(small); n != 10 || err != nil {
+		t.Errorf("small read returned %d, %v", n, err)
+	}
+
+	// Seek must be forwarded.
+	if _, err := r.Seek(size-5, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want[size-5:]) {
+		t.Errorf("got %x after seek, want %x", got, want[size-5:])
+	}
+}
@@ -0,0 +1,124 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"fmt"
+	"io"
+	"math"
+)
+
+// compressibleBlockSize is the size of each random+pattern block.
+const compressibleBlockSize = 4 << 10
+
+// compressibleReader is an io.ReadSeeker that returns data that compresses approximately
+// to a given ratio.
+// Each block starts with 1/ratio of pseudorandom data
+// followed by a repeating 0x00, 0x01, ... pattern.
+type compressibleReader struct {
+	rnd     randomReader
+	randLen int
+	size    int64
+	pos     int64
+}
+
+// newCompressibleReader returns a reader with data that compresses at approximately ratio:1.
+// The ratio must be at least 1.
+func newCompressibleReader(ratio float64, seed int64) (*compressibleReader, error) {
+	if ratio < 1 || math.IsNaN(ratio) || math.IsInf(ratio, 0) {
+		return nil, fmt.Errorf("compression ratio must be >= 1, got %v", ratio)
+	}
+	return &compressibleReader{
+		rnd:     randomReader{seed: uint64(seed), size: math.MaxInt64},
+		randLen: int(math.Round(compressibleBlockSize / ratio)),
+	}, nil
+}
+
+// ResetSize resets the reader to the beginning and sets a new size limit.
+func (c *compressibleReader) ResetSize(size int64) {
+	c.size = size
+	c.pos = 0
+}
+
+// Read reads data.
+func (c *compressibleReader) Read(p []byte) (n int, err error) {
+	n, err = c.ReadAt(p, c.pos)
+	c.pos += int64(n)
+	if err == io.EOF && n > 0 {
+		err = nil
+	}
+	return n, err
+}
+
+// ReadAt reads len(p) bytes starting at absolute offset off.
+// It does not use or modify the read position.
+func (c *compressibleReader) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, fmt.Errorf("negative offset: %d", off)
+	}
+	if off >= c.size {
+		return 0, io.EOF
+	}
+	toRead := len(p)
+	if remaining := c.size - off; int64(toRead) > remaining {
+		toRead = int(remaining)
+	}
+	for n < toRead {
+		inBlock := int((off + int64(n)) % compressibleBlockSize)
+		if inBlock < c.randLen {
+			end := min(toRead, n+c.randLen-inBlock)
+			c.rnd.ReadAt(p[n:end], off+int64(n))
+			n = end
+			continue
+		}
+		end := min(toRead, n+compressibleBlockSize-inBlock)
+		for i := n; i < end; i++ {
+			p[i] = byte(off + int64(i))
+		}
+		n = end
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Seek sets the offset for the next Read.
+func (c *compressibleReader) Seek(offset int64, whence int) (int64, error) {
+	var newPos int64
+	switch whence {
+	case io.SeekStart:
+		newPos = offset
+	case io.SeekCurrent:
+		newPos = c.pos + offset
+	case io.SeekEnd:
+		newPos = c.size + offset
+	default:
+		return 0, fmt.Errorf("invalid whence: %d", whence)
+	}
+
+	if newPos < 0 {
+		return 0, fmt.Errorf("negative position: %d", newPos)
+	}
+	if newPos > c.size {
+		newPos = c.size
+	}
+
+	c.pos = newPos
+	return c.pos, nil
+}
@@ -0,0 +1,70 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"math"
+	"testing"
+)
+
+func TestCompressibleReader(t *testing.T) {
+	const size = 4 << 20
+	for _, ratio := range []float64{1, 2, 4, 8} {
+		r, err := newCompressibleReader(ratio, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.ResetSize(size)
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		n, err := io.Copy(w, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Close()
+		if n != size {
+			t.Fatalf("got %d bytes, want %d", n, size)
+		}
+		got := float64(size) / float64(buf.Len())
+		if math.Abs(got-ratio) > ratio*0.15 {
+			t.Errorf("ratio %v: achieved %.2f", ratio, got)
+		}
+	}
+
+	// Reproducible, and seeking returns the same data.
+	a, _ := newCompressibleReader(3, 5)
+	a.ResetSize(100_000)
+	want, _ := io.ReadAll(a)
+	a.Seek(12345, io.SeekStart)
+	got, _ := io.ReadAll(a)
+	if !bytes.Equal(got, want[12345:]) {
+		t.Error("data after seek differs")
+	}
+	b, _ := newCompressibleReader(3, 5)
+	b.ResetSize(100_000)
+	if got, _ := io.ReadAll(b); !bytes.Equal(got, want) {
+		t.Error("same seed produced different data")
+	}
+
+	if _, err := newCompressibleReader(0.5, 1); err == nil {
+		t.Error("expected error for ratio < 1")
+	}
+}
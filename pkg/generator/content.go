@@ -0,0 +1,109 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+)
+
+// Generator kinds accepted by NewGenerator.
+// The file types in FileTypes are also accepted.
+const (
+	GeneratorStatic = "static"
+	GeneratorRandom = "random"
+	GeneratorZero   = "zero"
+)
+
+// Generator produces object content.
+type Generator interface {
+	// Reader returns a reader that will return size bytes and EOF after that.
+	// The reader is reused by the next call to Reader,
+	// so only a single reader can be used concurrently.
+	Reader(size int64) io.ReadSeeker
+
+	// Name returns the generator kind for reporting.
+	Name() string
+}
+
+// NewGenerator returns a content generator of the specified kind.
+// The pattern size is taken from WithRandomData().Size,
+// and the seed, where applicable, from WithRandomData().RngSeed.
+func NewGenerator(kind string, opts ...Option) (Generator, error) {
+	options := defaultOptions()
+	for _, ofn := range opts {
+		err := ofn(&options)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return newGenerator(kind, options)
+}
+
+func newGenerator(kind string, o Options) (Generator, error) {
+	switch kind {
+	case GeneratorStatic:
+		return newStaticReader(o.random.size), nil
+	case GeneratorRandom:
+		seed := rand.Int63()
+		if o.random.seed != nil {
+			seed = *o.random.seed
+		}
+		return newRandomReader(seed), nil
+	case GeneratorZero:
+		return newZeroReader(), nil
+	case FileTypePNG, FileTypeJPEG, FileTypePDF, FileTypeZIP:
+		return newFileTypeReader(kind)
+	default:
+		return nil, fmt.Errorf("unknown generator kind: %q", kind)
+	}
+}
+
+// Reader resets the static reader to size and returns it.
+func (s *staticReader) Reader(size int64) io.ReadSeeker {
+	s.ResetSize(size)
+	return s
+}
+
+// Name returns "static".
+func (s *staticReader) Name() string {
+	return GeneratorStatic
+}
+
+// Reader resets the random reader to size and returns it.
+func (r *randomReader) Reader(size int64) io.ReadSeeker {
+	r.ResetSize(size)
+	return r
+}
+
+// Name returns "random".
+func (r *randomReader) Name() string {
+	return GeneratorRandom
+}
+
+// Reader resets the zero reader to size and returns it.
+func (z *zeroReader) Reader(size int64) io.ReadSeeker {
+	z.ResetSize(size)
+	return z
+}
+
+// Name returns "zero".
+func (z *zeroReader) Name() string {
+	return GeneratorZero
+}
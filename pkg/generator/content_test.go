@@ -0,0 +1,62 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestNewGenerator(t *testing.T) {
+	tests := []struct {
+		kind    string
+		opts    []Option
+		want    string
+		wantErr bool
+	}{
+		{kind: GeneratorStatic, want: "*generator.staticReader"},
+		{kind: GeneratorRandom, opts: []Option{WithRandomData().RngSeed(1).Apply()}, want: "*generator.randomReader"},
+		{kind: GeneratorZero, want: "*generator.zeroReader"},
+		{kind: "unknown", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			g, err := NewGenerator(tt.kind, tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewGenerator() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := fmt.Sprintf("%T", g); got != tt.want {
+				t.Fatalf("NewGenerator() got = %v, want %v", got, tt.want)
+			}
+			if g.Name() != tt.kind {
+				t.Errorf("Name() = %q, want %q", g.Name(), tt.kind)
+			}
+			b, err := io.ReadAll(g.Reader(12345))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(b) != 12345 {
+				t.Errorf("Reader() returned %d bytes, want 12345", len(b))
+			}
+		})
+	}
+}
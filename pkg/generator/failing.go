@@ -0,0 +1,94 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrInjectedRead is returned by a FailingReader if no error is specified.
+var ErrInjectedRead = errors.New("injected read error")
+
+// FailingReader returns an error once a specified offset is reached,
+// simulating a connection that breaks in the middle of an upload.
+// If it is recoverable, seeking back to the start after the error
+// clears it, and all data can be read on the next attempt.
+type FailingReader struct {
+	r           io.ReadSeeker
+	after       int64
+	err         error
+	recoverable bool
+
+	pos      int64
+	failed   bool
+	disarmed bool
+	failures int
+}
+
+// NewFailingReader returns a reader that reads from r until offset after,
+// and then returns err from every read.
+// If err is nil, ErrInjectedRead is used.
+// If recoverable is set, a Seek to offset 0 after the error has occurred
+// resets the reader, which will then read all of r without failing.
+func NewFailingReader(r io.ReadSeeker, after int64, err error, recoverable bool) *FailingReader {
+	if err == nil {
+		err = ErrInjectedRead
+	}
+	return &FailingReader{r: r, after: max(after, 0), err: err, recoverable: recoverable}
+}
+
+// Failures returns the number of reads that returned the injected error.
+func (f *FailingReader) Failures() int {
+	return f.failures
+}
+
+// Read reads from the underlying reader, stopping at the failure offset.
+func (f *FailingReader) Read(p []byte) (int, error) {
+	if f.failed {
+		f.failures++
+		return 0, f.err
+	}
+	if !f.disarmed {
+		if f.pos >= f.after {
+			f.failed = true
+			f.failures++
+			return 0, f.err
+		}
+		if remain := f.after - f.pos; int64(len(p)) > remain {
+			p = p[:remain]
+		}
+	}
+	n, err := f.r.Read(p)
+	f.pos += int64(n)
+	return n, err
+}
+
+// Seek forwards to the underlying reader.
+func (f *FailingReader) Seek(offset int64, whence int) (int64, error) {
+	pos, err := f.r.Seek(offset, whence)
+	if err != nil {
+		return pos, err
+	}
+	f.pos = pos
+	if pos == 0 && f.failed && f.recoverable {
+		f.failed = false
+		f.disarmed = true
+	}
+	return pos, nil
+}
@@ -0,0 +1,83 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestFailingReader(t *testing.T) {
+	const size = 3 << 20
+	const after = 1 << 20
+	src := newStaticReader(0)
+	src.ResetSize(size)
+	want, _ := io.ReadAll(src)
+
+	src.ResetSize(size)
+	r := NewFailingReader(src, after, nil, false)
+	got, err := io.ReadAll(r)
+	if !errors.Is(err, ErrInjectedRead) {
+		t.Fatalf("got error %v, want %v", err, ErrInjectedRead)
+	}
+	if len(got) != after || !bytes.Equal(got, want[:after]) {
+		t.Fatalf("got %d bytes before the error, want %d", len(got), after)
+	}
+	// Not recoverable: a retry fails again.
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadAll(r); !errors.Is(err, ErrInjectedRead) {
+		t.Errorf("got error %v after seek, want %v", err, ErrInjectedRead)
+	}
+
+	// Recoverable: the second attempt succeeds.
+	src.ResetSize(size)
+	errBroken := errors.New("connection reset")
+	r = NewFailingReader(src, after, errBroken, true)
+	got, err = io.ReadAll(r)
+	if err != errBroken || len(got) != after {
+		t.Fatalf("got %d bytes and error %v, want %d bytes and %v", len(got), err, after, errBroken)
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	got, err = io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("second attempt: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("second attempt read %d bytes, want %d", len(got), len(want))
+	}
+	if r.Failures() != 1 {
+		t.Errorf("got %d failures, want 1", r.Failures())
+	}
+
+	// Seeking before the error keeps the failure offset absolute.
+	src.ResetSize(size)
+	r = NewFailingReader(src, after, nil, true)
+	if _, err := r.Seek(after-10, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	got, err = io.ReadAll(r)
+	if !errors.Is(err, ErrInjectedRead) || len(got) != 10 {
+		t.Errorf("got %d bytes and error %v after seek, want 10 bytes and %v", len(got), err, ErrInjectedRead)
+	}
+}
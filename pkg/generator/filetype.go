@@ -0,0 +1,171 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"slices"
+)
+
+// File types accepted by NewGenerator and WithFileType.
+const (
+	FileTypePNG  = "png"
+	FileTypeJPEG = "jpeg"
+	FileTypePDF  = "pdf"
+	FileTypeZIP  = "zip"
+)
+
+// FileTypes contains all supported file types.
+var FileTypes = []string{FileTypePNG, FileTypeJPEG, FileTypePDF, FileTypeZIP}
+
+// fileTypeContentTypes maps file types to the content type of the generated data.
+var fileTypeContentTypes = map[string]string{
+	FileTypePNG:  "image/png",
+	FileTypeJPEG: "image/jpeg",
+	FileTypePDF:  "application/pdf",
+	FileTypeZIP:  "application/zip",
+}
+
+// fileTypeHeader returns a minimal valid header of the file type.
+func fileTypeHeader(kind string) ([]byte, error) {
+	var b bytes.Buffer
+	switch kind {
+	case FileTypePNG:
+		// Signature followed by the IHDR chunk of a 1x1 grayscale image.
+		b.WriteString("\x89PNG\r\n\x1a\n")
+		ihdr := []byte("IHDR\x00\x00\x00\x01\x00\x00\x00\x01\x08\x00\x00\x00\x00")
+		binary.Write(&b, binary.BigEndian, uint32(len(ihdr)-4))
+		b.Write(ihdr)
+		binary.Write(&b, binary.BigEndian, crc32.ChecksumIEEE(ihdr))
+	case FileTypeJPEG:
+		// Start of image followed by a JFIF APP0 segment.
+		b.WriteString("\xff\xd8\xff\xe0\x00\x10JFIF\x00\x01\x01\x00\x00\x01\x00\x01\x00\x00")
+	case FileTypePDF:
+		// Version and a comment with binary characters, marking the file as binary.
+		b.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	case FileTypeZIP:
+		// Local file header of a stored file named "data".
+		const name = "data"
+		b.WriteString("PK\x03\x04")
+		binary.Write(&b, binary.LittleEndian, [11]uint16{10, 0, 0, 0, 0, 0, 0, 0, 0, uint16(len(name)), 0})
+		b.WriteString(name)
+	default:
+		return nil, fmt.Errorf("unknown file type: %q. Supported types: %v", kind, FileTypes)
+	}
+	return b.Bytes(), nil
+}
+
+// fileTypeReader is an io.ReadSeeker that returns the header of a file type
+// followed by the repeating byte sequence (0x00, 0x01, 0x02, ...) up to the size.
+// Objects smaller than the header contain a truncated header.
+// Positions and sizes are handled by the embedded staticReader,
+// the header replaces the start of its pattern.
+type fileTypeReader struct {
+	staticReader
+	kind   string
+	header []byte
+}
+
+// newFileTypeReader returns a reader of data with the header of the file type kind.
+func newFileTypeReader(kind string) (*fileTypeReader, error) {
+	h, err := fileTypeHeader(kind)
+	if err != nil {
+		return nil, err
+	}
+	r := newHeaderReader(h, 0)
+	r.kind = kind
+	return r, nil
+}
+
+// newHeaderReader returns a reader of size bytes starting with header.
+func newHeaderReader(header []byte, size int64) *fileTypeReader {
+	r := &fileTypeReader{staticReader: staticReader{pattern: StaticPattern(256)}, header: header}
+	r.ResetSize(size)
+	return r
+}
+
+// Read reads data.
+func (f *fileTypeReader) Read(p []byte) (n int, err error) {
+	n, err = f.ReadAt(p, f.pos)
+	f.pos += int64(n)
+	if err == io.EOF && n > 0 {
+		err = nil
+	}
+	return n, err
+}
+
+// ReadAt reads len(p) bytes starting at absolute offset off.
+// It does not use or modify the read position.
+func (f *fileTypeReader) ReadAt(p []byte, off int64) (n int, err error) {
+	n, err = f.staticReader.ReadAt(p, off)
+	if off >= 0 && off < int64(len(f.header)) {
+		copy(p[:n], f.header[off:])
+	}
+	return n, err
+}
+
+// WriteTo writes the remaining data to w.
+func (f *fileTypeReader) WriteTo(w io.Writer) (n int64, err error) {
+	if end := min(int64(len(f.header)), f.size); f.pos < end {
+		written, err := w.Write(f.header[f.pos:end])
+		f.pos += int64(written)
+		n += int64(written)
+		if err != nil {
+			return n, err
+		}
+		if f.pos < end {
+			return n, io.ErrShortWrite
+		}
+	}
+	m, err := f.staticReader.WriteTo(w)
+	return n + m, err
+}
+
+// Reader resets the reader to size and returns it.
+func (f *fileTypeReader) Reader(size int64) io.ReadSeeker {
+	f.ResetSize(size)
+	return f
+}
+
+// Name returns the file type.
+func (f *fileTypeReader) Name() string {
+	return f.kind
+}
+
+// ContentType returns the content type of the generated data.
+func (f *fileTypeReader) ContentType() string {
+	return fileTypeContentTypes[f.kind]
+}
+
+// WithFileType makes objects start with a minimal valid header of the file type,
+// followed by a repeating pattern, and sets the content type to match.
+// See FileTypes for supported types.
+// An empty type disables this.
+func WithFileType(kind string) Option {
+	return func(o *Options) error {
+		if kind != "" && !slices.Contains(FileTypes, kind) {
+			return fmt.Errorf("WithFileType: unknown file type: %q. Supported types: %v", kind, FileTypes)
+		}
+		o.fileType = kind
+		return nil
+	}
+}
@@ -0,0 +1,104 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestFileTypeReader(t *testing.T) {
+	for _, kind := range FileTypes {
+		g, err := NewGenerator(kind)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if g.Name() != kind {
+			t.Errorf("got name %q, want %q", g.Name(), kind)
+		}
+		for _, size := range []int64{512, 10000} {
+			b, err := io.ReadAll(g.Reader(size))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if int64(len(b)) != size {
+				t.Fatalf("%s: got %d bytes, want %d", kind, len(b), size)
+			}
+			if got, want := http.DetectContentType(b), fileTypeContentTypes[kind]; got != want {
+				t.Errorf("%s: detected %q, want %q", kind, got, want)
+			}
+
+			// Reading at an offset must return the same data.
+			r := g.Reader(size)
+			if _, err := r.Seek(5, io.SeekStart); err != nil {
+				t.Fatal(err)
+			}
+			part, _ := io.ReadAll(r)
+			if !bytes.Equal(part, b[5:]) {
+				t.Errorf("%s: data after seek does not match", kind)
+			}
+
+			// WriteTo must return the same data, also from inside the header.
+			for _, off := range []int64{0, 2, 300} {
+				r.Seek(off, io.SeekStart)
+				var buf bytes.Buffer
+				if _, err := io.Copy(&buf, r); err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(buf.Bytes(), b[off:]) {
+					t.Errorf("%s: data written from %d does not match", kind, off)
+				}
+			}
+		}
+		// Objects smaller than the header are truncated.
+		b, _ := io.ReadAll(g.Reader(3))
+		if len(b) != 3 {
+			t.Errorf("%s: got %d bytes, want 3", kind, len(b))
+		}
+	}
+	if _, err := NewGenerator("gif"); err == nil {
+		t.Error("want error for unknown file type")
+	}
+}
+
+func TestWithFileType(t *testing.T) {
+	src, err := New(WithRandomData().Apply(), WithSize(1000), WithFileType(FileTypePDF))
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj := src.Object()
+	if obj.ContentType != "application/pdf" {
+		t.Errorf("got content type %q", obj.ContentType)
+	}
+	b, err := io.ReadAll(obj.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b) != 1000 || http.DetectContentType(b) != "application/pdf" {
+		t.Errorf("got %d bytes of %s", len(b), http.DetectContentType(b))
+	}
+
+	if _, err := New(WithRandomData().Apply(), WithFileType("gif")); err == nil {
+		t.Error("want error for unknown file type")
+	}
+	if _, err := New(WithRandomData().Apply(), WithFileType(FileTypePNG), WithTraceHeader(true)); err == nil {
+		t.Error("want error when combined with trace header")
+	}
+}
@@ -24,6 +24,8 @@ import (
 	"math/rand"
 	"path"
 	"runtime"
+	"sync/atomic"
+	"time"
 )
 
 // Option provides options for data generation.
@@ -58,8 +60,19 @@ type Object struct {
 
 	VersionID string
 
+	// ETag of the uploaded object, if known.
+	ETag string
+
 	// Size of the object to expect.
 	Size int64
+
+	// Timestamp is the time rendered into templated content,
+	// so the content can be rendered again. Zero for other content.
+	Timestamp time.Time
+
+	// Locked is set if the object has Object Lock retention or a legal hold,
+	// so deleting it may need a governance bypass.
+	Locked bool
 }
 
 // Objects is a slice of objects.
@@ -100,6 +113,9 @@ func (o *Object) setPrefix(opts Options) {
 	}
 	b := make([]byte, opts.randomPrefix)
 	rng := rand.New(rand.NewSource(int64(rand.Uint64())))
+	if opts.random.seed != nil {
+		rng = rand.New(rand.NewSource(^*opts.random.seed))
+	}
 	randASCIIBytes(b, rng)
 	o.Prefix = path.Join(opts.customPrefix, string(b))
 }
@@ -124,7 +140,17 @@ func New(opts ...Option) (Source, error) {
 	if options.src == nil {
 		return nil, errors.New("internal error: generator Source was nil")
 	}
-	return options.src(options)
+	if options.traceHeader && options.fileType != "" {
+		return nil, errors.New("trace header cannot be combined with file type headers")
+	}
+	if options.template != nil && (options.traceHeader || options.fileType != "") {
+		return nil, errors.New("templates cannot be combined with trace or file type headers")
+	}
+	src, err := options.src(options.nextSeed())
+	if err != nil || !options.traceHeader {
+		return src, err
+	}
+	return traceSource{Source: src, seq: new(atomic.Uint64)}, nil
 }
 
 // NewFn return data source.
@@ -139,12 +165,27 @@ func NewFn(opts ...Option) (func() Source, error) {
 	if options.src == nil {
 		return nil, errors.New("internal error: generator Source was nil")
 	}
+	if options.traceHeader && options.fileType != "" {
+		return nil, errors.New("trace header cannot be combined with file type headers")
+	}
+	if options.template != nil && (options.traceHeader || options.fileType != "") {
+		return nil, errors.New("templates cannot be combined with trace or file type headers")
+	}
 
+	// Sequence numbers are unique across all sources.
+	seq := new(atomic.Uint64)
 	return func() Source {
-		s, err := options.src(options)
+		o := options.nextSeed()
+		if o.sizeSchedule != nil {
+			o.sizeSchedule = o.sizeSchedule.clone()
+		}
+		s, err := o.src(o)
 		if err != nil {
 			panic(err)
 		}
+		if options.traceHeader {
+			return traceSource{Source: s, seq: seq}
+		}
 		return s
 	}, nil
 }
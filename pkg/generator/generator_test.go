@@ -167,3 +167,32 @@ func BenchmarkWithRandomData(b *testing.B) {
 		})
 	}
 }
+
+func TestNewZeroSize(t *testing.T) {
+	for name, opts := range map[string][]Option{
+		"random":   {WithRandomData().Apply(), WithSize(0)},
+		"static":   {WithRandomData().Apply(), WithSize(0), WithStaticData(true)},
+		"filetype": {WithRandomData().Apply(), WithSize(0), WithFileType(FileTypes[0])},
+		"minmax":   {WithRandomData().Apply(), WithMinMaxSize(0, 0)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			src, err := New(opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for range 3 {
+				obj := src.Object()
+				if obj.Size != 0 {
+					t.Fatalf("object size %d, want 0", obj.Size)
+				}
+				b, err := io.ReadAll(obj.Reader)
+				if err != nil || len(b) != 0 {
+					t.Fatalf("read %d bytes, err %v", len(b), err)
+				}
+			}
+		})
+	}
+	if _, err := New(WithSize(-1)); err == nil {
+		t.Error("expected error for negative size")
+	}
+}
@@ -0,0 +1,158 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// KeyMode selects how a KeyGenerator picks keys.
+type KeyMode uint8
+
+const (
+	// KeyModeUnique returns a new key on every call.
+	KeyModeUnique KeyMode = iota
+
+	// KeyModeOverwrite returns keys from a fixed hot set of keys,
+	// so the same keys are written repeatedly.
+	KeyModeOverwrite
+)
+
+// KeyOptions configures a KeyGenerator.
+type KeyOptions struct {
+	// Prefix is prepended to all keys.
+	Prefix string
+
+	// Shards is the number of prefix shards keys are spread over.
+	// 0 or 1 will not add a shard to the key.
+	Shards int
+
+	// CounterWidth zero-pads the counter to this many digits.
+	CounterWidth int
+
+	// SuffixLength adds a random suffix of this length.
+	SuffixLength int
+
+	// Seed for the random suffix and random hot set selection.
+	Seed int64
+
+	// Mode selects unique or overwrite keys.
+	Mode KeyMode
+
+	// HotSet is the number of keys used in KeyModeOverwrite.
+	HotSet int
+
+	// RandomHotSet picks hot set keys randomly instead of round-robin.
+	RandomHotSet bool
+}
+
+// KeyGenerator generates object keys of the form 'prefix/{shard}/obj-{counter}[.{suffix}]'.
+// It is safe for concurrent use.
+type KeyGenerator struct {
+	o          KeyOptions
+	shardWidth int
+	counter    atomic.Uint64
+}
+
+// NewKeyGenerator returns a new key generator.
+func NewKeyGenerator(o KeyOptions) (*KeyGenerator, error) {
+	if o.Shards < 0 {
+		return nil, errors.New("NewKeyGenerator: shards must be >= 0")
+	}
+	if o.CounterWidth < 0 || o.CounterWidth > 20 {
+		return nil, errors.New("NewKeyGenerator: counter width must be >= 0 and <= 20")
+	}
+	if o.SuffixLength < 0 {
+		return nil, errors.New("NewKeyGenerator: suffix length must be >= 0")
+	}
+	switch o.Mode {
+	case KeyModeUnique:
+	case KeyModeOverwrite:
+		if o.HotSet <= 0 {
+			return nil, errors.New("NewKeyGenerator: hot set must be > 0 in overwrite mode")
+		}
+	default:
+		return nil, fmt.Errorf("NewKeyGenerator: unknown key mode: %d", o.Mode)
+	}
+	o.Prefix = strings.TrimSuffix(o.Prefix, "/")
+	return &KeyGenerator{
+		o:          o,
+		shardWidth: len(strconv.Itoa(max(o.Shards-1, 0))),
+	}, nil
+}
+
+// Next returns the next key.
+func (k *KeyGenerator) Next() string {
+	n := k.counter.Add(1) - 1
+	if k.o.Mode == KeyModeOverwrite {
+		if k.o.RandomHotSet {
+			n = splitMix64(uint64(k.o.Seed)^splitMix64(^n)) % uint64(k.o.HotSet)
+		} else {
+			n %= uint64(k.o.HotSet)
+		}
+	}
+	return k.key(n)
+}
+
+// Shard returns the shard prefix of shard n, including Prefix.
+func (k *KeyGenerator) Shard(n int) string {
+	if k.o.Shards <= 1 {
+		return k.o.Prefix
+	}
+	s := zeroPad(uint64(n), k.shardWidth)
+	if k.o.Prefix == "" {
+		return s
+	}
+	return k.o.Prefix + "/" + s
+}
+
+// key returns key number n.
+func (k *KeyGenerator) key(n uint64) string {
+	var sb strings.Builder
+	if shard := k.Shard(int(n % uint64(max(k.o.Shards, 1)))); shard != "" {
+		sb.WriteString(shard)
+		sb.WriteByte('/')
+	}
+	sb.WriteString("obj-")
+	sb.WriteString(zeroPad(n, k.o.CounterWidth))
+	if k.o.SuffixLength > 0 {
+		sb.WriteByte('.')
+		v := splitMix64(uint64(k.o.Seed) ^ splitMix64(n))
+		for i := 0; i < k.o.SuffixLength; i++ {
+			if i > 0 && i%10 == 0 {
+				v = splitMix64(v)
+			}
+			sb.WriteByte(asciiLetterBytes[v%uint64(len(asciiLetterBytes))])
+			v /= uint64(len(asciiLetterBytes))
+		}
+	}
+	return sb.String()
+}
+
+// zeroPad returns n as a decimal string padded with zeros to width.
+func zeroPad(n uint64, width int) string {
+	s := strconv.FormatUint(n, 10)
+	if len(s) >= width {
+		return s
+	}
+	return strings.Repeat("0", width-len(s)) + s
+}
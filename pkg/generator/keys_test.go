@@ -0,0 +1,146 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestKeyGenerator(t *testing.T) {
+	kg, err := NewKeyGenerator(KeyOptions{
+		Prefix:       "bench/",
+		Shards:       16,
+		CounterWidth: 8,
+		SuffixLength: 12,
+		Seed:         1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := kg.Next(), "bench/00/obj-00000000."; !strings.HasPrefix(got, want) {
+		t.Fatalf("Next() = %q, want prefix %q", got, want)
+	}
+
+	const workers, each = 8, 2000
+	keys := make(chan string, workers*each)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range each {
+				keys <- kg.Next()
+			}
+		}()
+	}
+	wg.Wait()
+	close(keys)
+
+	seen := make(map[string]struct{}, workers*each)
+	shards := make(map[string]int)
+	for k := range keys {
+		if _, ok := seen[k]; ok {
+			t.Fatalf("duplicate key %q", k)
+		}
+		seen[k] = struct{}{}
+		parts := strings.Split(k, "/")
+		if len(parts) != 3 {
+			t.Fatalf("unexpected key format %q", k)
+		}
+		shards[parts[1]]++
+	}
+	if len(shards) != 16 {
+		t.Fatalf("got %d shards, want 16", len(shards))
+	}
+	for s, n := range shards {
+		// 16001 keys in total, so each shard has 1000 or 1001.
+		if n < 999 || n > 1001 {
+			t.Errorf("shard %s has %d keys", s, n)
+		}
+	}
+
+	// Deterministic for a fixed seed.
+	a, _ := NewKeyGenerator(KeyOptions{SuffixLength: 20, Seed: 42})
+	b, _ := NewKeyGenerator(KeyOptions{SuffixLength: 20, Seed: 42})
+	c, _ := NewKeyGenerator(KeyOptions{SuffixLength: 20, Seed: 43})
+	for i := 0; i < 100; i++ {
+		ka, kb, kc := a.Next(), b.Next(), c.Next()
+		if ka != kb {
+			t.Fatalf("same seed: %q != %q", ka, kb)
+		}
+		if ka == kc {
+			t.Fatalf("different seed: %q == %q", ka, kc)
+		}
+	}
+}
+
+func TestKeyGeneratorModes(t *testing.T) {
+	const n = 10000
+	unique, err := NewKeyGenerator(KeyOptions{Shards: 4, Mode: KeyModeUnique})
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[string]struct{}, n)
+	for range n {
+		k := unique.Next()
+		if _, ok := seen[k]; ok {
+			t.Fatalf("unique mode repeated key %q", k)
+		}
+		seen[k] = struct{}{}
+	}
+
+	const hot = 10
+	// hotSet returns all keys in the hot set.
+	hotSet := func() map[string]int {
+		kg, _ := NewKeyGenerator(KeyOptions{Shards: 4})
+		keys := make(map[string]int, hot)
+		for range hot {
+			keys[kg.Next()] = 0
+		}
+		return keys
+	}
+	for _, random := range []bool{false, true} {
+		kg, err := NewKeyGenerator(KeyOptions{Shards: 4, Mode: KeyModeOverwrite, HotSet: hot, RandomHotSet: random, Seed: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts := hotSet()
+		for range n {
+			k := kg.Next()
+			if _, ok := counts[k]; !ok {
+				t.Fatalf("random: %v: key %q not in hot set", random, k)
+			}
+			counts[k]++
+		}
+		for k, c := range counts {
+			if !random && c != n/hot {
+				t.Errorf("round-robin: key %q used %d times, want %d", k, c, n/hot)
+			}
+			// Expect within 15% of uniform.
+			if random && (c < n/hot*85/100 || c > n/hot*115/100) {
+				t.Errorf("random: key %q used %d times, want about %d", k, c, n/hot)
+			}
+		}
+	}
+
+	if _, err := NewKeyGenerator(KeyOptions{Mode: KeyModeOverwrite}); err == nil {
+		t.Error("want error for overwrite mode without hot set")
+	}
+}
@@ -0,0 +1,88 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"errors"
+	"strconv"
+	"sync/atomic"
+)
+
+// MetadataValueChars are characters that are safe in HTTP header values.
+const MetadataValueChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890-_."
+
+// MetadataGenerator generates user metadata with random values.
+// It is safe for concurrent use, and returns the same sequence for the same seed.
+type MetadataGenerator struct {
+	keys    []string
+	valSize []int
+	seed    uint64
+	counter atomic.Uint64
+}
+
+// NewMetadataGenerator returns a generator of metadata with n keys.
+// The combined size of all keys and values is totalSize bytes.
+func NewMetadataGenerator(n, totalSize int, seed int64) (*MetadataGenerator, error) {
+	if n <= 0 {
+		return nil, errors.New("NewMetadataGenerator: number of keys must be > 0")
+	}
+	m := &MetadataGenerator{seed: uint64(seed)}
+	keySize := 0
+	for i := range n {
+		k := "meta-" + strconv.Itoa(i)
+		m.keys = append(m.keys, k)
+		keySize += len(k)
+	}
+	if totalSize < keySize {
+		return nil, errors.New("NewMetadataGenerator: total size too small for keys, need at least " + strconv.Itoa(keySize))
+	}
+	valSize := totalSize - keySize
+	for i := range n {
+		sz := valSize / n
+		if i < valSize%n {
+			sz++
+		}
+		m.valSize = append(m.valSize, sz)
+	}
+	return m, nil
+}
+
+// Next returns metadata for the next object.
+func (m *MetadataGenerator) Next() map[string]string {
+	n := m.counter.Add(1)
+	md := make(map[string]string, len(m.keys))
+	v := splitMix64(m.seed ^ splitMix64(n))
+	for i, k := range m.keys {
+		b := make([]byte, m.valSize[i])
+		for j := range b {
+			v = splitMix64(v)
+			b[j] = MetadataValueChars[v%uint64(len(MetadataValueChars))]
+		}
+		md[k] = string(b)
+	}
+	return md
+}
+
+// MetadataSize returns the combined size of all keys and values in md.
+func MetadataSize(md map[string]string) int {
+	n := 0
+	for k, v := range md {
+		n += len(k) + len(v)
+	}
+	return n
+}
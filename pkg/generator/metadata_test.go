@@ -0,0 +1,49 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestMetadataGenerator(t *testing.T) {
+	for _, test := range []struct{ keys, size int }{{1, 100}, {3, 256}, {7, 1000}, {10, 60}} {
+		m, err := NewMetadataGenerator(test.keys, test.size, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		a, b := m.Next(), m.Next()
+		if len(a) != test.keys {
+			t.Errorf("got %d keys, want %d", len(a), test.keys)
+		}
+		if got := MetadataSize(a); got != test.size {
+			t.Errorf("%d keys: got size %d, want %d", test.keys, got, test.size)
+		}
+		if maps.Equal(a, b) && test.size > 60 {
+			t.Errorf("%d keys: consecutive objects got same metadata", test.keys)
+		}
+		m2, _ := NewMetadataGenerator(test.keys, test.size, 1)
+		if !maps.Equal(a, m2.Next()) {
+			t.Errorf("%d keys: same seed returned different metadata", test.keys)
+		}
+	}
+	if _, err := NewMetadataGenerator(10, 5, 1); err == nil {
+		t.Error("want error when size is smaller than keys")
+	}
+}
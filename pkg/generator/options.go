@@ -34,6 +34,11 @@ type Options struct {
 	totalSize    int64
 	randomPrefix int
 	randSize     bool
+	seeds        *SeedSource
+	traceHeader  bool
+	fileType     string
+	template     *Template
+	sizeSchedule *SizeSchedule
 
 	// Activates the use of a distribution of sizes
 	flagSizesDistribution bool
@@ -47,6 +52,9 @@ type OptionApplier interface {
 
 // getSize will return a size for an object.
 func (o Options) getSize(rng *rand.Rand) int64 {
+	if o.sizeSchedule != nil {
+		return o.sizeSchedule.Next()
+	}
 	if o.flagSizesDistribution {
 		return o.sizesDistribution.Poll(rng)
 	}
@@ -56,6 +64,14 @@ func (o Options) getSize(rng *rand.Rand) int64 {
 	return GetExpRandSize(rng, o.minSize, o.totalSize)
 }
 
+// nextSeed returns options with the seed set from the seed source, if any.
+func (o Options) nextSeed() Options {
+	if o.seeds != nil {
+		o.random = o.random.RngSeed(o.seeds.Next())
+	}
+	return o
+}
+
 func defaultOptions() Options {
 	o := Options{
 		src:          newRandom,
@@ -78,14 +94,36 @@ func WithSizeHistograms(encoded string) Option {
 	}
 }
 
+// WithSizeSchedule will return object sizes from s in order.
+// Every source starts at the beginning of the schedule.
+func WithSizeSchedule(s *SizeSchedule) Option {
+	return func(o *Options) error {
+		if s == nil || s.Len() == 0 {
+			return errors.New("WithSizeSchedule: schedule has no sizes")
+		}
+		o.sizeSchedule = s
+		o.totalSize = s.Max()
+		return nil
+	}
+}
+
+// WithSeedSource will derive the seed of every created source from s.
+// This makes the generated names and data reproducible.
+func WithSeedSource(s *SeedSource) Option {
+	return func(o *Options) error {
+		o.seeds = s
+		return nil
+	}
+}
+
 // WithMinMaxSize sets the min and max size of the generated data.
 func WithMinMaxSize(minSize, maxSize int64) Option {
 	return func(o *Options) error {
-		if minSize <= 0 {
+		if minSize < 0 {
 			return errors.New("WithMinMaxSize: minSize must be >= 0")
 		}
 		if maxSize < 0 {
-			return errors.New("WithMinMaxSize: maxSize must be > 0")
+			return errors.New("WithMinMaxSize: maxSize must be >= 0")
 		}
 		if minSize > maxSize {
 			return errors.New("WithMinMaxSize: minSize must be < maxSize")
@@ -103,8 +141,8 @@ func WithMinMaxSize(minSize, maxSize int64) Option {
 // WithSize sets the size of the generated data.
 func WithSize(n int64) Option {
 	return func(o *Options) error {
-		if n <= 0 {
-			return errors.New("WithSize: size must be > 0")
+		if n < 0 {
+			return errors.New("WithSize: size must be >= 0")
 		}
 		if o.randSize && o.totalSize < 256 {
 			return errors.New("WithSize: random sized objects should be at least 256 bytes")
@@ -155,3 +193,13 @@ func WithStaticData(b bool) Option {
 		return nil
 	}
 }
+
+// WithTraceHeader replaces the first TraceHeaderSize bytes of every object
+// with a TraceHeader containing the hash of the object name and a sequence number,
+// so stored data can be traced back to the object it was generated for.
+func WithTraceHeader(b bool) Option {
+	return func(o *Options) error {
+		o.traceHeader = b
+		return nil
+	}
+}
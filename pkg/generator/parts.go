@@ -0,0 +1,74 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// PartReaders splits object content into parts for multipart uploads.
+// Each part can be read independently and concurrently,
+// and produces the bytes of its absolute offset range in the object.
+type PartReaders struct {
+	src      io.ReaderAt
+	size     int64
+	partSize int64
+}
+
+// NewPartReaders returns part readers for an object of the specified size
+// with content from g.
+// The generator must not be used for other objects while the parts are in use.
+func NewPartReaders(g Generator, size, partSize int64) (*PartReaders, error) {
+	if size < 0 {
+		return nil, fmt.Errorf("negative size: %d", size)
+	}
+	if partSize <= 0 {
+		return nil, fmt.Errorf("invalid part size: %d", partSize)
+	}
+	src, ok := g.Reader(size).(io.ReaderAt)
+	if !ok {
+		return nil, errors.New("generator does not support random access: " + g.Name())
+	}
+	return &PartReaders{src: src, size: size, partSize: partSize}, nil
+}
+
+// NumParts returns the number of parts.
+// A zero sized object has a single empty part.
+func (p *PartReaders) NumParts() int {
+	if p.size == 0 {
+		return 1
+	}
+	return int((p.size + p.partSize - 1) / p.partSize)
+}
+
+// PartSize returns the size of part index.
+// All parts but the last have the configured part size.
+func (p *PartReaders) PartSize(index int) int64 {
+	if index < 0 || index >= p.NumParts() {
+		return 0
+	}
+	return min(p.partSize, p.size-int64(index)*p.partSize)
+}
+
+// PartReader returns a reader for part index, starting at 0.
+// Each call returns a new reader.
+func (p *PartReaders) PartReader(index int) io.ReadSeeker {
+	return io.NewSectionReader(p.src, int64(index)*p.partSize, p.PartSize(index))
+}
@@ -0,0 +1,101 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"testing"
+)
+
+func TestPartReaders(t *testing.T) {
+	tests := []struct {
+		name      string
+		kind      string
+		size      int64
+		partSize  int64
+		wantParts int
+		wantLast  int64
+	}{
+		{name: "even", kind: GeneratorStatic, size: 4 << 20, partSize: 1 << 20, wantParts: 4, wantLast: 1 << 20},
+		{name: "remainder", kind: GeneratorStatic, size: 5<<20 + 123, partSize: 1 << 20, wantParts: 6, wantLast: 123},
+		{name: "single", kind: GeneratorRandom, size: 1000, partSize: 1 << 20, wantParts: 1, wantLast: 1000},
+		{name: "random", kind: GeneratorRandom, size: 3<<20 + 7, partSize: 1<<20 + 1, wantParts: 4, wantLast: 4},
+		{name: "empty", kind: GeneratorZero, size: 0, partSize: 1 << 20, wantParts: 1, wantLast: 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g, err := NewGenerator(test.kind, WithRandomData().RngSeed(1).Apply())
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := io.ReadAll(g.Reader(test.size))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			g, _ = NewGenerator(test.kind, WithRandomData().RngSeed(1).Apply())
+			p, err := NewPartReaders(g, test.size, test.partSize)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := p.NumParts(); got != test.wantParts {
+				t.Fatalf("got %d parts, want %d", got, test.wantParts)
+			}
+			if got := p.PartSize(p.NumParts() - 1); got != test.wantLast {
+				t.Errorf("last part size %d, want %d", got, test.wantLast)
+			}
+
+			// Read parts concurrently and in reverse order.
+			parts := make([][]byte, p.NumParts())
+			var wg sync.WaitGroup
+			for i := p.NumParts() - 1; i >= 0; i-- {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					b, err := io.ReadAll(p.PartReader(i))
+					if err != nil {
+						t.Error(err)
+					}
+					parts[i] = b
+				}(i)
+			}
+			wg.Wait()
+
+			var total int64
+			for i, b := range parts {
+				if int64(len(b)) != p.PartSize(i) {
+					t.Errorf("part %d: got %d bytes, want %d", i, len(b), p.PartSize(i))
+				}
+				total += int64(len(b))
+			}
+			if total != test.size {
+				t.Errorf("parts total %d bytes, want %d", total, test.size)
+			}
+			if !bytes.Equal(bytes.Join(parts, nil), want) {
+				t.Error("concatenated parts differ from single reader body")
+			}
+		})
+	}
+
+	g, _ := NewGenerator(GeneratorStatic)
+	if _, err := NewPartReaders(g, 100, 0); err == nil {
+		t.Error("want error for zero part size")
+	}
+}
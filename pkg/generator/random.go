@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"math/rand"
 	"sync/atomic"
+	"time"
 
 	"github.com/minio/pkg/v3/rng"
 )
@@ -84,23 +85,33 @@ func randomOptsDefaults() RandomOpts {
 }
 
 type randomSrc struct {
-	source     *rng.Reader
-	staticSrc  *staticReader
-	rng        *rand.Rand
-	obj        Object
-	o          Options
-	counter    atomic.Uint64
-	useStatic  bool
+	source    *rng.Reader
+	content   Generator
+	rng       *rand.Rand
+	obj       Object
+	o         Options
+	counter   atomic.Uint64
+	useStatic bool
+
+	// templateSeed seeds the random strings of templates.
+	templateSeed int64
+	// templateEpoch is the timestamp of templates from a seeded source.
+	// Objects are one second apart, so the content can be reproduced.
+	templateEpoch time.Time
 }
 
+// seededTemplateEpoch is the first template timestamp of seeded sources.
+var seededTemplateEpoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
 func newRandom(o Options) (Source, error) {
 	rndSrc := rand.NewSource(int64(rand.Uint64()))
 	if o.random.seed != nil {
 		rndSrc = rand.NewSource(*o.random.seed)
 	}
 
+	// Zero byte objects keep the configured block size.
 	size := o.random.size
-	if int64(size) > o.totalSize {
+	if o.totalSize > 0 && int64(size) > o.totalSize {
 		size = int(o.totalSize)
 	}
 	if size <= 0 {
@@ -119,9 +130,22 @@ func newRandom(o Options) (Source, error) {
 		},
 	}
 
-	if o.random.static {
-		// Create static reader that repeats a fixed byte pattern
-		r.staticSrc = newStaticReader(size)
+	if o.fileType != "" || o.random.static {
+		// Create reader with a file header followed by a repeating pattern,
+		// or a static reader that repeats a fixed byte pattern.
+		kind := o.fileType
+		if kind == "" {
+			kind = GeneratorStatic
+			o.random.size = size
+		}
+		content, err := newGenerator(kind, o)
+		if err != nil {
+			return nil, err
+		}
+		r.content = content
+		if ct, ok := content.(interface{ ContentType() string }); ok {
+			r.obj.ContentType = ct.ContentType()
+		}
 	} else {
 		// Use random data generator
 		input, err := rng.NewReader(rng.WithRNG(rand.New(rndSrc)), rng.WithSize(o.totalSize))
@@ -131,6 +155,12 @@ func newRandom(o Options) (Source, error) {
 		r.source = input
 	}
 
+	if o.template != nil {
+		r.templateSeed = r.rng.Int63()
+		if o.random.seed != nil {
+			r.templateEpoch = seededTemplateEpoch
+		}
+	}
 	r.obj.setPrefix(o)
 	return &r, nil
 }
@@ -142,10 +172,15 @@ func (r *randomSrc) Object() *Object {
 	r.obj.Size = r.o.getSize(r.rng)
 	r.obj.setName(fmt.Sprintf("%d.%s.rnd", n, string(nBuf[:])))
 
-	if r.useStatic {
-		// Reset static reader to the new size
-		r.staticSrc.ResetSize(r.obj.Size)
-		r.obj.Reader = r.staticSrc
+	if r.o.template != nil {
+		r.obj.Timestamp = time.Now()
+		if !r.templateEpoch.IsZero() {
+			r.obj.Timestamp = r.templateEpoch.Add(time.Duration(n-1) * time.Second)
+		}
+		r.obj.Reader = NewTemplateReader(r.o.template, int64(n), r.obj.Timestamp, r.templateSeed, r.obj.Size)
+	} else if r.content != nil {
+		// Reset static or file type reader to the new size
+		r.obj.Reader = r.content.Reader(r.obj.Size)
 	} else {
 		// Reset scrambler
 		r.source.ResetSize(r.obj.Size)
@@ -156,9 +191,17 @@ func (r *randomSrc) Object() *Object {
 
 func (r *randomSrc) String() string {
 	dataType := "Random data"
-	if r.useStatic {
+	switch {
+	case r.o.template != nil:
+		dataType = "Data from template"
+	case r.o.fileType != "":
+		dataType = "Data with " + r.o.fileType + " header"
+	case r.useStatic:
 		dataType = "Static data"
 	}
+	if r.o.sizeSchedule != nil {
+		return fmt.Sprintf("%s; %d scheduled sizes up to %d bytes", dataType, r.o.sizeSchedule.Len(), r.o.totalSize)
+	}
 	if r.o.randSize {
 		return fmt.Sprintf("%s; random size up to %d bytes", dataType, r.o.totalSize)
 	}
@@ -0,0 +1,123 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// randomReader is an io.ReadSeeker that returns incompressible pseudorandom data.
+// Every 8 byte word is derived from the seed and the word offset,
+// so the same seed and size will always produce the same bytes,
+// and data at any offset can be produced without generating what comes before it.
+type randomReader struct {
+	seed uint64
+	size int64
+	pos  int64
+}
+
+// newRandomReader returns a reader of seeded pseudorandom data.
+// Use ResetSize to set the size before reading.
+func newRandomReader(seed int64) *randomReader {
+	return &randomReader{seed: uint64(seed)}
+}
+
+// splitMix64 returns the SplitMix64 output for x.
+func splitMix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+// word returns the 8 bytes starting at word index idx.
+func (r *randomReader) word(idx int64) [8]byte {
+	var w [8]byte
+	binary.LittleEndian.PutUint64(w[:], splitMix64(r.seed^splitMix64(uint64(idx))))
+	return w
+}
+
+// ResetSize resets the reader to the beginning and sets a new size limit.
+func (r *randomReader) ResetSize(size int64) {
+	r.size = size
+	r.pos = 0
+}
+
+// Read reads pseudorandom data.
+func (r *randomReader) Read(p []byte) (n int, err error) {
+	n, err = r.ReadAt(p, r.pos)
+	r.pos += int64(n)
+	if err == io.EOF && n > 0 {
+		err = nil
+	}
+	return n, err
+}
+
+// ReadAt reads len(p) bytes starting at absolute offset off.
+// It does not use or modify the read position.
+func (r *randomReader) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, fmt.Errorf("negative offset: %d", off)
+	}
+	if off >= r.size {
+		return 0, io.EOF
+	}
+	toRead := len(p)
+	if remaining := r.size - off; int64(toRead) > remaining {
+		toRead = int(remaining)
+	}
+	idx := off / 8
+	skip := int(off % 8)
+	for n < toRead {
+		w := r.word(idx)
+		n += copy(p[n:toRead], w[skip:])
+		skip = 0
+		idx++
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Seek sets the offset for the next Read.
+func (r *randomReader) Seek(offset int64, whence int) (int64, error) {
+	var newPos int64
+	switch whence {
+	case io.SeekStart:
+		newPos = offset
+	case io.SeekCurrent:
+		newPos = r.pos + offset
+	case io.SeekEnd:
+		newPos = r.size + offset
+	default:
+		return 0, fmt.Errorf("invalid whence: %d", whence)
+	}
+
+	if newPos < 0 {
+		return 0, fmt.Errorf("negative position: %d", newPos)
+	}
+	if newPos > r.size {
+		newPos = r.size
+	}
+
+	r.pos = newPos
+	return r.pos, nil
+}
@@ -0,0 +1,80 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+	"testing"
+)
+
+func TestRandomReader(t *testing.T) {
+	const size = 1<<20 + 13
+	read := func(seed int64) []byte {
+		r := newRandomReader(seed)
+		r.ResetSize(size)
+		b, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(b) != size {
+			t.Fatalf("got %d bytes, want %d", len(b), size)
+		}
+		return b
+	}
+
+	a, b := read(1), read(1)
+	if !bytes.Equal(a, b) {
+		t.Fatal("same seed produced different output")
+	}
+	c := read(2)
+	same := 0
+	for i := range a {
+		if a[i] == c[i] {
+			same++
+		}
+	}
+	// Expect roughly 1/256 equal bytes.
+	if same > size/100 {
+		t.Errorf("different seeds too similar: %d of %d bytes equal", same, size)
+	}
+
+	// Seek and read back must match.
+	r := newRandomReader(1)
+	r.ResetSize(size)
+	for _, off := range []int64{0, 1, 7, 8, 9, 4095, size - 3} {
+		if _, err := r.Seek(off, io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+		got := make([]byte, 17)
+		n, _ := io.ReadFull(r, got)
+		if !bytes.Equal(got[:n], a[off:off+int64(n)]) {
+			t.Errorf("offset %d: mismatch after seek", off)
+		}
+	}
+
+	// Output should not compress.
+	var buf bytes.Buffer
+	w, _ := flate.NewWriter(&buf, flate.BestSpeed)
+	w.Write(a)
+	w.Close()
+	if buf.Len() < len(a)*99/100 {
+		t.Errorf("random data compressed from %d to %d bytes", len(a), buf.Len())
+	}
+}
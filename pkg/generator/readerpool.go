@@ -0,0 +1,109 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+// ReaderPool is a bounded pool of static pattern readers.
+// Unlike a sync.Pool, at most Cap readers exist, so memory use is capped at
+// Cap pattern buffers. Acquire blocks while all readers are in use.
+// Readers are created on first use, so a pool that is never fully used
+// does not allocate all buffers.
+// It is safe for concurrent use.
+type ReaderPool struct {
+	patternSize int
+	tokens      chan struct{}
+	free        chan *staticReader
+	created     atomic.Int64
+}
+
+// PooledReader is a static pattern reader acquired from a ReaderPool.
+// It must be returned with ReaderPool.Release and may not be used after that.
+type PooledReader struct {
+	*staticReader
+	pool *ReaderPool
+}
+
+// NewReaderPool returns a pool of at most n readers repeating the static pattern
+// of the given size. Use the worker count for n, so every worker can hold a reader.
+// Pattern sizes <= 0 use the default 128KB pattern.
+func NewReaderPool(n, patternSize int) (*ReaderPool, error) {
+	if n <= 0 {
+		return nil, errors.New("NewReaderPool: n must be > 0")
+	}
+	if patternSize <= 0 {
+		patternSize = 128 << 10
+	}
+	return &ReaderPool{
+		patternSize: patternSize,
+		tokens:      make(chan struct{}, n),
+		free:        make(chan *staticReader, n),
+	}, nil
+}
+
+// Cap returns the maximum number of readers.
+func (p *ReaderPool) Cap() int {
+	return cap(p.tokens)
+}
+
+// InUse returns the number of readers currently acquired.
+func (p *ReaderPool) InUse() int {
+	return len(p.tokens)
+}
+
+// Created returns the number of readers allocated so far.
+// It never exceeds Cap.
+func (p *ReaderPool) Created() int {
+	return int(p.created.Load())
+}
+
+// Acquire returns a reader positioned at the start that returns size bytes.
+// If all readers are in use Acquire blocks until one is released or ctx is canceled.
+func (p *ReaderPool) Acquire(ctx context.Context, size int64) (*PooledReader, error) {
+	select {
+	case p.tokens <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	var s *staticReader
+	select {
+	case s = <-p.free:
+	default:
+		s = newStaticReader(p.patternSize)
+		p.created.Add(1)
+	}
+	s.ResetSize(size)
+	return &PooledReader{staticReader: s, pool: p}, nil
+}
+
+// Release returns r to the pool.
+// Releasing a reader more than once or to another pool has no effect.
+func (p *ReaderPool) Release(r *PooledReader) {
+	if r == nil || r.pool != p {
+		return
+	}
+	s := r.staticReader
+	r.staticReader, r.pool = nil, nil
+	s.Reset()
+	p.free <- s
+	<-p.tokens
+}
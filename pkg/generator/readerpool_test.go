@@ -0,0 +1,104 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestReaderPool(t *testing.T) {
+	const workers, poolSize, patternSize = 16, 4, 1000
+	p, err := NewReaderPool(poolSize, patternSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var inUse, peak atomic.Int64
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 50 {
+				size := int64(w*100 + i)
+				r, err := p.Acquire(context.Background(), size)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				n := inUse.Add(1)
+				for {
+					old := peak.Load()
+					if n <= old || peak.CompareAndSwap(old, n) {
+						break
+					}
+				}
+				// Readers must be reset to the start with the new size.
+				if r.Size() != size || r.Remaining() != size {
+					t.Errorf("acquired reader has size %d, %d remaining, want %d", r.Size(), r.Remaining(), size)
+				}
+				got, err := io.ReadAll(r)
+				if err != nil || !bytes.Equal(got, PatternBytesAt(patternSize, 0, size)) {
+					t.Errorf("size %d: read %d bytes, err %v", size, len(got), err)
+				}
+				inUse.Add(-1)
+				p.Release(r)
+			}
+		}()
+	}
+	wg.Wait()
+	if peak.Load() > poolSize {
+		t.Errorf("%d readers in use at once, cap is %d", peak.Load(), poolSize)
+	}
+	if p.Created() > poolSize || p.InUse() != 0 || p.Cap() != poolSize {
+		t.Errorf("created %d, in use %d, cap %d", p.Created(), p.InUse(), p.Cap())
+	}
+
+	// A partly read reader is reset when released.
+	r, _ := p.Acquire(context.Background(), 500)
+	io.CopyN(io.Discard, r, 123)
+	p.Release(r)
+	// Releasing twice has no effect.
+	p.Release(r)
+	if p.InUse() != 0 {
+		t.Fatalf("%d in use after double release", p.InUse())
+	}
+	for range poolSize {
+		r, _ := p.Acquire(context.Background(), 10)
+		if b, _ := io.ReadAll(r); !bytes.Equal(b, PatternBytesAt(patternSize, 0, 10)) {
+			t.Errorf("reused reader returned %v", b)
+		}
+		defer p.Release(r)
+	}
+
+	// Acquire blocks while the pool is exhausted.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := p.Acquire(ctx, 1); err != context.DeadlineExceeded {
+		t.Errorf("exhausted pool: got %v, want deadline exceeded", err)
+	}
+
+	if _, err := NewReaderPool(0, patternSize); err == nil {
+		t.Error("expected error for empty pool")
+	}
+}
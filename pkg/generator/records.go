@@ -0,0 +1,165 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"fmt"
+	"io"
+	"slices"
+)
+
+// Record formats accepted by NewRecordGenerator.
+const (
+	RecordFormatCSV  = "csv"
+	RecordFormatJSON = "json"
+)
+
+// RecordColumns are the columns of every generated record, in order.
+var RecordColumns = []string{"id", "name", "category", "value"}
+
+// RecordCategories are the values of the category column.
+// Record i has category RecordCategories[i%len(RecordCategories)].
+var RecordCategories = []string{"alpha", "bravo", "delta", "gamma"}
+
+// Record is a single generated record.
+// All fields have a fixed width, so every record of a format has the same length.
+type Record struct {
+	// ID is 1000000000 plus the record index.
+	ID int64 `json:"id"`
+	// Name is "item-" followed by 6 hex digits of the index.
+	Name string `json:"name"`
+	// Category is one of RecordCategories.
+	Category string `json:"category"`
+	// Value is between 100 and 999.
+	Value int `json:"value"`
+}
+
+// RecordAt returns record i.
+// Records only depend on the index, so queries against generated content have known results.
+func RecordAt(i int64) Record {
+	return Record{
+		ID:       1000000000 + i,
+		Name:     fmt.Sprintf("item-%06x", i&0xffffff),
+		Category: RecordCategories[i%int64(len(RecordCategories))],
+		Value:    100 + int((i*37)%900),
+	}
+}
+
+// Values returns the fields of r as strings, in RecordColumns order.
+func (r Record) Values() []string {
+	return []string{fmt.Sprint(r.ID), r.Name, r.Category, fmt.Sprint(r.Value)}
+}
+
+// CountCategory returns how many of the first rows records have category.
+func CountCategory(rows int64, category string) int64 {
+	idx := int64(slices.Index(RecordCategories, category))
+	if idx < 0 || rows <= idx {
+		return 0
+	}
+	return (rows-idx-1)/int64(len(RecordCategories)) + 1
+}
+
+// RecordGenerator produces well-formed CSV or JSON lines content with the schema
+// in RecordColumns, for example for S3 Select queries.
+// CSV content starts with a header line.
+// It is safe for concurrent use.
+type RecordGenerator struct {
+	format string
+	header []byte
+	rowLen int64
+}
+
+// NewRecordGenerator returns a generator of records in format,
+// RecordFormatCSV or RecordFormatJSON.
+func NewRecordGenerator(format string) (*RecordGenerator, error) {
+	g := RecordGenerator{format: format}
+	switch format {
+	case RecordFormatCSV:
+		g.header = []byte("id,name,category,value\n")
+	case RecordFormatJSON:
+	default:
+		return nil, fmt.Errorf("unknown record format: %q", format)
+	}
+	g.rowLen = int64(len(g.appendRecord(nil, 0)))
+	return &g, nil
+}
+
+// Format returns the record format.
+func (g *RecordGenerator) Format() string {
+	return g.format
+}
+
+// ContentType returns the content type of the generated content.
+func (g *RecordGenerator) ContentType() string {
+	if g.format == RecordFormatCSV {
+		return "text/csv"
+	}
+	return "application/x-ndjson"
+}
+
+// Rows returns the number of records that fit in size bytes, but at least 1.
+func (g *RecordGenerator) Rows(size int64) int64 {
+	return max((size-int64(len(g.header)))/g.rowLen, 1)
+}
+
+// Size returns the size in bytes of content with rows records.
+func (g *RecordGenerator) Size(rows int64) int64 {
+	return int64(len(g.header)) + rows*g.rowLen
+}
+
+// Reader returns a reader of content with rows records.
+func (g *RecordGenerator) Reader(rows int64) io.Reader {
+	return &recordReader{g: g, rows: rows, buf: g.header}
+}
+
+// appendRecord appends record i, terminated by a newline, to dst.
+func (g *RecordGenerator) appendRecord(dst []byte, i int64) []byte {
+	r := RecordAt(i)
+	if g.format == RecordFormatCSV {
+		return fmt.Appendf(dst, "%d,%s,%s,%d\n", r.ID, r.Name, r.Category, r.Value)
+	}
+	return fmt.Appendf(dst, `{"id":%d,"name":"%s","category":"%s","value":%d}`+"\n", r.ID, r.Name, r.Category, r.Value)
+}
+
+// recordReader streams records, a buffer of them at a time.
+type recordReader struct {
+	g       *RecordGenerator
+	next    int64
+	rows    int64
+	buf     []byte
+	scratch []byte
+}
+
+// recordReaderBatch is the number of records encoded at once.
+const recordReaderBatch = 256
+
+func (r *recordReader) Read(p []byte) (int, error) {
+	if len(r.buf) == 0 {
+		if r.next >= r.rows {
+			return 0, io.EOF
+		}
+		r.scratch = r.scratch[:0]
+		for end := min(r.next+recordReaderBatch, r.rows); r.next < end; r.next++ {
+			r.scratch = r.g.appendRecord(r.scratch, r.next)
+		}
+		r.buf = r.scratch
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
@@ -0,0 +1,107 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"slices"
+	"testing"
+)
+
+func TestRecordGeneratorCSV(t *testing.T) {
+	g, err := NewRecordGenerator(RecordFormatCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const size = 100 << 10
+	rows := g.Rows(size)
+	if g.Size(rows) > size || g.Size(rows+1) <= size {
+		t.Fatalf("%d rows is %d bytes, not the most that fit in %d", rows, g.Size(rows), size)
+	}
+	b, err := io.ReadAll(g.Reader(rows))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int64(len(b)) != g.Size(rows) {
+		t.Fatalf("got %d bytes, want %d", len(b), g.Size(rows))
+	}
+	recs, err := csv.NewReader(bytes.NewReader(b)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(recs[0], RecordColumns) {
+		t.Errorf("got header %v, want %v", recs[0], RecordColumns)
+	}
+	if int64(len(recs)-1) != rows {
+		t.Fatalf("got %d records, want %d", len(recs)-1, rows)
+	}
+	alpha := int64(0)
+	for i, rec := range recs[1:] {
+		if want := RecordAt(int64(i)).Values(); !slices.Equal(rec, want) {
+			t.Fatalf("record %d: got %v, want %v", i, rec, want)
+		}
+		if rec[2] == "alpha" {
+			alpha++
+		}
+	}
+	if want := CountCategory(rows, "alpha"); alpha != want {
+		t.Errorf("got %d alpha records, CountCategory returned %d", alpha, want)
+	}
+}
+
+func TestRecordGeneratorJSON(t *testing.T) {
+	g, err := NewRecordGenerator(RecordFormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const rows = 1000
+	sc := bufio.NewScanner(g.Reader(rows))
+	n := int64(0)
+	for sc.Scan() {
+		var r Record
+		if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+			t.Fatalf("line %d: %v", n, err)
+		}
+		if r != RecordAt(n) {
+			t.Fatalf("line %d: got %+v, want %+v", n, r, RecordAt(n))
+		}
+		n++
+	}
+	if n != rows {
+		t.Errorf("got %d lines, want %d", n, rows)
+	}
+	if g.Rows(1) != 1 {
+		t.Errorf("got %d rows for a tiny size, want 1", g.Rows(1))
+	}
+	for _, tc := range []struct {
+		rows int64
+		cat  string
+		want int64
+	}{{0, "alpha", 0}, {1, "alpha", 1}, {1, "bravo", 0}, {4, "gamma", 1}, {9, "alpha", 3}, {9, "bravo", 2}, {9, "none", 0}} {
+		if got := CountCategory(tc.rows, tc.cat); got != tc.want {
+			t.Errorf("CountCategory(%d, %q) = %d, want %d", tc.rows, tc.cat, got, tc.want)
+		}
+	}
+	if _, err := NewRecordGenerator("xml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
@@ -0,0 +1,57 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"hash/fnv"
+	"sync/atomic"
+)
+
+// SeedSource derives seeds for independent components from a single master seed.
+// Two sources created with the same master seed return the same seeds
+// for the same names, and the same sequence from Next.
+// It is safe for concurrent use.
+type SeedSource struct {
+	master uint64
+	n      atomic.Uint64
+}
+
+// NewSeedSource returns a seed source for the specified master seed.
+func NewSeedSource(master int64) *SeedSource {
+	return &SeedSource{master: uint64(master)}
+}
+
+// Seed returns the seed for the component with the specified name.
+// The same name always returns the same seed.
+func (s *SeedSource) Seed(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return int64(splitMix64(s.master ^ splitMix64(h.Sum64())))
+}
+
+// Child returns a seed source for the component with the specified name.
+func (s *SeedSource) Child(name string) *SeedSource {
+	return NewSeedSource(s.Seed(name))
+}
+
+// Next returns the next seed in the sequence of the source.
+func (s *SeedSource) Next() int64 {
+	// SplitMix64 stream: the state is advanced by the golden ratio in splitMix64.
+	n := s.n.Add(1)
+	return int64(splitMix64(s.master + (n-1)*0x9e3779b97f4a7c15))
+}
@@ -0,0 +1,91 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestSeedSource(t *testing.T) {
+	names := []string{"data", "sizes", "keys", "mixed"}
+	a, b := NewSeedSource(42), NewSeedSource(42)
+	seen := make(map[int64]string)
+	for _, name := range names {
+		sa, sb := a.Seed(name), b.Seed(name)
+		if sa != sb {
+			t.Errorf("%s: got different seeds %d and %d", name, sa, sb)
+		}
+		if other, ok := seen[sa]; ok {
+			t.Errorf("%s: same seed as %s", name, other)
+		}
+		seen[sa] = name
+		if a.Child(name).Next() != b.Child(name).Next() {
+			t.Errorf("%s: children differ", name)
+		}
+	}
+	for i := range 100 {
+		if na, nb := a.Next(), b.Next(); na != nb {
+			t.Fatalf("sequence %d: %d != %d", i, na, nb)
+		}
+	}
+	if NewSeedSource(43).Seed("data") == a.Seed("data") {
+		t.Error("different master seeds returned same seed")
+	}
+}
+
+func TestSeedSourceGenerator(t *testing.T) {
+	run := func(master int64) (names []string, data [][]byte) {
+		fn, err := NewFn(WithSeedSource(NewSeedSource(master)), WithRandomData().Size(1<<10).Apply(), WithSize(10<<10), WithPrefixSize(8))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for range 3 {
+			src := fn()
+			for range 3 {
+				obj := src.Object()
+				b, err := io.ReadAll(obj.Reader)
+				if err != nil {
+					t.Fatal(err)
+				}
+				names = append(names, obj.Name)
+				data = append(data, b)
+			}
+		}
+		return names, data
+	}
+	namesA, dataA := run(1)
+	namesB, dataB := run(1)
+	for i := range namesA {
+		if namesA[i] != namesB[i] {
+			t.Errorf("object %d: name %q != %q", i, namesA[i], namesB[i])
+		}
+		if !bytes.Equal(dataA[i], dataB[i]) {
+			t.Errorf("object %d: data differs", i)
+		}
+	}
+	// Sources must not generate the same names.
+	if namesA[0] == namesA[3] {
+		t.Error("sources returned the same names")
+	}
+	namesC, _ := run(2)
+	if namesA[0] == namesC[0] {
+		t.Error("different master seeds returned same names")
+	}
+}
@@ -0,0 +1,106 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+)
+
+type sizeDistribution int
+
+const (
+	sizeConstant sizeDistribution = iota
+	sizeUniform
+	sizeLogNormal
+)
+
+// SizeSampler returns object sizes drawn from a configured distribution.
+// A SizeSampler should only be used by a single goroutine.
+type SizeSampler struct {
+	rng     *rand.Rand
+	dist    sizeDistribution
+	size    int64
+	maxSize int64
+	mu      float64
+	sigma   float64
+	minSize int64
+}
+
+// NewConstantSizeSampler returns a sampler that always returns size.
+func NewConstantSizeSampler(size int64) *SizeSampler {
+	return &SizeSampler{dist: sizeConstant, size: size, minSize: 1}
+}
+
+// NewUniformSizeSampler returns a sampler with sizes uniformly distributed in [minSize, maxSize].
+// A minSize of 0 includes zero byte objects.
+func NewUniformSizeSampler(minSize, maxSize, seed int64) (*SizeSampler, error) {
+	if minSize < 0 {
+		return nil, errors.New("NewUniformSizeSampler: minSize must be >= 0")
+	}
+	if minSize > maxSize {
+		return nil, errors.New("NewUniformSizeSampler: minSize must be <= maxSize")
+	}
+	return &SizeSampler{
+		rng:     rand.New(rand.NewSource(seed)),
+		dist:    sizeUniform,
+		size:    minSize,
+		maxSize: maxSize,
+		minSize: min(minSize, 1),
+	}, nil
+}
+
+// NewLogNormalSizeSampler returns a sampler with log-normal distributed sizes.
+// mean is the mean size in bytes and sigma the standard deviation of the logarithm of the size.
+func NewLogNormalSizeSampler(mean, sigma float64, seed int64) (*SizeSampler, error) {
+	if mean <= 0 {
+		return nil, errors.New("NewLogNormalSizeSampler: mean must be > 0")
+	}
+	if sigma < 0 {
+		return nil, errors.New("NewLogNormalSizeSampler: sigma must be >= 0")
+	}
+	return &SizeSampler{
+		rng:     rand.New(rand.NewSource(seed)),
+		dist:    sizeLogNormal,
+		mu:      math.Log(mean) - sigma*sigma/2,
+		sigma:   sigma,
+		minSize: 1,
+	}, nil
+}
+
+// WithMinSize sets the minimum size returned by random samplers.
+// The default is 1 byte. Use 0 to allow zero byte objects.
+func (s *SizeSampler) WithMinSize(n int64) *SizeSampler {
+	s.minSize = max(n, 0)
+	return s
+}
+
+// Next returns the next size.
+func (s *SizeSampler) Next() int64 {
+	var n int64
+	switch s.dist {
+	case sizeConstant:
+		return s.size
+	case sizeUniform:
+		n = s.size + s.rng.Int63n(s.maxSize-s.size+1)
+	case sizeLogNormal:
+		n = int64(math.Round(math.Exp(s.mu + s.sigma*s.rng.NormFloat64())))
+	}
+	return max(n, s.minSize)
+}
@@ -0,0 +1,116 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSizeSampler(t *testing.T) {
+	uniform, err := NewUniformSizeSampler(1000, 3000, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logNormal, err := NewLogNormalSizeSampler(1<<20, 0.5, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		s        *SizeSampler
+		wantMean float64
+		min, max int64
+	}{
+		{name: "constant", s: NewConstantSizeSampler(4096), wantMean: 4096, min: 4096, max: 4096},
+		{name: "uniform", s: uniform, wantMean: 2000, min: 1000, max: 3000},
+		{name: "lognormal", s: logNormal, wantMean: 1 << 20, min: 1, max: math.MaxInt64},
+	}
+	const samples = 100000
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sum float64
+			for i := 0; i < samples; i++ {
+				n := tt.s.Next()
+				if n < tt.min || n > tt.max {
+					t.Fatalf("Next() = %d, outside [%d, %d]", n, tt.min, tt.max)
+				}
+				sum += float64(n)
+			}
+			mean := sum / samples
+			if math.Abs(mean-tt.wantMean) > tt.wantMean*0.02 {
+				t.Errorf("mean = %.0f, want %.0f +/- 2%%", mean, tt.wantMean)
+			}
+		})
+	}
+
+	// Same seed, same sequence.
+	a, _ := NewLogNormalSizeSampler(1000, 1, 42)
+	b, _ := NewLogNormalSizeSampler(1000, 1, 42)
+	for i := 0; i < 1000; i++ {
+		if x, y := a.Next(), b.Next(); x != y {
+			t.Fatalf("sample %d: %d != %d for same seed", i, x, y)
+		}
+	}
+
+	// Minimum is clamped.
+	small, _ := NewLogNormalSizeSampler(10, 3, 1)
+	small.WithMinSize(5)
+	for i := 0; i < 10000; i++ {
+		if n := small.Next(); n < 5 {
+			t.Fatalf("Next() = %d, want >= 5", n)
+		}
+	}
+
+	// Zero is only returned when explicitly allowed.
+	var zeros int
+	tiny, _ := NewLogNormalSizeSampler(1, 2, 1)
+	for i := 0; i < 10000; i++ {
+		if tiny.Next() == 0 {
+			t.Fatal("Next() = 0 with default minimum")
+		}
+	}
+	tiny.WithMinSize(0)
+	for i := 0; i < 10000; i++ {
+		if tiny.Next() == 0 {
+			zeros++
+		}
+	}
+	if zeros == 0 {
+		t.Error("WithMinSize(0): zero never returned")
+	}
+	withZero, _ := NewUniformSizeSampler(0, 1, 1)
+	zeros = 0
+	for i := 0; i < 1000; i++ {
+		if withZero.Next() == 0 {
+			zeros++
+		}
+	}
+	if zeros < 400 || zeros > 600 {
+		t.Errorf("uniform [0, 1]: %d of 1000 zero, want about 500", zeros)
+	}
+	if NewConstantSizeSampler(0).Next() != 0 {
+		t.Error("constant zero size sampler returned non-zero")
+	}
+	if _, err := NewUniformSizeSampler(-1, 1, 1); err == nil {
+		t.Error("expected error when min < 0")
+	}
+	if _, err := NewUniformSizeSampler(10, 1, 1); err == nil {
+		t.Error("expected error when min > max")
+	}
+}
@@ -0,0 +1,105 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+)
+
+// SizeSchedule returns object sizes from a fixed list in order,
+// starting over when the end is reached.
+// A SizeSchedule should only be used by a single goroutine.
+type SizeSchedule struct {
+	sizes []int64
+	next  int
+}
+
+// LoadSizeSchedule reads a schedule from the file at path.
+// See ReadSizeSchedule for the format.
+func LoadSizeSchedule(path string) (*SizeSchedule, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	s, err := ReadSizeSchedule(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return s, nil
+}
+
+// ReadSizeSchedule reads a schedule with one size per line.
+// Sizes can be plain byte counts or have a unit, like 4k, 1M or 10KiB.
+// Units are parsed with humanize.ParseBytes, so 4k is 4000 bytes and 4KiB is 4096.
+// Empty lines and lines starting with # are ignored.
+func ReadSizeSchedule(r io.Reader) (*SizeSchedule, error) {
+	var s SizeSchedule
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		txt := strings.TrimSpace(sc.Text())
+		if txt == "" || strings.HasPrefix(txt, "#") {
+			continue
+		}
+		n, err := humanize.ParseBytes(txt)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if n > 1<<62 {
+			return nil, fmt.Errorf("line %d: size %q too large", line, txt)
+		}
+		s.sizes = append(s.sizes, int64(n))
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if len(s.sizes) == 0 {
+		return nil, errors.New("size schedule has no sizes")
+	}
+	return &s, nil
+}
+
+// Len returns the number of sizes in the schedule.
+func (s *SizeSchedule) Len() int {
+	return len(s.sizes)
+}
+
+// Next returns the next size.
+func (s *SizeSchedule) Next() int64 {
+	n := s.sizes[s.next]
+	s.next = (s.next + 1) % len(s.sizes)
+	return n
+}
+
+// Max returns the largest size in the schedule.
+func (s *SizeSchedule) Max() int64 {
+	return slices.Max(s.sizes)
+}
+
+// clone returns a copy of the schedule starting at the first size.
+func (s *SizeSchedule) clone() *SizeSchedule {
+	return &SizeSchedule{sizes: s.sizes}
+}
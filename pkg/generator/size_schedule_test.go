@@ -0,0 +1,91 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSizeSchedule(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sizes.txt")
+	content := "# sizes for the nightly run\n4k\n1M\n\n  10KiB  \n512\n2MiB\n0\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := LoadSizeSchedule(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []int64{4000, 1000000, 10 << 10, 512, 2 << 20, 0}
+	if s.Len() != len(want) {
+		t.Fatalf("got %d sizes, want %d", s.Len(), len(want))
+	}
+	// Sizes are returned in order and wrap around.
+	for i := range 3 * len(want) {
+		if got := s.Next(); got != want[i%len(want)] {
+			t.Fatalf("size %d: got %d, want %d", i, got, want[i%len(want)])
+		}
+	}
+
+	for _, tc := range []struct{ in, err string }{
+		{in: "4k\nlots\n", err: "line 2"},
+		{in: "-1\n", err: "line 1"},
+		{in: "# nothing\n\n", err: "no sizes"},
+	} {
+		_, err := ReadSizeSchedule(strings.NewReader(tc.in))
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%q: got error %v, want %q", tc.in, err, tc.err)
+		}
+	}
+	if _, err := LoadSizeSchedule(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestSizeScheduleSource(t *testing.T) {
+	s, err := ReadSizeSchedule(strings.NewReader("1k\n10\n4KiB\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := NewFn(WithRandomData().Apply(), WithSizeSchedule(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []int64{1000, 10, 4 << 10}
+	// Every source uses the schedule from the start.
+	for range 2 {
+		g := src()
+		for i := range 2 * len(want) {
+			obj := g.Object()
+			n, err := io.Copy(io.Discard, obj.Reader)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if obj.Size != want[i%len(want)] || n != obj.Size {
+				t.Fatalf("object %d: size %d, read %d, want %d", i, obj.Size, n, want[i%len(want)])
+			}
+		}
+	}
+	if _, err := NewFn(WithSizeSchedule(nil)); err == nil {
+		t.Error("expected error for nil schedule")
+	}
+}
@@ -1,19 +1,39 @@
 package generator
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"sync"
 )
 
 // staticReader is an io.ReadSeeker that repeats a fixed byte pattern.
+// The pattern is read-only after construction, so it can be shared by clones.
 type staticReader struct {
 	pattern []byte
-	size    int64
-	pos     int64
+	// chunk is the pattern repeated to at least writeChunkSize bytes, used by WriteTo.
+	chunk []byte
+	size  int64
+	pos   int64
 }
 
+// writeChunkSize is the minimum size of writes done by WriteTo.
+const writeChunkSize = 64 << 10
+
 // Used to create a new static reader that repeats a pattern with repeating byte sequence (0x00, 0x01, 0x02, ...).
 func newStaticReader(patternSize int) *staticReader {
+	return &staticReader{
+		pattern: StaticPattern(patternSize),
+		size:    0,
+		pos:     0,
+	}
+}
+
+// StaticPattern returns the default static pattern of the given size,
+// the repeating byte sequence (0x00, 0x01, 0x02, ...).
+// Sizes <= 0 will return the default 128KB pattern.
+func StaticPattern(patternSize int) []byte {
 	if patternSize <= 0 {
 		patternSize = 128 << 10 // Default to 128KB
 	}
@@ -21,49 +41,124 @@ func newStaticReader(patternSize int) *staticReader {
 	for i := range pattern {
 		pattern[i] = byte(i % 256)
 	}
-	return &staticReader{
-		pattern: pattern,
-		size:    0,
-		pos:     0,
+	return pattern
+}
+
+// PatternBytesAt returns the length bytes a static reader with the given pattern size
+// returns at offset, for example to verify ranged reads.
+// Only the returned window is allocated.
+func PatternBytesAt(patternSize int, offset, length int64) []byte {
+	if patternSize <= 0 {
+		patternSize = 128 << 10
 	}
+	if offset < 0 || length <= 0 {
+		return nil
+	}
+	b := make([]byte, length)
+	patternPos := int(offset % int64(patternSize))
+	for i := range b {
+		b[i] = byte(patternPos % 256)
+		patternPos++
+		if patternPos == patternSize {
+			patternPos = 0
+		}
+	}
+	return b
+}
+
+// newStaticReaderWithPattern returns a static reader that repeats a copy of pattern.
+func newStaticReaderWithPattern(pattern []byte) (*staticReader, error) {
+	if len(pattern) == 0 {
+		return nil, errors.New("static reader: empty pattern")
+	}
+	return &staticReader{pattern: bytes.Clone(pattern)}, nil
+}
+
+// zeroReader is a staticReader that only returns zeros.
+type zeroReader struct {
+	staticReader
+}
+
+// newZeroReader returns a reader that only returns 0x00 bytes.
+// Use ResetSize to set the size before reading.
+func newZeroReader() *zeroReader {
+	return &zeroReader{staticReader{pattern: make([]byte, 128<<10)}}
 }
 
 // Resets the reader to the beginning and sets a new size limit.
+// The pattern is kept, so a reader can be reused for any number of objects.
 func (s *staticReader) ResetSize(size int64) {
 	s.size = size
 	s.pos = 0
 }
 
-// Read reads data from the static pattern, repeating it as needed.
-func (s *staticReader) Read(p []byte) (n int, err error) {
-	if s.size <= 0 {
-		return 0, io.EOF
-	}
-	if len(s.pattern) == 0 {
-		return 0, io.EOF
+// Clone returns a reader with the same size positioned at the start.
+// The clone shares the pattern with s, and can be used concurrently with s.
+func (s *staticReader) Clone() *staticReader {
+	return &staticReader{
+		pattern: s.pattern,
+		chunk:   s.chunk,
+		size:    s.size,
 	}
+}
 
-	remaining := s.size - s.pos
-	if remaining <= 0 {
-		return 0, io.EOF
-	}
+// Size returns the configured size.
+func (s *staticReader) Size() int64 {
+	return s.size
+}
 
-	toRead := len(p)
-	if int64(toRead) > remaining {
-		toRead = int(remaining)
+// Remaining returns the number of bytes that have not been read yet.
+func (s *staticReader) Remaining() int64 {
+	return max(s.size-s.pos, 0)
+}
+
+// Reset resets the reader to the beginning with a size of 0.
+func (s *staticReader) Reset() {
+	s.ResetSize(0)
+}
+
+// staticReaderPools contains a *sync.Pool of readers for each pattern size.
+var staticReaderPools sync.Map
+
+// getStaticReader returns a reset static reader with the given pattern size.
+// Return the reader with putStaticReader when done.
+func getStaticReader(patternSize int) *staticReader {
+	if patternSize <= 0 {
+		patternSize = 128 << 10
+	}
+	p, ok := staticReaderPools.Load(patternSize)
+	if !ok {
+		p, _ = staticReaderPools.LoadOrStore(patternSize, &sync.Pool{
+			New: func() any {
+				return newStaticReader(patternSize)
+			},
+		})
 	}
+	return p.(*sync.Pool).Get().(*staticReader)
+}
 
-	patternPos := int(s.pos % int64(len(s.pattern)))
-	for i := 0; i < toRead; i++ {
-		p[i] = s.pattern[patternPos]
-		patternPos = (patternPos + 1) % len(s.pattern)
-		s.pos++
+// putStaticReader returns a reader obtained by getStaticReader to the pool.
+// The reader may not be used after this call.
+func putStaticReader(s *staticReader) {
+	if s == nil {
+		return
+	}
+	s.Reset()
+	if p, ok := staticReaderPools.Load(len(s.pattern)); ok {
+		p.(*sync.Pool).Put(s)
 	}
+}
 
-	if s.pos >= s.size {
-		return toRead, io.EOF
+// Read reads data from the static pattern, repeating it as needed.
+// Data is returned with a nil error and io.EOF is only returned
+// by the first call after all data has been read.
+func (s *staticReader) Read(p []byte) (n int, err error) {
+	n, err = s.ReadAt(p, s.pos)
+	s.pos += int64(n)
+	if err == io.EOF && n > 0 {
+		err = nil
 	}
-	return toRead, nil
+	return n, err
 }
 
 // Sets the offset for the next Read.
@@ -90,3 +185,67 @@ func (s *staticReader) Seek(offset int64, whence int) (int64, error) {
 	s.pos = newPos
 	return s.pos, nil
 }
+
+// ReadAt reads len(p) bytes of the pattern starting at absolute offset off.
+// It does not use or modify the read position, so it is safe to call
+// concurrently, for example from several io.SectionReader.
+func (s *staticReader) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, fmt.Errorf("negative offset: %d", off)
+	}
+	if off >= s.size || len(s.pattern) == 0 {
+		return 0, io.EOF
+	}
+	toRead := len(p)
+	if remaining := s.size - off; int64(toRead) > remaining {
+		toRead = int(remaining)
+	}
+	patternPos := int(off % int64(len(s.pattern)))
+	for n < toRead {
+		n += copy(p[n:toRead], s.pattern[patternPos:])
+		patternPos = 0
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// WriteTo writes the remaining data to w.
+// The pattern is written in chunks of at least writeChunkSize bytes without intermediate copies.
+func (s *staticReader) WriteTo(w io.Writer) (n int64, err error) {
+	if len(s.pattern) == 0 {
+		return 0, nil
+	}
+	chunk := s.writeChunk()
+	for s.pos < s.size {
+		patternPos := int(s.pos % int64(len(s.pattern)))
+		b := chunk[patternPos:]
+		if remaining := s.size - s.pos; int64(len(b)) > remaining {
+			b = b[:remaining]
+		}
+		written, err := w.Write(b)
+		s.pos += int64(written)
+		n += int64(written)
+		if err != nil {
+			return n, err
+		}
+		if written != len(b) {
+			return n, io.ErrShortWrite
+		}
+	}
+	return n, nil
+}
+
+// writeChunk returns the pattern repeated to at least writeChunkSize bytes.
+// The chunk length is a multiple of the pattern length.
+func (s *staticReader) writeChunk() []byte {
+	if len(s.pattern) >= writeChunkSize {
+		return s.pattern
+	}
+	if s.chunk == nil {
+		n := (writeChunkSize + len(s.pattern) - 1) / len(s.pattern)
+		s.chunk = bytes.Repeat(s.pattern, n)
+	}
+	return s.chunk
+}
@@ -0,0 +1,393 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"testing"
+)
+
+// patternByte returns the byte newStaticReader(patternSize) produces at off.
+func patternByte(patternSize int, off int64) byte {
+	return byte((off % int64(patternSize)) % 256)
+}
+
+func TestStaticReaderReadAt(t *testing.T) {
+	const (
+		patternSize = 1000
+		size        = 10_000
+	)
+	r := newStaticReader(patternSize)
+	r.ResetSize(size)
+
+	type rng struct {
+		off, length int64
+	}
+	ranges := []rng{
+		{0, 100},
+		{50, 2000},
+		{999, 2},
+		{1000, 1000},
+		{4321, 3333},
+		{size - 10, 10},
+		{size - 10, 100},
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		for _, rg := range ranges {
+			wg.Add(1)
+			go func(rg rng) {
+				defer wg.Done()
+				sr := io.NewSectionReader(r, rg.off, rg.length)
+				b, err := io.ReadAll(sr)
+				if err != nil {
+					t.Errorf("ReadAll(%d, %d): %v", rg.off, rg.length, err)
+					return
+				}
+				want := min(rg.length, size-rg.off)
+				if int64(len(b)) != want {
+					t.Errorf("ReadAll(%d, %d) got %d bytes, want %d", rg.off, rg.length, len(b), want)
+					return
+				}
+				for j, v := range b {
+					if exp := patternByte(patternSize, rg.off+int64(j)); v != exp {
+						t.Errorf("offset %d: got %d, want %d", rg.off+int64(j), v, exp)
+						return
+					}
+				}
+			}(rg)
+		}
+	}
+	wg.Wait()
+
+	if r.pos != 0 {
+		t.Errorf("ReadAt modified position: %d", r.pos)
+	}
+	if _, err := r.ReadAt(make([]byte, 1), -1); err == nil {
+		t.Error("expected error on negative offset")
+	}
+	if n, err := r.ReadAt(make([]byte, 1), size); n != 0 || err != io.EOF {
+		t.Errorf("ReadAt at size: got (%d, %v), want (0, EOF)", n, err)
+	}
+	if n, err := r.ReadAt(make([]byte, 20), size-10); n != 10 || err != io.EOF {
+		t.Errorf("ReadAt past end: got (%d, %v), want (10, EOF)", n, err)
+	}
+}
+
+func TestStaticReaderWriteTo(t *testing.T) {
+	for _, patternSize := range []int{1, 100, 1000, writeChunkSize + 1} {
+		for _, size := range []int64{0, 1, 999, writeChunkSize*3 + 17} {
+			r := newStaticReader(patternSize)
+			r.ResetSize(size)
+			want, err := io.ReadAll(struct{ io.Reader }{r})
+			if err != nil {
+				t.Fatal(err)
+			}
+			r.ResetSize(size)
+			// Start mid-stream to check offset handling.
+			off := size / 3
+			r.Seek(off, io.SeekStart)
+			var buf bytes.Buffer
+			n, err := r.WriteTo(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != size-off || !bytes.Equal(buf.Bytes(), want[off:]) {
+				t.Errorf("pattern %d, size %d: WriteTo output differs from Read output", patternSize, size)
+			}
+			if r.pos != size {
+				t.Errorf("pattern %d, size %d: pos = %d after WriteTo", patternSize, size, r.pos)
+			}
+		}
+	}
+}
+
+func BenchmarkStaticReaderCopy(b *testing.B) {
+	const size = 10 << 20
+	for _, tt := range []struct {
+		name string
+		wrap func(r *staticReader) io.Reader
+	}{
+		{name: "WriteTo", wrap: func(r *staticReader) io.Reader { return r }},
+		{name: "Read", wrap: func(r *staticReader) io.Reader { return struct{ io.Reader }{r} }},
+	} {
+		b.Run(tt.name, func(b *testing.B) {
+			r := newStaticReader(0)
+			b.SetBytes(size)
+			b.ReportAllocs()
+			for b.Loop() {
+				r.ResetSize(size)
+				if _, err := io.Copy(io.Discard, tt.wrap(r)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestStaticReaderPool(t *testing.T) {
+	r := getStaticReader(100)
+	r.ResetSize(1000)
+	io.CopyN(io.Discard, r, 500)
+	putStaticReader(r)
+
+	r = getStaticReader(100)
+	defer putStaticReader(r)
+	if len(r.pattern) != 100 {
+		t.Fatalf("pattern size = %d, want 100", len(r.pattern))
+	}
+	if r.size != 0 || r.pos != 0 {
+		t.Fatalf("pooled reader not reset: size %d, pos %d", r.size, r.pos)
+	}
+	r.ResetSize(300)
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range b {
+		if v != patternByte(100, int64(i)) {
+			t.Fatalf("offset %d: got %d, want %d", i, v, patternByte(100, int64(i)))
+		}
+	}
+}
+
+func BenchmarkStaticReaderPerObject(b *testing.B) {
+	const size = 4 << 10
+	b.Run("new", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			r := newStaticReader(0)
+			r.ResetSize(size)
+			io.Copy(io.Discard, r)
+		}
+	})
+	b.Run("pool", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			r := getStaticReader(0)
+			r.ResetSize(size)
+			io.Copy(io.Discard, r)
+			putStaticReader(r)
+		}
+	})
+}
+
+func TestZeroReader(t *testing.T) {
+	r := newZeroReader()
+	for _, size := range []int64{0, 1, 128<<10 + 1, 1 << 20} {
+		r.ResetSize(size)
+		b, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if int64(len(b)) != size {
+			t.Fatalf("got %d bytes, want %d", len(b), size)
+		}
+		if !bytes.Equal(b, make([]byte, size)) {
+			t.Fatalf("size %d: non-zero data returned", size)
+		}
+		if size < 10 {
+			continue
+		}
+		off, err := r.Seek(size/2, io.SeekStart)
+		if err != nil || off != size/2 {
+			t.Fatalf("Seek() = %d, %v", off, err)
+		}
+		b, _ = io.ReadAll(r)
+		if int64(len(b)) != size-off || !bytes.Equal(b, make([]byte, len(b))) {
+			t.Fatalf("size %d: unexpected data after seek", size)
+		}
+	}
+}
+
+func TestStaticReaderReadEOF(t *testing.T) {
+	type call struct {
+		bufSize int
+		wantN   int
+		wantErr error
+	}
+	tests := []struct {
+		name  string
+		size  int64
+		calls []call
+	}{
+		{
+			name:  "zero-size",
+			size:  0,
+			calls: []call{{10, 0, io.EOF}, {10, 0, io.EOF}},
+		},
+		{
+			name:  "exact-boundary",
+			size:  10,
+			calls: []call{{10, 10, nil}, {10, 0, io.EOF}},
+		},
+		{
+			name:  "past-end",
+			size:  10,
+			calls: []call{{6, 6, nil}, {6, 4, nil}, {6, 0, io.EOF}, {6, 0, io.EOF}},
+		},
+		{
+			name:  "pattern-wrap",
+			size:  250,
+			calls: []call{{99, 99, nil}, {150, 150, nil}, {1, 1, nil}, {1, 0, io.EOF}},
+		},
+		{
+			name:  "empty-buffer",
+			size:  10,
+			calls: []call{{0, 0, nil}, {10, 10, nil}, {0, 0, io.EOF}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newStaticReader(100)
+			r.ResetSize(tt.size)
+			var off int64
+			for i, c := range tt.calls {
+				p := make([]byte, c.bufSize)
+				n, err := r.Read(p)
+				if n != c.wantN || err != c.wantErr {
+					t.Fatalf("call %d: Read() = (%d, %v), want (%d, %v)", i, n, err, c.wantN, c.wantErr)
+				}
+				for j, v := range p[:n] {
+					if v != patternByte(100, off+int64(j)) {
+						t.Fatalf("call %d: offset %d: got %d", i, off+int64(j), v)
+					}
+				}
+				off += int64(n)
+			}
+		})
+	}
+}
+
+func TestStaticReaderSizeRemaining(t *testing.T) {
+	r := newStaticReader(100)
+	if r.Size() != 0 || r.Remaining() != 0 {
+		t.Fatalf("new reader: Size() = %d, Remaining() = %d", r.Size(), r.Remaining())
+	}
+	r.ResetSize(1000)
+	check := func(wantRemaining int64) {
+		t.Helper()
+		if r.Size() != 1000 {
+			t.Errorf("Size() = %d, want 1000", r.Size())
+		}
+		if r.Remaining() != wantRemaining {
+			t.Errorf("Remaining() = %d, want %d", r.Remaining(), wantRemaining)
+		}
+	}
+	check(1000)
+	io.CopyN(io.Discard, r, 300)
+	check(700)
+	io.Copy(io.Discard, r)
+	check(0)
+	r.Seek(-10, io.SeekEnd)
+	check(10)
+	r.ResetSize(500)
+	if r.Size() != 500 || r.Remaining() != 500 {
+		t.Errorf("after ResetSize: Size() = %d, Remaining() = %d", r.Size(), r.Remaining())
+	}
+}
+
+func TestStaticReaderWithPattern(t *testing.T) {
+	pattern := []byte("WARP-MAGIC-")
+	r, err := newStaticReaderWithPattern(pattern)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The pattern must be copied.
+	pattern[0] = 'X'
+	const size = 1000
+	r.ResetSize(size)
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := bytes.Repeat([]byte("WARP-MAGIC-"), size/11+1)[:size]
+	if !bytes.Equal(b, want) {
+		t.Errorf("got %q..., want %q...", b[:30], want[:30])
+	}
+	if _, err := newStaticReaderWithPattern(nil); err == nil {
+		t.Error("expected error for empty pattern")
+	}
+}
+
+func TestStaticReaderClone(t *testing.T) {
+	const size = 100_000
+	base := newStaticReader(1000)
+	base.ResetSize(size)
+	want, _ := io.ReadAll(base.Clone())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 64; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			r := base.Clone()
+			if &r.pattern[0] != &base.pattern[0] {
+				t.Error("clone does not share pattern")
+				return
+			}
+			off := int64(i * 997)
+			r.Seek(off, io.SeekStart)
+			var buf bytes.Buffer
+			if i%2 == 0 {
+				io.Copy(&buf, r)
+			} else {
+				io.Copy(&buf, struct{ io.Reader }{r})
+			}
+			if !bytes.Equal(buf.Bytes(), want[off:]) {
+				t.Errorf("clone %d returned unexpected data", i)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if base.pos != 0 {
+		t.Errorf("base position changed to %d", base.pos)
+	}
+}
+
+func TestPatternBytesAt(t *testing.T) {
+	const patternSize = 1000
+	r := newStaticReader(patternSize)
+	r.ResetSize(10_000)
+	tests := []struct {
+		offset, length int64
+	}{
+		{0, 10},
+		{990, 20},
+		{999, 1},
+		{1000, 1},
+		{500, 2500},
+		{7, 9000},
+	}
+	for _, tt := range tests {
+		want := make([]byte, tt.length)
+		n, err := r.ReadAt(want, tt.offset)
+		if err != nil || int64(n) != tt.length {
+			t.Fatalf("ReadAt(%d, %d) = %d, %v", tt.offset, tt.length, n, err)
+		}
+		got := PatternBytesAt(patternSize, tt.offset, tt.length)
+		if !bytes.Equal(got, want) {
+			t.Errorf("PatternBytesAt(%d, %d) differs from reader output", tt.offset, tt.length)
+		}
+	}
+	if got := PatternBytesAt(patternSize, 5, 0); len(got) != 0 {
+		t.Errorf("zero length returned %d bytes", len(got))
+	}
+}
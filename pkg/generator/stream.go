@@ -0,0 +1,65 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"io"
+)
+
+// streamReader hides everything but Read of the underlying reader,
+// so consumers cannot seek it or learn its length.
+type streamReader struct {
+	r io.Reader
+}
+
+// NewStreamReader returns a reader of the first size bytes of r
+// that only implements io.Reader.
+// Use the reader of a generated object, so the content is given by the seed of its source.
+// Since the length is unknown to the consumer, uploads of it are sent
+// with chunked transfer encoding instead of a Content-Length.
+func NewStreamReader(r io.Reader, size int64) io.Reader {
+	return &streamReader{r: io.LimitReader(r, size)}
+}
+
+// Read reads from the underlying reader.
+func (s *streamReader) Read(p []byte) (int, error) {
+	return s.r.Read(p)
+}
+
+// FixedLengthStreamReader is a stream reader of a known length.
+// Like the reader returned by NewStreamReader it can only be read,
+// but uploads can send Size as Content-Length instead of using chunked transfer encoding.
+// Use it to test servers that require a Content-Length with clients that
+// cannot seek or buffer the content.
+type FixedLengthStreamReader struct {
+	streamReader
+	size int64
+}
+
+// NewFixedLengthStreamReader returns a reader of the first size bytes of r
+// that only implements io.Reader and Size.
+// r must return at least size bytes.
+func NewFixedLengthStreamReader(r io.Reader, size int64) *FixedLengthStreamReader {
+	return &FixedLengthStreamReader{streamReader: streamReader{r: io.LimitReader(r, size)}, size: size}
+}
+
+// Size returns the total number of bytes the reader returns,
+// regardless of how much has been read.
+func (f *FixedLengthStreamReader) Size() int64 {
+	return f.size
+}
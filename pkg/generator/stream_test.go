@@ -0,0 +1,128 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+// randomData returns a reader with size bytes of seeded random data.
+func randomData(seed, size int64) io.Reader {
+	r := newRandomReader(seed)
+	r.ResetSize(size)
+	return r
+}
+
+func TestStreamReader(t *testing.T) {
+	for _, size := range []int64{0, 1, 7, 8, 4096, 1<<20 + 13} {
+		r := NewStreamReader(randomData(1, size), size)
+		if _, ok := r.(io.Seeker); ok {
+			t.Fatalf("size %d: reader can be seeked", size)
+		}
+		if _, ok := r.(interface{ Len() int }); ok {
+			t.Fatalf("size %d: reader exposes its length", size)
+		}
+		n, err := io.Copy(io.Discard, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != size {
+			t.Errorf("got %d bytes, want %d", n, size)
+		}
+		if n, err := r.Read(make([]byte, 10)); n != 0 || err != io.EOF {
+			t.Errorf("size %d: read after EOF returned %d, %v", size, n, err)
+		}
+	}
+}
+
+func TestStreamReaderSeeded(t *testing.T) {
+	// The content is taken from the seeded object data.
+	a, _ := io.ReadAll(NewFixedLengthStreamReader(randomData(5, 1000), 1000))
+	b, _ := io.ReadAll(NewStreamReader(randomData(5, 2000), 1000))
+	c, _ := io.ReadAll(NewStreamReader(randomData(6, 1000), 1000))
+	if len(a) != 1000 || !bytes.Equal(a, b) {
+		t.Error("same seed gave different content")
+	}
+	if bytes.Equal(a, c) {
+		t.Error("different seeds gave the same content")
+	}
+}
+
+func TestFixedLengthStreamReader(t *testing.T) {
+	type received struct {
+		contentLength int64
+		chunked       bool
+		n             int64
+	}
+	got := make(chan received, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, _ := io.Copy(io.Discard, r.Body)
+		got <- received{contentLength: r.ContentLength, chunked: slices.Contains(r.TransferEncoding, "chunked"), n: n}
+	}))
+	defer srv.Close()
+
+	for _, size := range []int64{0, 1, 4096, 1<<20 + 13} {
+		r := NewFixedLengthStreamReader(randomData(1, size), size)
+		if _, ok := any(r).(io.Seeker); ok {
+			t.Fatal("reader can be seeked")
+		}
+		if r.Size() != size {
+			t.Fatalf("size %d: Size() = %d", size, r.Size())
+		}
+		// net/http treats a zero length with a body as unknown.
+		var body io.Reader = r
+		if r.Size() == 0 {
+			body = http.NoBody
+		}
+		req, err := http.NewRequest(http.MethodPut, srv.URL, body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The stream is not recognized by net/http, the length is set from Size.
+		req.ContentLength = r.Size()
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		rcv := <-got
+		if rcv.contentLength != size || rcv.chunked || rcv.n != size {
+			t.Errorf("size %d: server got Content-Length %d, chunked %v, %d bytes", size, rcv.contentLength, rcv.chunked, rcv.n)
+		}
+		// The size is kept after reading.
+		if r.Size() != size {
+			t.Errorf("size %d: Size() = %d after reading", size, r.Size())
+		}
+	}
+
+	// Without the length a stream is sent chunked.
+	req, _ := http.NewRequest(http.MethodPut, srv.URL, NewStreamReader(randomData(1, 100), 100))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if rcv := <-got; !rcv.chunked || rcv.n != 100 {
+		t.Errorf("stream without length: %+v", rcv)
+	}
+}
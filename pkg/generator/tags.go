@@ -0,0 +1,86 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// Limits for object tags set by S3.
+const (
+	MaxObjectTags    = 10
+	MaxTagValueBytes = 256
+)
+
+// TagGenerator generates object tags with random values.
+// It is safe for concurrent use.
+type TagGenerator struct {
+	keys    []string
+	seed    uint64
+	counter atomic.Uint64
+
+	mu    sync.Mutex
+	sizes *SizeSampler
+}
+
+// NewTagGenerator returns a generator of n tags per object.
+// The size of each value is taken from sizes and capped at MaxTagValueBytes.
+func NewTagGenerator(n int, sizes *SizeSampler, seed int64) (*TagGenerator, error) {
+	if n <= 0 || n > MaxObjectTags {
+		return nil, errors.New("NewTagGenerator: number of tags must be between 1 and " + strconv.Itoa(MaxObjectTags))
+	}
+	if sizes == nil {
+		return nil, errors.New("NewTagGenerator: no value size sampler")
+	}
+	t := &TagGenerator{seed: uint64(seed), sizes: sizes}
+	for i := range n {
+		t.keys = append(t.keys, "tag-"+strconv.Itoa(i))
+	}
+	return t, nil
+}
+
+// Len returns the number of tags returned by Next.
+func (t *TagGenerator) Len() int {
+	return len(t.keys)
+}
+
+// Next returns tags for the next object.
+func (t *TagGenerator) Next() map[string]string {
+	n := t.counter.Add(1)
+	sizes := make([]int64, len(t.keys))
+	t.mu.Lock()
+	for i := range sizes {
+		sizes[i] = min(t.sizes.Next(), MaxTagValueBytes)
+	}
+	t.mu.Unlock()
+
+	tags := make(map[string]string, len(t.keys))
+	v := splitMix64(t.seed ^ splitMix64(n))
+	for i, k := range t.keys {
+		b := make([]byte, sizes[i])
+		for j := range b {
+			v = splitMix64(v)
+			b[j] = MetadataValueChars[v%uint64(len(MetadataValueChars))]
+		}
+		tags[k] = string(b)
+	}
+	return tags
+}
@@ -0,0 +1,63 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestTagGenerator(t *testing.T) {
+	sizes, err := NewUniformSizeSampler(1, 1000, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := NewTagGenerator(4, sizes, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Len() != 4 {
+		t.Errorf("got length %d, want 4", g.Len())
+	}
+	a, b := g.Next(), g.Next()
+	for _, tags := range []map[string]string{a, b} {
+		if len(tags) != 4 {
+			t.Errorf("got %d tags, want 4", len(tags))
+		}
+		for k, v := range tags {
+			if len(v) == 0 || len(v) > MaxTagValueBytes {
+				t.Errorf("tag %s: value size %d out of range", k, len(v))
+			}
+		}
+	}
+	if maps.Equal(a, b) {
+		t.Error("consecutive objects got same tags")
+	}
+
+	g, _ = NewTagGenerator(2, NewConstantSizeSampler(10), 1)
+	for k, v := range g.Next() {
+		if len(v) != 10 {
+			t.Errorf("tag %s: got value size %d, want 10", k, len(v))
+		}
+	}
+	for _, n := range []int{0, MaxObjectTags + 1} {
+		if _, err := NewTagGenerator(n, NewConstantSizeSampler(10), 1); err == nil {
+			t.Errorf("%d tags: want error", n)
+		}
+	}
+}
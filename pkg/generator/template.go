@@ -0,0 +1,205 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxTemplateRand is the longest random string a {{rand:n}} placeholder can produce.
+const maxTemplateRand = 1 << 20
+
+// templateRandChars are the characters of {{rand:n}} strings.
+const templateRandChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+type templateField uint8
+
+const (
+	templateLiteral templateField = iota
+	templateID
+	templateTS
+	templateRand
+)
+
+type templatePart struct {
+	field templateField
+	lit   string
+	n     int
+}
+
+// Template is content with placeholders that are substituted for each object.
+// The supported placeholders are:
+//
+//	{{id}}      the object ID as a decimal number
+//	{{ts}}      the object timestamp in RFC 3339 format, UTC
+//	{{rand:n}}  n random ASCII letters and digits
+//
+// A Template is safe for concurrent use.
+type Template struct {
+	parts []templatePart
+}
+
+// ParseTemplate compiles a template.
+// Text outside placeholders is copied unchanged.
+func ParseTemplate(s string) (*Template, error) {
+	var t Template
+	for len(s) > 0 {
+		start := strings.Index(s, "{{")
+		if start < 0 {
+			t.parts = append(t.parts, templatePart{lit: s})
+			break
+		}
+		if start > 0 {
+			t.parts = append(t.parts, templatePart{lit: s[:start]})
+		}
+		end := strings.Index(s[start:], "}}")
+		if end < 0 {
+			return nil, fmt.Errorf("template: unterminated placeholder at offset %d", start)
+		}
+		name := s[start+2 : start+end]
+		switch {
+		case name == "id":
+			t.parts = append(t.parts, templatePart{field: templateID})
+		case name == "ts":
+			t.parts = append(t.parts, templatePart{field: templateTS})
+		case strings.HasPrefix(name, "rand:"):
+			n, err := strconv.Atoi(strings.TrimPrefix(name, "rand:"))
+			if err != nil || n <= 0 || n > maxTemplateRand {
+				return nil, fmt.Errorf("template: invalid random length in {{%s}}", name)
+			}
+			t.parts = append(t.parts, templatePart{field: templateRand, n: n})
+		default:
+			return nil, fmt.Errorf("template: unknown placeholder {{%s}}", name)
+		}
+		s = s[start+end+2:]
+	}
+	return &t, nil
+}
+
+// Render returns the template with placeholders substituted.
+// Random strings are drawn from a source seeded with seed and id,
+// so the same arguments always render the same content.
+func (t *Template) Render(id int64, ts time.Time, seed int64) []byte {
+	var buf bytes.Buffer
+	var rng *rand.Rand
+	for _, p := range t.parts {
+		switch p.field {
+		case templateLiteral:
+			buf.WriteString(p.lit)
+		case templateID:
+			buf.WriteString(strconv.FormatInt(id, 10))
+		case templateTS:
+			buf.WriteString(ts.UTC().Format(time.RFC3339Nano))
+		case templateRand:
+			if rng == nil {
+				rng = rand.New(rand.NewSource(int64(splitMix64(uint64(seed) ^ splitMix64(uint64(id))))))
+			}
+			b := make([]byte, p.n)
+			for i := range b {
+				b[i] = templateRandChars[rng.Intn(len(templateRandChars))]
+			}
+			buf.Write(b)
+		}
+	}
+	return buf.Bytes()
+}
+
+// TemplateReader is an io.ReadSeeker that returns a rendered template
+// followed by the repeating byte sequence (0x00, 0x01, 0x02, ...) up to the size.
+// Objects smaller than the rendered template contain a truncated template.
+type TemplateReader struct {
+	r *fileTypeReader
+}
+
+// NewTemplateReader returns a reader of size bytes starting with t rendered for the object.
+func NewTemplateReader(t *Template, id int64, ts time.Time, seed int64, size int64) *TemplateReader {
+	return &TemplateReader{r: newHeaderReader(t.Render(id, ts, seed), size)}
+}
+
+// Read reads data.
+func (t *TemplateReader) Read(p []byte) (int, error) {
+	return t.r.Read(p)
+}
+
+// ReadAt reads len(p) bytes starting at absolute offset off.
+// It does not use or modify the read position.
+func (t *TemplateReader) ReadAt(p []byte, off int64) (int, error) {
+	return t.r.ReadAt(p, off)
+}
+
+// Seek sets the offset for the next Read.
+func (t *TemplateReader) Seek(offset int64, whence int) (int64, error) {
+	return t.r.Seek(offset, whence)
+}
+
+// Size returns the total size of the content.
+func (t *TemplateReader) Size() int64 {
+	return t.r.Size()
+}
+
+// Verify reads all of r and verifies it is exactly the content
+// NewTemplateReader returns for the same arguments.
+// On mismatch a *MismatchError is returned.
+func (t *Template) Verify(r io.Reader, id int64, ts time.Time, seed int64, size int64) error {
+	want := NewTemplateReader(t, id, ts, seed, size)
+	got := make([]byte, 32<<10)
+	exp := make([]byte, len(got))
+	var off int64
+	for {
+		n, err := r.Read(got)
+		if n > 0 {
+			if off+int64(n) > size {
+				return ErrVerifyTooLong
+			}
+			want.ReadAt(exp[:n], off)
+			for i := range n {
+				if got[i] != exp[i] {
+					return &MismatchError{Offset: off + int64(i), Got: got[i], Want: exp[i]}
+				}
+			}
+			off += int64(n)
+		}
+		if err == io.EOF {
+			if off < size {
+				return io.ErrUnexpectedEOF
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// WithTemplate makes objects start with t rendered for each object,
+// followed by a repeating pattern.
+// The object ID is the sequence number of the object within its source,
+// and the timestamp is the time the object is generated.
+// A nil template disables this.
+func WithTemplate(t *Template) Option {
+	return func(o *Options) error {
+		o.template = t
+		return nil
+	}
+}
@@ -0,0 +1,158 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTemplateReader(t *testing.T) {
+	tmpl, err := ParseTemplate(`{"id": {{id}}, "ts": "{{ts}}", "key": "{{rand:32}}", "tag": "{{rand:4}}"}` + "\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := time.Date(2025, 6, 7, 8, 9, 10, 0, time.FixedZone("x", 3600))
+	re := regexp.MustCompile(`^\{"id": (\d+), "ts": "2025-06-07T07:09:10Z", "key": "([a-zA-Z0-9]{32})", "tag": "([a-zA-Z0-9]{4})"\}` + "\n")
+	const size = 1000
+	keys := make(map[string]bool)
+	for _, id := range []int64{0, 1, 42, 1 << 40} {
+		r := NewTemplateReader(tmpl, id, ts, 7, size)
+		b, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(b) != size || r.Size() != size {
+			t.Fatalf("id %d: got %d bytes, want %d", id, len(b), size)
+		}
+		m := re.FindSubmatch(b)
+		if m == nil {
+			t.Fatalf("id %d: unexpected content %q", id, b[:100])
+		}
+		if string(m[1]) != fmt.Sprint(id) {
+			t.Errorf("id %d: rendered id %s", id, m[1])
+		}
+		keys[string(m[2])] = true
+		// Padding is the static pattern at the same offset.
+		for off := len(m[0]); off < size; off++ {
+			if b[off] != byte(off) {
+				t.Fatalf("id %d: padding byte at offset %d is 0x%02x", id, off, b[off])
+			}
+		}
+		// Rendering again verifies.
+		if err := tmpl.Verify(bytes.NewReader(b), id, ts, 7, size); err != nil {
+			t.Errorf("id %d: %v", id, err)
+		}
+		// A different seed does not.
+		var mismatch *MismatchError
+		if err := tmpl.Verify(bytes.NewReader(b), id, ts, 8, size); !errors.As(err, &mismatch) {
+			t.Errorf("id %d: got %v with a different seed, want mismatch", id, err)
+		}
+		// Seek and read part of the content.
+		if _, err := r.Seek(10, io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+		part := make([]byte, 20)
+		if _, err := io.ReadFull(r, part); err != nil || !bytes.Equal(part, b[10:30]) {
+			t.Errorf("id %d: unexpected data after seek", id)
+		}
+	}
+	if len(keys) != 4 {
+		t.Errorf("got %d distinct random keys for 4 objects", len(keys))
+	}
+
+	// Small objects contain a truncated template.
+	b, _ := io.ReadAll(NewTemplateReader(tmpl, 5, ts, 7, 8))
+	if string(b) != `{"id": 5` {
+		t.Errorf("got %q for 8 byte object", b)
+	}
+	if err := tmpl.Verify(strings.NewReader(`{"id": 5`), 5, ts, 7, 9); err != io.ErrUnexpectedEOF {
+		t.Errorf("got %v for short content, want %v", err, io.ErrUnexpectedEOF)
+	}
+
+	for _, bad := range []string{"{{id", "{{name}}", "{{rand:0}}", "{{rand:x}}", "{{rand}}"} {
+		if _, err := ParseTemplate(bad); err == nil {
+			t.Errorf("%q: expected error", bad)
+		}
+	}
+}
+
+func TestWithTemplate(t *testing.T) {
+	tmpl, err := ParseTemplate("object {{id}} {{rand:8}}\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := New(WithRandomData().Apply(), WithSize(100), WithTemplate(tmpl))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 3; i++ {
+		obj := src.Object()
+		b, err := io.ReadAll(obj.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(b) != 100 || !strings.HasPrefix(string(b), fmt.Sprintf("object %d ", i)) {
+			t.Errorf("object %d: unexpected content %q", i, b[:20])
+		}
+	}
+	if _, err := New(WithRandomData().Apply(), WithTemplate(tmpl), WithFileType(FileTypePNG)); err == nil {
+		t.Error("expected error combining template and file type")
+	}
+}
+
+func TestWithTemplateSeeded(t *testing.T) {
+	tmpl, err := ParseTemplate(`{"id": {{id}}, "ts": "{{ts}}", "key": "{{rand:8}}"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := func() (data [][]byte, ts []time.Time) {
+		src, err := New(WithRandomData().RngSeed(3).Apply(), WithSize(100), WithTemplate(tmpl))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for range 3 {
+			obj := src.Object()
+			b, err := io.ReadAll(obj.Reader)
+			if err != nil {
+				t.Fatal(err)
+			}
+			data = append(data, b)
+			ts = append(ts, obj.Timestamp)
+		}
+		return data, ts
+	}
+	first, ts := content()
+	time.Sleep(10 * time.Millisecond)
+	second, _ := content()
+	for i := range first {
+		if !bytes.Equal(first[i], second[i]) {
+			t.Errorf("object %d differs for the same seed:\n%s\n%s", i+1, first[i], second[i])
+		}
+		// The stored timestamp is the rendered one.
+		if want := ts[i].UTC().Format(time.RFC3339Nano); !strings.Contains(string(first[i]), want) {
+			t.Errorf("object %d does not contain timestamp %s: %s", i+1, want, first[i])
+		}
+	}
+}
@@ -0,0 +1,71 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"context"
+	"io"
+
+	"golang.org/x/time/rate"
+)
+
+// throttledReader limits the rate data can be read from r.
+type throttledReader struct {
+	ctx   context.Context
+	r     io.ReadSeeker
+	l     *rate.Limiter
+	burst int
+}
+
+// NewThrottledReader returns a reader that returns data from r at most at bytesPerSec.
+// Reads will return the context error if ctx is canceled while waiting.
+// Seeking is forwarded to r and does not affect the rate.
+// If bytesPerSec is <= 0, r is returned.
+func NewThrottledReader(ctx context.Context, r io.ReadSeeker, bytesPerSec int64) io.ReadSeeker {
+	if bytesPerSec <= 0 {
+		return r
+	}
+	// Allow bursts of 1/20th of a second, so reads are smooth.
+	burst := int(max(min(bytesPerSec/20, 1<<20), 1))
+	return &throttledReader{
+		ctx:   ctx,
+		r:     r,
+		l:     rate.NewLimiter(rate.Limit(bytesPerSec), burst),
+		burst: burst,
+	}
+}
+
+// Read reads up to one burst from the underlying reader after
+// waiting for the bytes to be available.
+func (t *throttledReader) Read(p []byte) (int, error) {
+	if len(p) > t.burst {
+		p = p[:t.burst]
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if err := t.l.WaitN(t.ctx, len(p)); err != nil {
+		return 0, err
+	}
+	return t.r.Read(p)
+}
+
+// Seek forwards to the underlying reader.
+func (t *throttledReader) Seek(offset int64, whence int) (int64, error) {
+	return t.r.Seek(offset, whence)
+}
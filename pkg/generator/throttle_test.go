@@ -0,0 +1,83 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestThrottledReader(t *testing.T) {
+	const rate = 1 << 20
+	const size = rate / 4
+
+	src := newStaticReader(0)
+	src.ResetSize(size)
+	r := NewThrottledReader(context.Background(), src, rate)
+	start := time.Now()
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	elapsed := time.Since(start)
+	if len(b) != size {
+		t.Fatalf("got %d bytes, want %d", len(b), size)
+	}
+	// The initial burst is available immediately.
+	want := time.Duration(size-rate/20) * time.Second / rate
+	if elapsed < want*9/10 || elapsed > want*2+100*time.Millisecond {
+		t.Errorf("read took %v, want about %v", elapsed, want)
+	}
+
+	// Seek must be forwarded.
+	if _, err := r.Seek(10, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	got := make([]byte, 10)
+	if _, err := io.ReadFull(r, got); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, b[10:20]) {
+		t.Error("unexpected data after seek")
+	}
+
+	if NewThrottledReader(context.Background(), src, 0) != io.ReadSeeker(src) {
+		t.Error("unlimited reader should not be wrapped")
+	}
+}
+
+func TestThrottledReaderCancel(t *testing.T) {
+	src := newStaticReader(0)
+	src.ResetSize(1 << 20)
+	ctx, cancel := context.WithCancel(context.Background())
+	// 10 bytes per second would take a day to read.
+	r := NewThrottledReader(ctx, src, 10)
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	_, err := io.ReadAll(r)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("cancel took %v", elapsed)
+	}
+}
@@ -0,0 +1,171 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"hash/fnv"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// TraceHeaderSize is the size of the header written by NewTraceReader.
+const TraceHeaderSize = 32
+
+// traceMagic starts every trace header.
+var traceMagic = [4]byte{'W', 'R', 'P', 'T'}
+
+// ErrNoTraceHeader is returned when data does not start with a valid trace header.
+var ErrNoTraceHeader = errors.New("no trace header")
+
+// TraceHeader identifies the object some data was generated for.
+//
+// It is encoded as 32 bytes: the magic "WRPT", the key hash, the sequence number
+// and the time in Unix nanoseconds as little endian 64 bit integers,
+// followed by the CRC32 (IEEE) of the preceding 28 bytes.
+type TraceHeader struct {
+	// KeyHash is the TraceKeyHash of the object name.
+	KeyHash uint64
+	// Seq is the sequence number of the object.
+	Seq uint64
+	// Time the object was generated.
+	Time time.Time
+}
+
+// TraceKeyHash returns the hash of key stored in a trace header.
+func TraceKeyHash(key string) uint64 {
+	h := fnv.New64a()
+	io.WriteString(h, key)
+	return h.Sum64()
+}
+
+// Marshal returns the encoded header.
+func (h TraceHeader) Marshal() [TraceHeaderSize]byte {
+	var b [TraceHeaderSize]byte
+	copy(b[:], traceMagic[:])
+	binary.LittleEndian.PutUint64(b[4:], h.KeyHash)
+	binary.LittleEndian.PutUint64(b[12:], h.Seq)
+	binary.LittleEndian.PutUint64(b[20:], uint64(h.Time.UnixNano()))
+	binary.LittleEndian.PutUint32(b[28:], crc32.ChecksumIEEE(b[:28]))
+	return b
+}
+
+// ParseTraceHeader decodes the header at the start of b.
+// ErrNoTraceHeader is returned if b is too short or does not contain a valid header.
+func ParseTraceHeader(b []byte) (TraceHeader, error) {
+	if len(b) < TraceHeaderSize || [4]byte(b[:4]) != traceMagic {
+		return TraceHeader{}, ErrNoTraceHeader
+	}
+	if crc32.ChecksumIEEE(b[:28]) != binary.LittleEndian.Uint32(b[28:]) {
+		return TraceHeader{}, fmt.Errorf("%w: checksum mismatch", ErrNoTraceHeader)
+	}
+	return TraceHeader{
+		KeyHash: binary.LittleEndian.Uint64(b[4:]),
+		Seq:     binary.LittleEndian.Uint64(b[12:]),
+		Time:    time.Unix(0, int64(binary.LittleEndian.Uint64(b[20:]))),
+	}, nil
+}
+
+// traceReader replaces the first bytes of r with a trace header.
+type traceReader struct {
+	r   io.ReadSeeker
+	hdr [TraceHeaderSize]byte
+	pos int64
+}
+
+// NewTraceReader returns a reader that returns the content of r,
+// with the first TraceHeaderSize bytes replaced by h.
+// The rest of the content is unchanged and at the same offsets as in r,
+// so it can be verified like data without a header.
+// If r is shorter than the header, the header is truncated.
+func NewTraceReader(r io.ReadSeeker, h TraceHeader) io.ReadSeeker {
+	return &traceReader{r: r, hdr: h.Marshal()}
+}
+
+// Read reads from the underlying reader and replaces any header bytes.
+func (t *traceReader) Read(p []byte) (int, error) {
+	n, err := t.r.Read(p)
+	if t.pos < TraceHeaderSize {
+		copy(p[:n], t.hdr[t.pos:])
+	}
+	t.pos += int64(n)
+	return n, err
+}
+
+// Seek forwards to the underlying reader.
+func (t *traceReader) Seek(offset int64, whence int) (int64, error) {
+	pos, err := t.r.Seek(offset, whence)
+	if err == nil {
+		t.pos = pos
+	}
+	return pos, err
+}
+
+// traceSource adds trace headers to the objects of a source.
+type traceSource struct {
+	Source
+	seq *atomic.Uint64
+}
+
+// Object returns the next object of the source with a trace header.
+func (t traceSource) Object() *Object {
+	obj := t.Source.Object()
+	obj.Reader = NewTraceReader(obj.Reader, TraceHeader{
+		KeyHash: TraceKeyHash(obj.Name),
+		Seq:     t.seq.Add(1),
+		Time:    time.Now(),
+	})
+	return obj
+}
+
+// VerifyTraced reads all of r and verifies it contains exactly size bytes of pattern,
+// except for a trace header at the start, which is returned.
+// Objects shorter than TraceHeaderSize only contain a partial header,
+// so only the header is checked for them.
+func VerifyTraced(r io.Reader, pattern []byte, size int64) (TraceHeader, error) {
+	var hdr [TraceHeaderSize]byte
+	n, err := io.ReadFull(r, hdr[:min(size, TraceHeaderSize)])
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return TraceHeader{}, err
+	}
+	if size < TraceHeaderSize {
+		// Only check the part of the magic that fits.
+		if m := min(n, len(traceMagic)); !bytes.Equal(hdr[:m], traceMagic[:m]) {
+			return TraceHeader{}, ErrNoTraceHeader
+		}
+		if extra, _ := r.Read(make([]byte, 1)); extra > 0 {
+			return TraceHeader{}, ErrVerifyTooLong
+		}
+		return TraceHeader{}, nil
+	}
+	h, err := ParseTraceHeader(hdr[:])
+	if err != nil {
+		return h, err
+	}
+	v := &VerifyReader{r: r, pattern: pattern, size: size, pos: TraceHeaderSize}
+	_, err = io.Copy(io.Discard, v)
+	return h, err
+}
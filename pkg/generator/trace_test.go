@@ -0,0 +1,172 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestTraceHeader(t *testing.T) {
+	h := TraceHeader{KeyHash: TraceKeyHash("prefix/obj-1"), Seq: 42, Time: time.Unix(1700000000, 123456789)}
+	b := h.Marshal()
+	got, err := ParseTraceHeader(b[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.KeyHash != h.KeyHash || got.Seq != 42 || !got.Time.Equal(h.Time) {
+		t.Errorf("got %+v, want %+v", got, h)
+	}
+	if TraceKeyHash("prefix/obj-1") == TraceKeyHash("prefix/obj-2") {
+		t.Error("different keys have the same hash")
+	}
+
+	corrupt := b
+	corrupt[15]++
+	if _, err := ParseTraceHeader(corrupt[:]); !errors.Is(err, ErrNoTraceHeader) {
+		t.Errorf("corrupted header: got %v", err)
+	}
+	if _, err := ParseTraceHeader(b[:TraceHeaderSize-1]); !errors.Is(err, ErrNoTraceHeader) {
+		t.Errorf("short header: got %v", err)
+	}
+	if _, err := ParseTraceHeader(StaticPattern(64)); !errors.Is(err, ErrNoTraceHeader) {
+		t.Errorf("pattern: got %v", err)
+	}
+}
+
+func TestTraceReader(t *testing.T) {
+	const patternSize = 1000
+	const size = 10000
+	pattern := StaticPattern(patternSize)
+	h := TraceHeader{KeyHash: TraceKeyHash("obj"), Seq: 7, Time: time.Now()}
+	newReader := func(size int64) io.ReadSeeker {
+		sr := newStaticReader(patternSize)
+		sr.ResetSize(size)
+		return NewTraceReader(sr, h)
+	}
+
+	data, err := io.ReadAll(newReader(size))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != size {
+		t.Fatalf("got %d bytes, want %d", len(data), size)
+	}
+	got, err := ParseTraceHeader(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.KeyHash != h.KeyHash || got.Seq != h.Seq || !got.Time.Equal(h.Time) {
+		t.Errorf("got header %+v, want %+v", got, h)
+	}
+	// The rest is the pattern at its usual offsets.
+	if !bytes.Equal(data[TraceHeaderSize:], PatternBytesAt(patternSize, TraceHeaderSize, size-TraceHeaderSize)) {
+		t.Error("pattern after header mismatch")
+	}
+
+	// Small reads and seeking inside the header.
+	r := newReader(size)
+	if _, err := r.Seek(10, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 7)
+	var partial []byte
+	for len(partial) < 40 {
+		n, err := r.Read(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		partial = append(partial, buf[:n]...)
+	}
+	if !bytes.Equal(partial, data[10:10+len(partial)]) {
+		t.Error("mismatch after seek")
+	}
+
+	// Verification.
+	traced, err := VerifyTraced(newReader(size), pattern, size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if traced.Seq != h.Seq {
+		t.Errorf("verified header seq %d, want %d", traced.Seq, h.Seq)
+	}
+	tampered := bytes.Clone(data)
+	tampered[5000]++
+	var mismatch *MismatchError
+	if _, err := VerifyTraced(bytes.NewReader(tampered), pattern, size); !errors.As(err, &mismatch) || mismatch.Offset != 5000 {
+		t.Errorf("tampered data: got %v", err)
+	}
+	if _, err := VerifyTraced(bytes.NewReader(data[:size-1]), pattern, size); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("short data: got %v", err)
+	}
+	plain := PatternBytesAt(patternSize, 0, size)
+	if _, err := VerifyTraced(bytes.NewReader(plain), pattern, size); !errors.Is(err, ErrNoTraceHeader) {
+		t.Errorf("data without header: got %v", err)
+	}
+
+	// Objects smaller than the header contain a truncated header.
+	small, err := io.ReadAll(newReader(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	full := h.Marshal()
+	if !bytes.Equal(small, full[:10]) {
+		t.Errorf("got %x, want %x", small, full[:10])
+	}
+	if _, err := VerifyTraced(bytes.NewReader(small), pattern, 10); err != nil {
+		t.Errorf("small object: %v", err)
+	}
+}
+
+func TestTraceHeaderSource(t *testing.T) {
+	src, err := NewFn(WithRandomData().Apply(), WithSize(4096), WithTraceHeader(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b := src(), src()
+	seen := make(map[uint64]bool)
+	for i := range 10 {
+		s := a
+		if i%2 == 1 {
+			s = b
+		}
+		obj := s.Object()
+		data, err := io.ReadAll(obj.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if int64(len(data)) != obj.Size {
+			t.Fatalf("got %d bytes, want %d", len(data), obj.Size)
+		}
+		h, err := ParseTraceHeader(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h.KeyHash != TraceKeyHash(obj.Name) {
+			t.Errorf("%s: header key hash does not match", obj.Name)
+		}
+		// Sequence numbers are unique across sources.
+		if seen[h.Seq] {
+			t.Errorf("duplicate sequence number %d", h.Seq)
+		}
+		seen[h.Seq] = true
+	}
+}
@@ -0,0 +1,113 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// MismatchError is returned when verified data does not match the expected data.
+type MismatchError struct {
+	// Offset of the first mismatching byte.
+	Offset int64
+	Got    byte
+	Want   byte
+}
+
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf("data mismatch at offset %d: got 0x%02x, want 0x%02x", e.Offset, e.Got, e.Want)
+}
+
+// ErrVerifyTooLong is returned when more data than expected is read.
+var ErrVerifyTooLong = errors.New("verify: more data than expected")
+
+// VerifyReader compares data read through it with a repeating pattern,
+// such as the one returned by StaticPattern.
+// Verification is done in a single forward pass without buffering.
+type VerifyReader struct {
+	r       io.Reader
+	pattern []byte
+	size    int64
+	pos     int64
+	err     error
+}
+
+// NewVerifyReader returns a reader that verifies that r returns exactly size bytes of pattern.
+// The pattern is not copied and should not be modified.
+func NewVerifyReader(r io.Reader, pattern []byte, size int64) *VerifyReader {
+	return &VerifyReader{r: r, pattern: pattern, size: size}
+}
+
+// Read reads from the underlying reader and verifies the data.
+// On mismatch a *MismatchError is returned.
+// If the underlying reader returns io.EOF before size bytes have been read,
+// io.ErrUnexpectedEOF is returned.
+func (v *VerifyReader) Read(p []byte) (n int, err error) {
+	if v.err != nil {
+		return 0, v.err
+	}
+	n, err = v.r.Read(p)
+	if verr := v.verify(p[:n]); verr != nil {
+		v.err = verr
+		return n, verr
+	}
+	if err == io.EOF && v.pos < v.size {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		v.err = err
+	}
+	return n, err
+}
+
+func (v *VerifyReader) verify(b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+	if v.pos+int64(len(b)) > v.size {
+		return ErrVerifyTooLong
+	}
+	if len(v.pattern) == 0 {
+		return errors.New("verify: empty pattern")
+	}
+	patternPos := int(v.pos % int64(len(v.pattern)))
+	for i, got := range b {
+		if want := v.pattern[patternPos]; got != want {
+			return &MismatchError{Offset: v.pos + int64(i), Got: got, Want: want}
+		}
+		patternPos++
+		if patternPos == len(v.pattern) {
+			patternPos = 0
+		}
+	}
+	v.pos += int64(len(b))
+	return nil
+}
+
+// Verified returns the number of bytes verified so far.
+func (v *VerifyReader) Verified() int64 {
+	return v.pos
+}
+
+// Verify reads all of r and verifies it contains exactly size bytes of pattern.
+func Verify(r io.Reader, pattern []byte, size int64) error {
+	_, err := io.Copy(io.Discard, NewVerifyReader(r, pattern, size))
+	return err
+}
@@ -0,0 +1,78 @@
+/*
+ * Warp (C) 2019-2025 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestVerifyReader(t *testing.T) {
+	const (
+		patternSize = 1000
+		size        = 10_000
+	)
+	pattern := StaticPattern(patternSize)
+	body := func() []byte {
+		r := newStaticReader(patternSize)
+		r.ResetSize(size)
+		b, _ := io.ReadAll(r)
+		return b
+	}
+	corrupt := func(off int) []byte {
+		b := body()
+		b[off]++
+		return b
+	}
+	tests := []struct {
+		name         string
+		data         []byte
+		wantErr      error
+		wantMismatch bool
+		wantOffset   int64
+	}{
+		{name: "ok", data: body()},
+		{name: "first-byte", data: corrupt(0), wantMismatch: true, wantOffset: 0},
+		{name: "pattern-boundary", data: corrupt(patternSize), wantMismatch: true, wantOffset: patternSize},
+		{name: "last-byte", data: corrupt(size - 1), wantMismatch: true, wantOffset: size - 1},
+		{name: "short", data: body()[:size-1], wantErr: io.ErrUnexpectedEOF},
+		{name: "long", data: append(body(), 0), wantErr: ErrVerifyTooLong},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Use odd read sizes.
+			r := io.MultiReader(bytes.NewReader(tt.data[:333]), bytes.NewReader(tt.data[333:]))
+			err := Verify(r, pattern, size)
+			if tt.wantMismatch {
+				var mm *MismatchError
+				if !errors.As(err, &mm) {
+					t.Fatalf("got error %v, want mismatch at %d", err, tt.wantOffset)
+				}
+				if mm.Offset != tt.wantOffset {
+					t.Errorf("got mismatch at %d, want %d", mm.Offset, tt.wantOffset)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}